@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/fixtures"
+	"github.com/antoniofmoliveira/courses/db/database/transfer"
+	"gopkg.in/yaml.v3"
+)
+
+const usage = `usage:
+  coursesdb copy -from <source.env> -to <destination.env>
+  coursesdb seed <fixture.yaml|fixture.json>
+  coursesdb generate [-categories n] [-courses n] [-users n] [-seed n]`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	switch os.Args[1] {
+	case "copy":
+		copyDatabase(os.Args[2:])
+	case "seed":
+		seed(os.Args[2:])
+	case "generate":
+		generate(os.Args[2:])
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func copyDatabase(args []string) {
+	flags := flag.NewFlagSet("copy", flag.ExitOnError)
+	from := flags.String("from", "", "env file of the source database")
+	to := flags.String("to", "", "env file of the destination database")
+	flags.Parse(args)
+	if *from == "" || *to == "" {
+		log.Fatal(usage)
+	}
+
+	src, err := open(*from)
+	if err != nil {
+		log.Fatalf("source: %v", err)
+	}
+	defer src.DB.Close()
+	dst, err := open(*to)
+	if err != nil {
+		log.Fatalf("destination: %v", err)
+	}
+	defer dst.DB.Close()
+
+	reports, err := transfer.Copy(context.Background(), src, dst)
+	fmt.Printf("%-12s %10s %10s %10s %12s\n", "table", "source", "copied", "skipped", "destination")
+	for _, r := range reports {
+		fmt.Printf("%-12s %10d %10d %10d %12d\n", r.Table, r.Source, r.Copied, r.Skipped, r.Destination)
+	}
+	if err != nil {
+		log.Fatalf("copy stopped, run it again to resume: %v", err)
+	}
+}
+
+func open(file string) (transfer.Database, error) {
+	db, driver, err := database.OpenFile(file)
+	if err != nil {
+		return transfer.Database{}, err
+	}
+	return transfer.Database{DB: db, Driver: driver}, nil
+}
+
+// seed loads a fixture into the database of the .env in the current folder.
+func seed(args []string) {
+	if len(args) != 1 {
+		log.Fatal(usage)
+	}
+	f, err := fixtures.ReadFile(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	report, err := fixtures.Load(context.Background(), database.GetDBImplementation(), f)
+	fmt.Printf("%-12s %10s %10s\n", "", "created", "existing")
+	for _, line := range []struct {
+		name  string
+		count fixtures.Count
+	}{{"categories", report.Categories}, {"courses", report.Courses}, {"users", report.Users}} {
+		fmt.Printf("%-12s %10d %10d\n", line.name, line.count.Created, line.count.Existing)
+	}
+	if err != nil {
+		log.Fatalf("seed stopped, run it again to resume: %v", err)
+	}
+}
+
+// generate writes a synthetic fixture to the standard output.
+func generate(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	var sizes fixtures.Sizes
+	flags.IntVar(&sizes.Categories, "categories", 10, "number of categories")
+	flags.IntVar(&sizes.CoursesPerCategory, "courses", 10, "number of courses of each category")
+	flags.IntVar(&sizes.Users, "users", 10, "number of users")
+	seed := flags.Uint64("seed", 1, "seed of the random names and descriptions")
+	flags.Parse(args)
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fixtures.Generate(sizes, *seed)); err != nil {
+		log.Fatal(err)
+	}
+	if err := encoder.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/migrations"
+)
+
+const usage = "usage: migrate up | down | status | to <version>"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	db, driver, err := database.Open()
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	migrator, err := migrations.New(db, driver)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	switch os.Args[1] {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		err = migrator.Down(ctx)
+	case "to":
+		if len(os.Args) != 3 {
+			log.Fatal(usage)
+		}
+		version, convErr := strconv.Atoi(os.Args[2])
+		if convErr != nil {
+			log.Fatalf("invalid version %q", os.Args[2])
+		}
+		err = migrator.To(ctx, version)
+	case "status":
+	default:
+		log.Fatal(usage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, status := range statuses {
+		applied := "pending"
+		if status.Applied {
+			applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%4d  %-24s %s\n", status.Version, status.Name, applied)
+	}
+}
@@ -1,6 +1,8 @@
 package configs
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -13,6 +15,37 @@ type conf struct {
 	DBUser     string `mapstructure:"DB_USER"`
 	DBPassword string `mapstructure:"DB_PASSWORD"`
 	DBName     string `mapstructure:"DB_NAME"`
+	// CategoryOnDelete is restrict (default) or cascade.
+	CategoryOnDelete string `mapstructure:"CATEGORY_ON_DELETE"`
+	// Pool limits; zero keeps the database/sql default.
+	DBMaxOpenConns    int           `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns    int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	DBConnMaxIdleTime time.Duration `mapstructure:"DB_CONN_MAX_IDLE_TIME"`
+	// DBConnectTimeout bounds connecting and the startup ping.
+	DBConnectTimeout time.Duration `mapstructure:"DB_CONNECT_TIMEOUT"`
+	// DBStatementTimeout aborts statements running longer, zero for no
+	// limit. SQLite has no such setting.
+	DBStatementTimeout time.Duration `mapstructure:"DB_STATEMENT_TIMEOUT"`
+	// DBTLS is the tls parameter of MariaDB (true, skip-verify, preferred)
+	// or the sslmode of PostgreSQL. DBTLSCA is a PEM file of the CA to trust.
+	DBTLS   string `mapstructure:"DB_TLS"`
+	DBTLSCA string `mapstructure:"DB_TLS_CA"`
+	// SQLite pragmas.
+	DBSQLiteJournalMode string        `mapstructure:"DB_SQLITE_JOURNAL_MODE"`
+	DBSQLiteBusyTimeout time.Duration `mapstructure:"DB_SQLITE_BUSY_TIMEOUT"`
+	// DBReadReplicas lists host:port of read replicas, comma separated. They
+	// are reached with the primary's credentials and settings.
+	DBReadReplicas []string `mapstructure:"DB_READ_REPLICAS"`
+	// Read-through cache of category and course lookups, off by default.
+	// CacheSize bounds the entries of each cached lookup.
+	CacheEnabled     bool          `mapstructure:"CACHE_ENABLED"`
+	CacheSize        int           `mapstructure:"CACHE_SIZE"`
+	CacheCategoryTTL time.Duration `mapstructure:"CACHE_CATEGORY_TTL"`
+	CacheCourseTTL   time.Duration `mapstructure:"CACHE_COURSE_TTL"`
+	// Admins lists the emails, comma separated, of the users who may change
+	// any course, not only those they teach.
+	Admins []string `mapstructure:"ADMINS"`
 	// WebServerPort  string `mapstructure:"WEB_SERVER_PORT"`
 	// WebServerHost  string `mapstructure:"WEB_SERVER_HOST"`
 	// JWTSecret      string `mapstructure:"JWT_SECRET"`
@@ -27,6 +60,7 @@ func LoadConfig(path string) (*conf, error) {
 	viper.AddConfigPath(path)
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
+	setDefaults(viper.GetViper())
 
 	if err := viper.ReadInConfig(); err != nil {
 		panic(err)
@@ -39,3 +73,47 @@ func LoadConfig(path string) (*conf, error) {
 
 	return cfg, nil
 }
+
+// LoadFile reads the settings of one env file, with the defaults but
+// without the environment overriding them, so that two databases can be
+// configured side by side.
+func LoadFile(file string) (*conf, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType("env")
+	setDefaults(v)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	var c conf
+	if err := v.Unmarshal(&c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("DB_CONNECT_TIMEOUT", "5s")
+	v.SetDefault("DB_SQLITE_JOURNAL_MODE", "WAL")
+	v.SetDefault("DB_SQLITE_BUSY_TIMEOUT", "5s")
+	v.SetDefault("CACHE_SIZE", 1000)
+	v.SetDefault("CACHE_CATEGORY_TTL", "5m")
+	v.SetDefault("CACHE_COURSE_TTL", "1m")
+}
+
+// Cache holds the settings of the repository cache.
+type Cache struct {
+	Enabled     bool
+	Size        int
+	CategoryTTL time.Duration
+	CourseTTL   time.Duration
+}
+
+func (c *conf) Cache() Cache {
+	return Cache{
+		Enabled:     c.CacheEnabled,
+		Size:        c.CacheSize,
+		CategoryTTL: c.CacheCategoryTTL,
+		CourseTTL:   c.CacheCourseTTL,
+	}
+}
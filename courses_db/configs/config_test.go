@@ -0,0 +1,38 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoadFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "source.env")
+	if err := os.WriteFile(file, []byte("DB_DRIVER=mysql\nDB_NAME=courses\nDB_READ_REPLICAS=db2:3306,db3:3306\nADMINS=ann@example.com,bob@example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DB_NAME", "from the environment")
+
+	c, err := LoadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.DBDriver != "mysql" || c.DBName != "courses" {
+		t.Errorf("LoadFile() driver, name = %q, %q", c.DBDriver, c.DBName)
+	}
+	if want := []string{"db2:3306", "db3:3306"}; !slices.Equal(c.DBReadReplicas, want) {
+		t.Errorf("LoadFile() replicas = %v, want %v", c.DBReadReplicas, want)
+	}
+	if want := []string{"ann@example.com", "bob@example.com"}; !slices.Equal(c.Admins, want) {
+		t.Errorf("LoadFile() admins = %v, want %v", c.Admins, want)
+	}
+	if c.DBConnectTimeout != 5*time.Second || c.CacheSize != 1000 {
+		t.Errorf("LoadFile() defaults = %v, %d", c.DBConnectTimeout, c.CacheSize)
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.env")); err == nil {
+		t.Error("LoadFile() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,100 @@
+package configs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlTLSConfig is the name DB_TLS_CA is registered under with the MariaDB
+// driver.
+const mysqlTLSConfig = "courses"
+
+// Pool holds the connection pool settings applied to every SQL connection.
+type Pool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+func (c *conf) Pool() Pool {
+	return Pool{
+		MaxOpenConns:    c.DBMaxOpenConns,
+		MaxIdleConns:    c.DBMaxIdleConns,
+		ConnMaxLifetime: c.DBConnMaxLifetime,
+		ConnMaxIdleTime: c.DBConnMaxIdleTime,
+	}
+}
+
+// DSN returns the data source name of the configured SQL driver for the
+// server at host and port: the primary or one of the read replicas.
+func (c *conf) DSN(host, port string) (string, error) {
+	switch c.DBDriver {
+	case "mysql":
+		return c.mysqlDSN(host, port)
+	case "sqlite3":
+		// foreign keys are off by default in SQLite, per connection
+		params := url.Values{"_foreign_keys": {"on"}}
+		if c.DBSQLiteJournalMode != "" {
+			params.Set("_journal_mode", c.DBSQLiteJournalMode)
+		}
+		if c.DBSQLiteBusyTimeout > 0 {
+			params.Set("_busy_timeout", strconv.FormatInt(c.DBSQLiteBusyTimeout.Milliseconds(), 10))
+		}
+		return c.DBName + ".db?" + params.Encode(), nil
+	case "postgres":
+		sslMode := c.DBTLS
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", host, port, c.DBUser, c.DBPassword, c.DBName, sslMode)
+		if c.DBTLSCA != "" {
+			dsn += " sslrootcert=" + c.DBTLSCA
+		}
+		if c.DBConnectTimeout > 0 {
+			dsn += fmt.Sprintf(" connect_timeout=%d", int(math.Ceil(c.DBConnectTimeout.Seconds())))
+		}
+		if c.DBStatementTimeout > 0 {
+			dsn += fmt.Sprintf(" statement_timeout=%d", c.DBStatementTimeout.Milliseconds())
+		}
+		return dsn, nil
+	}
+	return "", fmt.Errorf("unsupported database driver %q", c.DBDriver)
+}
+
+func (c *conf) mysqlDSN(host, port string) (string, error) {
+	mc := mysql.NewConfig()
+	mc.User, mc.Passwd, mc.DBName = c.DBUser, c.DBPassword, c.DBName
+	mc.Net, mc.Addr = "tcp", net.JoinHostPort(host, port)
+	mc.ParseTime, mc.ClientFoundRows = true, true
+	mc.Timeout = c.DBConnectTimeout
+	mc.TLSConfig = c.DBTLS
+	if c.DBTLSCA != "" {
+		pem, err := os.ReadFile(c.DBTLSCA)
+		if err != nil {
+			return "", err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return "", fmt.Errorf("no certificate found in %s", c.DBTLSCA)
+		}
+		if err := mysql.RegisterTLSConfig(mysqlTLSConfig, &tls.Config{RootCAs: roots}); err != nil {
+			return "", err
+		}
+		mc.TLSConfig = mysqlTLSConfig
+	}
+	if c.DBStatementTimeout > 0 {
+		// MariaDB's max_statement_time is in seconds
+		mc.Params = map[string]string{"max_statement_time": strconv.FormatFloat(c.DBStatementTimeout.Seconds(), 'f', -1, 64)}
+	}
+	return mc.FormatDSN(), nil
+}
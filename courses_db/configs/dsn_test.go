@@ -0,0 +1,59 @@
+package configs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    conf
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "mysql",
+			conf: conf{DBDriver: "mysql", DBUser: "app", DBPassword: "secret", DBName: "courses"},
+			want: "app:secret@tcp(db1:3306)/courses?clientFoundRows=true&parseTime=true",
+		},
+		{
+			name: "mysql with timeouts and tls",
+			conf: conf{DBDriver: "mysql", DBUser: "app", DBPassword: "secret", DBName: "courses", DBTLS: "true", DBConnectTimeout: 5 * time.Second, DBStatementTimeout: 1500 * time.Millisecond},
+			want: "app:secret@tcp(db1:3306)/courses?clientFoundRows=true&parseTime=true&timeout=5s&tls=true&max_statement_time=1.5",
+		},
+		{
+			name: "sqlite",
+			conf: conf{DBDriver: "sqlite3", DBName: "courses", DBSQLiteJournalMode: "WAL", DBSQLiteBusyTimeout: 5 * time.Second},
+			want: "courses.db?_busy_timeout=5000&_foreign_keys=on&_journal_mode=WAL",
+		},
+		{
+			name: "sqlite without pragmas",
+			conf: conf{DBDriver: "sqlite3", DBName: "courses"},
+			want: "courses.db?_foreign_keys=on",
+		},
+		{
+			name: "postgres",
+			conf: conf{DBDriver: "postgres", DBUser: "app", DBPassword: "secret", DBName: "courses"},
+			want: "host=db1 port=3306 user=app password=secret dbname=courses sslmode=disable",
+		},
+		{
+			name: "postgres with timeouts and tls",
+			conf: conf{DBDriver: "postgres", DBUser: "app", DBPassword: "secret", DBName: "courses", DBTLS: "verify-full", DBTLSCA: "/etc/ca.pem", DBConnectTimeout: 1500 * time.Millisecond, DBStatementTimeout: 2 * time.Second},
+			want: "host=db1 port=3306 user=app password=secret dbname=courses sslmode=verify-full sslrootcert=/etc/ca.pem connect_timeout=2 statement_timeout=2000",
+		},
+		{name: "unknown driver", conf: conf{DBDriver: "oracle"}, wantErr: true},
+		{name: "mysql with a missing CA", conf: conf{DBDriver: "mysql", DBTLSCA: "/nonexistent/ca.pem"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.conf.DSN("db1", "3306")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DSN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DSN() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Package audit attributes changes to the caller making them and names what
+// the change history records.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Entity types with a change history.
+const (
+	Course   = "course"
+	Category = "category"
+)
+
+// Actions recorded in the change history.
+const (
+	Create  = "create"
+	Update  = "update"
+	Delete  = "delete"
+	Restore = "restore"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context whose changes are attributed to subject, the
+// subject of the caller's JWT.
+func WithActor(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, actorKey{}, subject)
+}
+
+// Actor returns who changes made with ctx are attributed to, "" when the
+// caller is not known.
+func Actor(ctx context.Context) string {
+	subject, _ := ctx.Value(actorKey{}).(string)
+	return subject
+}
+
+// Snapshot encodes the state of an entity for the history, nil when there
+// is none.
+func Snapshot(state any) (*string, error) {
+	if state == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	return &s, nil
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func TestAudit(t *testing.T) {
+	implementations := map[string]*DBImplementation{
+		"sqlite": newSqliteImplementation(t, query.Cascade),
+		"memory": NewMemoryImplementation(query.Cascade),
+	}
+	for backend, dbi := range implementations {
+		t.Run(backend, func(t *testing.T) {
+			ann := audit.WithActor(context.Background(), "ann@example.com")
+			bob := audit.WithActor(context.Background(), "bob@example.com")
+
+			category, err := dbi.CategoryRepository.Create(ann, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			course, err := dbi.CourseRepository.Create(ann, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if course.CreatedBy != "ann@example.com" || course.UpdatedBy != "ann@example.com" || !course.UpdatedAt.Equal(course.CreatedAt) {
+				t.Errorf("Create() = %+v, want created and updated by ann", course)
+			}
+			if err := dbi.CourseRepository.Update(bob, dto.CourseInputDto{ID: course.ID, Name: "advanced", CategoryID: category.ID}); err != nil {
+				t.Fatal(err)
+			}
+			got, err := dbi.CourseRepository.Find(bob, course.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.CreatedBy != "ann@example.com" || got.UpdatedBy != "bob@example.com" || got.UpdatedAt.Before(got.CreatedAt) {
+				t.Errorf("Find() = %+v, want created by ann and updated by bob", got)
+			}
+			// the cascade records the course deletion too
+			if err := dbi.CategoryRepository.Delete(bob, category.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := dbi.CategoryRepository.Restore(ann, category.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := dbi.CourseRepository.Restore(ann, course.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			history := func(entityType, id string) []dto.HistoryOutputDto {
+				t.Helper()
+				got, err := dbi.HistoryRepository.List(ann, query.HistorySpec{EntityType: entityType, EntityID: id})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return got.Entries
+			}
+			courseHistory := history(audit.Course, course.ID)
+			want := []struct{ action, by, beforeName, afterName string }{
+				{audit.Create, "ann@example.com", "", "basics"},
+				{audit.Update, "bob@example.com", "basics", "advanced"},
+				{audit.Delete, "bob@example.com", "advanced", "advanced"},
+				{audit.Restore, "ann@example.com", "advanced", "advanced"},
+			}
+			if len(courseHistory) != len(want) {
+				t.Fatalf("course history = %+v, want %d entries", courseHistory, len(want))
+			}
+			for i, w := range want {
+				entry := courseHistory[i]
+				if entry.Action != w.action || entry.ChangedBy != w.by || snapshotName(t, entry.Before) != w.beforeName || snapshotName(t, entry.After) != w.afterName {
+					t.Errorf("entry %d = %s by %s, %s -> %s; want %+v", i, entry.Action, entry.ChangedBy, entry.Before, entry.After, w)
+				}
+			}
+			var deleted dto.CourseOutputDto
+			if err := json.Unmarshal(courseHistory[2].After, &deleted); err != nil || deleted.DeletedAt == nil {
+				t.Errorf("delete snapshot = %s, want deleted_at set", courseHistory[2].After)
+			}
+			var actions []string
+			for _, entry := range history(audit.Category, category.ID) {
+				actions = append(actions, entry.Action)
+			}
+			if want := []string{audit.Create, audit.Delete, audit.Restore}; !reflect.DeepEqual(actions, want) {
+				t.Errorf("category history = %v, want %v", actions, want)
+			}
+
+			first, err := dbi.HistoryRepository.List(ann, query.HistorySpec{EntityType: audit.Course, EntityID: course.ID, Page: query.Page{Limit: 3}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rest, err := dbi.HistoryRepository.List(ann, query.HistorySpec{EntityType: audit.Course, EntityID: course.ID, Page: query.Page{Limit: 3, Cursor: first.NextCursor}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(first.Entries) != 3 || len(rest.Entries) != 1 || rest.Entries[0].Action != audit.Restore || rest.NextCursor != "" {
+				t.Errorf("pages = %+v, %+v", first, rest)
+			}
+		})
+	}
+}
+
+func TestAuditRollback(t *testing.T) {
+	implementations := map[string]*DBImplementation{
+		"sqlite": newSqliteImplementation(t, query.Restrict),
+		"memory": NewMemoryImplementation(query.Restrict),
+	}
+	errAbort := errors.New("abort")
+	for backend, dbi := range implementations {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			var id string
+			err := InTx(ctx, dbi, func(tx *Tx) error {
+				category, err := tx.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+				id = category.ID
+				if err != nil {
+					return err
+				}
+				return errAbort
+			})
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("InTx() error = %v", err)
+			}
+			got, err := dbi.HistoryRepository.List(ctx, query.HistorySpec{EntityType: audit.Category, EntityID: id})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Entries) != 0 {
+				t.Errorf("history after rollback = %+v, want none", got.Entries)
+			}
+		})
+	}
+}
+
+// snapshotName returns the name held by a history snapshot, "" for none.
+func snapshotName(t *testing.T, snapshot json.RawMessage) string {
+	t.Helper()
+	if snapshot == nil {
+		return ""
+	}
+	var state struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(snapshot, &state); err != nil {
+		t.Fatalf("snapshot %s: %v", snapshot, err)
+	}
+	return state.Name
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New[string, int](2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	type step struct {
+		name   string
+		do     func()
+		key    string
+		want   int
+		wantOK bool
+	}
+	steps := []step{
+		{name: "empty", key: "a"},
+		{name: "added", do: func() { c.Add("a", 1) }, key: "a", want: 1, wantOK: true},
+		{name: "replaced", do: func() { c.Add("a", 2) }, key: "a", want: 2, wantOK: true},
+		// a was used last, b goes when c comes in
+		{name: "least recently used evicted", do: func() { c.Add("b", 3); c.Get("a"); c.Add("c", 4) }, key: "b"},
+		{name: "recently used kept", key: "a", want: 2, wantOK: true},
+		{name: "removed", do: func() { c.Remove("a") }, key: "a"},
+		{name: "matching removed", do: func() { c.Add("d", 5); c.RemoveFunc(func(_ string, v int) bool { return v == 4 }) }, key: "c"},
+		{name: "others kept", key: "d", want: 5, wantOK: true},
+		{name: "expired", do: func() { now = now.Add(time.Minute + time.Second) }, key: "d"},
+	}
+	for _, s := range steps {
+		if s.do != nil {
+			s.do()
+		}
+		if got, ok := c.Get(s.key); got != s.want || ok != s.wantOK {
+			t.Errorf("%s: Get(%q) = %d, %v, want %d, %v", s.name, s.key, got, ok, s.want, s.wantOK)
+		}
+	}
+
+	want := Stats{Hits: 5, Misses: 5, Evictions: 1, Len: 0}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
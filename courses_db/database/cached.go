@@ -0,0 +1,270 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/configs"
+	"github.com/antoniofmoliveira/courses/db/database/cache"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// CacheStats reports the cached lookups: categories and courses by id, and
+// the category of a course by the course id.
+type CacheStats struct {
+	Categories       cache.Stats `json:"categories"`
+	Courses          cache.Stats `json:"courses"`
+	CourseCategories cache.Stats `json:"course_categories"`
+}
+
+// repositoryCache holds the lookups cached for a DBImplementation. Only
+// live items are cached; misses and errors go to the database every time.
+type repositoryCache struct {
+	categories       *cache.LRU[string, dto.CategoryOutputDto]
+	courses          *cache.LRU[string, dto.CourseOutputDto]
+	courseCategories *cache.LRU[string, dto.CategoryOutputDto]
+}
+
+// forgetCategory evicts a category changed by a write, with the courses a
+// cascading delete may have taken along.
+func (c *repositoryCache) forgetCategory(id string) {
+	c.categories.Remove(id)
+	c.courses.RemoveFunc(func(_ string, course dto.CourseOutputDto) bool { return course.CategoryID == id })
+	c.courseCategories.RemoveFunc(func(_ string, category dto.CategoryOutputDto) bool { return category.ID == id })
+}
+
+func (c *repositoryCache) forgetCourse(id string) {
+	c.courses.Remove(id)
+	c.courseCategories.Remove(id)
+}
+
+// WithCache puts a read-through cache in front of the category and course
+// lookups of d when cfg enables it. Writes made through d, in or out of a
+// unit of work, evict what they change; writes made by other processes show
+// once the cached entries expire.
+func (d *DBImplementation) WithCache(cfg configs.Cache) *DBImplementation {
+	if !cfg.Enabled {
+		return d
+	}
+	c := &repositoryCache{
+		categories:       cache.New[string, dto.CategoryOutputDto](cfg.Size, cfg.CategoryTTL),
+		courses:          cache.New[string, dto.CourseOutputDto](cfg.Size, cfg.CourseTTL),
+		courseCategories: cache.New[string, dto.CategoryOutputDto](cfg.Size, cfg.CategoryTTL),
+	}
+	d.cache = c
+	d.CategoryRepository = &cachedCategoryRepository{CategoryRepositoryInterface: d.CategoryRepository, cache: c}
+	d.CourseRepository = &cachedCourseRepository{CourseRepositoryInterface: d.CourseRepository, cache: c}
+	if begin := d.begin; begin != nil {
+		d.begin = func(ctx context.Context) (*Tx, error) {
+			tx, err := begin(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.wrapTx(tx), nil
+		}
+	}
+	return d
+}
+
+// CacheReporter reports how the cache of a DBImplementation is doing.
+// DBImplementation is one.
+type CacheReporter interface {
+	CacheStats() CacheStats
+}
+
+// CacheStats reports the hits and misses of the cache, zero when it is off.
+func (d *DBImplementation) CacheStats() CacheStats {
+	if d.cache == nil {
+		return CacheStats{}
+	}
+	return CacheStats{
+		Categories:       d.cache.categories.Stats(),
+		Courses:          d.cache.courses.Stats(),
+		CourseCategories: d.cache.courseCategories.Stats(),
+	}
+}
+
+// wrapTx has the repositories of a unit of work read past the cache, which
+// must not hold uncommitted items, and evict what they change once the
+// unit of work commits.
+func (c *repositoryCache) wrapTx(tx *Tx) *Tx {
+	pending := &pendingEvictions{}
+	tx.CategoryRepository = &cachedCategoryRepository{CategoryRepositoryInterface: tx.CategoryRepository, cache: c, pending: pending}
+	tx.CourseRepository = &cachedCourseRepository{CourseRepositoryInterface: tx.CourseRepository, cache: c, pending: pending}
+	commit := tx.commit
+	tx.commit = func() error {
+		if err := commit(); err != nil {
+			return err
+		}
+		pending.run()
+		return nil
+	}
+	return tx
+}
+
+// pendingEvictions are the evictions of a unit of work, made on commit.
+type pendingEvictions struct {
+	mu     sync.Mutex
+	evicts []func()
+}
+
+func (p *pendingEvictions) run() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, evict := range p.evicts {
+		evict()
+	}
+	p.evicts = nil
+}
+
+// evict runs fn now, or on commit when pending is set.
+func evict(pending *pendingEvictions, fn func()) {
+	if pending == nil {
+		fn()
+		return
+	}
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	pending.evicts = append(pending.evicts, fn)
+}
+
+// cachedCategoryRepository serves Find and FindByCourseID from the cache;
+// outside a unit of work, pending is nil.
+type cachedCategoryRepository struct {
+	CategoryRepositoryInterface
+	cache   *repositoryCache
+	pending *pendingEvictions
+}
+
+func (c *cachedCategoryRepository) Find(ctx context.Context, id string) (dto.CategoryOutputDto, error) {
+	if c.pending == nil {
+		if category, ok := c.cache.categories.Get(id); ok {
+			return category, nil
+		}
+	}
+	category, err := c.CategoryRepositoryInterface.Find(ctx, id)
+	if err == nil && c.pending == nil {
+		c.cache.categories.Add(id, category)
+	}
+	return category, err
+}
+
+func (c *cachedCategoryRepository) FindByCourseID(ctx context.Context, courseID string) (dto.CategoryOutputDto, error) {
+	if c.pending == nil {
+		if category, ok := c.cache.courseCategories.Get(courseID); ok {
+			return category, nil
+		}
+	}
+	category, err := c.CategoryRepositoryInterface.FindByCourseID(ctx, courseID)
+	if err == nil && c.pending == nil {
+		c.cache.courseCategories.Add(courseID, category)
+	}
+	return category, err
+}
+
+func (c *cachedCategoryRepository) forget(ids ...string) {
+	evict(c.pending, func() {
+		for _, id := range ids {
+			c.cache.forgetCategory(id)
+		}
+	})
+}
+
+func (c *cachedCategoryRepository) Update(ctx context.Context, category dto.CategoryInputDto) error {
+	defer c.forget(category.ID)
+	return c.CategoryRepositoryInterface.Update(ctx, category)
+}
+
+func (c *cachedCategoryRepository) Delete(ctx context.Context, id string) error {
+	defer c.forget(id)
+	return c.CategoryRepositoryInterface.Delete(ctx, id)
+}
+
+func (c *cachedCategoryRepository) Restore(ctx context.Context, id string) error {
+	defer c.forget(id)
+	return c.CategoryRepositoryInterface.Restore(ctx, id)
+}
+
+// Purge can lift subcategories to the top level, so every cached
+// subcategory is evicted.
+func (c *cachedCategoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	defer evict(c.pending, func() {
+		nested := func(_ string, category dto.CategoryOutputDto) bool { return category.ParentID != "" }
+		c.cache.categories.RemoveFunc(nested)
+		c.cache.courseCategories.RemoveFunc(nested)
+	})
+	return c.CategoryRepositoryInterface.Purge(ctx, before)
+}
+
+func (c *cachedCategoryRepository) UpdateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error) {
+	ids := make([]string, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	defer c.forget(ids...)
+	return c.CategoryRepositoryInterface.UpdateBatch(ctx, categories)
+}
+
+func (c *cachedCategoryRepository) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	defer c.forget(ids...)
+	return c.CategoryRepositoryInterface.DeleteBatch(ctx, ids)
+}
+
+// cachedCourseRepository serves Find from the cache; outside a unit of
+// work, pending is nil.
+type cachedCourseRepository struct {
+	CourseRepositoryInterface
+	cache   *repositoryCache
+	pending *pendingEvictions
+}
+
+func (c *cachedCourseRepository) Find(ctx context.Context, id string) (dto.CourseOutputDto, error) {
+	if c.pending == nil {
+		if course, ok := c.cache.courses.Get(id); ok {
+			return course, nil
+		}
+	}
+	course, err := c.CourseRepositoryInterface.Find(ctx, id)
+	if err == nil && c.pending == nil {
+		c.cache.courses.Add(id, course)
+	}
+	return course, err
+}
+
+func (c *cachedCourseRepository) forget(ids ...string) {
+	evict(c.pending, func() {
+		for _, id := range ids {
+			c.cache.forgetCourse(id)
+		}
+	})
+}
+
+func (c *cachedCourseRepository) Update(ctx context.Context, course dto.CourseInputDto) error {
+	defer c.forget(course.ID)
+	return c.CourseRepositoryInterface.Update(ctx, course)
+}
+
+func (c *cachedCourseRepository) Delete(ctx context.Context, id string) error {
+	defer c.forget(id)
+	return c.CourseRepositoryInterface.Delete(ctx, id)
+}
+
+func (c *cachedCourseRepository) Restore(ctx context.Context, id string) error {
+	defer c.forget(id)
+	return c.CourseRepositoryInterface.Restore(ctx, id)
+}
+
+func (c *cachedCourseRepository) UpdateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	ids := make([]string, len(courses))
+	for i, course := range courses {
+		ids[i] = course.ID
+	}
+	defer c.forget(ids...)
+	return c.CourseRepositoryInterface.UpdateBatch(ctx, courses)
+}
+
+func (c *cachedCourseRepository) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	defer c.forget(ids...)
+	return c.CourseRepositoryInterface.DeleteBatch(ctx, ids)
+}
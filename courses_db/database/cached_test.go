@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/configs"
+	"github.com/antoniofmoliveira/courses/db/database/cache"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	dbi := NewMemoryImplementation(query.Restrict).WithCache(configs.Cache{Enabled: true, Size: 2, CategoryTTL: time.Minute, CourseTTL: time.Minute})
+	golang, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: golang.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	findCategory := func(want string) {
+		t.Helper()
+		got, err := dbi.CategoryRepository.Find(ctx, golang.ID)
+		if err != nil || got.Name != want {
+			t.Fatalf("Find() = %+v, %v, want %s", got, err, want)
+		}
+	}
+	rename := func(repo CategoryRepositoryInterface, name string) error {
+		return repo.Update(ctx, dto.CategoryInputDto{ID: golang.ID, Name: name})
+	}
+
+	findCategory("Go")
+	findCategory("Go")
+	for range 2 {
+		if got, err := dbi.CategoryRepository.FindByCourseID(ctx, course.ID); err != nil || got.ID != golang.ID {
+			t.Fatalf("FindByCourseID() = %+v, %v", got, err)
+		}
+	}
+	if err := rename(dbi.CategoryRepository, "Golang"); err != nil {
+		t.Fatal(err)
+	}
+	findCategory("Golang")
+	if got, err := dbi.CategoryRepository.FindByCourseID(ctx, course.ID); err != nil || got.Name != "Golang" {
+		t.Fatalf("FindByCourseID() after update = %+v, %v", got, err)
+	}
+
+	// a unit of work evicts on commit only
+	if err := InTx(ctx, dbi, func(tx *Tx) error { return rename(tx.CategoryRepository, "Go language") }); err != nil {
+		t.Fatal(err)
+	}
+	findCategory("Go language")
+	tx, err := dbi.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rename(tx.CategoryRepository, "rolled back"); err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+	findCategory("Go language")
+
+	want := CacheStats{
+		Categories:       cache.Stats{Hits: 2, Misses: 3, Len: 1},
+		CourseCategories: cache.Stats{Hits: 1, Misses: 2},
+	}
+	if got := dbi.CacheStats(); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+
+	if err := dbi.CourseRepository.Delete(ctx, course.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbi.CategoryRepository.FindByCourseID(ctx, course.ID); err == nil {
+		t.Error("FindByCourseID() of a deleted course answered from the cache")
+	}
+}
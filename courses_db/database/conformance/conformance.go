@@ -0,0 +1,1733 @@
+// Package conformance checks that a DBImplementation behaves like every
+// other backend: the same results, the same errors for the same mistakes.
+// Backends run it from their tests:
+//
+//	conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+//		return newEmptyDatabase(t, onDelete)
+//	})
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+// Factory returns a DBImplementation over an empty, migrated database. It
+// is called once per group of checks; backends sharing one server reset it
+// each time.
+type Factory func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation
+
+// step is one call of a group of checks, made in order. A step failing
+// stops the group, as the later steps build on it.
+type step struct {
+	name    string
+	run     func() error
+	wantErr error
+}
+
+func runSteps(t *testing.T, steps []step) {
+	t.Helper()
+	for _, s := range steps {
+		err := s.run()
+		if s.wantErr == nil && err != nil || s.wantErr != nil && !errors.Is(err, s.wantErr) {
+			t.Fatalf("%s: error = %v, want %v", s.name, err, s.wantErr)
+		}
+	}
+}
+
+// Run checks every repository of the implementations newDB returns.
+func Run(t *testing.T, newDB Factory) {
+	t.Run("categories", func(t *testing.T) { testCategories(t, newDB(t, query.Restrict)) })
+	t.Run("courses", func(t *testing.T) { testCourses(t, newDB(t, query.Restrict)) })
+	t.Run("users", func(t *testing.T) { testUsers(t, newDB(t, query.Restrict)) })
+	t.Run("listing", func(t *testing.T) { testListing(t, newDB(t, query.Restrict)) })
+	t.Run("cascade", func(t *testing.T) { testCascade(t, newDB(t, query.Cascade)) })
+	t.Run("history", func(t *testing.T) { testHistory(t, newDB(t, query.Restrict)) })
+	t.Run("search", func(t *testing.T) { testSearch(t, newDB(t, query.Restrict)) })
+	t.Run("transactions", func(t *testing.T) { testTransactions(t, newDB(t, query.Restrict)) })
+	t.Run("batch", func(t *testing.T) { testBatch(t, newDB(t, query.Restrict)) })
+	t.Run("purge", func(t *testing.T) { testPurge(t, newDB(t, query.Restrict)) })
+	t.Run("modules", func(t *testing.T) { testModules(t, newDB(t, query.Restrict)) })
+	t.Run("lessons", func(t *testing.T) { testLessons(t, newDB(t, query.Restrict)) })
+	t.Run("enrollments", func(t *testing.T) { testEnrollments(t, newDB(t, query.Restrict)) })
+	t.Run("instructors", func(t *testing.T) { testInstructors(t, newDB(t, query.Restrict)) })
+	t.Run("category tree", func(t *testing.T) { testCategoryTree(t, newDB(t, query.Restrict)) })
+	t.Run("tags", func(t *testing.T) { testTags(t, newDB(t, query.Restrict)) })
+}
+
+func testCategories(t *testing.T, dbi *database.DBImplementation) {
+	ctx := audit.WithActor(context.Background(), "ann@example.com")
+	repo := dbi.CategoryRepository
+	var category dto.CategoryOutputDto
+	runSteps(t, []step{
+		{name: "create", run: func() (err error) {
+			category, err = repo.Create(ctx, dto.CategoryInputDto{Name: "Go", Description: "golang"})
+			if err == nil && (category.ID == "" || category.Name != "Go" || category.Description != "golang" ||
+				category.Version != 1 || category.CreatedBy != "ann@example.com" || category.CreatedAt.IsZero()) {
+				t.Errorf("Create() = %+v", category)
+			}
+			return err
+		}},
+		{name: "id is a uuid", run: func() error {
+			_, err := uuid.Parse(category.ID)
+			return err
+		}},
+		{name: "create without name", run: func() error {
+			_, err := repo.Create(ctx, dto.CategoryInputDto{Description: "x"})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "find", run: func() error {
+			got, err := repo.Find(ctx, category.ID)
+			if err == nil && (got.ID != category.ID || got.Name != "Go" || got.Description != "golang" || got.Version != 1) {
+				t.Errorf("Find() = %+v, want %+v", got, category)
+			}
+			return err
+		}},
+		{name: "find unknown", run: func() error {
+			_, err := repo.Find(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "update", run: func() error {
+			return repo.Update(ctx, dto.CategoryInputDto{ID: category.ID, Name: "Golang", Description: "the Go language", Version: 1})
+		}},
+		{name: "update is visible", run: func() error {
+			got, err := repo.Find(ctx, category.ID)
+			if err == nil && (got.Name != "Golang" || got.Description != "the Go language" || got.Version != 2) {
+				t.Errorf("Find() = %+v, want the update at version 2", got)
+			}
+			return err
+		}},
+		{name: "update at stale version", run: func() error {
+			return repo.Update(ctx, dto.CategoryInputDto{ID: category.ID, Name: "Go", Version: 1})
+		}, wantErr: database.ErrStaleVersion},
+		{name: "update without name", run: func() error {
+			return repo.Update(ctx, dto.CategoryInputDto{ID: category.ID})
+		}, wantErr: database.ErrValidation},
+		{name: "update unknown", run: func() error {
+			return repo.Update(ctx, dto.CategoryInputDto{ID: uuid.NewString(), Name: "x"})
+		}, wantErr: database.ErrNotFound},
+		{name: "delete", run: func() error { return repo.Delete(ctx, category.ID) }},
+		{name: "deleted is hidden", run: func() error {
+			_, err := repo.Find(ctx, category.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "delete twice", run: func() error { return repo.Delete(ctx, category.ID) }, wantErr: database.ErrNotFound},
+		{name: "update deleted", run: func() error {
+			return repo.Update(ctx, dto.CategoryInputDto{ID: category.ID, Name: "x"})
+		}, wantErr: database.ErrNotFound},
+		{name: "delete unknown", run: func() error { return repo.Delete(ctx, uuid.NewString()) }, wantErr: database.ErrNotFound},
+		{name: "restore", run: func() error { return repo.Restore(ctx, category.ID) }},
+		{name: "restored is back", run: func() error {
+			got, err := repo.Find(ctx, category.ID)
+			if err == nil && (got.DeletedAt != nil || got.Version != 4) {
+				t.Errorf("Find() = %+v, want live at version 4", got)
+			}
+			return err
+		}},
+		{name: "restore live", run: func() error { return repo.Restore(ctx, category.ID) }, wantErr: database.ErrNotFound},
+		{name: "restore unknown", run: func() error { return repo.Restore(ctx, uuid.NewString()) }, wantErr: database.ErrNotFound},
+	})
+}
+
+func testCourses(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.CourseRepository
+	var category, other dto.CategoryOutputDto
+	var course *dto.CourseOutputDto
+	runSteps(t, []step{
+		{name: "create categories", run: func() (err error) {
+			if category, err = dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"}); err != nil {
+				return err
+			}
+			other, err = dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Rust"})
+			return err
+		}},
+		{name: "create", run: func() (err error) {
+			course, err = repo.Create(ctx, dto.CourseInputDto{Name: "basics", Description: "intro", CategoryID: category.ID})
+			if err == nil && (course.ID == "" || course.Name != "basics" || course.CategoryID != category.ID || course.Version != 1) {
+				t.Errorf("Create() = %+v", course)
+			}
+			return err
+		}},
+		{name: "create without name", run: func() error {
+			_, err := repo.Create(ctx, dto.CourseInputDto{CategoryID: category.ID})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "create without category", run: func() error {
+			_, err := repo.Create(ctx, dto.CourseInputDto{Name: "x"})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "create in unknown category", run: func() error {
+			_, err := repo.Create(ctx, dto.CourseInputDto{Name: "x", CategoryID: uuid.NewString()})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "find", run: func() error {
+			got, err := repo.Find(ctx, course.ID)
+			if err == nil && (got.Name != "basics" || got.Description != "intro" || got.CategoryID != category.ID) {
+				t.Errorf("Find() = %+v", got)
+			}
+			return err
+		}},
+		{name: "find unknown", run: func() error {
+			_, err := repo.Find(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "category of course", run: func() error {
+			got, err := dbi.CategoryRepository.FindByCourseID(ctx, course.ID)
+			if err == nil && got.ID != category.ID {
+				t.Errorf("FindByCourseID() = %+v, want %s", got, category.ID)
+			}
+			return err
+		}},
+		{name: "category of unknown course", run: func() error {
+			_, err := dbi.CategoryRepository.FindByCourseID(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "courses of category", run: func() error {
+			got, err := repo.FindByCategoryID(ctx, category.ID, query.Page{})
+			if err == nil && (len(got.Courses) != 1 || got.Courses[0].ID != course.ID) {
+				t.Errorf("FindByCategoryID() = %+v, want the course", got.Courses)
+			}
+			return err
+		}},
+		{name: "move to other category", run: func() error {
+			return repo.Update(ctx, dto.CourseInputDto{ID: course.ID, Name: "basics", CategoryID: other.ID, Version: 1})
+		}},
+		{name: "moved course", run: func() error {
+			got, err := repo.FindByCategoryID(ctx, other.ID, query.Page{})
+			if err == nil && (len(got.Courses) != 1 || got.Courses[0].Version != 2) {
+				t.Errorf("FindByCategoryID() = %+v, want the course at version 2", got.Courses)
+			}
+			return err
+		}},
+		{name: "move to unknown category", run: func() error {
+			return repo.Update(ctx, dto.CourseInputDto{ID: course.ID, Name: "basics", CategoryID: uuid.NewString()})
+		}, wantErr: database.ErrValidation},
+		{name: "update at stale version", run: func() error {
+			return repo.Update(ctx, dto.CourseInputDto{ID: course.ID, Name: "x", CategoryID: other.ID, Version: 1})
+		}, wantErr: database.ErrStaleVersion},
+		{name: "update unknown", run: func() error {
+			return repo.Update(ctx, dto.CourseInputDto{ID: uuid.NewString(), Name: "x", CategoryID: other.ID})
+		}, wantErr: database.ErrNotFound},
+		{name: "delete category with courses", run: func() error {
+			return dbi.CategoryRepository.Delete(ctx, other.ID)
+		}, wantErr: database.ErrHasDependents},
+		{name: "delete", run: func() error { return repo.Delete(ctx, course.ID) }},
+		{name: "deleted is hidden", run: func() error {
+			_, err := repo.Find(ctx, course.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "deleted is left out of its category", run: func() error {
+			got, err := repo.FindByCategoryID(ctx, other.ID, query.Page{})
+			if err == nil && len(got.Courses) != 0 {
+				t.Errorf("FindByCategoryID() = %+v, want none", got.Courses)
+			}
+			return err
+		}},
+		{name: "delete unknown", run: func() error { return repo.Delete(ctx, uuid.NewString()) }, wantErr: database.ErrNotFound},
+		{name: "delete empty category", run: func() error { return dbi.CategoryRepository.Delete(ctx, other.ID) }},
+		{name: "restore into deleted category", run: func() error { return repo.Restore(ctx, course.ID) }, wantErr: database.ErrValidation},
+		{name: "restore category and course", run: func() error {
+			if err := dbi.CategoryRepository.Restore(ctx, other.ID); err != nil {
+				return err
+			}
+			return repo.Restore(ctx, course.ID)
+		}},
+		{name: "restore live", run: func() error { return repo.Restore(ctx, course.ID) }, wantErr: database.ErrNotFound},
+	})
+}
+
+func testUsers(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.UserRepository
+	var user dto.UserOutputDto
+	runSteps(t, []step{
+		{name: "create", run: func() (err error) {
+			user, err = repo.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "hash"})
+			if err == nil && (user.ID == "" || user.Name != "Ann" || user.Email != "ann@example.com" || user.Version != 1) {
+				t.Errorf("Create() = %+v", user)
+			}
+			return err
+		}},
+		{name: "create without email", run: func() error {
+			_, err := repo.Create(ctx, dto.UserInputDto{Name: "Bob"})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "duplicate email", run: func() error {
+			_, err := repo.Create(ctx, dto.UserInputDto{Name: "Other", Email: "ann@example.com", Password: "x"})
+			return err
+		}, wantErr: database.ErrConflict},
+		{name: "password by email", run: func() error {
+			got, err := repo.FindByEmail(ctx, "ann@example.com")
+			if err == nil && (got.Email != "ann@example.com" || got.Password != "hash") {
+				t.Errorf("FindByEmail() = %+v", got)
+			}
+			return err
+		}},
+		{name: "unknown email", run: func() error {
+			_, err := repo.FindByEmail(ctx, "nobody@example.com")
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "find", run: func() error {
+			got, err := repo.Find(ctx, user.ID)
+			if err == nil && (got.Email != "ann@example.com" || got.Name != "Ann") {
+				t.Errorf("Find() = %+v", got)
+			}
+			return err
+		}},
+		{name: "find unknown", run: func() error {
+			_, err := repo.Find(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "update", run: func() error {
+			return repo.Update(ctx, dto.UserInputDto{ID: user.ID, Name: "Ann Lee", Email: "ann@example.com", Password: "hash2", Version: 1})
+		}},
+		{name: "update is visible", run: func() error {
+			got, err := repo.FindByEmail(ctx, "ann@example.com")
+			if err == nil && got.Password != "hash2" {
+				t.Errorf("FindByEmail() = %+v, want the new password", got)
+			}
+			return err
+		}},
+		{name: "update at stale version", run: func() error {
+			return repo.Update(ctx, dto.UserInputDto{ID: user.ID, Name: "x", Email: "ann@example.com", Version: 1})
+		}, wantErr: database.ErrStaleVersion},
+		{name: "take another user's email", run: func() error {
+			other, err := repo.Create(ctx, dto.UserInputDto{Name: "Bob", Email: "bob@example.com", Password: "x"})
+			if err != nil {
+				return err
+			}
+			return repo.Update(ctx, dto.UserInputDto{ID: other.ID, Name: "Bob", Email: "ann@example.com", Password: "x"})
+		}, wantErr: database.ErrConflict},
+		{name: "update unknown", run: func() error {
+			return repo.Update(ctx, dto.UserInputDto{ID: uuid.NewString(), Name: "x", Email: "x@example.com"})
+		}, wantErr: database.ErrNotFound},
+		{name: "delete", run: func() error { return repo.Delete(ctx, user.ID) }},
+		{name: "deleted cannot log in", run: func() error {
+			_, err := repo.FindByEmail(ctx, "ann@example.com")
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "deleted is hidden", run: func() error {
+			_, err := repo.Find(ctx, user.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "delete twice", run: func() error { return repo.Delete(ctx, user.ID) }, wantErr: database.ErrNotFound},
+		{name: "restore", run: func() error { return repo.Restore(ctx, user.ID) }},
+		{name: "restore live", run: func() error { return repo.Restore(ctx, user.ID) }, wantErr: database.ErrNotFound},
+	})
+}
+
+// testListing pages through every listing, in every order. Names share
+// their case so that the order does not depend on the collation.
+func testListing(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	var categoryNames []string
+	var categoryID string
+	for _, name := range []string{"Go", "Assembly", "Rust", "Basic"} {
+		category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		categoryNames, categoryID = append(categoryNames, name), category.ID
+		time.Sleep(time.Millisecond) // distinct creation times
+	}
+	var courseNames []string
+	for _, name := range []string{"Basics", "Advanced", "Concurrency", "Generics", "Benchmarks"} {
+		if _, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: name, CategoryID: categoryID}); err != nil {
+			t.Fatal(err)
+		}
+		courseNames = append(courseNames, name)
+		time.Sleep(time.Millisecond)
+	}
+	for _, name := range []string{"Ann", "Bob", "Cid"} {
+		if _, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: name, Email: name + "@example.com", Password: "x"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	courseList := func(spec query.CourseSpec) ([]string, error) {
+		var names []string
+		for {
+			page, err := dbi.CourseRepository.List(ctx, spec)
+			if err != nil {
+				return nil, err
+			}
+			if len(page.Courses) > spec.Page.Size() {
+				t.Errorf("page of %d courses, limit %d", len(page.Courses), spec.Page.Size())
+			}
+			for _, c := range page.Courses {
+				names = append(names, c.Name)
+			}
+			if page.NextCursor == "" {
+				return names, nil
+			}
+			spec.Page.Cursor = page.NextCursor
+		}
+	}
+	categoryList := func(spec query.CategorySpec) ([]string, error) {
+		var names []string
+		for {
+			page, err := dbi.CategoryRepository.List(ctx, spec)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range page.Categories {
+				names = append(names, c.Name)
+			}
+			if page.NextCursor == "" {
+				return names, nil
+			}
+			spec.Page.Cursor = page.NextCursor
+		}
+	}
+	byName := query.Sort{Field: query.SortByName}
+	byCreated := query.Sort{Field: query.SortByCreated}
+	tests := []struct {
+		name string
+		list func() ([]string, error)
+		want []string
+	}{
+		{name: "courses by name", list: func() ([]string, error) {
+			return courseList(query.CourseSpec{Sort: byName, Page: query.Page{Limit: 2}})
+		}, want: []string{"Advanced", "Basics", "Benchmarks", "Concurrency", "Generics"}},
+		{name: "courses by name descending", list: func() ([]string, error) {
+			return courseList(query.CourseSpec{Sort: query.Sort{Field: query.SortByName, Desc: true}, Page: query.Page{Limit: 3}})
+		}, want: []string{"Generics", "Concurrency", "Benchmarks", "Basics", "Advanced"}},
+		{name: "courses by creation", list: func() ([]string, error) {
+			return courseList(query.CourseSpec{Sort: byCreated, Page: query.Page{Limit: 2}})
+		}, want: courseNames},
+		{name: "courses by prefix", list: func() ([]string, error) {
+			return courseList(query.CourseSpec{NamePrefix: "b", Sort: byName, Page: query.Page{Limit: 1}})
+		}, want: []string{"Basics", "Benchmarks"}},
+		{name: "courses of category", list: func() ([]string, error) {
+			return courseList(query.CourseSpec{CategoryID: categoryID, Sort: byName})
+		}, want: []string{"Advanced", "Basics", "Benchmarks", "Concurrency", "Generics"}},
+		{name: "courses of unknown category", list: func() ([]string, error) {
+			return courseList(query.CourseSpec{CategoryID: uuid.NewString()})
+		}},
+		{name: "categories by name", list: func() ([]string, error) {
+			return categoryList(query.CategorySpec{Sort: byName, Page: query.Page{Limit: 3}})
+		}, want: []string{"Assembly", "Basic", "Go", "Rust"}},
+		{name: "categories by creation descending", list: func() ([]string, error) {
+			return categoryList(query.CategorySpec{Sort: query.Sort{Field: query.SortByCreated, Desc: true}, Page: query.Page{Limit: 1}})
+		}, want: []string{"Basic", "Rust", "Assembly", "Go"}},
+		{name: "categories by prefix", list: func() ([]string, error) {
+			return categoryList(query.CategorySpec{NamePrefix: "a", Sort: byName})
+		}, want: []string{"Assembly"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.list()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("names = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("by id", func(t *testing.T) {
+		courses, err := dbi.CourseRepository.FindAll(ctx, query.Page{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		categories, err := dbi.CategoryRepository.FindAll(ctx, query.Page{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var users []dto.UserOutputDto
+		spec := query.UserSpec{Page: query.Page{Limit: 2}}
+		for {
+			page, err := dbi.UserRepository.List(ctx, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			users = append(users, page.Users...)
+			if page.NextCursor == "" {
+				break
+			}
+			spec.Page.Cursor = page.NextCursor
+		}
+		if len(courses.Courses) != len(courseNames) || len(categories.Categories) != len(categoryNames) || len(users) != 3 {
+			t.Fatalf("got %d courses, %d categories, %d users", len(courses.Courses), len(categories.Categories), len(users))
+		}
+		sorted := func(ids []string) bool { return slices.IsSorted(ids) }
+		var courseIDs, categoryIDs, userIDs []string
+		for _, c := range courses.Courses {
+			courseIDs = append(courseIDs, c.ID)
+		}
+		for _, c := range categories.Categories {
+			categoryIDs = append(categoryIDs, c.ID)
+		}
+		for _, u := range users {
+			userIDs = append(userIDs, u.ID)
+		}
+		if !sorted(courseIDs) || !sorted(categoryIDs) || !sorted(userIDs) {
+			t.Errorf("listings not ordered by id: %v, %v, %v", courseIDs, categoryIDs, userIDs)
+		}
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		users, err := dbi.UserRepository.FindAll(ctx, query.Page{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dbi.UserRepository.Delete(ctx, users.Users[0].ID); err != nil {
+			t.Fatal(err)
+		}
+		live, err := dbi.UserRepository.List(ctx, query.UserSpec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		all, err := dbi.UserRepository.List(ctx, query.UserSpec{IncludeDeleted: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(live.Users) != 2 || len(all.Users) != 3 {
+			t.Errorf("got %d live and %d users in all, want 2 and 3", len(live.Users), len(all.Users))
+		}
+	})
+
+	t.Run("invalid cursors", func(t *testing.T) {
+		nameCursor, err := dbi.CourseRepository.List(ctx, query.CourseSpec{Sort: byName, Page: query.Page{Limit: 1}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		calls := map[string]func() error{
+			"courses": func() error {
+				_, err := dbi.CourseRepository.FindAll(ctx, query.Page{Cursor: "junk"})
+				return err
+			},
+			"categories": func() error {
+				_, err := dbi.CategoryRepository.FindAll(ctx, query.Page{Cursor: "junk"})
+				return err
+			},
+			"users": func() error {
+				_, err := dbi.UserRepository.FindAll(ctx, query.Page{Cursor: "junk"})
+				return err
+			},
+			"cursor of another order": func() error {
+				_, err := dbi.CourseRepository.List(ctx, query.CourseSpec{Sort: byCreated, Page: query.Page{Cursor: nameCursor.NextCursor}})
+				return err
+			},
+		}
+		for name, call := range calls {
+			if err := call(); !errors.Is(err, query.ErrInvalidCursor) {
+				t.Errorf("%s: error = %v, want %v", name, err, query.ErrInvalidCursor)
+			}
+		}
+	})
+}
+
+func testCascade(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	var category dto.CategoryOutputDto
+	var course *dto.CourseOutputDto
+	runSteps(t, []step{
+		{name: "create", run: func() (err error) {
+			if category, err = dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"}); err != nil {
+				return err
+			}
+			course, err = dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+			return err
+		}},
+		{name: "delete category with courses", run: func() error { return dbi.CategoryRepository.Delete(ctx, category.ID) }},
+		{name: "course went with it", run: func() error {
+			_, err := dbi.CourseRepository.Find(ctx, course.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "restore category", run: func() error { return dbi.CategoryRepository.Restore(ctx, category.ID) }},
+		{name: "course stays deleted", run: func() error {
+			_, err := dbi.CourseRepository.Find(ctx, course.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "restore course", run: func() error { return dbi.CourseRepository.Restore(ctx, course.ID) }},
+	})
+}
+
+func testHistory(t *testing.T, dbi *database.DBImplementation) {
+	ann := audit.WithActor(context.Background(), "ann@example.com")
+	category, err := dbi.CategoryRepository.Create(ann, dto.CategoryInputDto{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := dbi.CourseRepository.Create(ann, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.CourseRepository.Update(ann, dto.CourseInputDto{ID: course.ID, Name: "advanced", CategoryID: category.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.CourseRepository.Delete(ann, course.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.CourseRepository.Restore(ann, course.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var actions []string
+	spec := query.HistorySpec{EntityType: audit.Course, EntityID: course.ID, Page: query.Page{Limit: 3}}
+	for {
+		page, err := dbi.HistoryRepository.List(ann, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range page.Entries {
+			if entry.ChangedBy != "ann@example.com" || entry.EntityID != course.ID || entry.After == nil {
+				t.Errorf("entry = %+v", entry)
+			}
+			actions = append(actions, entry.Action)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		spec.Page.Cursor = page.NextCursor
+	}
+	if want := []string{audit.Create, audit.Update, audit.Delete, audit.Restore}; !slices.Equal(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+	none, err := dbi.HistoryRepository.List(ann, query.HistorySpec{EntityType: audit.Category, EntityID: uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none.Entries) != 0 {
+		t.Errorf("history of unknown category = %+v", none.Entries)
+	}
+	if _, err := dbi.HistoryRepository.List(ann, query.HistorySpec{EntityType: audit.Course, EntityID: course.ID, Page: query.Page{Cursor: "junk"}}); !errors.Is(err, query.ErrInvalidCursor) {
+		t.Errorf("List() with junk cursor error = %v, want %v", err, query.ErrInvalidCursor)
+	}
+}
+
+func testSearch(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Golang", Description: "the Go language"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "Go basics", Description: "Go types and syntax", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "Go web", Description: "servers", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.CourseRepository.Delete(ctx, deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		spec    query.SearchSpec
+		want    []string
+		wantErr error
+	}{
+		{name: "best match first", spec: query.SearchSpec{Query: "go"}, want: []string{course.ID, category.ID}},
+		{name: "every word", spec: query.SearchSpec{Query: "go syntax"}, want: []string{course.ID}},
+		{name: "categories only", spec: query.SearchSpec{Query: "go", EntityType: "category"}, want: []string{category.ID}},
+		{name: "deleted left out", spec: query.SearchSpec{Query: "servers"}},
+		{name: "no match", spec: query.SearchSpec{Query: "pasta"}},
+		{name: "no words", spec: query.SearchSpec{Query: "*"}, wantErr: query.ErrInvalidSearch},
+		{name: "junk cursor", spec: query.SearchSpec{Query: "go", Page: query.Page{Cursor: "junk"}}, wantErr: query.ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dbi.SearchRepository.Search(ctx, tt.spec)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Search() error = %v, want %v", err, tt.wantErr)
+			}
+			var ids []string
+			for _, result := range got.Results {
+				ids = append(ids, result.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("Search() = %+v, want ids %v", got.Results, tt.want)
+			}
+		})
+	}
+
+	first, err := dbi.SearchRepository.Search(ctx, query.SearchSpec{Query: "go", Page: query.Page{Limit: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, err := dbi.SearchRepository.Search(ctx, query.SearchSpec{Query: "go", Page: query.Page{Limit: 1, Cursor: first.NextCursor}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Results) != 1 || len(rest.Results) != 1 || rest.Results[0].ID != category.ID || rest.NextCursor != "" {
+		t.Errorf("pages = %+v, %+v", first, rest)
+	}
+}
+
+func testTransactions(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+	var kept, dropped string
+	err := database.InTx(ctx, dbi, func(tx *database.Tx) error {
+		category, err := tx.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "kept"})
+		kept = category.ID
+		return err
+	})
+	if errors.Is(err, database.ErrTransactionsUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = database.InTx(ctx, dbi, func(tx *database.Tx) error {
+		category, err := tx.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "dropped"})
+		if err != nil {
+			return err
+		}
+		dropped = category.ID
+		if _, err := tx.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: dropped}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("InTx() error = %v, want %v", err, errAbort)
+	}
+	if _, err := dbi.CategoryRepository.Find(ctx, kept); err != nil {
+		t.Errorf("committed category: %v", err)
+	}
+	if _, err := dbi.CategoryRepository.Find(ctx, dropped); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("rolled back category error = %v, want %v", err, database.ErrNotFound)
+	}
+	courses, err := dbi.CourseRepository.FindAll(ctx, query.Page{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses.Courses) != 0 {
+		t.Errorf("courses after rollback = %+v", courses.Courses)
+	}
+}
+
+// batchErrors returns the error of each item of a batch.
+func batchErrors(results []dto.BatchItemOutputDto) []error {
+	errs := make([]error, len(results))
+	for i, result := range results {
+		if result.Index != i {
+			return []error{fmt.Errorf("result %d has index %d", i, result.Index)}
+		}
+		errs[i] = result.Err
+	}
+	return errs
+}
+
+func checkBatch(t *testing.T, name string, results []dto.BatchItemOutputDto, err error, want ...error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: error = %v", name, err)
+	}
+	got := batchErrors(results)
+	if len(got) != len(want) {
+		t.Fatalf("%s: results = %+v, want %d", name, results, len(want))
+	}
+	for i := range want {
+		if want[i] == nil && got[i] != nil || want[i] != nil && !errors.Is(got[i], want[i]) {
+			t.Errorf("%s: item %d error = %v, want %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func testBatch(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	results, err := dbi.CategoryRepository.CreateBatch(ctx, []dto.CategoryInputDto{{Name: "Go"}, {Description: "no name"}, {Name: "Empty"}})
+	checkBatch(t, "create categories", results, err, nil, database.ErrValidation, nil)
+	golang, empty := results[0].ID, results[2].ID
+
+	// more than one multi-row insert, rejected items in between
+	inputs := []dto.CourseInputDto{{Name: "no category", CategoryID: uuid.New().String()}, {CategoryID: golang}}
+	want := []error{database.ErrValidation, database.ErrValidation}
+	for i := range query.BatchSize + 3 {
+		inputs = append(inputs, dto.CourseInputDto{Name: fmt.Sprintf("course %02d", i), CategoryID: golang})
+		want = append(want, nil)
+	}
+	results, err = dbi.CourseRepository.CreateBatch(ctx, inputs)
+	checkBatch(t, "create courses", results, err, want...)
+	courses, err := dbi.CourseRepository.FindByCategoryID(ctx, golang, query.Page{Limit: query.MaxLimit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses.Courses) != query.BatchSize+3 {
+		t.Fatalf("courses created = %d, want %d", len(courses.Courses), query.BatchSize+3)
+	}
+	first, second := results[2].ID, results[3].ID
+	created, err := dbi.CourseRepository.Find(ctx, first)
+	if err != nil || created.Name != "course 00" || created.Version != 1 {
+		t.Errorf("Find() = %+v, %v", created, err)
+	}
+	history, err := dbi.HistoryRepository.List(ctx, query.HistorySpec{EntityType: audit.Course, EntityID: first})
+	if err != nil || len(history.Entries) != 1 || history.Entries[0].Action != audit.Create {
+		t.Errorf("history of a course created in a batch = %+v, %v", history.Entries, err)
+	}
+
+	results, err = dbi.CourseRepository.UpdateBatch(ctx, []dto.CourseInputDto{
+		{ID: first, Name: "renamed", CategoryID: golang, Version: 1},
+		{ID: second, Name: "stale", CategoryID: golang, Version: 5},
+		{ID: uuid.New().String(), Name: "missing", CategoryID: golang},
+	})
+	checkBatch(t, "update courses", results, err, nil, database.ErrConflict, database.ErrNotFound)
+	if updated, err := dbi.CourseRepository.Find(ctx, first); err != nil || updated.Name != "renamed" || updated.Version != 2 {
+		t.Errorf("updated course = %+v, %v", updated, err)
+	}
+	if kept, err := dbi.CourseRepository.Find(ctx, second); err != nil || kept.Name != "course 01" {
+		t.Errorf("rejected course = %+v, %v", kept, err)
+	}
+
+	results, err = dbi.CategoryRepository.UpdateBatch(ctx, []dto.CategoryInputDto{{ID: empty, Name: "Still empty"}, {ID: golang}})
+	checkBatch(t, "update categories", results, err, nil, database.ErrValidation)
+
+	results, err = dbi.CourseRepository.DeleteBatch(ctx, []string{first, first})
+	checkBatch(t, "delete courses", results, err, nil, database.ErrNotFound)
+	results, err = dbi.CategoryRepository.DeleteBatch(ctx, []string{golang, empty})
+	checkBatch(t, "delete categories", results, err, database.ErrHasDependents, nil)
+	if _, err := dbi.CategoryRepository.Find(ctx, empty); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("deleted category error = %v, want %v", err, database.ErrNotFound)
+	}
+
+	errAbort := errors.New("abort")
+	err = database.InTx(ctx, dbi, func(tx *database.Tx) error {
+		results, err := tx.CategoryRepository.CreateBatch(ctx, []dto.CategoryInputDto{{Name: "dropped"}})
+		if err != nil {
+			return err
+		}
+		empty = results[0].ID
+		return errAbort
+	})
+	if errors.Is(err, database.ErrTransactionsUnsupported) {
+		return
+	}
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("InTx() error = %v, want %v", err, errAbort)
+	}
+	if _, err := dbi.CategoryRepository.Find(ctx, empty); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("batch rolled back with its transaction: error = %v, want %v", err, database.ErrNotFound)
+	}
+}
+
+func testPurge(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "kept", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.CourseRepository.Delete(ctx, course.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.UserRepository.Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := dbi.Purge(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (dto.PurgeOutputDto{}) {
+		t.Errorf("Purge() of old deletions = %+v, want none", got)
+	}
+	got, err = dbi.Purge(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (dto.PurgeOutputDto{Courses: 1, Users: 1}); got != want {
+		t.Errorf("Purge() = %+v, want %+v", got, want)
+	}
+	if err := dbi.CourseRepository.Restore(ctx, course.ID); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("Restore() of purged course error = %v, want %v", err, database.ErrNotFound)
+	}
+	if _, err := dbi.CourseRepository.Find(ctx, kept.ID); err != nil {
+		t.Errorf("live course: %v", err)
+	}
+	// a new user may take the purged user's email
+	if _, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "x"}); err != nil {
+		t.Errorf("Create() after purge: %v", err)
+	}
+}
+
+// order returns the ids of modules or lessons, failing the check unless
+// their positions run from 1 in list order.
+func order[T any](t *testing.T, items []T, id func(T) string, position func(T) int) []string {
+	t.Helper()
+	ids := make([]string, len(items))
+	for i, item := range items {
+		if position(item) != i+1 {
+			t.Errorf("position of %s = %d, want %d", id(item), position(item), i+1)
+		}
+		ids[i] = id(item)
+	}
+	return ids
+}
+
+func testModules(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.ModuleRepository
+	moduleID := func(m dto.ModuleOutputDto) string { return m.ID }
+	modulePosition := func(m dto.ModuleOutputDto) int { return m.Position }
+	checkOrder := func(courseID string, want ...string) error {
+		got, err := repo.FindByCourseID(ctx, courseID)
+		if err == nil && !slices.Equal(order(t, got.Modules, moduleID, modulePosition), want) {
+			t.Errorf("FindByCourseID() = %+v, want %v", got.Modules, want)
+		}
+		return err
+	}
+	var course dto.CourseOutputDto
+	var first, second, third dto.ModuleOutputDto
+	runSteps(t, []step{
+		{name: "create course", run: func() error {
+			category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				return err
+			}
+			created, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+			if err == nil {
+				course = *created
+			}
+			return err
+		}},
+		{name: "create", run: func() (err error) {
+			if first, err = repo.Create(ctx, dto.ModuleInputDto{CourseID: course.ID, Title: "setup"}); err != nil {
+				return err
+			}
+			if second, err = repo.Create(ctx, dto.ModuleInputDto{CourseID: course.ID, Title: "syntax", Description: "the language"}); err != nil {
+				return err
+			}
+			third, err = repo.Create(ctx, dto.ModuleInputDto{CourseID: course.ID, Title: "tooling"})
+			if err == nil && (second.Position != 2 || second.Description != "the language" || second.Version != 1) {
+				t.Errorf("Create() = %+v", second)
+			}
+			return err
+		}},
+		{name: "created at the end", run: func() error { return checkOrder(course.ID, first.ID, second.ID, third.ID) }},
+		{name: "create without title", run: func() error {
+			_, err := repo.Create(ctx, dto.ModuleInputDto{CourseID: course.ID})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "create in unknown course", run: func() error {
+			_, err := repo.Create(ctx, dto.ModuleInputDto{CourseID: uuid.NewString(), Title: "x"})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "find", run: func() error {
+			got, err := repo.Find(ctx, second.ID)
+			if err == nil && (got.Title != "syntax" || got.CourseID != course.ID || got.Position != 2) {
+				t.Errorf("Find() = %+v", got)
+			}
+			return err
+		}},
+		{name: "find unknown", run: func() error {
+			_, err := repo.Find(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "update", run: func() error {
+			return repo.Update(ctx, dto.ModuleInputDto{ID: second.ID, Title: "types", Version: 1})
+		}},
+		{name: "update at stale version", run: func() error {
+			return repo.Update(ctx, dto.ModuleInputDto{ID: second.ID, Title: "x", Version: 1})
+		}, wantErr: database.ErrStaleVersion},
+		{name: "update unknown", run: func() error {
+			return repo.Update(ctx, dto.ModuleInputDto{ID: uuid.NewString(), Title: "x"})
+		}, wantErr: database.ErrNotFound},
+		{name: "reorder", run: func() error { return repo.Reorder(ctx, course.ID, []string{third.ID, first.ID, second.ID}) }},
+		{name: "reordered", run: func() error { return checkOrder(course.ID, third.ID, first.ID, second.ID) }},
+		{name: "reorder missing a module", run: func() error {
+			return repo.Reorder(ctx, course.ID, []string{third.ID, first.ID})
+		}, wantErr: database.ErrValidation},
+		{name: "reorder with a repeat", run: func() error {
+			return repo.Reorder(ctx, course.ID, []string{third.ID, first.ID, first.ID})
+		}, wantErr: database.ErrValidation},
+		{name: "reorder with a stranger", run: func() error {
+			return repo.Reorder(ctx, course.ID, []string{third.ID, first.ID, uuid.NewString()})
+		}, wantErr: database.ErrValidation},
+		{name: "reorder unknown course", run: func() error {
+			return repo.Reorder(ctx, uuid.NewString(), nil)
+		}, wantErr: database.ErrNotFound},
+		{name: "delete", run: func() error { return repo.Delete(ctx, third.ID) }},
+		{name: "later modules move up", run: func() error { return checkOrder(course.ID, first.ID, second.ID) }},
+		{name: "delete unknown", run: func() error { return repo.Delete(ctx, third.ID) }, wantErr: database.ErrNotFound},
+		{name: "created after a delete", run: func() (err error) {
+			third, err = repo.Create(ctx, dto.ModuleInputDto{CourseID: course.ID, Title: "tooling"})
+			if err == nil && third.Position != 3 {
+				t.Errorf("Create() position = %d, want 3", third.Position)
+			}
+			return err
+		}},
+		{name: "delete course", run: func() error { return dbi.CourseRepository.Delete(ctx, course.ID) }},
+		{name: "modules of deleted course", run: func() error {
+			_, err := repo.FindByCourseID(ctx, course.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "create in deleted course", run: func() error {
+			_, err := repo.Create(ctx, dto.ModuleInputDto{CourseID: course.ID, Title: "x"})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "restored course keeps its modules", run: func() error {
+			if err := dbi.CourseRepository.Restore(ctx, course.ID); err != nil {
+				return err
+			}
+			return checkOrder(course.ID, first.ID, second.ID, third.ID)
+		}},
+	})
+}
+
+func testLessons(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.LessonRepository
+	lessonID := func(l dto.LessonOutputDto) string { return l.ID }
+	lessonPosition := func(l dto.LessonOutputDto) int { return l.Position }
+	checkOrder := func(moduleID string, want ...string) error {
+		got, err := repo.FindByModuleID(ctx, moduleID)
+		if err == nil && !slices.Equal(order(t, got.Lessons, lessonID, lessonPosition), want) {
+			t.Errorf("FindByModuleID() = %+v, want %v", got.Lessons, want)
+		}
+		return err
+	}
+	var course *dto.CourseOutputDto
+	var module, other dto.ModuleOutputDto
+	var first, second, third dto.LessonOutputDto
+	runSteps(t, []step{
+		{name: "create modules", run: func() (err error) {
+			category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				return err
+			}
+			if course, err = dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID}); err != nil {
+				return err
+			}
+			if module, err = dbi.ModuleRepository.Create(ctx, dto.ModuleInputDto{CourseID: course.ID, Title: "setup"}); err != nil {
+				return err
+			}
+			other, err = dbi.ModuleRepository.Create(ctx, dto.ModuleInputDto{CourseID: course.ID, Title: "syntax"})
+			return err
+		}},
+		{name: "create", run: func() (err error) {
+			if first, err = repo.Create(ctx, dto.LessonInputDto{ModuleID: module.ID, Title: "install", ContentType: "video", DurationSeconds: 300}); err != nil {
+				return err
+			}
+			if second, err = repo.Create(ctx, dto.LessonInputDto{ModuleID: module.ID, Title: "hello", ContentType: "text", Body: "package main"}); err != nil {
+				return err
+			}
+			third, err = repo.Create(ctx, dto.LessonInputDto{ModuleID: module.ID, Title: "check", ContentType: "quiz"})
+			if err == nil && (first.Position != 1 || first.DurationSeconds != 300 || first.ContentType != "video" || first.Version != 1) {
+				t.Errorf("Create() = %+v", first)
+			}
+			return err
+		}},
+		{name: "created at the end", run: func() error { return checkOrder(module.ID, first.ID, second.ID, third.ID) }},
+		{name: "other module has its own order", run: func() error {
+			lesson, err := repo.Create(ctx, dto.LessonInputDto{ModuleID: other.ID, Title: "types", ContentType: "text"})
+			if err == nil && lesson.Position != 1 {
+				t.Errorf("Create() position = %d, want 1", lesson.Position)
+			}
+			return err
+		}},
+		{name: "create with unknown content type", run: func() error {
+			_, err := repo.Create(ctx, dto.LessonInputDto{ModuleID: module.ID, Title: "x", ContentType: "slides"})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "create with negative duration", run: func() error {
+			_, err := repo.Create(ctx, dto.LessonInputDto{ModuleID: module.ID, Title: "x", ContentType: "text", DurationSeconds: -1})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "create in unknown module", run: func() error {
+			_, err := repo.Create(ctx, dto.LessonInputDto{ModuleID: uuid.NewString(), Title: "x", ContentType: "text"})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "find", run: func() error {
+			got, err := repo.Find(ctx, second.ID)
+			if err == nil && (got.Title != "hello" || got.Body != "package main" || got.ModuleID != module.ID) {
+				t.Errorf("Find() = %+v", got)
+			}
+			return err
+		}},
+		{name: "find unknown", run: func() error {
+			_, err := repo.Find(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "update", run: func() error {
+			return repo.Update(ctx, dto.LessonInputDto{ID: second.ID, Title: "hello, world", ContentType: "audio", DurationSeconds: 60, Version: 1})
+		}},
+		{name: "updated", run: func() error {
+			got, err := repo.Find(ctx, second.ID)
+			if err == nil && (got.Title != "hello, world" || got.ContentType != "audio" || got.DurationSeconds != 60 || got.Body != "" || got.Version != 2) {
+				t.Errorf("Find() = %+v", got)
+			}
+			return err
+		}},
+		{name: "update at stale version", run: func() error {
+			return repo.Update(ctx, dto.LessonInputDto{ID: second.ID, Title: "x", ContentType: "text", Version: 1})
+		}, wantErr: database.ErrStaleVersion},
+		{name: "update unknown", run: func() error {
+			return repo.Update(ctx, dto.LessonInputDto{ID: uuid.NewString(), Title: "x", ContentType: "text"})
+		}, wantErr: database.ErrNotFound},
+		{name: "reorder", run: func() error { return repo.Reorder(ctx, module.ID, []string{second.ID, third.ID, first.ID}) }},
+		{name: "reordered", run: func() error { return checkOrder(module.ID, second.ID, third.ID, first.ID) }},
+		{name: "reorder missing a lesson", run: func() error {
+			return repo.Reorder(ctx, module.ID, []string{second.ID, third.ID})
+		}, wantErr: database.ErrValidation},
+		{name: "reorder unknown module", run: func() error {
+			return repo.Reorder(ctx, uuid.NewString(), nil)
+		}, wantErr: database.ErrNotFound},
+		{name: "delete", run: func() error { return repo.Delete(ctx, second.ID) }},
+		{name: "later lessons move up", run: func() error { return checkOrder(module.ID, third.ID, first.ID) }},
+		{name: "delete unknown", run: func() error { return repo.Delete(ctx, second.ID) }, wantErr: database.ErrNotFound},
+		{name: "delete module", run: func() error { return dbi.ModuleRepository.Delete(ctx, module.ID) }},
+		{name: "lessons go with their module", run: func() error {
+			_, err := repo.Find(ctx, first.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "lessons of deleted module", run: func() error {
+			_, err := repo.FindByModuleID(ctx, module.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "purged course takes its modules", run: func() error {
+			if err := dbi.CourseRepository.Delete(ctx, course.ID); err != nil {
+				return err
+			}
+			if _, err := dbi.Purge(ctx, time.Now().Add(time.Hour)); err != nil {
+				return err
+			}
+			_, err := dbi.ModuleRepository.Find(ctx, other.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+	})
+}
+
+func testEnrollments(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.EnrollmentRepository
+	var ann, bob string
+	var basics, advanced dto.CourseOutputDto
+	checkCourses := func(userID string, want ...string) error {
+		got, err := repo.FindByUserID(ctx, userID, query.Page{})
+		var ids []string
+		for _, e := range got.Enrollments {
+			ids = append(ids, e.CourseID)
+		}
+		if err == nil && !slices.Equal(ids, want) {
+			t.Errorf("FindByUserID() = %+v, want %v", got.Enrollments, want)
+		}
+		return err
+	}
+	checkStudents := func(courseID string, want ...string) error {
+		got, err := repo.FindByCourseID(ctx, courseID, query.Page{})
+		var ids []string
+		for _, e := range got.Enrollments {
+			ids = append(ids, e.UserID)
+		}
+		if err == nil && !slices.Equal(ids, want) {
+			t.Errorf("FindByCourseID() = %+v, want %v", got.Enrollments, want)
+		}
+		return err
+	}
+	runSteps(t, []step{
+		{name: "create users and courses", run: func() error {
+			for _, name := range []string{"ann", "bob"} {
+				if _, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: name, Email: name + "@example.com", Password: "x"}); err != nil {
+					return err
+				}
+			}
+			category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				return err
+			}
+			for _, course := range []*dto.CourseOutputDto{&basics, &advanced} {
+				created, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "course", CategoryID: category.ID})
+				if err != nil {
+					return err
+				}
+				*course = *created
+			}
+			if basics.ID > advanced.ID {
+				basics, advanced = advanced, basics
+			}
+			return nil
+		}},
+		{name: "find user id by email", run: func() (err error) {
+			if ann, err = dbi.UserRepository.FindIDByEmail(ctx, "ann@example.com"); err != nil {
+				return err
+			}
+			bob, err = dbi.UserRepository.FindIDByEmail(ctx, "bob@example.com")
+			return err
+		}},
+		{name: "find id of unknown email", run: func() error {
+			_, err := dbi.UserRepository.FindIDByEmail(ctx, "nobody@example.com")
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "enroll", run: func() error {
+			got, err := repo.Enroll(ctx, dto.EnrollmentInputDto{UserID: ann, CourseID: basics.ID})
+			if err == nil && (got.UserID != ann || got.CourseID != basics.ID || got.Progress != 0 ||
+				got.EnrolledAt.IsZero() || got.LastAccessedAt != nil) {
+				t.Errorf("Enroll() = %+v", got)
+			}
+			if err != nil {
+				return err
+			}
+			if _, err := repo.Enroll(ctx, dto.EnrollmentInputDto{UserID: ann, CourseID: advanced.ID}); err != nil {
+				return err
+			}
+			_, err = repo.Enroll(ctx, dto.EnrollmentInputDto{UserID: bob, CourseID: basics.ID})
+			return err
+		}},
+		{name: "enroll twice", run: func() error {
+			_, err := repo.Enroll(ctx, dto.EnrollmentInputDto{UserID: ann, CourseID: basics.ID})
+			return err
+		}, wantErr: database.ErrConflict},
+		{name: "enroll unknown user", run: func() error {
+			_, err := repo.Enroll(ctx, dto.EnrollmentInputDto{UserID: uuid.NewString(), CourseID: basics.ID})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "enroll in unknown course", run: func() error {
+			_, err := repo.Enroll(ctx, dto.EnrollmentInputDto{UserID: ann, CourseID: uuid.NewString()})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "courses of a user", run: func() error { return checkCourses(ann, basics.ID, advanced.ID) }},
+		{name: "students of a course", run: func() error { return checkStudents(basics.ID, slices.Sorted(slices.Values([]string{ann, bob}))...) }},
+		{name: "pages of courses", run: func() error {
+			first, err := repo.FindByUserID(ctx, ann, query.Page{Limit: 1})
+			if err != nil {
+				return err
+			}
+			second, err := repo.FindByUserID(ctx, ann, query.Page{Limit: 1, Cursor: first.NextCursor})
+			if err == nil && (len(first.Enrollments) != 1 || first.Enrollments[0].CourseID != basics.ID ||
+				len(second.Enrollments) != 1 || second.Enrollments[0].CourseID != advanced.ID || second.NextCursor != "") {
+				t.Errorf("pages = %+v, %+v", first, second)
+			}
+			return err
+		}},
+		{name: "students of unknown course", run: func() error {
+			_, err := repo.FindByCourseID(ctx, uuid.NewString(), query.Page{})
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "update progress", run: func() error {
+			return repo.UpdateProgress(ctx, dto.EnrollmentInputDto{UserID: ann, CourseID: basics.ID, Progress: 40})
+		}},
+		{name: "progress is visible", run: func() error {
+			got, err := repo.Find(ctx, ann, basics.ID)
+			if err == nil && (got.Progress != 40 || got.LastAccessedAt == nil) {
+				t.Errorf("Find() = %+v, want progress 40 with an access time", got)
+			}
+			return err
+		}},
+		{name: "update progress over 100", run: func() error {
+			return repo.UpdateProgress(ctx, dto.EnrollmentInputDto{UserID: ann, CourseID: basics.ID, Progress: 101})
+		}, wantErr: database.ErrValidation},
+		{name: "update progress of unknown enrollment", run: func() error {
+			return repo.UpdateProgress(ctx, dto.EnrollmentInputDto{UserID: bob, CourseID: advanced.ID, Progress: 10})
+		}, wantErr: database.ErrNotFound},
+		{name: "deleted course is hidden", run: func() error {
+			if err := dbi.CourseRepository.Delete(ctx, advanced.ID); err != nil {
+				return err
+			}
+			return checkCourses(ann, basics.ID)
+		}},
+		{name: "enroll in deleted course", run: func() error {
+			_, err := repo.Enroll(ctx, dto.EnrollmentInputDto{UserID: bob, CourseID: advanced.ID})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "deleted user is hidden", run: func() error {
+			if err := dbi.UserRepository.Delete(ctx, bob); err != nil {
+				return err
+			}
+			return checkStudents(basics.ID, ann)
+		}},
+		{name: "unenroll", run: func() error { return repo.Unenroll(ctx, ann, basics.ID) }},
+		{name: "unenrolled", run: func() error {
+			_, err := repo.Find(ctx, ann, basics.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "unenroll twice", run: func() error { return repo.Unenroll(ctx, ann, basics.ID) }, wantErr: database.ErrNotFound},
+		{name: "purge takes the enrollments", run: func() error {
+			if _, err := dbi.Purge(ctx, time.Now().Add(time.Hour)); err != nil {
+				return err
+			}
+			if _, err := repo.Find(ctx, bob, basics.ID); !errors.Is(err, database.ErrNotFound) {
+				t.Errorf("Find() of a purged user = %v, want not found", err)
+			}
+			_, err := repo.Find(ctx, ann, advanced.ID)
+			return err
+		}, wantErr: database.ErrNotFound},
+	})
+}
+
+func testInstructors(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.InstructorRepository
+	var ann, bob, carl string
+	var basics, advanced string
+	checkInstructors := func(courseID string, want ...string) error {
+		got, err := repo.FindByCourseID(ctx, courseID)
+		var ids []string
+		for _, i := range got.Instructors {
+			ids = append(ids, i.UserID)
+		}
+		if err == nil && !slices.Equal(ids, slices.Sorted(slices.Values(want))) {
+			t.Errorf("FindByCourseID() = %+v, want %v", got.Instructors, want)
+		}
+		return err
+	}
+	checkTaught := func(userID string, want ...string) error {
+		got, err := dbi.CourseRepository.FindByInstructorID(ctx, userID, query.Page{})
+		var ids []string
+		for _, c := range got.Courses {
+			ids = append(ids, c.ID)
+		}
+		if err == nil && !slices.Equal(ids, want) {
+			t.Errorf("FindByInstructorID() = %+v, want %v", got.Courses, want)
+		}
+		return err
+	}
+	checkTeaches := func(userID, courseID string, want bool) error {
+		got, err := repo.Teaches(ctx, userID, courseID)
+		if err == nil && got != want {
+			t.Errorf("Teaches() = %v, want %v", got, want)
+		}
+		return err
+	}
+	runSteps(t, []step{
+		{name: "create users and courses", run: func() error {
+			for _, id := range []*string{&ann, &bob, &carl} {
+				created, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "user", Email: uuid.NewString() + "@example.com", Password: "x"})
+				if err != nil {
+					return err
+				}
+				*id = created.ID
+			}
+			annDto, err := dbi.UserRepository.Find(ctx, ann)
+			if err != nil {
+				return err
+			}
+			category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				return err
+			}
+			// basics is created as ann, advanced anonymously
+			created, err := dbi.CourseRepository.Create(audit.WithActor(ctx, annDto.Email), dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+			if err != nil {
+				return err
+			}
+			basics = created.ID
+			if created, err = dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "advanced", CategoryID: category.ID}); err != nil {
+				return err
+			}
+			advanced = created.ID
+			return nil
+		}},
+		{name: "creator teaches", run: func() error {
+			if err := checkTeaches(ann, basics, true); err != nil {
+				return err
+			}
+			return checkInstructors(advanced)
+		}},
+		{name: "add", run: func() error {
+			got, err := repo.Add(ctx, dto.InstructorInputDto{CourseID: basics, UserID: bob})
+			if err == nil && (got.CourseID != basics || got.UserID != bob || got.Name != "user" || got.Email == "" || got.AddedAt.IsZero()) {
+				t.Errorf("Add() = %+v", got)
+			}
+			return err
+		}},
+		{name: "add twice", run: func() error {
+			_, err := repo.Add(ctx, dto.InstructorInputDto{CourseID: basics, UserID: bob})
+			return err
+		}, wantErr: database.ErrConflict},
+		{name: "add unknown user", run: func() error {
+			_, err := repo.Add(ctx, dto.InstructorInputDto{CourseID: basics, UserID: uuid.NewString()})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "add to unknown course", run: func() error {
+			_, err := repo.Add(ctx, dto.InstructorInputDto{CourseID: uuid.NewString(), UserID: bob})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "instructors of a course", run: func() error { return checkInstructors(basics, ann, bob) }},
+		{name: "instructors of unknown course", run: func() error {
+			_, err := repo.FindByCourseID(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "courses taught", run: func() error {
+			if err := checkTaught(bob, basics); err != nil {
+				return err
+			}
+			return checkTaught(carl)
+		}},
+		{name: "pages of courses taught", run: func() error {
+			if _, err := repo.Add(ctx, dto.InstructorInputDto{CourseID: advanced, UserID: ann}); err != nil {
+				return err
+			}
+			want := slices.Sorted(slices.Values([]string{basics, advanced}))
+			first, err := dbi.CourseRepository.FindByInstructorID(ctx, ann, query.Page{Limit: 1})
+			if err != nil {
+				return err
+			}
+			second, err := dbi.CourseRepository.FindByInstructorID(ctx, ann, query.Page{Limit: 1, Cursor: first.NextCursor})
+			if err == nil && (len(first.Courses) != 1 || first.Courses[0].ID != want[0] ||
+				len(second.Courses) != 1 || second.Courses[0].ID != want[1] || second.NextCursor != "") {
+				t.Errorf("pages = %+v, %+v", first, second)
+			}
+			return err
+		}},
+		{name: "remove", run: func() error { return repo.Remove(ctx, basics, bob) }},
+		{name: "removed", run: func() error { return checkTeaches(bob, basics, false) }},
+		{name: "remove twice", run: func() error { return repo.Remove(ctx, basics, bob) }, wantErr: database.ErrNotFound},
+		{name: "remove the last instructor", run: func() error { return repo.Remove(ctx, basics, ann) }, wantErr: database.ErrConflict},
+		{name: "last instructor is kept", run: func() error { return checkInstructors(basics, ann) }},
+		{name: "deleted course is not listed", run: func() error {
+			if err := dbi.CourseRepository.Delete(ctx, advanced); err != nil {
+				return err
+			}
+			if err := checkTaught(ann, basics); err != nil {
+				return err
+			}
+			return checkTeaches(ann, advanced, true)
+		}},
+		{name: "deleted user is hidden", run: func() error {
+			if _, err := repo.Add(ctx, dto.InstructorInputDto{CourseID: basics, UserID: carl}); err != nil {
+				return err
+			}
+			if err := dbi.UserRepository.Delete(ctx, carl); err != nil {
+				return err
+			}
+			return checkInstructors(basics, ann)
+		}},
+		{name: "purge takes the instructors", run: func() error {
+			if _, err := dbi.Purge(ctx, time.Now().Add(time.Hour)); err != nil {
+				return err
+			}
+			if err := checkTeaches(carl, basics, false); err != nil {
+				return err
+			}
+			return checkTeaches(ann, advanced, false)
+		}},
+	})
+}
+
+func testCategoryTree(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.CategoryRepository
+	var programming, golang, concurrency, web string
+	var course string
+	ids := func(categories []dto.CategoryOutputDto) []string {
+		ids := make([]string, len(categories))
+		for i, c := range categories {
+			ids[i] = c.ID
+		}
+		return ids
+	}
+	move := func(id, parentID string) error {
+		return repo.Update(ctx, dto.CategoryInputDto{ID: id, Name: "moved", ParentID: parentID})
+	}
+	runSteps(t, []step{
+		{name: "create", run: func() error {
+			for _, c := range []struct {
+				id     *string
+				parent *string
+			}{{&programming, new(string)}, {&golang, &programming}, {&concurrency, &golang}, {&web, &programming}} {
+				created, err := repo.Create(ctx, dto.CategoryInputDto{Name: "category", ParentID: *c.parent})
+				if err != nil {
+					return err
+				}
+				if created.ParentID != *c.parent {
+					t.Errorf("Create() parent = %q, want %q", created.ParentID, *c.parent)
+				}
+				*c.id = created.ID
+			}
+			created, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "channels", CategoryID: concurrency})
+			course = created.ID
+			return err
+		}},
+		{name: "create under unknown parent", run: func() error {
+			_, err := repo.Create(ctx, dto.CategoryInputDto{Name: "orphan", ParentID: uuid.NewString()})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "children", run: func() error {
+			got, err := repo.FindChildren(ctx, programming, query.Page{})
+			if want := slices.Sorted(slices.Values([]string{golang, web})); err == nil && !slices.Equal(ids(got.Categories), want) {
+				t.Errorf("FindChildren() = %v, want %v", ids(got.Categories), want)
+			}
+			return err
+		}},
+		{name: "ancestors", run: func() error {
+			got, err := repo.FindAncestors(ctx, concurrency)
+			if want := []string{programming, golang}; err == nil && !slices.Equal(ids(got.Categories), want) {
+				t.Errorf("FindAncestors() = %v, want %v", ids(got.Categories), want)
+			}
+			return err
+		}},
+		{name: "ancestors of a top-level category", run: func() error {
+			got, err := repo.FindAncestors(ctx, programming)
+			if err == nil && len(got.Categories) != 0 {
+				t.Errorf("FindAncestors() = %v, want none", ids(got.Categories))
+			}
+			return err
+		}},
+		{name: "ancestors of unknown category", run: func() error {
+			_, err := repo.FindAncestors(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "subtree", run: func() error {
+			got, err := repo.FindSubtree(ctx, programming)
+			want := append([]string{programming}, slices.Sorted(slices.Values([]string{golang, web}))...)
+			if want = append(want, concurrency); err == nil && !slices.Equal(ids(got.Categories), want) {
+				t.Errorf("FindSubtree() = %v, want %v", ids(got.Categories), want)
+			}
+			return err
+		}},
+		{name: "courses in the tree", run: func() error {
+			for _, id := range []string{programming, golang, concurrency} {
+				got, err := dbi.CourseRepository.FindByCategoryTree(ctx, id, query.Page{})
+				if err != nil {
+					return err
+				}
+				if len(got.Courses) != 1 || got.Courses[0].ID != course {
+					t.Errorf("FindByCategoryTree(%s) = %+v, want the course", id, got.Courses)
+				}
+			}
+			got, err := dbi.CourseRepository.FindByCategoryTree(ctx, web, query.Page{})
+			if err == nil && len(got.Courses) != 0 {
+				t.Errorf("FindByCategoryTree() of a sibling = %+v, want none", got.Courses)
+			}
+			return err
+		}},
+		{name: "move under itself", run: func() error { return move(golang, golang) }, wantErr: database.ErrValidation},
+		{name: "move under a subcategory", run: func() error { return move(programming, concurrency) }, wantErr: database.ErrValidation},
+		{name: "move", run: func() error {
+			if err := move(concurrency, web); err != nil {
+				return err
+			}
+			got, err := repo.FindAncestors(ctx, concurrency)
+			if want := []string{programming, web}; err == nil && !slices.Equal(ids(got.Categories), want) {
+				t.Errorf("FindAncestors() after move = %v, want %v", ids(got.Categories), want)
+			}
+			return err
+		}},
+		{name: "delete with subcategories", run: func() error { return repo.Delete(ctx, web) }, wantErr: database.ErrHasDependents},
+		{name: "restore under a deleted parent", run: func() error {
+			generics, err := repo.Create(ctx, dto.CategoryInputDto{Name: "generics", ParentID: golang})
+			if err != nil {
+				return err
+			}
+			if err := repo.Delete(ctx, generics.ID); err != nil {
+				return err
+			}
+			if err := repo.Delete(ctx, golang); err != nil {
+				return err
+			}
+			if err := move(web, golang); !errors.Is(err, database.ErrValidation) {
+				t.Errorf("move under a deleted category error = %v, want %v", err, database.ErrValidation)
+			}
+			return repo.Restore(ctx, generics.ID)
+		}, wantErr: database.ErrValidation},
+		{name: "purge takes deleted subtrees", run: func() error {
+			if _, err := dbi.Purge(ctx, time.Now().Add(time.Hour)); err != nil {
+				return err
+			}
+			if err := repo.Restore(ctx, golang); !errors.Is(err, database.ErrNotFound) {
+				t.Errorf("Restore() of purged category error = %v, want %v", err, database.ErrNotFound)
+			}
+			got, err := repo.FindSubtree(ctx, programming)
+			if want := []string{programming, web, concurrency}; err == nil && !slices.Equal(ids(got.Categories), want) {
+				t.Errorf("FindSubtree() after purge = %v, want %v", ids(got.Categories), want)
+			}
+			return err
+		}},
+	})
+}
+
+func testTags(t *testing.T, dbi *database.DBImplementation) {
+	ctx := context.Background()
+	repo := dbi.TagRepository
+	var basics, advanced, web string
+	var beginner, golang, cloud dto.TagOutputDto
+	checkNames := func(name string, got dto.TagListOutputDto, err error, want ...string) error {
+		var names []string
+		for _, tag := range got.Tags {
+			names = append(names, tag.Name)
+		}
+		if err == nil && !slices.Equal(names, want) {
+			t.Errorf("%s = %+v, want %v", name, got.Tags, want)
+		}
+		return err
+	}
+	checkTagged := func(spec query.CourseSpec, want ...string) error {
+		got, err := dbi.CourseRepository.List(ctx, spec)
+		var ids []string
+		for _, c := range got.Courses {
+			ids = append(ids, c.ID)
+		}
+		if err == nil && !slices.Equal(ids, slices.Sorted(slices.Values(want))) {
+			t.Errorf("List(%v, all %v) = %+v, want %v", spec.Tags, spec.AllTags, got.Courses, want)
+		}
+		return err
+	}
+	checkCount := func(id string, want int64) error {
+		got, err := repo.Find(ctx, id)
+		if err == nil && got.Courses != want {
+			t.Errorf("Find() courses = %d, want %d", got.Courses, want)
+		}
+		return err
+	}
+	unknown := uuid.NewString()
+	// tag takes the course by reference, as the ids are set by the first step
+	tag := func(courseID *string, name string, into *dto.TagOutputDto) func() error {
+		return func() error {
+			got, err := repo.Tag(ctx, dto.CourseTagInputDto{CourseID: *courseID, Name: name})
+			if err == nil && into != nil {
+				*into = got
+			}
+			return err
+		}
+	}
+	runSteps(t, []step{
+		{name: "create courses", run: func() error {
+			category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				return err
+			}
+			for _, c := range []struct {
+				id   *string
+				name string
+			}{{&basics, "basics"}, {&advanced, "advanced"}, {&web, "web"}} {
+				created, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: c.name, CategoryID: category.ID})
+				if err != nil {
+					return err
+				}
+				*c.id = created.ID
+			}
+			return nil
+		}},
+		{name: "create", run: func() error {
+			got, err := repo.Create(ctx, dto.TagInputDto{Name: "  Cloud   Native "})
+			if err == nil && (got.ID == "" || got.Name != "cloud native" || got.Courses != 0 || got.CreatedAt.IsZero()) {
+				t.Errorf("Create() = %+v", got)
+			}
+			cloud = got
+			return err
+		}},
+		{name: "create a taken name", run: func() error {
+			_, err := repo.Create(ctx, dto.TagInputDto{Name: "CLOUD native"})
+			return err
+		}, wantErr: database.ErrConflict},
+		{name: "create a blank name", run: func() error {
+			_, err := repo.Create(ctx, dto.TagInputDto{Name: "  "})
+			return err
+		}, wantErr: database.ErrValidation},
+		{name: "find unknown", run: func() error {
+			_, err := repo.Find(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "tag creates the tag", run: func() error {
+			err := tag(&basics, "Beginner", &beginner)()
+			if err == nil && (beginner.Name != "beginner" || beginner.Courses != 1) {
+				t.Errorf("Tag() = %+v", beginner)
+			}
+			return err
+		}},
+		{name: "tag reuses the tag", run: func() error {
+			var got dto.TagOutputDto
+			err := tag(&advanced, "beginner", &got)()
+			if err == nil && (got.ID != beginner.ID || got.Courses != 2) {
+				t.Errorf("Tag() = %+v, want %s on 2 courses", got, beginner.ID)
+			}
+			return err
+		}},
+		{name: "tag more", run: func() error {
+			if err := tag(&basics, "go", &golang)(); err != nil {
+				return err
+			}
+			if err := tag(&web, "go", nil)(); err != nil {
+				return err
+			}
+			return tag(&web, "cloud native", nil)()
+		}},
+		{name: "tag twice", run: tag(&basics, "BEGINNER", nil), wantErr: database.ErrConflict},
+		{name: "tag unknown course", run: tag(&unknown, "go", nil), wantErr: database.ErrValidation},
+		{name: "tag with a blank name", run: tag(&basics, "", nil), wantErr: database.ErrValidation},
+		{name: "tags of a course", run: func() error {
+			got, err := repo.FindByCourseID(ctx, basics)
+			return checkNames("FindByCourseID()", got, err, "beginner", "go")
+		}},
+		{name: "tags of unknown course", run: func() error {
+			_, err := repo.FindByCourseID(ctx, uuid.NewString())
+			return err
+		}, wantErr: database.ErrNotFound},
+		{name: "courses with any tag", run: func() error {
+			if err := checkTagged(query.CourseSpec{Tags: []string{"Beginner", "cloud native"}}, basics, advanced, web); err != nil {
+				return err
+			}
+			return checkTagged(query.CourseSpec{Tags: []string{"unknown"}})
+		}},
+		{name: "courses with all tags", run: func() error {
+			if err := checkTagged(query.CourseSpec{Tags: []string{"beginner", "go", "GO"}, AllTags: true}, basics); err != nil {
+				return err
+			}
+			if err := checkTagged(query.CourseSpec{Tags: []string{"go", "cloud native"}, AllTags: true}, web); err != nil {
+				return err
+			}
+			return checkTagged(query.CourseSpec{Tags: []string{"beginner", "unknown"}, AllTags: true})
+		}},
+		{name: "autocomplete", run: func() error {
+			got, err := repo.List(ctx, query.TagSpec{NamePrefix: "C"})
+			return checkNames("List(C)", got, err, "cloud native")
+		}},
+		{name: "pages of tags", run: func() error {
+			first, err := repo.List(ctx, query.TagSpec{Page: query.Page{Limit: 2}})
+			if err := checkNames("first page", first, err, "beginner", "cloud native"); err != nil {
+				return err
+			}
+			second, err := repo.List(ctx, query.TagSpec{Page: query.Page{Limit: 2, Cursor: first.NextCursor}})
+			if err == nil && second.NextCursor != "" {
+				t.Errorf("second page cursor = %q, want none", second.NextCursor)
+			}
+			return checkNames("second page", second, err, "go")
+		}},
+		{name: "counts leave deleted courses out", run: func() error {
+			if err := dbi.CourseRepository.Delete(ctx, advanced); err != nil {
+				return err
+			}
+			if err := checkCount(beginner.ID, 1); err != nil {
+				return err
+			}
+			return checkTagged(query.CourseSpec{Tags: []string{"beginner"}}, basics)
+		}},
+		{name: "untag", run: func() error { return repo.Untag(ctx, basics, golang.ID) }},
+		{name: "untagged", run: func() error { return checkCount(golang.ID, 1) }},
+		{name: "untag twice", run: func() error { return repo.Untag(ctx, basics, golang.ID) }, wantErr: database.ErrNotFound},
+		{name: "delete", run: func() error { return repo.Delete(ctx, cloud.ID) }},
+		{name: "deleted tag leaves its courses", run: func() error {
+			got, err := repo.FindByCourseID(ctx, web)
+			return checkNames("FindByCourseID()", got, err, "go")
+		}},
+		{name: "delete twice", run: func() error { return repo.Delete(ctx, cloud.ID) }, wantErr: database.ErrNotFound},
+		{name: "purge keeps the tags", run: func() error {
+			if _, err := dbi.Purge(ctx, time.Now().Add(time.Hour)); err != nil {
+				return err
+			}
+			if err := checkCount(beginner.ID, 1); err != nil {
+				return err
+			}
+			return checkTagged(query.CourseSpec{Tags: []string{"beginner"}, IncludeDeleted: true}, basics)
+		}},
+	})
+}
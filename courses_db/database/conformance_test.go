@@ -0,0 +1,120 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/configs"
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/conformance"
+	"github.com/antoniofmoliveira/courses/db/database/migrations"
+	"github.com/antoniofmoliveira/courses/db/database/mongodb"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var testCache = configs.Cache{Enabled: true, Size: 10, CategoryTTL: time.Minute, CourseTTL: time.Minute}
+
+// The server backends run when their variable names a throwaway database,
+// which every group of checks wipes:
+//
+//	MARIADB_TEST_DSN="root:root@tcp(localhost:3306)/courses_test?parseTime=true&clientFoundRows=true"
+//	POSTGRES_TEST_DSN="host=localhost user=postgres password=postgres dbname=courses_test sslmode=disable"
+//	MONGODB_TEST_URI="mongodb://localhost:27017"
+func TestConformance(t *testing.T) {
+	t.Run("sqlite", func(t *testing.T) {
+		conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+			return migrated(t, "sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on", onDelete)
+		})
+	})
+	t.Run("memory", func(t *testing.T) {
+		conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+			return database.NewMemoryImplementation(onDelete)
+		})
+	})
+	// the cache must not change what the repositories answer
+	t.Run("sqlite cached", func(t *testing.T) {
+		conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+			return migrated(t, "sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on", onDelete).WithCache(testCache)
+		})
+	})
+	t.Run("memory cached", func(t *testing.T) {
+		conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+			return database.NewMemoryImplementation(onDelete).WithCache(testCache)
+		})
+	})
+	t.Run("mariadb", func(t *testing.T) {
+		dsn := os.Getenv("MARIADB_TEST_DSN")
+		if dsn == "" {
+			t.Skip("MARIADB_TEST_DSN not set")
+		}
+		conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+			return migrated(t, "mysql", dsn, onDelete)
+		})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv("POSTGRES_TEST_DSN")
+		if dsn == "" {
+			t.Skip("POSTGRES_TEST_DSN not set")
+		}
+		conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+			return migrated(t, "postgres", dsn, onDelete)
+		})
+	})
+	t.Run("mongodb", func(t *testing.T) {
+		uri := os.Getenv("MONGODB_TEST_URI")
+		if uri == "" {
+			t.Skip("MONGODB_TEST_URI not set")
+		}
+		conformance.Run(t, func(t *testing.T, onDelete query.OnDelete) *database.DBImplementation {
+			ctx := context.Background()
+			client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { client.Disconnect(ctx) })
+			db := client.Database("courses_conformance")
+			if err := db.Drop(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if err := mongodb.EnsureIndexes(ctx, db); err != nil {
+				t.Fatal(err)
+			}
+			return database.NewMongoImplementation(db, onDelete)
+		})
+	})
+}
+
+// migrated opens a SQL database and migrates it from scratch.
+func migrated(t *testing.T, driver, dsn string, onDelete query.OnDelete) *database.DBImplementation {
+	t.Helper()
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := migrations.New(db, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := m.To(ctx, 0); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	if err := m.Up(ctx); errors.Is(err, migrations.ErrNoFTS5) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	dbi, err := database.NewSQLImplementation(db, driver, onDelete)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dbi
+}
@@ -0,0 +1,264 @@
+// Package dberr holds the errors every repository backend returns, so the
+// APIs can tell a missing row from a conflict or a bad input without looking
+// at driver errors.
+package dberr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/entity"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrConflict      = errors.New("conflict")
+	ErrHasDependents = errors.New("has dependents")
+	ErrValidation    = errors.New("validation failed")
+	ErrForbidden     = errors.New("forbidden")
+	// ErrUnauthenticated is for callers that did not say who they are.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrStaleVersion comes along with ErrConflict when an update names a
+	// version that is no longer the current one.
+	ErrStaleVersion = errors.New("stale version")
+)
+
+// Error is one of the sentinel errors with a message for the client and,
+// possibly, the driver error behind it. errors.Is matches both.
+type Error struct {
+	Kind  error
+	Msg   string
+	Cause error
+}
+
+func (e *Error) Error() string { return e.Msg }
+
+func (e *Error) Is(target error) bool { return target == e.Kind }
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+func New(kind error, msg string) error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+func Wrap(kind error, msg string, cause error) error {
+	return &Error{Kind: kind, Msg: msg, Cause: cause}
+}
+
+func NotFound(what string) error {
+	return New(ErrNotFound, what+" not found")
+}
+
+// Stale is returned when what was changed by someone else since the version
+// the update names was read.
+func Stale(what string) error {
+	return Wrap(ErrConflict, what+" was modified since it was read", ErrStaleVersion)
+}
+
+// CheckVersion returns a stale error when want is set and is not current.
+func CheckVersion(what string, want, current int64) error {
+	if want != 0 && want != current {
+		return Stale(what)
+	}
+	return nil
+}
+
+// CategoryHasCourses is returned when deleting a category still in use.
+var CategoryHasCourses = New(ErrHasDependents, "category has courses")
+
+// UnknownCategory is returned when a course refers to a category that does
+// not exist.
+var UnknownCategory = New(ErrValidation, "course category does not exist")
+
+// CategoryHasChildren is returned when deleting a category with live
+// subcategories.
+var CategoryHasChildren = New(ErrHasDependents, "category has subcategories")
+
+// UnknownParentCategory is returned when a category is placed under, or
+// restored below, a category that does not exist or is deleted.
+var UnknownParentCategory = New(ErrValidation, "parent category does not exist")
+
+// CategoryCycle is returned when a category would become its own ancestor.
+var CategoryCycle = New(ErrValidation, "a category cannot be placed under itself or its subcategories")
+
+// UnknownCourse is returned when a module refers to a course that does not
+// exist.
+var UnknownCourse = New(ErrValidation, "module course does not exist")
+
+// UnknownModule is returned when a lesson refers to a module that does not
+// exist.
+var UnknownModule = New(ErrValidation, "lesson module does not exist")
+
+// UnknownEnrollmentUser and UnknownEnrollmentCourse are returned when an
+// enrollment refers to a user or course that does not exist.
+var (
+	UnknownEnrollmentUser   = New(ErrValidation, "enrollment user does not exist")
+	UnknownEnrollmentCourse = New(ErrValidation, "enrollment course does not exist")
+)
+
+// AlreadyEnrolled is returned when enrolling a user twice in a course.
+var AlreadyEnrolled = New(ErrConflict, "user is already enrolled in the course")
+
+// UnknownInstructorUser and UnknownInstructorCourse are returned when an
+// instructor refers to a user or course that does not exist.
+var (
+	UnknownInstructorUser   = New(ErrValidation, "instructor user does not exist")
+	UnknownInstructorCourse = New(ErrValidation, "instructor course does not exist")
+)
+
+// AlreadyInstructor is returned when adding an instructor twice to a course.
+var AlreadyInstructor = New(ErrConflict, "user is already an instructor of the course")
+
+// LastInstructor is returned when removing the only instructor of a course.
+var LastInstructor = New(ErrConflict, "a course keeps at least one instructor")
+
+// UnknownTagCourse is returned when tagging a course that does not exist.
+var UnknownTagCourse = New(ErrValidation, "tagged course does not exist")
+
+// TagExists is returned when creating a tag whose name is taken.
+var TagExists = New(ErrConflict, "tag already exists")
+
+// AlreadyTagged is returned when tagging a course twice with a tag.
+var AlreadyTagged = New(ErrConflict, "course already has the tag")
+
+// NotOwner is returned when changing a course the caller does not teach.
+var NotOwner = New(ErrForbidden, "only the instructors of the course and admins may change it")
+
+// Unauthenticated is returned when an admin only operation has no actor.
+var Unauthenticated = New(ErrUnauthenticated, "a bearer token of an admin is required")
+
+// NotAdmin is returned when an admin only operation has an actor who is not
+// one of the admins.
+var NotAdmin = New(ErrForbidden, "only admins may do this")
+
+// CheckOrder rejects a reordering of the children of a parent unless ids
+// lists each of the current ones exactly once.
+func CheckOrder(current, ids []string, children, parent string) error {
+	listed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+	if len(ids) != len(current) || len(listed) != len(ids) {
+		return New(ErrValidation, "the ids must list every "+children+" of the "+parent+" once")
+	}
+	for _, id := range current {
+		if !listed[id] {
+			return New(ErrValidation, "the ids must list every "+children+" of the "+parent+" once")
+		}
+	}
+	return nil
+}
+
+func ValidateCategory(category dto.CategoryInputDto) error {
+	if category.Name == "" {
+		return New(ErrValidation, "category name is required")
+	}
+	return nil
+}
+
+func ValidateCourse(course dto.CourseInputDto) error {
+	if course.Name == "" {
+		return New(ErrValidation, "course name is required")
+	}
+	if course.CategoryID == "" {
+		return New(ErrValidation, "course category_id is required")
+	}
+	return nil
+}
+
+// ValidateModule checks the fields an update may change; the course of a
+// new module is checked by the repository.
+func ValidateModule(module dto.ModuleInputDto) error {
+	if module.Title == "" {
+		return New(ErrValidation, "module title is required")
+	}
+	return nil
+}
+
+// ValidateLesson checks the fields an update may change; the module of a
+// new lesson is checked by the repository.
+func ValidateLesson(lesson dto.LessonInputDto) error {
+	if lesson.Title == "" {
+		return New(ErrValidation, "lesson title is required")
+	}
+	if !entity.ContentType(lesson.ContentType).Valid() {
+		return New(ErrValidation, "lesson content_type must be one of text, video, audio and quiz")
+	}
+	if lesson.DurationSeconds < 0 {
+		return New(ErrValidation, "lesson duration_seconds must not be negative")
+	}
+	return nil
+}
+
+// ValidateProgress checks the completion percentage of an enrollment.
+func ValidateProgress(enrollment dto.EnrollmentInputDto) error {
+	if enrollment.Progress < 0 || enrollment.Progress > 100 {
+		return New(ErrValidation, "enrollment progress must be between 0 and 100")
+	}
+	return nil
+}
+
+// ValidateTag checks a tag name, normalized with entity.NormalizeTagName.
+func ValidateTag(name string) error {
+	if name == "" {
+		return New(ErrValidation, "tag name is required")
+	}
+	if len(name) > entity.MaxTagName {
+		return New(ErrValidation, fmt.Sprintf("tag name must be at most %d bytes", entity.MaxTagName))
+	}
+	return nil
+}
+
+func ValidateUser(user dto.UserInputDto) error {
+	if user.Name == "" {
+		return New(ErrValidation, "user name is required")
+	}
+	if user.Email == "" {
+		return New(ErrValidation, "user email is required")
+	}
+	return nil
+}
+
+// NoRows turns sql.ErrNoRows into a not found error for what; other errors
+// are returned unchanged.
+func NoRows(what string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return Wrap(ErrNotFound, what+" not found", err)
+	}
+	return err
+}
+
+// Affected returns a not found error for what when an update or delete
+// matched no row.
+func Affected(result sql.Result, what string) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return NotFound(what)
+	}
+	return nil
+}
+
+// Changed returns a stale error when an update guarded by the version read
+// earlier in the transaction matched no row.
+func Changed(result sql.Result, what string) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return Stale(what)
+	}
+	return nil
+}
+
+// Rejected reports whether err is one of the errors above: the input was
+// refused, the database itself is fine.
+func Rejected(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
@@ -0,0 +1,15 @@
+package database
+
+import "github.com/antoniofmoliveira/courses/db/database/dberr"
+
+// Errors every repository returns, whatever the backend. Match them with
+// errors.Is; see package dberr.
+var (
+	ErrNotFound        = dberr.ErrNotFound
+	ErrConflict        = dberr.ErrConflict
+	ErrHasDependents   = dberr.ErrHasDependents
+	ErrValidation      = dberr.ErrValidation
+	ErrForbidden       = dberr.ErrForbidden
+	ErrStaleVersion    = dberr.ErrStaleVersion
+	ErrUnauthenticated = dberr.ErrUnauthenticated
+)
@@ -0,0 +1,240 @@
+// Package fixtures loads categories, courses and users described in a YAML
+// or JSON file, and generates synthetic ones for load testing. Loading is
+// idempotent: an item already in the database is left as it is.
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/entity"
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is the content of a fixture file. Courses name their category by
+// its key, which only lives in the file.
+type Fixture struct {
+	Categories []Category `json:"categories" yaml:"categories"`
+	Courses    []Course   `json:"courses" yaml:"courses"`
+	Users      []User     `json:"users" yaml:"users"`
+}
+
+type Category struct {
+	Key         string `json:"key" yaml:"key"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type Course struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Category    string `json:"category" yaml:"category"`
+}
+
+type User struct {
+	Name     string `json:"name" yaml:"name"`
+	Email    string `json:"email" yaml:"email"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// ReadFile reads a fixture file, as JSON when its extension is .json and as
+// YAML otherwise. Unknown fields are an error.
+func ReadFile(path string) (Fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Fixture{}, err
+	}
+	defer file.Close()
+	var f Fixture
+	if filepath.Ext(path) == ".json" {
+		decoder := json.NewDecoder(file)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&f)
+	} else {
+		decoder := yaml.NewDecoder(file)
+		decoder.KnownFields(true)
+		err = decoder.Decode(&f)
+	}
+	if err != nil {
+		return Fixture{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Check reports the first key that is missing, repeated or unknown.
+func (f Fixture) Check() error {
+	keys := map[string]bool{}
+	for i, category := range f.Categories {
+		if category.Key == "" {
+			return fmt.Errorf("category %d: missing key", i)
+		}
+		if keys[category.Key] {
+			return fmt.Errorf("category %d: key %q repeated", i, category.Key)
+		}
+		keys[category.Key] = true
+	}
+	for i, course := range f.Courses {
+		if !keys[course.Category] {
+			return fmt.Errorf("course %d: unknown category %q", i, course.Category)
+		}
+	}
+	return nil
+}
+
+// Count is the number of items of a kind created by a load, and found
+// already there.
+type Count struct {
+	Created  int
+	Existing int
+}
+
+type Report struct {
+	Categories Count
+	Courses    Count
+	Users      Count
+}
+
+// Load inserts the items of f that dbi does not hold yet. A category is
+// found by its name, a course by its name and category, and a user by the
+// email. Users are checked and their passwords hashed by entity.NewUser, as
+// the APIs do.
+//
+// Load is not atomic: after a failure, loading f again completes it.
+func Load(ctx context.Context, dbi *database.DBImplementation, f Fixture) (Report, error) {
+	var report Report
+	if err := f.Check(); err != nil {
+		return report, err
+	}
+	categoryIDs, err := loadCategories(ctx, dbi.CategoryRepository, f.Categories, &report.Categories)
+	if err != nil {
+		return report, err
+	}
+	if err := loadCourses(ctx, dbi.CourseRepository, f.Courses, categoryIDs, &report.Courses); err != nil {
+		return report, err
+	}
+	if err := loadUsers(ctx, dbi.UserRepository, f.Users, &report.Users); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// loadCategories returns the id of every category by its key.
+func loadCategories(ctx context.Context, repo database.CategoryRepositoryInterface, categories []Category, count *Count) (map[string]string, error) {
+	existing := map[string]string{}
+	page := query.Page{Limit: query.MaxLimit}
+	for {
+		list, err := repo.FindAll(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range list.Categories {
+			existing[category.Name] = category.ID
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		page.Cursor = list.NextCursor
+	}
+
+	ids := map[string]string{}
+	var missing []dto.CategoryInputDto
+	var keys []string
+	for _, category := range categories {
+		if id, ok := existing[category.Name]; ok {
+			ids[category.Key] = id
+			count.Existing++
+			continue
+		}
+		missing = append(missing, dto.CategoryInputDto{Name: category.Name, Description: category.Description})
+		keys = append(keys, category.Key)
+	}
+	if len(missing) == 0 {
+		return ids, nil
+	}
+	results, err := repo.CreateBatch(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("category %q: %w", keys[result.Index], result.Err)
+		}
+		ids[keys[result.Index]] = result.ID
+		count.Created++
+	}
+	return ids, nil
+}
+
+func loadCourses(ctx context.Context, repo database.CourseRepositoryInterface, courses []Course, categoryIDs map[string]string, count *Count) error {
+	type courseKey struct{ categoryID, name string }
+	existing := map[courseKey]bool{}
+	page := query.Page{Limit: query.MaxLimit}
+	for {
+		list, err := repo.FindAll(ctx, page)
+		if err != nil {
+			return err
+		}
+		for _, course := range list.Courses {
+			existing[courseKey{course.CategoryID, course.Name}] = true
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		page.Cursor = list.NextCursor
+	}
+
+	var missing []dto.CourseInputDto
+	for _, course := range courses {
+		key := courseKey{categoryIDs[course.Category], course.Name}
+		if existing[key] {
+			count.Existing++
+			continue
+		}
+		// a course repeated in the file is created once
+		existing[key] = true
+		missing = append(missing, dto.CourseInputDto{Name: course.Name, Description: course.Description, CategoryID: key.categoryID})
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	results, err := repo.CreateBatch(ctx, missing)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			return fmt.Errorf("course %q: %w", missing[result.Index].Name, result.Err)
+		}
+		count.Created++
+	}
+	return nil
+}
+
+func loadUsers(ctx context.Context, repo database.UserRepositoryInterface, users []User, count *Count) error {
+	for _, user := range users {
+		_, err := repo.FindByEmail(ctx, user.Email)
+		if err == nil {
+			count.Existing++
+			continue
+		}
+		if !errors.Is(err, database.ErrNotFound) {
+			return err
+		}
+		created, err := entity.NewUser(user.Name, user.Email, user.Password)
+		if err != nil {
+			return fmt.Errorf("user %q: %w", user.Email, err)
+		}
+		if _, err := repo.Create(ctx, dto.UserInputDto{Name: user.Name, Email: user.Email, Password: created.Password}); err != nil {
+			return fmt.Errorf("user %q: %w", user.Email, err)
+		}
+		count.Created++
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package fixtures_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/fixtures"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/entity"
+)
+
+const yamlFixture = `
+categories:
+  - key: go
+    name: Go
+    description: The Go language
+courses:
+  - name: Go basics
+    category: go
+  - name: Go on the web
+    category: go
+users:
+  - name: user
+    email: user@user.com
+    password: "123456"
+`
+
+const jsonFixture = `{
+  "categories": [{"key": "go", "name": "Go", "description": "The Go language"}],
+  "courses": [{"name": "Go basics", "category": "go"}, {"name": "Go on the web", "category": "go"}],
+  "users": [{"name": "user", "email": "user@user.com", "password": "123456"}]
+}`
+
+func write(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadFile(t *testing.T) {
+	want := fixtures.Fixture{
+		Categories: []fixtures.Category{{Key: "go", Name: "Go", Description: "The Go language"}},
+		Courses:    []fixtures.Course{{Name: "Go basics", Category: "go"}, {Name: "Go on the web", Category: "go"}},
+		Users:      []fixtures.User{{Name: "user", Email: "user@user.com", Password: "123456"}},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{name: "yaml", file: "seed.yaml", content: yamlFixture},
+		{name: "json", file: "seed.json", content: jsonFixture},
+		{name: "unknown yaml field", file: "seed.yml", content: "categories:\n  - key: go\n    title: Go\n", wantErr: true},
+		{name: "unknown json field", file: "seed.json", content: `{"lessons": []}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fixtures.ReadFile(write(t, tt.file, tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ReadFile() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ReadFile() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture fixtures.Fixture
+		wantErr string
+	}{
+		{name: "empty", fixture: fixtures.Fixture{}},
+		{
+			name:    "missing key",
+			fixture: fixtures.Fixture{Categories: []fixtures.Category{{Name: "Go"}}},
+			wantErr: "category 0: missing key",
+		},
+		{
+			name:    "repeated key",
+			fixture: fixtures.Fixture{Categories: []fixtures.Category{{Key: "go", Name: "Go"}, {Key: "go", Name: "Golang"}}},
+			wantErr: `category 1: key "go" repeated`,
+		},
+		{
+			name: "unknown category",
+			fixture: fixtures.Fixture{
+				Categories: []fixtures.Category{{Key: "go", Name: "Go"}},
+				Courses:    []fixtures.Course{{Name: "Rust basics", Category: "rust"}},
+			},
+			wantErr: `course 0: unknown category "rust"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fixture.Check()
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("Check() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	dbi := database.NewMemoryImplementation(query.Restrict)
+	f, err := fixtures.ReadFile(write(t, "seed.yaml", yamlFixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := fixtures.Load(ctx, dbi, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fixtures.Report{
+		Categories: fixtures.Count{Created: 1},
+		Courses:    fixtures.Count{Created: 2},
+		Users:      fixtures.Count{Created: 1},
+	}
+	if report != want {
+		t.Errorf("first Load() = %+v, want %+v", report, want)
+	}
+	user, err := dbi.UserRepository.FindByEmail(ctx, "user@user.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(&entity.User{Password: user.Password}).ValidatePassword("123456") {
+		t.Errorf("stored password %q is not the hash of the fixture one", user.Password)
+	}
+
+	// loading again, with one more course, only adds the course
+	f.Courses = append(f.Courses, fixtures.Course{Name: "Go concurrency", Category: "go"})
+	report, err = fixtures.Load(ctx, dbi, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = fixtures.Report{
+		Categories: fixtures.Count{Existing: 1},
+		Courses:    fixtures.Count{Created: 1, Existing: 2},
+		Users:      fixtures.Count{Existing: 1},
+	}
+	if report != want {
+		t.Errorf("second Load() = %+v, want %+v", report, want)
+	}
+	courses, err := dbi.CourseRepository.FindAll(ctx, query.Page{})
+	if err != nil || len(courses.Courses) != 3 {
+		t.Errorf("courses = %+v, %v, want 3", courses, err)
+	}
+
+	// users sharing a password get hashes of their own
+	f.Users = append(f.Users,
+		fixtures.User{Name: "ann", Email: "ann@user.com", Password: "secret"},
+		fixtures.User{Name: "bob", Email: "bob@user.com", Password: "secret"})
+	if _, err := fixtures.Load(ctx, dbi, f); err != nil {
+		t.Fatal(err)
+	}
+	ann, err := dbi.UserRepository.FindByEmail(ctx, "ann@user.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := dbi.UserRepository.FindByEmail(ctx, "bob@user.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ann.Password == bob.Password {
+		t.Error("users sharing a password share its hash")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	sizes := fixtures.Sizes{Categories: 3, CoursesPerCategory: 4, Users: 5}
+	f := fixtures.Generate(sizes, 1)
+	if len(f.Categories) != 3 || len(f.Courses) != 12 || len(f.Users) != 5 {
+		t.Fatalf("Generate() made %d categories, %d courses and %d users", len(f.Categories), len(f.Courses), len(f.Users))
+	}
+	if err := f.Check(); err != nil {
+		t.Error(err)
+	}
+	if again := fixtures.Generate(sizes, 1); !reflect.DeepEqual(f, again) {
+		t.Error("Generate() with the same seed made another fixture")
+	}
+	if other := fixtures.Generate(sizes, 2); reflect.DeepEqual(f, other) {
+		t.Error("Generate() with another seed made the same fixture")
+	}
+	if !strings.HasPrefix(f.Courses[5].Name, "Course 2.2 ") || f.Courses[5].Category != "category-2" {
+		t.Errorf("sixth course = %+v, want the second of category-2", f.Courses[5])
+	}
+
+	report, err := fixtures.Load(context.Background(), database.NewMemoryImplementation(query.Restrict), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fixtures.Report{
+		Categories: fixtures.Count{Created: 3},
+		Courses:    fixtures.Count{Created: 12},
+		Users:      fixtures.Count{Created: 5},
+	}
+	if report != want {
+		t.Errorf("Load() = %+v, want %+v", report, want)
+	}
+}
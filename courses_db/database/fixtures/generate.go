@@ -0,0 +1,61 @@
+package fixtures
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// SyntheticPassword is the password of every generated user.
+const SyntheticPassword = "password"
+
+// Sizes is how much Generate makes.
+type Sizes struct {
+	Categories         int
+	CoursesPerCategory int
+	Users              int
+}
+
+var words = strings.Fields(`go web data cloud design testing security mobile api database
+	networks systems compilers graphics audio games machine learning
+	introduction advanced practical modern applied fundamentals patterns`)
+
+// Generate makes a synthetic fixture of the given sizes. The same seed
+// makes the same fixture, and every name and email is unique, so loading
+// it twice creates nothing the second time.
+func Generate(sizes Sizes, seed uint64) Fixture {
+	r := rand.New(rand.NewPCG(seed, seed))
+	var f Fixture
+	for c := range sizes.Categories {
+		key := fmt.Sprintf("category-%d", c+1)
+		f.Categories = append(f.Categories, Category{
+			Key:         key,
+			Name:        fmt.Sprintf("Category %d %s", c+1, phrase(r, 2)),
+			Description: phrase(r, 8),
+		})
+		for n := range sizes.CoursesPerCategory {
+			f.Courses = append(f.Courses, Course{
+				Name:        fmt.Sprintf("Course %d.%d %s", c+1, n+1, phrase(r, 3)),
+				Description: phrase(r, 12),
+				Category:    key,
+			})
+		}
+	}
+	for u := range sizes.Users {
+		f.Users = append(f.Users, User{
+			Name:     fmt.Sprintf("User %d", u+1),
+			Email:    fmt.Sprintf("user%d@example.com", u+1),
+			Password: SyntheticPassword,
+		})
+	}
+	return f
+}
+
+// phrase returns n random words.
+func phrase(r *rand.Rand, n int) string {
+	picked := make([]string, n)
+	for i := range picked {
+		picked[i] = words[r.IntN(len(words))]
+	}
+	return strings.Join(picked, " ")
+}
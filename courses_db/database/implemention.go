@@ -1,24 +1,131 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net"
+	"net/url"
+	"time"
 
 	"github.com/antoniofmoliveira/courses/db/configs"
 	"github.com/antoniofmoliveira/courses/db/database/mariadb"
+	"github.com/antoniofmoliveira/courses/db/database/memory"
+	"github.com/antoniofmoliveira/courses/db/database/migrations"
+	"github.com/antoniofmoliveira/courses/db/database/mongodb"
+	"github.com/antoniofmoliveira/courses/db/database/postgres"
+	"github.com/antoniofmoliveira/courses/db/database/query"
 	"github.com/antoniofmoliveira/courses/db/database/sqlite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type DBImplementation struct {
-	db                 *sql.DB
-	CategoryRepository CategoryRepositoryInterface
-	CourseRepository   CourseRepositoryInterface
-	UserRepository     UserRepositoryInterface
+	db                   *sql.DB
+	CategoryRepository   CategoryRepositoryInterface
+	CourseRepository     CourseRepositoryInterface
+	UserRepository       UserRepositoryInterface
+	HistoryRepository    HistoryRepositoryInterface
+	SearchRepository     SearchRepositoryInterface
+	ModuleRepository     ModuleRepositoryInterface
+	LessonRepository     LessonRepositoryInterface
+	EnrollmentRepository EnrollmentRepositoryInterface
+	InstructorRepository InstructorRepositoryInterface
+	TagRepository        TagRepositoryInterface
+	begin                func(ctx context.Context) (*Tx, error)
+	cache                *repositoryCache
+	admins               map[string]bool
 }
 
 var dbi *DBImplementation
 
+// Open connects to the SQL database configured in .env and returns it with
+// its driver name, after a ping. The schema is not checked; servers go
+// through GetDBImplementation, the migrate command uses Open directly.
+func Open() (*sql.DB, string, error) {
+	cfg, err := configs.LoadConfig(".")
+	if err != nil {
+		return nil, "", err
+	}
+	dsn, err := cfg.DSN(cfg.DBHost, cfg.DBPort)
+	if err != nil {
+		return nil, cfg.DBDriver, err
+	}
+	db, err := openSQL(cfg.DBDriver, dsn, cfg.Pool(), cfg.DBConnectTimeout)
+	return db, cfg.DBDriver, err
+}
+
+// OpenFile is Open for the SQL database configured in the given env file,
+// for tools working on two databases at once.
+func OpenFile(file string) (*sql.DB, string, error) {
+	cfg, err := configs.LoadFile(file)
+	if err != nil {
+		return nil, "", err
+	}
+	dsn, err := cfg.DSN(cfg.DBHost, cfg.DBPort)
+	if err != nil {
+		return nil, cfg.DBDriver, err
+	}
+	db, err := openSQL(cfg.DBDriver, dsn, cfg.Pool(), cfg.DBConnectTimeout)
+	return db, cfg.DBDriver, err
+}
+
+// openReplicas connects to the read replicas listed in .env, none when the
+// list is empty.
+func openReplicas() ([]*sql.DB, error) {
+	cfg, err := configs.LoadConfig(".")
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.DBReadReplicas) > 0 && cfg.DBDriver == "sqlite3" {
+		return nil, fmt.Errorf("read replicas are not supported by %s", cfg.DBDriver)
+	}
+	var replicas []*sql.DB
+	for _, replica := range cfg.DBReadReplicas {
+		host, port, err := net.SplitHostPort(replica)
+		if err != nil {
+			return nil, fmt.Errorf("read replica %q: %w", replica, err)
+		}
+		dsn, err := cfg.DSN(host, port)
+		if err != nil {
+			return nil, err
+		}
+		db, err := openSQL(cfg.DBDriver, dsn, cfg.Pool(), cfg.DBConnectTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("read replica %s: %w", replica, err)
+		}
+		replicas = append(replicas, db)
+	}
+	return replicas, nil
+}
+
+// openSQL opens a connection pool and pings it, so that a wrong address or
+// credentials fail at startup rather than on the first request.
+func openSQL(driver, dsn string, pool configs.Pool, timeout time.Duration) (*sql.DB, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
 func GetDBImplementation() *DBImplementation {
 	if dbi != nil {
 		return dbi
@@ -28,37 +135,183 @@ func GetDBImplementation() *DBImplementation {
 	if err != nil {
 		panic(err)
 	}
-	if cfg.DBDriver == "mysql" {
-		conn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
-		db, err := sql.Open(cfg.DBDriver, conn)
-		if err != nil {
-			log.Fatalf("failed to open database: %v", err)
-		}
-		dbi = &DBImplementation{
-			db:                 db,
-			CategoryRepository: mariadb.NewCategoryRepository(db),
-			CourseRepository:   mariadb.NewCourseRepository(db),
-			UserRepository:     mariadb.NewUserRepository(db),
-		}
+	onDelete, err := query.ParseOnDelete(cfg.CategoryOnDelete)
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if cfg.DBDriver == "memory" {
+		dbi = NewMemoryImplementation(onDelete).WithCache(cfg.Cache()).WithOwnership(cfg.Admins)
 		return dbi
 	}
-	if cfg.DBDriver == "sqlite3" {
-		conn := fmt.Sprintf("%s.db", cfg.DBName)
-		db, err := sql.Open(cfg.DBDriver, conn)
+	if cfg.DBDriver == "mongodb" {
+		uri := url.URL{Scheme: "mongodb", Host: net.JoinHostPort(cfg.DBHost, cfg.DBPort)}
+		if cfg.DBUser != "" {
+			uri.User = url.UserPassword(cfg.DBUser, cfg.DBPassword)
+		}
+		clientOptions := options.Client().ApplyURI(uri.String()).SetMaxConnIdleTime(cfg.DBConnMaxIdleTime)
+		if cfg.DBConnectTimeout > 0 {
+			clientOptions.SetConnectTimeout(cfg.DBConnectTimeout).SetServerSelectionTimeout(cfg.DBConnectTimeout)
+		}
+		if cfg.DBMaxOpenConns > 0 {
+			clientOptions.SetMaxPoolSize(uint64(cfg.DBMaxOpenConns))
+		}
+		if cfg.DBStatementTimeout > 0 {
+			clientOptions.SetTimeout(cfg.DBStatementTimeout)
+		}
+		ctx := context.Background()
+		client, err := mongo.Connect(ctx, clientOptions)
 		if err != nil {
 			log.Fatalf("failed to open database: %v", err)
 		}
-		dbi = &DBImplementation{
-			db:                 db,
-			CategoryRepository: sqlite.NewCategoryRepository(db),
-			CourseRepository:   sqlite.NewCourseRepository(db),
-			UserRepository:     sqlite.NewUserRepository(db),
+		if err := client.Ping(ctx, nil); err != nil {
+			log.Fatalf("failed to open database: %v", err)
+		}
+		db := client.Database(cfg.DBName)
+		if err := mongodb.EnsureIndexes(ctx, db); err != nil {
+			log.Fatalf("failed to create indexes: %v", err)
 		}
+		dbi = NewMongoImplementation(db, onDelete).WithCache(cfg.Cache()).WithOwnership(cfg.Admins)
 		return dbi
 	}
-	if cfg.DBDriver == "mongodb" {
-		// TODO!
+
+	db, driver, err := Open()
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
 	}
+	migrator, err := migrations.New(db, driver)
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatalf("refusing to start: %v", err)
+	}
+
+	replicas, err := openReplicas()
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+
+	dbi, err = NewSQLImplementation(db, driver, onDelete, replicas...)
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+	return dbi.WithCache(cfg.Cache()).WithOwnership(cfg.Admins)
+}
 
-	return nil
+// NewSQLImplementation returns the DBImplementation of a migrated database
+// opened with the given driver. Reads outside transactions go to the
+// replicas, if any.
+func NewSQLImplementation(db *sql.DB, driver string, onDelete query.OnDelete, replicas ...*sql.DB) (*DBImplementation, error) {
+	switch driver {
+	case "mysql":
+		return sqlImplementation(db, replicas, mariadbRepositories(onDelete)), nil
+	case "sqlite3":
+		return sqlImplementation(db, replicas, sqliteRepositories(onDelete)), nil
+	case "postgres":
+		return sqlImplementation(db, replicas, postgresRepositories(onDelete)), nil
+	}
+	return nil, fmt.Errorf("unsupported database driver %q", driver)
+}
+
+// NewMongoImplementation returns the DBImplementation of a MongoDB database
+// whose indexes are in place.
+func NewMongoImplementation(db *mongo.Database, onDelete query.OnDelete) *DBImplementation {
+	return &DBImplementation{
+		CategoryRepository:   mongodb.NewCategoryRepository(db, onDelete),
+		CourseRepository:     mongodb.NewCourseRepository(db),
+		UserRepository:       mongodb.NewUserRepository(db),
+		HistoryRepository:    mongodb.NewHistoryRepository(db),
+		SearchRepository:     mongodb.NewSearchRepository(db),
+		ModuleRepository:     mongodb.NewModuleRepository(db),
+		LessonRepository:     mongodb.NewLessonRepository(db),
+		EnrollmentRepository: mongodb.NewEnrollmentRepository(db),
+		InstructorRepository: mongodb.NewInstructorRepository(db),
+		TagRepository:        mongodb.NewTagRepository(db),
+	}
+}
+
+// NewMemoryImplementation returns a DBImplementation keeping its data in
+// memory, independent of the .env configuration.
+func NewMemoryImplementation(onDelete query.OnDelete) *DBImplementation {
+	store := memory.NewStore()
+	return &DBImplementation{
+		CategoryRepository:   memory.NewCategoryRepository(store, onDelete),
+		CourseRepository:     memory.NewCourseRepository(store),
+		UserRepository:       memory.NewUserRepository(store),
+		HistoryRepository:    memory.NewHistoryRepository(store),
+		SearchRepository:     memory.NewSearchRepository(store),
+		ModuleRepository:     memory.NewModuleRepository(store),
+		LessonRepository:     memory.NewLessonRepository(store),
+		EnrollmentRepository: memory.NewEnrollmentRepository(store),
+		InstructorRepository: memory.NewInstructorRepository(store),
+		TagRepository:        memory.NewTagRepository(store),
+		begin: func(ctx context.Context) (*Tx, error) {
+			memTx := store.Begin()
+			return &Tx{
+				CategoryRepository:   memTx.CategoryRepository(onDelete),
+				CourseRepository:     memTx.CourseRepository(),
+				UserRepository:       memTx.UserRepository(),
+				HistoryRepository:    memTx.HistoryRepository(),
+				SearchRepository:     memTx.SearchRepository(),
+				ModuleRepository:     memTx.ModuleRepository(),
+				LessonRepository:     memTx.LessonRepository(),
+				EnrollmentRepository: memTx.EnrollmentRepository(),
+				InstructorRepository: memTx.InstructorRepository(),
+				TagRepository:        memTx.TagRepository(),
+				commit:               memTx.Commit,
+				rollback:             memTx.Rollback,
+			}, nil
+		},
+	}
+}
+
+func mariadbRepositories(onDelete query.OnDelete) func(q query.DBTX) *Tx {
+	return func(q query.DBTX) *Tx {
+		return &Tx{
+			CategoryRepository:   mariadb.NewCategoryRepository(q, onDelete),
+			CourseRepository:     mariadb.NewCourseRepository(q),
+			UserRepository:       mariadb.NewUserRepository(q),
+			HistoryRepository:    mariadb.NewHistoryRepository(q),
+			SearchRepository:     mariadb.NewSearchRepository(q),
+			ModuleRepository:     mariadb.NewModuleRepository(q),
+			LessonRepository:     mariadb.NewLessonRepository(q),
+			EnrollmentRepository: mariadb.NewEnrollmentRepository(q),
+			InstructorRepository: mariadb.NewInstructorRepository(q),
+			TagRepository:        mariadb.NewTagRepository(q),
+		}
+	}
+}
+
+func sqliteRepositories(onDelete query.OnDelete) func(q query.DBTX) *Tx {
+	return func(q query.DBTX) *Tx {
+		return &Tx{
+			CategoryRepository:   sqlite.NewCategoryRepository(q, onDelete),
+			CourseRepository:     sqlite.NewCourseRepository(q),
+			UserRepository:       sqlite.NewUserRepository(q),
+			HistoryRepository:    sqlite.NewHistoryRepository(q),
+			SearchRepository:     sqlite.NewSearchRepository(q),
+			ModuleRepository:     sqlite.NewModuleRepository(q),
+			LessonRepository:     sqlite.NewLessonRepository(q),
+			EnrollmentRepository: sqlite.NewEnrollmentRepository(q),
+			InstructorRepository: sqlite.NewInstructorRepository(q),
+			TagRepository:        sqlite.NewTagRepository(q),
+		}
+	}
+}
+
+func postgresRepositories(onDelete query.OnDelete) func(q query.DBTX) *Tx {
+	return func(q query.DBTX) *Tx {
+		return &Tx{
+			CategoryRepository:   postgres.NewCategoryRepository(q, onDelete),
+			CourseRepository:     postgres.NewCourseRepository(q),
+			UserRepository:       postgres.NewUserRepository(q),
+			HistoryRepository:    postgres.NewHistoryRepository(q),
+			SearchRepository:     postgres.NewSearchRepository(q),
+			ModuleRepository:     postgres.NewModuleRepository(q),
+			LessonRepository:     postgres.NewLessonRepository(q),
+			EnrollmentRepository: postgres.NewEnrollmentRepository(q),
+			InstructorRepository: postgres.NewInstructorRepository(q),
+			TagRepository:        postgres.NewTagRepository(q),
+		}
+	}
 }
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+func TestCategoryOnDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		onDelete    query.OnDelete
+		wantErr     error
+		wantCourses int
+	}{
+		{name: "restrict", onDelete: query.Restrict, wantErr: ErrHasDependents, wantCourses: 1},
+		{name: "cascade", onDelete: query.Cascade, wantCourses: 0},
+	}
+	for _, tt := range tests {
+		implementations := map[string]*DBImplementation{
+			"sqlite": newSqliteImplementation(t, tt.onDelete),
+			"memory": NewMemoryImplementation(tt.onDelete),
+		}
+		for backend, dbi := range implementations {
+			t.Run(backend+" "+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				course, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_, err = dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "orphan", CategoryID: uuid.NewString()})
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("Create() with unknown category error = %v, want ErrValidation", err)
+				}
+				err = dbi.CourseRepository.Update(ctx, dto.CourseInputDto{ID: course.ID, Name: "basics", CategoryID: uuid.NewString()})
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("Update() with unknown category error = %v, want ErrValidation", err)
+				}
+
+				err = dbi.CategoryRepository.Delete(ctx, category.ID)
+				if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+				}
+				courses, err := dbi.CourseRepository.FindByCategoryID(ctx, category.ID, query.Page{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(courses.Courses) != tt.wantCourses {
+					t.Errorf("got %d courses, want %d", len(courses.Courses), tt.wantCourses)
+				}
+			})
+		}
+	}
+}
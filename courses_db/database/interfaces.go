@@ -1,30 +1,169 @@
 package database
 
-import "github.com/antoniofmoliveira/courses/dto"
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
 
 type CourseRepositoryInterface interface {
-	Create(dto dto.CourseInputDto) (*dto.CourseOutputDto, error)
-	FindAll() (dto.CourseListOutputDto, error)
-	FindByCategoryID(categoryID string) (dto.CourseListOutputDto, error)
-	Find(id string) (dto.CourseOutputDto, error)
-	Update(course dto.CourseInputDto) error
-	Delete(id string) error
+	Create(ctx context.Context, dto dto.CourseInputDto) (*dto.CourseOutputDto, error)
+	// CreateBatch, UpdateBatch and DeleteBatch apply many changes at once
+	// and return one result per item, in order. An item the repository
+	// rejects (see dberr.Rejected) is reported in its result and does not
+	// stop the others; any other error fails the whole batch. Only
+	// CreateBatch is set-based on the SQL backends: UpdateBatch and
+	// DeleteBatch run the single-item Update or Delete, with its history
+	// entry, under a savepoint per item, so they cost about as many round
+	// trips as that many single calls, saving only the transaction and the
+	// statement preparation.
+	CreateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error)
+	UpdateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error)
+	DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error)
+	FindAll(ctx context.Context, page query.Page) (dto.CourseListOutputDto, error)
+	FindByCategoryID(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error)
+	// FindByCategoryTree lists the live courses of a category and of its
+	// subcategories, at any depth.
+	FindByCategoryTree(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error)
+	// FindByInstructorID lists the live courses taught by a user.
+	FindByInstructorID(ctx context.Context, userID string, page query.Page) (dto.CourseListOutputDto, error)
+	List(ctx context.Context, spec query.CourseSpec) (dto.CourseListOutputDto, error)
+	Find(ctx context.Context, id string) (dto.CourseOutputDto, error)
+	Update(ctx context.Context, course dto.CourseInputDto) error
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
 
+// CategoryRepositoryInterface manages categories, which form a tree. A
+// category is placed under a live parent, never under itself or one of its
+// subcategories, and cannot be deleted while it has live subcategories.
 type CategoryRepositoryInterface interface {
-	Create(dto dto.CategoryInputDto) (dto.CategoryOutputDto, error)
-	FindAll() (dto.CategoryListOutputDto, error)
-	FindByCourseID(courseID string) (dto.CategoryOutputDto, error)
-	Find(id string) (dto.CategoryOutputDto, error)
-	Update(category dto.CategoryInputDto) error
-	Delete(id string) error
+	Create(ctx context.Context, dto dto.CategoryInputDto) (dto.CategoryOutputDto, error)
+	CreateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error)
+	UpdateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error)
+	DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error)
+	FindAll(ctx context.Context, page query.Page) (dto.CategoryListOutputDto, error)
+	List(ctx context.Context, spec query.CategorySpec) (dto.CategoryListOutputDto, error)
+	FindByCourseID(ctx context.Context, courseID string) (dto.CategoryOutputDto, error)
+	Find(ctx context.Context, id string) (dto.CategoryOutputDto, error)
+	// FindChildren lists the live categories right under a category.
+	FindChildren(ctx context.Context, id string, page query.Page) (dto.CategoryListOutputDto, error)
+	// FindAncestors lists the categories above a live category, the
+	// top-level one first: its breadcrumb, without itself.
+	FindAncestors(ctx context.Context, id string) (dto.CategoryListOutputDto, error)
+	// FindSubtree lists a live category and its live descendants, by depth
+	// then by id.
+	FindSubtree(ctx context.Context, id string) (dto.CategoryListOutputDto, error)
+	// Update also moves the category under ParentID, or to the top level.
+	Update(ctx context.Context, category dto.CategoryInputDto) error
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ModuleRepositoryInterface manages the modules of courses. A module is
+// created at the end of its course and stays in it; Update changes its title
+// and description. Deleting a module deletes its lessons for good.
+type ModuleRepositoryInterface interface {
+	Create(ctx context.Context, module dto.ModuleInputDto) (dto.ModuleOutputDto, error)
+	// FindByCourseID lists the modules of a live course, in order.
+	FindByCourseID(ctx context.Context, courseID string) (dto.ModuleListOutputDto, error)
+	Find(ctx context.Context, id string) (dto.ModuleOutputDto, error)
+	Update(ctx context.Context, module dto.ModuleInputDto) error
+	Delete(ctx context.Context, id string) error
+	// Reorder puts the modules of a course in the order of ids, which must
+	// list each of them once.
+	Reorder(ctx context.Context, courseID string, ids []string) error
+}
+
+// LessonRepositoryInterface manages the lessons of modules, the same way
+// ModuleRepositoryInterface manages modules.
+type LessonRepositoryInterface interface {
+	Create(ctx context.Context, lesson dto.LessonInputDto) (dto.LessonOutputDto, error)
+	FindByModuleID(ctx context.Context, moduleID string) (dto.LessonListOutputDto, error)
+	Find(ctx context.Context, id string) (dto.LessonOutputDto, error)
+	Update(ctx context.Context, lesson dto.LessonInputDto) error
+	Delete(ctx context.Context, id string) error
+	Reorder(ctx context.Context, moduleID string, ids []string) error
+}
+
+// EnrollmentRepositoryInterface manages which users follow which courses and
+// how far they have got. Enrollments go away with their user or course when
+// these are purged.
+type EnrollmentRepositoryInterface interface {
+	// Enroll enrolls a live user in a live course, with no progress yet.
+	Enroll(ctx context.Context, enrollment dto.EnrollmentInputDto) (dto.EnrollmentOutputDto, error)
+	Unenroll(ctx context.Context, userID, courseID string) error
+	Find(ctx context.Context, userID, courseID string) (dto.EnrollmentOutputDto, error)
+	// FindByUserID lists the enrollments of a user in live courses, ordered
+	// by course id.
+	FindByUserID(ctx context.Context, userID string, page query.Page) (dto.EnrollmentListOutputDto, error)
+	// FindByCourseID lists the enrollments of live users in a live course,
+	// ordered by user id.
+	FindByCourseID(ctx context.Context, courseID string, page query.Page) (dto.EnrollmentListOutputDto, error)
+	// UpdateProgress sets the progress of an enrollment and marks the course
+	// as accessed now.
+	UpdateProgress(ctx context.Context, enrollment dto.EnrollmentInputDto) error
+}
+
+// InstructorRepositoryInterface manages who teaches which courses. Whoever
+// creates a course is its first instructor, when they are a user.
+type InstructorRepositoryInterface interface {
+	// Add makes a live user an instructor of a live course.
+	Add(ctx context.Context, instructor dto.InstructorInputDto) (dto.InstructorOutputDto, error)
+	// Remove refuses to leave a course without instructors.
+	Remove(ctx context.Context, courseID, userID string) error
+	// FindByCourseID lists the live instructors of a live course, ordered by
+	// user id.
+	FindByCourseID(ctx context.Context, courseID string) (dto.InstructorListOutputDto, error)
+	// Teaches reports whether the user is an instructor of the course,
+	// deleted or not.
+	Teaches(ctx context.Context, userID, courseID string) (bool, error)
+}
+
+// TagRepositoryInterface manages tags and the courses carrying them. Tag
+// names are normalized, see entity.NormalizeTagName, and unique; the course
+// counts of tags leave deleted courses out. Deleting a tag takes it off its
+// courses.
+type TagRepositoryInterface interface {
+	Create(ctx context.Context, tag dto.TagInputDto) (dto.TagOutputDto, error)
+	Find(ctx context.Context, id string) (dto.TagOutputDto, error)
+	// List lists tags by name, those starting with NamePrefix if set.
+	List(ctx context.Context, spec query.TagSpec) (dto.TagListOutputDto, error)
+	Delete(ctx context.Context, id string) error
+	// Tag tags a live course, creating the tag when new.
+	Tag(ctx context.Context, tag dto.CourseTagInputDto) (dto.TagOutputDto, error)
+	Untag(ctx context.Context, courseID, tagID string) error
+	// FindByCourseID lists the tags of a live course, by name.
+	FindByCourseID(ctx context.Context, courseID string) (dto.TagListOutputDto, error)
 }
 
 type UserRepositoryInterface interface {
-	Create(user dto.UserInputDto) (dto.UserOutputDto, error)
-	FindByEmail(email string) (*dto.GetJWTInput, error)
-	FindAll() (dto.UserListOutputDto, error)
-	Find(id string) (dto.UserOutputDto, error)
-	Update(user dto.UserInputDto) error
-	Delete(id string) error
+	Create(ctx context.Context, user dto.UserInputDto) (dto.UserOutputDto, error)
+	FindByEmail(ctx context.Context, email string) (*dto.GetJWTInput, error)
+	// FindIDByEmail returns the id of the live user with the email, which is
+	// the subject of the tokens the APIs issue.
+	FindIDByEmail(ctx context.Context, email string) (string, error)
+	FindAll(ctx context.Context, page query.Page) (dto.UserListOutputDto, error)
+	List(ctx context.Context, spec query.UserSpec) (dto.UserListOutputDto, error)
+	Find(ctx context.Context, id string) (dto.UserOutputDto, error)
+	Update(ctx context.Context, user dto.UserInputDto) error
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// HistoryRepositoryInterface reads the change history of courses and
+// categories. Entries are appended by the repositories making the changes.
+type HistoryRepositoryInterface interface {
+	List(ctx context.Context, spec query.HistorySpec) (dto.HistoryListOutputDto, error)
+}
+
+// SearchRepositoryInterface finds courses and categories by the words of
+// their name and description.
+type SearchRepositoryInterface interface {
+	Search(ctx context.Context, spec query.SearchSpec) (dto.SearchOutputDto, error)
 }
@@ -0,0 +1,28 @@
+package mariadb
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Server errors for a duplicate key, a parent row still referenced and a
+// missing parent row.
+const (
+	erDupEntry         = 1062
+	erRowIsReferenced2 = 1451
+	erNoReferencedRow2 = 1452
+)
+
+func isDuplicate(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erDupEntry
+}
+
+// isForeignKey reports a foreign key violation, from either side of the
+// relation.
+func isForeignKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) &&
+		(mysqlErr.Number == erRowIsReferenced2 || mysqlErr.Number == erNoReferencedRow2)
+}
@@ -0,0 +1,89 @@
+package mariadb
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+type HistoryRepository struct {
+	db query.DBTX
+}
+
+func NewHistoryRepository(db query.DBTX) *HistoryRepository {
+	return &HistoryRepository{db: db}
+}
+
+func (h *HistoryRepository) List(ctx context.Context, spec query.HistorySpec) (dto.HistoryListOutputDto, error) {
+	after, err := historyAfter(spec.Page)
+	if err != nil {
+		return dto.HistoryListOutputDto{}, err
+	}
+	rows, err := h.db.QueryContext(ctx, "SELECT id, action, before_state, after_state, changed_by, changed_at FROM history WHERE entity_type = ? AND entity_id = ? AND id > ? ORDER BY id LIMIT ?",
+		spec.EntityType, spec.EntityID, after, spec.Page.Size()+1)
+	if err != nil {
+		return dto.HistoryListOutputDto{}, err
+	}
+	defer rows.Close()
+	history := dto.HistoryListOutputDto{}
+	for rows.Next() {
+		entry := dto.HistoryOutputDto{EntityType: spec.EntityType, EntityID: spec.EntityID}
+		var id int64
+		var before, after []byte
+		if err := rows.Scan(&id, &entry.Action, &before, &after, &entry.ChangedBy, &entry.ChangedAt); err != nil {
+			return dto.HistoryListOutputDto{}, err
+		}
+		entry.ID, entry.Before, entry.After = strconv.FormatInt(id, 10), before, after
+		history.Entries = append(history.Entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.HistoryListOutputDto{}, err
+	}
+	history.Entries, history.NextCursor = query.Trim(history.Entries, spec.Page,
+		func(e dto.HistoryOutputDto) string { return query.EncodeCursor(e.ID) })
+	return history, nil
+}
+
+// historyAfter returns the history id a page starts after, 0 for the first
+// page.
+func historyAfter(page query.Page) (int64, error) {
+	after, err := page.After()
+	if err != nil || after == "" {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(after, 10, 64)
+	if err != nil {
+		return 0, query.ErrInvalidCursor
+	}
+	return id, nil
+}
+
+const insertHistory = "INSERT INTO history (entity_type, entity_id, action, before_state, after_state, changed_by, changed_at)"
+
+// record appends a change to the history, in the same transaction as the
+// change itself.
+func record(ctx context.Context, q query.DBTX, entityType, entityID, action string, before, after any) error {
+	row, err := historyRow(ctx, entityType, entityID, action, before, after)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, insertHistory+" VALUES (?, ?, ?, ?, ?, ?, ?)", row...)
+	return err
+}
+
+// historyRow is the history row of a change, in insertHistory order.
+func historyRow(ctx context.Context, entityType, entityID, action string, before, after any) ([]any, error) {
+	beforeState, err := audit.Snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterState, err := audit.Snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+	return []any{entityType, entityID, action, beforeState, afterState, audit.Actor(ctx), time.Now().UTC().Truncate(time.Microsecond)}, nil
+}
@@ -0,0 +1,138 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+const instructorColumns = "i.course_id, i.user_id, u.name, u.email, i.added_at"
+
+type Instructor struct {
+	db query.DBTX
+}
+
+func NewInstructorRepository(db query.DBTX) *Instructor {
+	return &Instructor{db: db}
+}
+
+func scanInstructor(row interface{ Scan(...any) error }) (dto.InstructorOutputDto, error) {
+	var instructor dto.InstructorOutputDto
+	err := row.Scan(&instructor.CourseID, &instructor.UserID, &instructor.Name, &instructor.Email, &instructor.AddedAt)
+	return instructor, err
+}
+
+func (i *Instructor) Add(ctx context.Context, instructor dto.InstructorInputDto) (dto.InstructorOutputDto, error) {
+	var added dto.InstructorOutputDto
+	err := query.Atomic(ctx, i.db, func(q query.DBTX) error {
+		live, err := liveUser(ctx, q, instructor.UserID)
+		if err != nil {
+			return err
+		}
+		if !live {
+			return dberr.UnknownInstructorUser
+		}
+		if live, err = liveCourse(ctx, q, instructor.CourseID); err != nil {
+			return err
+		}
+		if !live {
+			return dberr.UnknownInstructorCourse
+		}
+		_, err = q.ExecContext(ctx, "INSERT INTO course_instructors (course_id, user_id, added_at) VALUES (?, ?, ?)",
+			instructor.CourseID, instructor.UserID, time.Now().UTC().Truncate(time.Microsecond))
+		if isDuplicate(err) {
+			return dberr.AlreadyInstructor
+		}
+		if err != nil {
+			return err
+		}
+		added, err = scanInstructor(q.QueryRowContext(ctx, "SELECT "+instructorColumns+" FROM course_instructors i JOIN users u ON u.id = i.user_id WHERE i.course_id = ? AND i.user_id = ?",
+			instructor.CourseID, instructor.UserID))
+		return err
+	})
+	if err != nil {
+		return dto.InstructorOutputDto{}, err
+	}
+	return added, nil
+}
+
+func (i *Instructor) Remove(ctx context.Context, courseID, userID string) error {
+	return query.Atomic(ctx, i.db, func(q query.DBTX) error {
+		result, err := q.ExecContext(ctx, "DELETE FROM course_instructors WHERE course_id = ? AND user_id = ?", courseID, userID)
+		if err != nil {
+			return err
+		}
+		if err := dberr.Affected(result, "instructor"); err != nil {
+			return err
+		}
+		var left int
+		if err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM course_instructors WHERE course_id = ?", courseID).Scan(&left); err != nil {
+			return err
+		}
+		if left == 0 {
+			return dberr.LastInstructor
+		}
+		return nil
+	})
+}
+
+func (i *Instructor) FindByCourseID(ctx context.Context, courseID string) (dto.InstructorListOutputDto, error) {
+	live, err := liveCourse(ctx, i.db, courseID)
+	if err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	if !live {
+		return dto.InstructorListOutputDto{}, dberr.NotFound("course")
+	}
+	rows, err := i.db.QueryContext(ctx, "SELECT "+instructorColumns+" FROM course_instructors i JOIN users u ON u.id = i.user_id WHERE i.course_id = ? AND u.deleted_at IS NULL ORDER BY i.user_id", courseID)
+	if err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	defer rows.Close()
+	instructors := dto.InstructorListOutputDto{}
+	for rows.Next() {
+		instructor, err := scanInstructor(rows)
+		if err != nil {
+			return dto.InstructorListOutputDto{}, err
+		}
+		instructors.Instructors = append(instructors.Instructors, instructor)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	return instructors, nil
+}
+
+func (i *Instructor) Teaches(ctx context.Context, userID, courseID string) (bool, error) {
+	var count int
+	err := i.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM course_instructors WHERE course_id = ? AND user_id = ?", courseID, userID).Scan(&count)
+	return count > 0, err
+}
+
+// addCreator makes the live user the changes of ctx are attributed to, if
+// any, the first instructor of new courses.
+func addCreator(ctx context.Context, q query.DBTX, addedAt time.Time, courseIDs ...string) error {
+	actor := audit.Actor(ctx)
+	if actor == "" || len(courseIDs) == 0 {
+		return nil
+	}
+	var userID string
+	err := q.QueryRowContext(ctx, "SELECT id FROM users WHERE email = ? AND deleted_at IS NULL", actor).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	rows := make([][]any, len(courseIDs))
+	for i, courseID := range courseIDs {
+		rows[i] = []any{courseID, userID, addedAt}
+	}
+	return query.InsertRows(ctx, q, query.Question, "INSERT INTO course_instructors (course_id, user_id, added_at)", rows)
+}
@@ -0,0 +1,64 @@
+package mariadb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+type SearchRepository struct {
+	db query.DBTX
+}
+
+func NewSearchRepository(db query.DBTX) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// searchSelect matches one table against the FULLTEXT index of migration 9.
+// Every term is required; words shorter than the server's minimum token
+// length are not indexed and match nothing.
+const searchSelect = "SELECT '%s' AS entity_type, id, name, description, MATCH (name, description) AGAINST (? IN BOOLEAN MODE) AS score FROM %s WHERE deleted_at IS NULL AND MATCH (name, description) AGAINST (? IN BOOLEAN MODE)"
+
+func (s *SearchRepository) Search(ctx context.Context, spec query.SearchSpec) (dto.SearchOutputDto, error) {
+	terms, err := spec.Terms()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	offset, err := spec.Page.Offset()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	against := "+" + strings.Join(terms, " +")
+	var selects []string
+	var args []any
+	for _, table := range []struct{ entityType, name string }{{"category", "categories"}, {"course", "courses"}} {
+		if spec.Includes(table.entityType) {
+			selects = append(selects, fmt.Sprintf(searchSelect, table.entityType, table.name))
+			args = append(args, against, against)
+		}
+	}
+	args = append(args, spec.Page.Size()+1, offset)
+	rows, err := s.db.QueryContext(ctx, "SELECT entity_type, id, name, description, score FROM ("+strings.Join(selects, " UNION ALL ")+") AS results ORDER BY score DESC, entity_type, id LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	defer rows.Close()
+	results := dto.SearchOutputDto{}
+	for rows.Next() {
+		var result dto.SearchResultOutputDto
+		var description string
+		if err := rows.Scan(&result.EntityType, &result.ID, &result.Name, &description, &result.Score); err != nil {
+			return dto.SearchOutputDto{}, err
+		}
+		result.Snippet = query.Snippet(terms, result.Name, description)
+		results.Results = append(results.Results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	results.Results, results.NextCursor = query.TrimOffset(results.Results, spec.Page, offset)
+	return results, nil
+}
@@ -0,0 +1,158 @@
+package mariadb
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/entity"
+	"github.com/google/uuid"
+)
+
+// tagColumns count the live courses of each tag.
+const tagColumns = "id, name, (SELECT COUNT(*) FROM course_tags ct JOIN courses c ON c.id = ct.course_id WHERE ct.tag_id = tags.id AND c.deleted_at IS NULL), created_at, created_by"
+
+type Tag struct {
+	db query.DBTX
+}
+
+func NewTagRepository(db query.DBTX) *Tag {
+	return &Tag{db: db}
+}
+
+func scanTag(row interface{ Scan(...any) error }) (dto.TagOutputDto, error) {
+	var tag dto.TagOutputDto
+	err := row.Scan(&tag.ID, &tag.Name, &tag.Courses, &tag.CreatedAt, &tag.CreatedBy)
+	return tag, err
+}
+
+func (t *Tag) Create(ctx context.Context, tag dto.TagInputDto) (dto.TagOutputDto, error) {
+	name := entity.NormalizeTagName(tag.Name)
+	if err := dberr.ValidateTag(name); err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	id := uuid.New().String()
+	_, err := t.db.ExecContext(ctx, "INSERT INTO tags (id, name, created_at, created_by) VALUES (?, ?, ?, ?)",
+		id, name, time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx))
+	if isDuplicate(err) {
+		return dto.TagOutputDto{}, dberr.TagExists
+	}
+	if err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	return t.Find(ctx, id)
+}
+
+func (t *Tag) Find(ctx context.Context, id string) (dto.TagOutputDto, error) {
+	tag, err := scanTag(t.db.QueryRowContext(ctx, "SELECT "+tagColumns+" FROM tags WHERE id = ?", id))
+	if err != nil {
+		return dto.TagOutputDto{}, dberr.NoRows("tag", err)
+	}
+	return tag, nil
+}
+
+func (t *Tag) List(ctx context.Context, spec query.TagSpec) (dto.TagListOutputDto, error) {
+	stmt, err := spec.Select(query.MariaDB, tagColumns, "tags")
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	tags, err := t.list(ctx, stmt.SQL, stmt.Args...)
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	byName := query.Sort{Field: query.SortByName}
+	tags.Tags, tags.NextCursor = query.Trim(tags.Tags, spec.Page,
+		func(t dto.TagOutputDto) string { return byName.Cursor(t.ID, t.Name, t.CreatedAt) })
+	return tags, nil
+}
+
+func (t *Tag) Delete(ctx context.Context, id string) error {
+	result, err := t.db.ExecContext(ctx, "DELETE FROM tags WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return dberr.Affected(result, "tag")
+}
+
+func (t *Tag) Tag(ctx context.Context, tag dto.CourseTagInputDto) (dto.TagOutputDto, error) {
+	name := entity.NormalizeTagName(tag.Name)
+	if err := dberr.ValidateTag(name); err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	var tagged dto.TagOutputDto
+	err := query.Atomic(ctx, t.db, func(q query.DBTX) error {
+		live, err := liveCourse(ctx, q, tag.CourseID)
+		if err != nil {
+			return err
+		}
+		if !live {
+			return dberr.UnknownTagCourse
+		}
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		// a tag created meanwhile by someone else is used as is
+		_, err = q.ExecContext(ctx, "INSERT IGNORE INTO tags (id, name, created_at, created_by) VALUES (?, ?, ?, ?)",
+			uuid.New().String(), name, now, audit.Actor(ctx))
+		if err != nil {
+			return err
+		}
+		var tagID string
+		if err := q.QueryRowContext(ctx, "SELECT id FROM tags WHERE name = ?", name).Scan(&tagID); err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, "INSERT INTO course_tags (course_id, tag_id, added_at) VALUES (?, ?, ?)", tag.CourseID, tagID, now)
+		if isDuplicate(err) {
+			return dberr.AlreadyTagged
+		}
+		if err != nil {
+			return err
+		}
+		tagged, err = scanTag(q.QueryRowContext(ctx, "SELECT "+tagColumns+" FROM tags WHERE id = ?", tagID))
+		return err
+	})
+	if err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	return tagged, nil
+}
+
+func (t *Tag) Untag(ctx context.Context, courseID, tagID string) error {
+	result, err := t.db.ExecContext(ctx, "DELETE FROM course_tags WHERE course_id = ? AND tag_id = ?", courseID, tagID)
+	if err != nil {
+		return err
+	}
+	return dberr.Affected(result, "course tag")
+}
+
+func (t *Tag) FindByCourseID(ctx context.Context, courseID string) (dto.TagListOutputDto, error) {
+	live, err := liveCourse(ctx, t.db, courseID)
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	if !live {
+		return dto.TagListOutputDto{}, dberr.NotFound("course")
+	}
+	return t.list(ctx, "SELECT "+tagColumns+" FROM tags WHERE id IN (SELECT tag_id FROM course_tags WHERE course_id = ?) ORDER BY name", courseID)
+}
+
+func (t *Tag) list(ctx context.Context, stmt string, args ...any) (dto.TagListOutputDto, error) {
+	rows, err := t.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	defer rows.Close()
+	tags := dto.TagListOutputDto{}
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return dto.TagListOutputDto{}, err
+		}
+		tags.Tags = append(tags.Tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	return tags, nil
+}
@@ -1,85 +1,170 @@
 package mariadb
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"time"
 
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
 	"github.com/antoniofmoliveira/courses/dto"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 )
 
+const userColumns = "id, name, email, created_at, updated_at, created_by, updated_by, version, deleted_at"
+
 type UserRepository struct {
-	db *sql.DB
+	db query.DBTX
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
-	c := &UserRepository{
+func NewUserRepository(db query.DBTX) *UserRepository {
+	return &UserRepository{
 		db: db,
 	}
-	c.db.Exec("CREATE TABLE IF NOT EXISTS users (id CHAR(36) PRIMARY KEY, name TEXT, email TEXT, password TEXT)")
-	return c
 }
 
-func (r *UserRepository) FindByEmail(email string) (*dto.GetJWTInput, error) {
+func scanUser(row interface{ Scan(...any) error }) (dto.UserOutputDto, error) {
+	var user dto.UserOutputDto
+	err := row.Scan(&user.ID, &user.Name, &user.Email,
+		&user.CreatedAt, &user.UpdatedAt, &user.CreatedBy, &user.UpdatedBy, &user.Version, &user.DeletedAt)
+	return user, err
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*dto.GetJWTInput, error) {
 	var password string
-	err := r.db.QueryRow("SELECT password FROM users WHERE email = ?", email).
+	err := r.db.QueryRowContext(ctx, "SELECT password FROM users WHERE email = ? AND deleted_at IS NULL", email).
 		Scan(&password)
 	if err != nil {
-		return nil, err
+		return nil, dberr.NoRows("user", err)
 	}
 	return &dto.GetJWTInput{Email: email, Password: password}, nil
 }
 
-func (r *UserRepository) Create(user dto.UserInputDto) (dto.UserOutputDto, error) {
-	id := uuid.New().String()
-	_, err := r.db.Exec("INSERT INTO users (id, name, email, password) VALUES (?,?, ?, ?)",
-		id, user.Name, user.Email, user.Password)
+func (r *UserRepository) FindIDByEmail(ctx context.Context, email string) (string, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM users WHERE email = ? AND deleted_at IS NULL", email).Scan(&id)
 	if err != nil {
+		return "", dberr.NoRows("user", err)
+	}
+	return id, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user dto.UserInputDto) (dto.UserOutputDto, error) {
+	if err := dberr.ValidateUser(user); err != nil {
 		return dto.UserOutputDto{}, err
 	}
-	return dto.UserOutputDto{ID: id, Name: user.Name, Email: user.Email}, nil
+	now, actor := time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx)
+	created := dto.UserOutputDto{
+		ID:        uuid.New().String(),
+		Name:      user.Name,
+		Email:     user.Email,
+		CreatedAt: now,
+		UpdatedAt: now,
+		CreatedBy: actor,
+		UpdatedBy: actor,
+		Version:   1,
+	}
+	_, err := r.db.ExecContext(ctx, "INSERT INTO users (id, name, email, password, created_at, updated_at, created_by, updated_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		created.ID, user.Name, user.Email, user.Password, now, now, actor, actor)
+	if err != nil {
+		if isDuplicate(err) {
+			return dto.UserOutputDto{}, dberr.Wrap(dberr.ErrConflict, "email already in use", err)
+		}
+		return dto.UserOutputDto{}, err
+	}
+	return created, nil
 }
 
-func (r *UserRepository) Delete(id string) error {
-	_, err := r.db.Exec("DELETE FROM users WHERE id = ?", id)
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET version = version + 1, deleted_at = ?, updated_at = ?, updated_by = ? WHERE id = ? AND deleted_at IS NULL",
+		now, now, audit.Actor(ctx), id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return dberr.Affected(result, "user")
 }
 
-func (r *UserRepository) Update(user dto.UserInputDto) error {
-	_, err := r.db.Exec("UPDATE users SET name = ?, email = ?, password = ? WHERE id = ?",
-		user.Name, user.Email, user.Password, user.ID)
+func (r *UserRepository) Update(ctx context.Context, user dto.UserInputDto) error {
+	if err := dberr.ValidateUser(user); err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET version = version + 1, name = ?, email = ?, password = ?, updated_at = ?, updated_by = ? WHERE id = ? AND deleted_at IS NULL AND (? = 0 OR version = ?)",
+		user.Name, user.Email, user.Password, time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx), user.ID, user.Version, user.Version)
 	if err != nil {
+		if isDuplicate(err) {
+			return dberr.Wrap(dberr.ErrConflict, "email already in use", err)
+		}
 		return err
 	}
-	return nil
+	err = dberr.Affected(result, "user")
+	if errors.Is(err, dberr.ErrNotFound) && user.Version != 0 {
+		// the guard may have failed on the version rather than the id
+		if _, findErr := r.Find(ctx, user.ID); findErr == nil {
+			return dberr.Stale("user")
+		}
+	}
+	return err
 }
 
-func (r *UserRepository) FindAll() (dto.UserListOutputDto, error) {
-	rows, err := r.db.Query("SELECT id, name, email FROM users")
+func (r *UserRepository) FindAll(ctx context.Context, page query.Page) (dto.UserListOutputDto, error) {
+	return r.List(ctx, query.UserSpec{Page: page})
+}
+
+func (r *UserRepository) List(ctx context.Context, spec query.UserSpec) (dto.UserListOutputDto, error) {
+	page := spec.Page
+	after, err := page.After()
+	if err != nil {
+		return dto.UserListOutputDto{}, err
+	}
+	live := " AND deleted_at IS NULL"
+	if spec.IncludeDeleted {
+		live = ""
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE id > ?"+live+" ORDER BY id LIMIT ?",
+		after, page.Size()+1)
 	if err != nil {
 		return dto.UserListOutputDto{}, err
 	}
 	defer rows.Close()
 	var users dto.UserListOutputDto
 	for rows.Next() {
-		var user dto.UserOutputDto
-		if err := rows.Scan(&user.ID, &user.Name, &user.Email); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return dto.UserListOutputDto{}, err
 		}
 		users.Users = append(users.Users, user)
 	}
+	users.Users, users.NextCursor = query.Trim(users.Users, page,
+		func(u dto.UserOutputDto) string { return query.EncodeCursor(u.ID) })
 	return users, nil
 }
 
-func (r *UserRepository) Find(id string) (dto.UserOutputDto, error) {
-	var user dto.UserOutputDto
-	err := r.db.QueryRow("SELECT id, name, email FROM users WHERE id = ?", id).
-		Scan(&user.ID, &user.Name, &user.Email)
+func (r *UserRepository) Find(ctx context.Context, id string) (dto.UserOutputDto, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = ? AND deleted_at IS NULL", id))
 	if err != nil {
-		return dto.UserOutputDto{}, err
+		return dto.UserOutputDto{}, dberr.NoRows("user", err)
 	}
 	return user, nil
 }
+
+// Restore brings back a deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET version = version + 1, deleted_at = NULL, updated_at = ?, updated_by = ? WHERE id = ? AND deleted_at IS NOT NULL",
+		time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx), id)
+	if err != nil {
+		return err
+	}
+	return dberr.Affected(result, "user")
+}
+
+// Purge removes users deleted before the given time.
+func (r *UserRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM users WHERE deleted_at < ?", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,322 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+type CategoryRepository struct {
+	store    *Store
+	tx       *Tx
+	onDelete query.OnDelete
+}
+
+func NewCategoryRepository(store *Store, onDelete query.OnDelete) *CategoryRepository {
+	return &CategoryRepository{store: store, onDelete: onDelete}
+}
+
+func categoryRow(c dto.CategoryOutputDto) row {
+	return row{id: c.ID, name: c.Name, createdAt: c.CreatedAt}
+}
+
+func (c *CategoryRepository) Create(ctx context.Context, categoryDto dto.CategoryInputDto) (dto.CategoryOutputDto, error) {
+	if err := dberr.ValidateCategory(categoryDto); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	category := dto.CategoryOutputDto{
+		ID:          uuid.New().String(),
+		Name:        categoryDto.Name,
+		Description: categoryDto.Description,
+		ParentID:    categoryDto.ParentID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	if err := c.store.checkParent("", category.ParentID); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	c.store.categories[category.ID] = category
+	c.tx.record(func() { delete(c.store.categories, category.ID) })
+	if err := c.store.appendHistory(ctx, c.tx, audit.Category, category.ID, audit.Create, nil, category); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	return category, nil
+}
+
+func (c *CategoryRepository) FindAll(ctx context.Context, page query.Page) (dto.CategoryListOutputDto, error) {
+	return c.List(ctx, query.CategorySpec{Page: page})
+}
+
+func (c *CategoryRepository) List(ctx context.Context, spec query.CategorySpec) (dto.CategoryListOutputDto, error) {
+	c.store.mu.RLock()
+	var matches []dto.CategoryOutputDto
+	for _, category := range c.store.categories {
+		if category.DeletedAt != nil && !spec.IncludeDeleted {
+			continue
+		}
+		if spec.ParentID != "" && category.ParentID != spec.ParentID {
+			continue
+		}
+		if spec.NamePrefix == "" || hasPrefix(category.Name, spec.NamePrefix) {
+			matches = append(matches, category)
+		}
+	}
+	c.store.mu.RUnlock()
+	items, next, err := list(matches, categoryRow, spec.Sort, spec.Page)
+	if err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	return dto.CategoryListOutputDto{Categories: items, NextCursor: next}, nil
+}
+
+func (c *CategoryRepository) FindByCourseID(ctx context.Context, courseID string) (dto.CategoryOutputDto, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	course, ok := c.store.courses[courseID]
+	if !ok || course.DeletedAt != nil {
+		return dto.CategoryOutputDto{}, dberr.NotFound("category")
+	}
+	category, ok := c.store.categories[course.CategoryID]
+	if !ok || category.DeletedAt != nil {
+		return dto.CategoryOutputDto{}, dberr.NotFound("category")
+	}
+	return category, nil
+}
+
+func (c *CategoryRepository) Find(ctx context.Context, id string) (dto.CategoryOutputDto, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	category, ok := c.store.categories[id]
+	if !ok || category.DeletedAt != nil {
+		return dto.CategoryOutputDto{}, dberr.NotFound("category")
+	}
+	return category, nil
+}
+
+func (c *CategoryRepository) FindChildren(ctx context.Context, id string, page query.Page) (dto.CategoryListOutputDto, error) {
+	return c.List(ctx, query.CategorySpec{ParentID: id, Page: page})
+}
+
+func (c *CategoryRepository) FindAncestors(ctx context.Context, id string) (dto.CategoryListOutputDto, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	category, ok := c.store.categories[id]
+	if !ok || category.DeletedAt != nil {
+		return dto.CategoryListOutputDto{}, dberr.NotFound("category")
+	}
+	var ancestors []dto.CategoryOutputDto
+	for category.ParentID != "" {
+		if category, ok = c.store.categories[category.ParentID]; !ok {
+			break
+		}
+		ancestors = append(ancestors, category)
+	}
+	slices.Reverse(ancestors)
+	return dto.CategoryListOutputDto{Categories: ancestors}, nil
+}
+
+func (c *CategoryRepository) FindSubtree(ctx context.Context, id string) (dto.CategoryListOutputDto, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	category, ok := c.store.categories[id]
+	if !ok || category.DeletedAt != nil {
+		return dto.CategoryListOutputDto{}, dberr.NotFound("category")
+	}
+	tree := []dto.CategoryOutputDto{category}
+	for level := tree; len(level) > 0; {
+		var next []dto.CategoryOutputDto
+		for _, parent := range level {
+			for _, child := range c.store.categories {
+				if child.ParentID == parent.ID && child.DeletedAt == nil {
+					next = append(next, child)
+				}
+			}
+		}
+		slices.SortFunc(next, func(a, b dto.CategoryOutputDto) int { return strings.Compare(a.ID, b.ID) })
+		tree, level = append(tree, next...), next
+	}
+	return dto.CategoryListOutputDto{Categories: tree}, nil
+}
+
+func (c *CategoryRepository) Update(ctx context.Context, categoryDto dto.CategoryInputDto) error {
+	if err := dberr.ValidateCategory(categoryDto); err != nil {
+		return err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	category, ok := c.store.categories[categoryDto.ID]
+	if !ok || category.DeletedAt != nil {
+		return dberr.NotFound("category")
+	}
+	if err := dberr.CheckVersion("category", categoryDto.Version, category.Version); err != nil {
+		return err
+	}
+	if err := c.store.checkParent(category.ID, categoryDto.ParentID); err != nil {
+		return err
+	}
+	previous := category
+	category.Name = categoryDto.Name
+	category.Description = categoryDto.Description
+	category.ParentID = categoryDto.ParentID
+	category.UpdatedAt, category.UpdatedBy = time.Now().UTC(), audit.Actor(ctx)
+	category.Version++
+	c.store.categories[category.ID] = category
+	c.tx.record(func() { c.store.categories[previous.ID] = previous })
+	return c.store.appendHistory(ctx, c.tx, audit.Category, category.ID, audit.Update, previous, category)
+}
+
+func (c *CategoryRepository) Delete(ctx context.Context, id string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	category, ok := c.store.categories[id]
+	if !ok || category.DeletedAt != nil {
+		return dberr.NotFound("category")
+	}
+	for _, child := range c.store.categories {
+		if child.ParentID == id && child.DeletedAt == nil {
+			return dberr.CategoryHasChildren
+		}
+	}
+	var live []dto.CourseOutputDto
+	for _, course := range c.store.courses {
+		if course.CategoryID == id && course.DeletedAt == nil {
+			live = append(live, course)
+		}
+	}
+	if len(live) > 0 && c.onDelete != query.Cascade {
+		return dberr.CategoryHasCourses
+	}
+	deletedAt := time.Now().UTC()
+	for _, course := range live {
+		if err := c.store.deleteCourse(ctx, c.tx, course, deletedAt); err != nil {
+			return err
+		}
+	}
+	previous := category
+	category.DeletedAt, category.UpdatedAt, category.UpdatedBy = &deletedAt, deletedAt, audit.Actor(ctx)
+	category.Version++
+	c.store.categories[id] = category
+	c.tx.record(func() { c.store.categories[id] = previous })
+	return c.store.appendHistory(ctx, c.tx, audit.Category, id, audit.Delete, previous, category)
+}
+
+// Restore brings back a deleted category, provided its parent is not
+// deleted. Its courses stay deleted.
+func (c *CategoryRepository) Restore(ctx context.Context, id string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	category, ok := c.store.categories[id]
+	if !ok || category.DeletedAt == nil {
+		return dberr.NotFound("category")
+	}
+	if err := c.store.checkParent("", category.ParentID); err != nil {
+		return err
+	}
+	previous := category
+	category.DeletedAt, category.UpdatedAt, category.UpdatedBy = nil, time.Now().UTC(), audit.Actor(ctx)
+	category.Version++
+	c.store.categories[id] = category
+	c.tx.record(func() { c.store.categories[id] = previous })
+	return c.store.appendHistory(ctx, c.tx, audit.Category, id, audit.Restore, previous, category)
+}
+
+// Purge removes categories deleted before the given time. Categories still
+// referenced by a course are kept until the course is purged; the
+// subcategories of a purged category move to the top level.
+func (c *CategoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	referenced := map[string]bool{}
+	for _, course := range c.store.courses {
+		referenced[course.CategoryID] = true
+	}
+	var purged int64
+	for id, category := range c.store.categories {
+		if category.DeletedAt == nil || !category.DeletedAt.Before(before) || referenced[id] {
+			continue
+		}
+		delete(c.store.categories, id)
+		c.tx.record(func() { c.store.categories[id] = category })
+		purged++
+	}
+	for id, category := range c.store.categories {
+		if _, ok := c.store.categories[category.ParentID]; category.ParentID == "" || ok {
+			continue
+		}
+		previous := category
+		category.ParentID = ""
+		c.store.categories[id] = category
+		c.tx.record(func() { c.store.categories[id] = previous })
+	}
+	return purged, nil
+}
+
+// CreateBatch creates categories in one transaction. Invalid categories are
+// rejected in their result and the others created.
+func (c *CategoryRepository) CreateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error) {
+	return c.store.batch(c.tx, len(categories), func(tx *Tx, i int) (string, error) {
+		created, err := tx.CategoryRepository(c.onDelete).Create(ctx, categories[i])
+		if err != nil {
+			return "", err
+		}
+		return created.ID, nil
+	})
+}
+
+func (c *CategoryRepository) UpdateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error) {
+	return c.store.batch(c.tx, len(categories), func(tx *Tx, i int) (string, error) {
+		return categories[i].ID, tx.CategoryRepository(c.onDelete).Update(ctx, categories[i])
+	})
+}
+
+func (c *CategoryRepository) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	return c.store.batch(c.tx, len(ids), func(tx *Tx, i int) (string, error) {
+		return ids[i], tx.CategoryRepository(c.onDelete).Delete(ctx, ids[i])
+	})
+}
+
+// checkParent rejects a parent that does not exist or is deleted and, when
+// moving the category id, one that is the category itself or one of its
+// subcategories. Callers hold the store lock.
+func (s *Store) checkParent(id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	parent, ok := s.categories[parentID]
+	if !ok || parent.DeletedAt != nil {
+		return dberr.UnknownParentCategory
+	}
+	if id != "" && s.subtree(id)[parentID] {
+		return dberr.CategoryCycle
+	}
+	return nil
+}
+
+// subtree returns the ids of a category and of its live descendants.
+// Callers hold the store lock.
+func (s *Store) subtree(id string) map[string]bool {
+	ids := map[string]bool{id: true}
+	for grown := true; grown; {
+		grown = false
+		for _, category := range s.categories {
+			if ids[category.ParentID] && !ids[category.ID] && category.DeletedAt == nil {
+				ids[category.ID] = true
+				grown = true
+			}
+		}
+	}
+	return ids
+}
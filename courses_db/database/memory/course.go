@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+type Course struct {
+	store *Store
+	tx    *Tx
+}
+
+func NewCourseRepository(store *Store) *Course {
+	return &Course{store: store}
+}
+
+func courseRow(c dto.CourseOutputDto) row {
+	return row{id: c.ID, name: c.Name, createdAt: c.CreatedAt}
+}
+
+func (c *Course) Create(ctx context.Context, courseDto dto.CourseInputDto) (*dto.CourseOutputDto, error) {
+	if err := dberr.ValidateCourse(courseDto); err != nil {
+		return nil, err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	course := dto.CourseOutputDto{
+		ID:          uuid.New().String(),
+		Name:        courseDto.Name,
+		Description: courseDto.Description,
+		CategoryID:  courseDto.CategoryID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	if !c.liveCategory(course.CategoryID) {
+		return nil, dberr.UnknownCategory
+	}
+	c.store.courses[course.ID] = course
+	c.tx.record(func() { delete(c.store.courses, course.ID) })
+	c.store.addCreator(ctx, c.tx, course.ID, now)
+	if err := c.store.appendHistory(ctx, c.tx, audit.Course, course.ID, audit.Create, nil, course); err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+func (c *Course) FindAll(ctx context.Context, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{Page: page})
+}
+
+func (c *Course) FindByCategoryID(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{CategoryID: categoryID, Page: page})
+}
+
+func (c *Course) FindByCategoryTree(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{CategoryTree: categoryID, Page: page})
+}
+
+func (c *Course) FindByInstructorID(ctx context.Context, userID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{InstructorID: userID, Page: page})
+}
+
+func (c *Course) List(ctx context.Context, spec query.CourseSpec) (dto.CourseListOutputDto, error) {
+	c.store.mu.RLock()
+	var tree map[string]bool
+	if spec.CategoryTree != "" {
+		tree = c.store.subtree(spec.CategoryTree)
+	}
+	tags := query.TagNames(spec.Tags)
+	var matches []dto.CourseOutputDto
+	for _, course := range c.store.courses {
+		if course.DeletedAt != nil && !spec.IncludeDeleted {
+			continue
+		}
+		if spec.NamePrefix != "" && !hasPrefix(course.Name, spec.NamePrefix) {
+			continue
+		}
+		if spec.CategoryID != "" && course.CategoryID != spec.CategoryID {
+			continue
+		}
+		if spec.CategoryTree != "" && !tree[course.CategoryID] {
+			continue
+		}
+		if _, ok := c.store.instructors[instructorKey{course: course.ID, user: spec.InstructorID}]; spec.InstructorID != "" && !ok {
+			continue
+		}
+		if len(tags) > 0 && !c.store.tagged(course.ID, tags, spec.AllTags) {
+			continue
+		}
+		matches = append(matches, course)
+	}
+	c.store.mu.RUnlock()
+	items, next, err := list(matches, courseRow, spec.Sort, spec.Page)
+	if err != nil {
+		return dto.CourseListOutputDto{}, err
+	}
+	return dto.CourseListOutputDto{Courses: items, NextCursor: next}, nil
+}
+
+func (c *Course) Find(ctx context.Context, id string) (dto.CourseOutputDto, error) {
+	c.store.mu.RLock()
+	defer c.store.mu.RUnlock()
+	course, ok := c.store.courses[id]
+	if !ok || course.DeletedAt != nil {
+		return dto.CourseOutputDto{}, dberr.NotFound("course")
+	}
+	return course, nil
+}
+
+func (c *Course) Update(ctx context.Context, courseDto dto.CourseInputDto) error {
+	if err := dberr.ValidateCourse(courseDto); err != nil {
+		return err
+	}
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	course, ok := c.store.courses[courseDto.ID]
+	if !ok || course.DeletedAt != nil {
+		return dberr.NotFound("course")
+	}
+	if err := dberr.CheckVersion("course", courseDto.Version, course.Version); err != nil {
+		return err
+	}
+	if !c.liveCategory(courseDto.CategoryID) {
+		return dberr.UnknownCategory
+	}
+	previous := course
+	course.Name = courseDto.Name
+	course.Description = courseDto.Description
+	course.CategoryID = courseDto.CategoryID
+	course.UpdatedAt, course.UpdatedBy = time.Now().UTC(), audit.Actor(ctx)
+	course.Version++
+	c.store.courses[course.ID] = course
+	c.tx.record(func() { c.store.courses[previous.ID] = previous })
+	return c.store.appendHistory(ctx, c.tx, audit.Course, course.ID, audit.Update, previous, course)
+}
+
+func (c *Course) Delete(ctx context.Context, id string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	course, ok := c.store.courses[id]
+	if !ok || course.DeletedAt != nil {
+		return dberr.NotFound("course")
+	}
+	return c.store.deleteCourse(ctx, c.tx, course, time.Now().UTC())
+}
+
+// deleteCourse soft deletes a live course. Callers hold the store lock.
+func (s *Store) deleteCourse(ctx context.Context, tx *Tx, course dto.CourseOutputDto, deletedAt time.Time) error {
+	previous := course
+	course.DeletedAt, course.UpdatedAt, course.UpdatedBy = &deletedAt, deletedAt, audit.Actor(ctx)
+	course.Version++
+	s.courses[course.ID] = course
+	tx.record(func() { s.courses[previous.ID] = previous })
+	return s.appendHistory(ctx, tx, audit.Course, course.ID, audit.Delete, previous, course)
+}
+
+// Restore brings back a deleted course, provided its category is not deleted.
+func (c *Course) Restore(ctx context.Context, id string) error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	course, ok := c.store.courses[id]
+	if !ok || course.DeletedAt == nil {
+		return dberr.NotFound("course")
+	}
+	if !c.liveCategory(course.CategoryID) {
+		return dberr.UnknownCategory
+	}
+	previous := course
+	course.DeletedAt, course.UpdatedAt, course.UpdatedBy = nil, time.Now().UTC(), audit.Actor(ctx)
+	course.Version++
+	c.store.courses[id] = course
+	c.tx.record(func() { c.store.courses[id] = previous })
+	return c.store.appendHistory(ctx, c.tx, audit.Course, id, audit.Restore, previous, course)
+}
+
+// Purge removes courses deleted before the given time, with their modules,
+// enrollments, instructors and tags.
+func (c *Course) Purge(ctx context.Context, before time.Time) (int64, error) {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	var purged int64
+	for id, course := range c.store.courses {
+		if course.DeletedAt == nil || !course.DeletedAt.Before(before) {
+			continue
+		}
+		delete(c.store.courses, id)
+		c.tx.record(func() { c.store.courses[id] = course })
+		for _, module := range c.store.modules {
+			if module.CourseID == id {
+				c.store.deleteModule(c.tx, module)
+			}
+		}
+		c.store.deleteEnrollments(c.tx, func(key enrollmentKey) bool { return key.course == id })
+		c.store.deleteInstructors(c.tx, func(key instructorKey) bool { return key.course == id })
+		c.store.deleteCourseTags(c.tx, func(key courseTagKey) bool { return key.course == id })
+		purged++
+	}
+	return purged, nil
+}
+
+// liveCategory reports whether the category exists and is not deleted.
+// Callers hold the store lock.
+func (c *Course) liveCategory(id string) bool {
+	category, ok := c.store.categories[id]
+	return ok && category.DeletedAt == nil
+}
+
+// CreateBatch creates courses in one transaction. Invalid courses are
+// rejected in their result and the others created.
+func (c *Course) CreateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	return c.store.batch(c.tx, len(courses), func(tx *Tx, i int) (string, error) {
+		created, err := tx.CourseRepository().Create(ctx, courses[i])
+		if err != nil {
+			return "", err
+		}
+		return created.ID, nil
+	})
+}
+
+func (c *Course) UpdateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	return c.store.batch(c.tx, len(courses), func(tx *Tx, i int) (string, error) {
+		return courses[i].ID, tx.CourseRepository().Update(ctx, courses[i])
+	})
+}
+
+func (c *Course) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	return c.store.batch(c.tx, len(ids), func(tx *Tx, i int) (string, error) {
+		return ids[i], tx.CourseRepository().Delete(ctx, ids[i])
+	})
+}
@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+type enrollmentKey struct {
+	user, course string
+}
+
+type Enrollment struct {
+	store *Store
+	tx    *Tx
+}
+
+func NewEnrollmentRepository(store *Store) *Enrollment {
+	return &Enrollment{store: store}
+}
+
+func (e *Enrollment) Enroll(ctx context.Context, enrollmentDto dto.EnrollmentInputDto) (dto.EnrollmentOutputDto, error) {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	if u, ok := e.store.users[enrollmentDto.UserID]; !ok || u.DeletedAt != nil {
+		return dto.EnrollmentOutputDto{}, dberr.UnknownEnrollmentUser
+	}
+	if !e.store.liveCourse(enrollmentDto.CourseID) {
+		return dto.EnrollmentOutputDto{}, dberr.UnknownEnrollmentCourse
+	}
+	key := enrollmentKey{user: enrollmentDto.UserID, course: enrollmentDto.CourseID}
+	if _, ok := e.store.enrollments[key]; ok {
+		return dto.EnrollmentOutputDto{}, dberr.AlreadyEnrolled
+	}
+	enrollment := dto.EnrollmentOutputDto{
+		UserID:     key.user,
+		CourseID:   key.course,
+		EnrolledAt: time.Now().UTC(),
+	}
+	e.store.enrollments[key] = enrollment
+	e.tx.record(func() { delete(e.store.enrollments, key) })
+	return enrollment, nil
+}
+
+func (e *Enrollment) Unenroll(ctx context.Context, userID, courseID string) error {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	key := enrollmentKey{user: userID, course: courseID}
+	enrollment, ok := e.store.enrollments[key]
+	if !ok {
+		return dberr.NotFound("enrollment")
+	}
+	delete(e.store.enrollments, key)
+	e.tx.record(func() { e.store.enrollments[key] = enrollment })
+	return nil
+}
+
+func (e *Enrollment) Find(ctx context.Context, userID, courseID string) (dto.EnrollmentOutputDto, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+	enrollment, ok := e.store.enrollments[enrollmentKey{user: userID, course: courseID}]
+	if !ok {
+		return dto.EnrollmentOutputDto{}, dberr.NotFound("enrollment")
+	}
+	return enrollment, nil
+}
+
+func (e *Enrollment) FindByUserID(ctx context.Context, userID string, page query.Page) (dto.EnrollmentListOutputDto, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+	return e.list(page, func(key enrollmentKey) (string, bool) {
+		return key.course, key.user == userID && e.store.liveCourse(key.course)
+	})
+}
+
+func (e *Enrollment) FindByCourseID(ctx context.Context, courseID string, page query.Page) (dto.EnrollmentListOutputDto, error) {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+	if !e.store.liveCourse(courseID) {
+		return dto.EnrollmentListOutputDto{}, dberr.NotFound("course")
+	}
+	return e.list(page, func(key enrollmentKey) (string, bool) {
+		u, ok := e.store.users[key.user]
+		return key.user, key.course == courseID && ok && u.DeletedAt == nil
+	})
+}
+
+func (e *Enrollment) UpdateProgress(ctx context.Context, enrollmentDto dto.EnrollmentInputDto) error {
+	if err := dberr.ValidateProgress(enrollmentDto); err != nil {
+		return err
+	}
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+	key := enrollmentKey{user: enrollmentDto.UserID, course: enrollmentDto.CourseID}
+	before, ok := e.store.enrollments[key]
+	if !ok {
+		return dberr.NotFound("enrollment")
+	}
+	now := time.Now().UTC()
+	after := before
+	after.Progress, after.LastAccessedAt = enrollmentDto.Progress, &now
+	e.store.enrollments[key] = after
+	e.tx.record(func() { e.store.enrollments[key] = before })
+	return nil
+}
+
+// list pages through the enrollments match selects, ordered by the key it
+// returns for them like the SQL backends do. Callers hold the store lock.
+func (e *Enrollment) list(page query.Page, match func(enrollmentKey) (string, bool)) (dto.EnrollmentListOutputDto, error) {
+	after, err := page.After()
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	keys := map[string]dto.EnrollmentOutputDto{}
+	var sorted []string
+	for key, enrollment := range e.store.enrollments {
+		if k, ok := match(key); ok && k > after {
+			keys[k] = enrollment
+			sorted = append(sorted, k)
+		}
+	}
+	sort.Strings(sorted)
+	if len(sorted) > page.Size()+1 {
+		sorted = sorted[:page.Size()+1]
+	}
+	sorted, next := query.Trim(sorted, page, query.EncodeCursor)
+	enrollments := dto.EnrollmentListOutputDto{NextCursor: next}
+	for _, k := range sorted {
+		enrollments.Enrollments = append(enrollments.Enrollments, keys[k])
+	}
+	return enrollments, nil
+}
+
+// deleteEnrollments removes the enrollments whose key matches. Callers hold
+// the store lock.
+func (s *Store) deleteEnrollments(tx *Tx, match func(enrollmentKey) bool) {
+	for key, enrollment := range s.enrollments {
+		if match(key) {
+			delete(s.enrollments, key)
+			tx.record(func() { s.enrollments[key] = enrollment })
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// change is a history entry with the sequence number it is ordered by.
+type change struct {
+	dto.HistoryOutputDto
+	seq int64
+}
+
+type HistoryRepository struct {
+	store *Store
+}
+
+func NewHistoryRepository(store *Store) *HistoryRepository {
+	return &HistoryRepository{store: store}
+}
+
+func (h *HistoryRepository) List(ctx context.Context, spec query.HistorySpec) (dto.HistoryListOutputDto, error) {
+	cursor, err := spec.Page.After()
+	if err != nil {
+		return dto.HistoryListOutputDto{}, err
+	}
+	var after int64
+	if cursor != "" {
+		if after, err = strconv.ParseInt(cursor, 10, 64); err != nil {
+			return dto.HistoryListOutputDto{}, query.ErrInvalidCursor
+		}
+	}
+	h.store.mu.RLock()
+	var entries []dto.HistoryOutputDto
+	for _, c := range h.store.history {
+		if c.seq > after && c.EntityType == spec.EntityType && c.EntityID == spec.EntityID {
+			entries = append(entries, c.HistoryOutputDto)
+			if len(entries) > spec.Page.Size() {
+				break
+			}
+		}
+	}
+	h.store.mu.RUnlock()
+	entries, next := query.Trim(entries, spec.Page,
+		func(e dto.HistoryOutputDto) string { return query.EncodeCursor(e.ID) })
+	return dto.HistoryListOutputDto{Entries: entries, NextCursor: next}, nil
+}
+
+// appendHistory records a change of an entity, undone with the transaction
+// that made it. Callers hold the store lock.
+func (s *Store) appendHistory(ctx context.Context, tx *Tx, entityType, entityID, action string, before, after any) error {
+	c := change{HistoryOutputDto: dto.HistoryOutputDto{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ChangedBy:  audit.Actor(ctx),
+		ChangedAt:  time.Now().UTC(),
+	}}
+	var err error
+	if c.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if c.After, err = snapshot(after); err != nil {
+		return err
+	}
+	s.historySeq++
+	c.seq = s.historySeq
+	c.ID = strconv.FormatInt(c.seq, 10)
+	n := len(s.history)
+	s.history = append(s.history, c)
+	tx.record(func() { s.history = s.history[:n] })
+	return nil
+}
+
+func snapshot(state any) (json.RawMessage, error) {
+	s, err := audit.Snapshot(state)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return json.RawMessage(*s), nil
+}
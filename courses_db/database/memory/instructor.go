@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+type instructorKey struct {
+	course, user string
+}
+
+type Instructor struct {
+	store *Store
+	tx    *Tx
+}
+
+func NewInstructorRepository(store *Store) *Instructor {
+	return &Instructor{store: store}
+}
+
+func (i *Instructor) Add(ctx context.Context, instructorDto dto.InstructorInputDto) (dto.InstructorOutputDto, error) {
+	i.store.mu.Lock()
+	defer i.store.mu.Unlock()
+	u, ok := i.store.users[instructorDto.UserID]
+	if !ok || u.DeletedAt != nil {
+		return dto.InstructorOutputDto{}, dberr.UnknownInstructorUser
+	}
+	if !i.store.liveCourse(instructorDto.CourseID) {
+		return dto.InstructorOutputDto{}, dberr.UnknownInstructorCourse
+	}
+	key := instructorKey{course: instructorDto.CourseID, user: instructorDto.UserID}
+	if _, ok := i.store.instructors[key]; ok {
+		return dto.InstructorOutputDto{}, dberr.AlreadyInstructor
+	}
+	addedAt := time.Now().UTC()
+	i.store.instructors[key] = addedAt
+	i.tx.record(func() { delete(i.store.instructors, key) })
+	return instructorOutput(key, u, addedAt), nil
+}
+
+func (i *Instructor) Remove(ctx context.Context, courseID, userID string) error {
+	i.store.mu.Lock()
+	defer i.store.mu.Unlock()
+	key := instructorKey{course: courseID, user: userID}
+	addedAt, ok := i.store.instructors[key]
+	if !ok {
+		return dberr.NotFound("instructor")
+	}
+	for other := range i.store.instructors {
+		if other.course == courseID && other.user != userID {
+			delete(i.store.instructors, key)
+			i.tx.record(func() { i.store.instructors[key] = addedAt })
+			return nil
+		}
+	}
+	return dberr.LastInstructor
+}
+
+func (i *Instructor) FindByCourseID(ctx context.Context, courseID string) (dto.InstructorListOutputDto, error) {
+	i.store.mu.RLock()
+	defer i.store.mu.RUnlock()
+	if !i.store.liveCourse(courseID) {
+		return dto.InstructorListOutputDto{}, dberr.NotFound("course")
+	}
+	instructors := dto.InstructorListOutputDto{}
+	for key, addedAt := range i.store.instructors {
+		if u, ok := i.store.users[key.user]; key.course == courseID && ok && u.DeletedAt == nil {
+			instructors.Instructors = append(instructors.Instructors, instructorOutput(key, u, addedAt))
+		}
+	}
+	sort.Slice(instructors.Instructors, func(a, b int) bool {
+		return instructors.Instructors[a].UserID < instructors.Instructors[b].UserID
+	})
+	return instructors, nil
+}
+
+func (i *Instructor) Teaches(ctx context.Context, userID, courseID string) (bool, error) {
+	i.store.mu.RLock()
+	defer i.store.mu.RUnlock()
+	_, ok := i.store.instructors[instructorKey{course: courseID, user: userID}]
+	return ok, nil
+}
+
+func instructorOutput(key instructorKey, u user, addedAt time.Time) dto.InstructorOutputDto {
+	return dto.InstructorOutputDto{CourseID: key.course, UserID: key.user, Name: u.Name, Email: u.Email, AddedAt: addedAt}
+}
+
+// addCreator makes the live user the changes of ctx are attributed to, if
+// any, the first instructor of a new course. Callers hold the store lock.
+func (s *Store) addCreator(ctx context.Context, tx *Tx, courseID string, addedAt time.Time) {
+	actor := audit.Actor(ctx)
+	if actor == "" {
+		return
+	}
+	for _, u := range s.users {
+		if u.Email == actor && u.DeletedAt == nil {
+			key := instructorKey{course: courseID, user: u.ID}
+			s.instructors[key] = addedAt
+			tx.record(func() { delete(s.instructors, key) })
+			return
+		}
+	}
+}
+
+// deleteInstructors removes the instructors whose key matches. Callers hold
+// the store lock.
+func (s *Store) deleteInstructors(tx *Tx, match func(instructorKey) bool) {
+	for key, addedAt := range s.instructors {
+		if match(key) {
+			delete(s.instructors, key)
+			tx.record(func() { s.instructors[key] = addedAt })
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+type Lesson struct {
+	store *Store
+	tx    *Tx
+}
+
+func NewLessonRepository(store *Store) *Lesson {
+	return &Lesson{store: store}
+}
+
+// Create adds a lesson at the end of its module.
+func (l *Lesson) Create(ctx context.Context, lessonDto dto.LessonInputDto) (dto.LessonOutputDto, error) {
+	if err := dberr.ValidateLesson(lessonDto); err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	if _, ok := l.store.modules[lessonDto.ModuleID]; !ok {
+		return dto.LessonOutputDto{}, dberr.UnknownModule
+	}
+	lesson := dto.LessonOutputDto{
+		ID:              uuid.New().String(),
+		ModuleID:        lessonDto.ModuleID,
+		Title:           lessonDto.Title,
+		ContentType:     lessonDto.ContentType,
+		DurationSeconds: lessonDto.DurationSeconds,
+		Body:            lessonDto.Body,
+		Position:        len(l.store.moduleLessons(lessonDto.ModuleID)) + 1,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		CreatedBy:       actor,
+		UpdatedBy:       actor,
+		Version:         1,
+	}
+	l.store.lessons[lesson.ID] = lesson
+	l.tx.record(func() { delete(l.store.lessons, lesson.ID) })
+	return lesson, nil
+}
+
+func (l *Lesson) FindByModuleID(ctx context.Context, moduleID string) (dto.LessonListOutputDto, error) {
+	l.store.mu.RLock()
+	defer l.store.mu.RUnlock()
+	if _, ok := l.store.modules[moduleID]; !ok {
+		return dto.LessonListOutputDto{}, dberr.NotFound("module")
+	}
+	return dto.LessonListOutputDto{Lessons: l.store.moduleLessons(moduleID)}, nil
+}
+
+func (l *Lesson) Find(ctx context.Context, id string) (dto.LessonOutputDto, error) {
+	l.store.mu.RLock()
+	defer l.store.mu.RUnlock()
+	lesson, ok := l.store.lessons[id]
+	if !ok {
+		return dto.LessonOutputDto{}, dberr.NotFound("lesson")
+	}
+	return lesson, nil
+}
+
+func (l *Lesson) Update(ctx context.Context, lessonDto dto.LessonInputDto) error {
+	if err := dberr.ValidateLesson(lessonDto); err != nil {
+		return err
+	}
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	lesson, ok := l.store.lessons[lessonDto.ID]
+	if !ok {
+		return dberr.NotFound("lesson")
+	}
+	if err := dberr.CheckVersion("lesson", lessonDto.Version, lesson.Version); err != nil {
+		return err
+	}
+	previous := lesson
+	lesson.Title, lesson.ContentType = lessonDto.Title, lessonDto.ContentType
+	lesson.DurationSeconds, lesson.Body = lessonDto.DurationSeconds, lessonDto.Body
+	lesson.UpdatedAt, lesson.UpdatedBy = time.Now().UTC(), audit.Actor(ctx)
+	lesson.Version++
+	l.store.lessons[lesson.ID] = lesson
+	l.tx.record(func() { l.store.lessons[previous.ID] = previous })
+	return nil
+}
+
+// Delete removes a lesson; the lessons after it move up.
+func (l *Lesson) Delete(ctx context.Context, id string) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	lesson, ok := l.store.lessons[id]
+	if !ok {
+		return dberr.NotFound("lesson")
+	}
+	delete(l.store.lessons, id)
+	l.tx.record(func() { l.store.lessons[id] = lesson })
+	for _, after := range l.store.moduleLessons(lesson.ModuleID)[lesson.Position-1:] {
+		previous := after
+		after.Position--
+		l.store.lessons[after.ID] = after
+		l.tx.record(func() { l.store.lessons[previous.ID] = previous })
+	}
+	return nil
+}
+
+func (l *Lesson) Reorder(ctx context.Context, moduleID string, ids []string) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	if _, ok := l.store.modules[moduleID]; !ok {
+		return dberr.NotFound("module")
+	}
+	lessons := l.store.moduleLessons(moduleID)
+	current := make([]string, len(lessons))
+	for i, lesson := range lessons {
+		current[i] = lesson.ID
+	}
+	if err := dberr.CheckOrder(current, ids, "lesson", "module"); err != nil {
+		return err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	for i, id := range ids {
+		lesson := l.store.lessons[id]
+		if lesson.Position == i+1 {
+			continue
+		}
+		previous := lesson
+		lesson.Position, lesson.UpdatedAt, lesson.UpdatedBy = i+1, now, actor
+		lesson.Version++
+		l.store.lessons[id] = lesson
+		l.tx.record(func() { l.store.lessons[previous.ID] = previous })
+	}
+	return nil
+}
+
+// moduleLessons returns the lessons of a module in order. Callers hold the
+// store lock.
+func (s *Store) moduleLessons(moduleID string) []dto.LessonOutputDto {
+	var lessons []dto.LessonOutputDto
+	for _, lesson := range s.lessons {
+		if lesson.ModuleID == moduleID {
+			lessons = append(lessons, lesson)
+		}
+	}
+	sort.Slice(lessons, func(i, j int) bool { return lessons[i].Position < lessons[j].Position })
+	return lessons
+}
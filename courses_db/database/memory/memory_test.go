@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+func TestRepositories(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	categories := NewCategoryRepository(store, query.Restrict)
+	courses := NewCourseRepository(store)
+	users := NewUserRepository(store)
+
+	category, _ := categories.Create(ctx, dto.CategoryInputDto{Name: "Go", Description: "golang"})
+	course, _ := courses.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+	courses.Create(ctx, dto.CourseInputDto{Name: "Advanced", CategoryID: category.ID})
+	courses.Create(ctx, dto.CourseInputDto{Name: "Concurrency", CategoryID: category.ID})
+	user, _ := users.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "hash"})
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{name: "generated uuid", run: func() error {
+			_, err := uuid.Parse(course.ID)
+			return err
+		}},
+		{name: "category of course", run: func() error {
+			got, err := categories.FindByCourseID(ctx, course.ID)
+			if err == nil && got != category {
+				t.Errorf("FindByCourseID() = %v, want %v", got, category)
+			}
+			return err
+		}},
+		{name: "pages by name", run: func() error {
+			spec := query.CourseSpec{Sort: query.Sort{Field: query.SortByName, Desc: true}, Page: query.Page{Limit: 2}}
+			var names []string
+			for {
+				page, err := courses.List(ctx, spec)
+				if err != nil {
+					return err
+				}
+				for _, c := range page.Courses {
+					names = append(names, c.Name)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				spec.Page.Cursor = page.NextCursor
+			}
+			if want := []string{"basics", "Concurrency", "Advanced"}; len(names) != 3 || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+				t.Errorf("List() names = %v, want %v", names, want)
+			}
+			return nil
+		}},
+		{name: "prefix ignores case", run: func() error {
+			got, err := courses.List(ctx, query.CourseSpec{NamePrefix: "BA"})
+			if err == nil && len(got.Courses) != 1 {
+				t.Errorf("List() = %v, want one course", got.Courses)
+			}
+			return err
+		}},
+		{name: "password by email", run: func() error {
+			got, err := users.FindByEmail(ctx, user.Email)
+			if err == nil && got.Password != "hash" {
+				t.Errorf("FindByEmail() password = %q", got.Password)
+			}
+			return err
+		}},
+		{name: "find missing", run: func() error {
+			_, err := courses.Find(ctx, uuid.NewString())
+			return err
+		}, wantErr: dberr.ErrNotFound},
+		{name: "duplicate email", run: func() error {
+			_, err := users.Create(ctx, dto.UserInputDto{Name: "Other", Email: user.Email})
+			return err
+		}, wantErr: dberr.ErrConflict},
+		{name: "course without name", run: func() error {
+			_, err := courses.Create(ctx, dto.CourseInputDto{CategoryID: category.ID})
+			return err
+		}, wantErr: dberr.ErrValidation},
+		{name: "course with unknown category", run: func() error {
+			_, err := courses.Create(ctx, dto.CourseInputDto{Name: "x", CategoryID: uuid.NewString()})
+			return err
+		}, wantErr: dberr.ErrValidation},
+		{name: "move course to unknown category", run: func() error {
+			return courses.Update(ctx, dto.CourseInputDto{ID: course.ID, Name: "x", CategoryID: uuid.NewString()})
+		}, wantErr: dberr.ErrValidation},
+		{name: "update missing", run: func() error {
+			return categories.Update(ctx, dto.CategoryInputDto{ID: uuid.NewString(), Name: "x"})
+		}, wantErr: dberr.ErrNotFound},
+		{name: "invalid cursor", run: func() error {
+			_, err := users.FindAll(ctx, query.Page{Cursor: "junk"})
+			return err
+		}, wantErr: query.ErrInvalidCursor},
+		{name: "delete category with courses", run: func() error {
+			return categories.Delete(ctx, category.ID)
+		}, wantErr: dberr.ErrHasDependents},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("error = %v", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	categories := NewCategoryRepository(store, query.Restrict)
+	courses := NewCourseRepository(store)
+	category, _ := categories.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// once the category is gone creating fails with UnknownCategory
+			course, err := courses.Create(ctx, dto.CourseInputDto{Name: "c", CategoryID: category.ID})
+			courses.FindByCategoryID(ctx, category.ID, query.Page{})
+			categories.Delete(ctx, category.ID)
+			if err == nil {
+				courses.Delete(ctx, course.ID)
+			}
+		}()
+	}
+	wg.Wait()
+	if got, _ := courses.FindAll(ctx, query.Page{}); len(got.Courses) != 0 {
+		t.Errorf("FindAll() = %d courses, want 0", len(got.Courses))
+	}
+}
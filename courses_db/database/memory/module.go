@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+type Module struct {
+	store *Store
+	tx    *Tx
+}
+
+func NewModuleRepository(store *Store) *Module {
+	return &Module{store: store}
+}
+
+// Create adds a module at the end of its course.
+func (m *Module) Create(ctx context.Context, moduleDto dto.ModuleInputDto) (dto.ModuleOutputDto, error) {
+	if err := dberr.ValidateModule(moduleDto); err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	if !m.store.liveCourse(moduleDto.CourseID) {
+		return dto.ModuleOutputDto{}, dberr.UnknownCourse
+	}
+	module := dto.ModuleOutputDto{
+		ID:          uuid.New().String(),
+		CourseID:    moduleDto.CourseID,
+		Title:       moduleDto.Title,
+		Description: moduleDto.Description,
+		Position:    len(m.store.courseModules(moduleDto.CourseID)) + 1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	m.store.modules[module.ID] = module
+	m.tx.record(func() { delete(m.store.modules, module.ID) })
+	return module, nil
+}
+
+func (m *Module) FindByCourseID(ctx context.Context, courseID string) (dto.ModuleListOutputDto, error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+	if !m.store.liveCourse(courseID) {
+		return dto.ModuleListOutputDto{}, dberr.NotFound("course")
+	}
+	return dto.ModuleListOutputDto{Modules: m.store.courseModules(courseID)}, nil
+}
+
+func (m *Module) Find(ctx context.Context, id string) (dto.ModuleOutputDto, error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+	module, ok := m.store.modules[id]
+	if !ok {
+		return dto.ModuleOutputDto{}, dberr.NotFound("module")
+	}
+	return module, nil
+}
+
+func (m *Module) Update(ctx context.Context, moduleDto dto.ModuleInputDto) error {
+	if err := dberr.ValidateModule(moduleDto); err != nil {
+		return err
+	}
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	module, ok := m.store.modules[moduleDto.ID]
+	if !ok {
+		return dberr.NotFound("module")
+	}
+	if err := dberr.CheckVersion("module", moduleDto.Version, module.Version); err != nil {
+		return err
+	}
+	previous := module
+	module.Title, module.Description = moduleDto.Title, moduleDto.Description
+	module.UpdatedAt, module.UpdatedBy = time.Now().UTC(), audit.Actor(ctx)
+	module.Version++
+	m.store.modules[module.ID] = module
+	m.tx.record(func() { m.store.modules[previous.ID] = previous })
+	return nil
+}
+
+// Delete removes a module and its lessons. The modules after it move up.
+func (m *Module) Delete(ctx context.Context, id string) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	module, ok := m.store.modules[id]
+	if !ok {
+		return dberr.NotFound("module")
+	}
+	m.store.deleteModule(m.tx, module)
+	for _, after := range m.store.courseModules(module.CourseID)[module.Position-1:] {
+		previous := after
+		after.Position--
+		m.store.modules[after.ID] = after
+		m.tx.record(func() { m.store.modules[previous.ID] = previous })
+	}
+	return nil
+}
+
+func (m *Module) Reorder(ctx context.Context, courseID string, ids []string) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+	if !m.store.liveCourse(courseID) {
+		return dberr.NotFound("course")
+	}
+	modules := m.store.courseModules(courseID)
+	current := make([]string, len(modules))
+	for i, module := range modules {
+		current[i] = module.ID
+	}
+	if err := dberr.CheckOrder(current, ids, "module", "course"); err != nil {
+		return err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	for i, id := range ids {
+		module := m.store.modules[id]
+		if module.Position == i+1 {
+			continue
+		}
+		previous := module
+		module.Position, module.UpdatedAt, module.UpdatedBy = i+1, now, actor
+		module.Version++
+		m.store.modules[id] = module
+		m.tx.record(func() { m.store.modules[previous.ID] = previous })
+	}
+	return nil
+}
+
+// liveCourse reports whether the course exists and is not deleted. Callers
+// hold the store lock.
+func (s *Store) liveCourse(id string) bool {
+	course, ok := s.courses[id]
+	return ok && course.DeletedAt == nil
+}
+
+// courseModules returns the modules of a course in order. Callers hold the
+// store lock.
+func (s *Store) courseModules(courseID string) []dto.ModuleOutputDto {
+	var modules []dto.ModuleOutputDto
+	for _, module := range s.modules {
+		if module.CourseID == courseID {
+			modules = append(modules, module)
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Position < modules[j].Position })
+	return modules
+}
+
+// deleteModule removes a module and its lessons, leaving the positions of
+// the other modules as they are. Callers hold the store lock.
+func (s *Store) deleteModule(tx *Tx, module dto.ModuleOutputDto) {
+	delete(s.modules, module.ID)
+	tx.record(func() { s.modules[module.ID] = module })
+	for id, lesson := range s.lessons {
+		if lesson.ModuleID == module.ID {
+			delete(s.lessons, id)
+			tx.record(func() { s.lessons[id] = lesson })
+		}
+	}
+}
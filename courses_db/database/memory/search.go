@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+type SearchRepository struct {
+	store *Store
+}
+
+func NewSearchRepository(store *Store) *SearchRepository {
+	return &SearchRepository{store: store}
+}
+
+// Search scans every live course and category. A result must hold every
+// term; its score is the share of its words that match.
+func (s *SearchRepository) Search(ctx context.Context, spec query.SearchSpec) (dto.SearchOutputDto, error) {
+	terms, err := spec.Terms()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	offset, err := spec.Page.Offset()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	var results []dto.SearchResultOutputDto
+	add := func(entityType, id, name, description string) {
+		text := name + " " + description
+		for _, term := range terms {
+			if query.Matches(text, []string{term}) == 0 {
+				return
+			}
+		}
+		results = append(results, dto.SearchResultOutputDto{
+			EntityType: entityType,
+			ID:         id,
+			Name:       name,
+			Snippet:    query.Snippet(terms, name, description),
+			Score:      float64(query.Matches(text, terms)) / math.Sqrt(float64(len(strings.Fields(text)))),
+		})
+	}
+	s.store.mu.RLock()
+	if spec.Includes("category") {
+		for _, c := range s.store.categories {
+			if c.DeletedAt == nil {
+				add("category", c.ID, c.Name, c.Description)
+			}
+		}
+	}
+	if spec.Includes("course") {
+		for _, c := range s.store.courses {
+			if c.DeletedAt == nil {
+				add("course", c.ID, c.Name, c.Description)
+			}
+		}
+	}
+	s.store.mu.RUnlock()
+	slices.SortFunc(results, func(a, b dto.SearchResultOutputDto) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.EntityType, b.EntityType), cmp.Compare(a.ID, b.ID))
+	})
+	results = results[min(offset, len(results)):]
+	results, next := query.TrimOffset(results, spec.Page, offset)
+	return dto.SearchOutputDto{Results: results, NextCursor: next}, nil
+}
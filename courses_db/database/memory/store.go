@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// Store holds the data shared by the in-memory repositories. The zero value
+// is not usable, use NewStore.
+type Store struct {
+	mu          sync.RWMutex
+	categories  map[string]dto.CategoryOutputDto
+	courses     map[string]dto.CourseOutputDto
+	modules     map[string]dto.ModuleOutputDto
+	lessons     map[string]dto.LessonOutputDto
+	enrollments map[enrollmentKey]dto.EnrollmentOutputDto
+	instructors map[instructorKey]time.Time
+	tags        map[string]dto.TagOutputDto
+	courseTags  map[courseTagKey]time.Time
+	users       map[string]user
+	history     []change
+	historySeq  int64
+}
+
+type user struct {
+	dto.UserOutputDto
+	password string
+}
+
+func NewStore() *Store {
+	return &Store{
+		categories:  map[string]dto.CategoryOutputDto{},
+		courses:     map[string]dto.CourseOutputDto{},
+		modules:     map[string]dto.ModuleOutputDto{},
+		lessons:     map[string]dto.LessonOutputDto{},
+		enrollments: map[enrollmentKey]dto.EnrollmentOutputDto{},
+		instructors: map[instructorKey]time.Time{},
+		tags:        map[string]dto.TagOutputDto{},
+		courseTags:  map[courseTagKey]time.Time{},
+		users:       map[string]user{},
+	}
+}
+
+// row is the part of an item listings filter and order on.
+type row struct {
+	id        string
+	name      string
+	createdAt time.Time
+}
+
+func compare(a, b row, field query.SortField) int {
+	switch field {
+	case query.SortByName:
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+	case query.SortByCreated:
+		if c := a.createdAt.Compare(b.createdAt); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a.id, b.id)
+}
+
+// list orders items like the SQL backends do, skips those up to the page
+// cursor and returns the page with the cursor of the next one.
+func list[T any](items []T, rowOf func(T) row, s query.Sort, p query.Page) ([]T, string, error) {
+	key, id, ok, err := p.Keyset(s)
+	if err != nil {
+		return nil, "", err
+	}
+	sign := 1
+	if s.Desc {
+		sign = -1
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return sign*compare(rowOf(items[i]), rowOf(items[j]), s.Field) < 0
+	})
+	if ok {
+		after := row{id: id}
+		switch k := key.(type) {
+		case string:
+			after.name = k
+		case time.Time:
+			after.createdAt = k
+		}
+		start := sort.Search(len(items), func(i int) bool {
+			return sign*compare(rowOf(items[i]), after, s.Field) > 0
+		})
+		items = items[start:]
+	}
+	if len(items) > p.Size()+1 {
+		items = items[:p.Size()+1]
+	}
+	items, next := query.Trim(items, p, func(item T) string {
+		r := rowOf(item)
+		return s.Cursor(r.id, r.name, r.createdAt)
+	})
+	return items, next, nil
+}
+
+// hasPrefix matches like the SQL backends' case-insensitive LIKE 'prefix%'.
+func hasPrefix(name, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix))
+}
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/entity"
+	"github.com/google/uuid"
+)
+
+type courseTagKey struct {
+	course, tag string
+}
+
+type Tag struct {
+	store *Store
+	tx    *Tx
+}
+
+func NewTagRepository(store *Store) *Tag {
+	return &Tag{store: store}
+}
+
+func (t *Tag) Create(ctx context.Context, tagDto dto.TagInputDto) (dto.TagOutputDto, error) {
+	name := entity.NormalizeTagName(tagDto.Name)
+	if err := dberr.ValidateTag(name); err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	if _, ok := t.store.tagByName(name); ok {
+		return dto.TagOutputDto{}, dberr.TagExists
+	}
+	return t.create(ctx, name), nil
+}
+
+// create adds a tag. Callers hold the store lock.
+func (t *Tag) create(ctx context.Context, name string) dto.TagOutputDto {
+	tag := dto.TagOutputDto{ID: uuid.New().String(), Name: name, CreatedAt: time.Now().UTC(), CreatedBy: audit.Actor(ctx)}
+	t.store.tags[tag.ID] = tag
+	t.tx.record(func() { delete(t.store.tags, tag.ID) })
+	return tag
+}
+
+func (t *Tag) Find(ctx context.Context, id string) (dto.TagOutputDto, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	tag, ok := t.store.tags[id]
+	if !ok {
+		return dto.TagOutputDto{}, dberr.NotFound("tag")
+	}
+	return t.store.counted(tag), nil
+}
+
+func (t *Tag) List(ctx context.Context, spec query.TagSpec) (dto.TagListOutputDto, error) {
+	prefix := entity.NormalizeTagName(spec.NamePrefix)
+	t.store.mu.RLock()
+	var matches []dto.TagOutputDto
+	for _, tag := range t.store.tags {
+		if hasPrefix(tag.Name, prefix) {
+			matches = append(matches, t.store.counted(tag))
+		}
+	}
+	t.store.mu.RUnlock()
+	items, next, err := list(matches, tagRow, query.Sort{Field: query.SortByName}, spec.Page)
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	return dto.TagListOutputDto{Tags: items, NextCursor: next}, nil
+}
+
+func (t *Tag) Delete(ctx context.Context, id string) error {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	tag, ok := t.store.tags[id]
+	if !ok {
+		return dberr.NotFound("tag")
+	}
+	delete(t.store.tags, id)
+	t.tx.record(func() { t.store.tags[id] = tag })
+	t.store.deleteCourseTags(t.tx, func(key courseTagKey) bool { return key.tag == id })
+	return nil
+}
+
+func (t *Tag) Tag(ctx context.Context, courseTag dto.CourseTagInputDto) (dto.TagOutputDto, error) {
+	name := entity.NormalizeTagName(courseTag.Name)
+	if err := dberr.ValidateTag(name); err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	if !t.store.liveCourse(courseTag.CourseID) {
+		return dto.TagOutputDto{}, dberr.UnknownTagCourse
+	}
+	tag, ok := t.store.tagByName(name)
+	if !ok {
+		tag = t.create(ctx, name)
+	}
+	key := courseTagKey{course: courseTag.CourseID, tag: tag.ID}
+	if _, ok := t.store.courseTags[key]; ok {
+		return dto.TagOutputDto{}, dberr.AlreadyTagged
+	}
+	t.store.courseTags[key] = time.Now().UTC()
+	t.tx.record(func() { delete(t.store.courseTags, key) })
+	return t.store.counted(tag), nil
+}
+
+func (t *Tag) Untag(ctx context.Context, courseID, tagID string) error {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	key := courseTagKey{course: courseID, tag: tagID}
+	addedAt, ok := t.store.courseTags[key]
+	if !ok {
+		return dberr.NotFound("course tag")
+	}
+	delete(t.store.courseTags, key)
+	t.tx.record(func() { t.store.courseTags[key] = addedAt })
+	return nil
+}
+
+func (t *Tag) FindByCourseID(ctx context.Context, courseID string) (dto.TagListOutputDto, error) {
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	if !t.store.liveCourse(courseID) {
+		return dto.TagListOutputDto{}, dberr.NotFound("course")
+	}
+	tags := dto.TagListOutputDto{}
+	for key := range t.store.courseTags {
+		if key.course == courseID {
+			tags.Tags = append(tags.Tags, t.store.counted(t.store.tags[key.tag]))
+		}
+	}
+	sort.Slice(tags.Tags, func(a, b int) bool { return tags.Tags[a].Name < tags.Tags[b].Name })
+	return tags, nil
+}
+
+func tagRow(t dto.TagOutputDto) row {
+	return row{id: t.ID, name: t.Name, createdAt: t.CreatedAt}
+}
+
+// tagByName finds a tag by its normalized name. Callers hold the store
+// lock.
+func (s *Store) tagByName(name string) (dto.TagOutputDto, bool) {
+	for _, tag := range s.tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return dto.TagOutputDto{}, false
+}
+
+// counted sets the number of live courses carrying a tag. Callers hold the
+// store lock.
+func (s *Store) counted(tag dto.TagOutputDto) dto.TagOutputDto {
+	tag.Courses = 0
+	for key := range s.courseTags {
+		if key.tag == tag.ID && s.liveCourse(key.course) {
+			tag.Courses++
+		}
+	}
+	return tag
+}
+
+// tagged reports whether a course carries any of the named tags, or all of
+// them when all is set. Callers hold the store lock.
+func (s *Store) tagged(courseID string, names []string, all bool) bool {
+	carried := 0
+	for _, name := range names {
+		tag, ok := s.tagByName(name)
+		if !ok {
+			continue
+		}
+		if _, ok := s.courseTags[courseTagKey{course: courseID, tag: tag.ID}]; ok {
+			carried++
+		}
+	}
+	if all {
+		return carried == len(names)
+	}
+	return carried > 0
+}
+
+// deleteCourseTags removes the course tags whose key matches. Callers hold
+// the store lock.
+func (s *Store) deleteCourseTags(tx *Tx, match func(courseTagKey) bool) {
+	for key, addedAt := range s.courseTags {
+		if match(key) {
+			delete(s.courseTags, key)
+			tx.record(func() { s.courseTags[key] = addedAt })
+		}
+	}
+}
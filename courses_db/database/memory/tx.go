@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// Tx is a unit of work over a Store. Changes made through its repositories
+// are visible to everyone right away; Rollback undoes them.
+type Tx struct {
+	store *Store
+	mu    sync.Mutex
+	undo  []func()
+	done  bool
+}
+
+func (s *Store) Begin() *Tx {
+	return &Tx{store: s}
+}
+
+func (t *Tx) CategoryRepository(onDelete query.OnDelete) *CategoryRepository {
+	return &CategoryRepository{store: t.store, tx: t, onDelete: onDelete}
+}
+
+func (t *Tx) CourseRepository() *Course {
+	return &Course{store: t.store, tx: t}
+}
+
+func (t *Tx) ModuleRepository() *Module {
+	return &Module{store: t.store, tx: t}
+}
+
+func (t *Tx) LessonRepository() *Lesson {
+	return &Lesson{store: t.store, tx: t}
+}
+
+func (t *Tx) EnrollmentRepository() *Enrollment {
+	return &Enrollment{store: t.store, tx: t}
+}
+
+func (t *Tx) InstructorRepository() *Instructor {
+	return &Instructor{store: t.store, tx: t}
+}
+
+func (t *Tx) TagRepository() *Tag {
+	return &Tag{store: t.store, tx: t}
+}
+
+func (t *Tx) HistoryRepository() *HistoryRepository {
+	return &HistoryRepository{store: t.store}
+}
+
+func (t *Tx) SearchRepository() *SearchRepository {
+	return &SearchRepository{store: t.store}
+}
+
+func (t *Tx) UserRepository() *UserRepository {
+	return &UserRepository{store: t.store, tx: t}
+}
+
+// record remembers how to undo a change. Callers hold the store lock; a nil
+// Tx records nothing.
+func (t *Tx) record(undo func()) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.done {
+		t.undo = append(t.undo, undo)
+	}
+}
+
+func (t *Tx) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.undo, t.done = nil, true
+	return nil
+}
+
+func (t *Tx) Rollback() error {
+	// same lock order as the repositories: store first, then the undo log
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.done {
+		return nil
+	}
+	for i := len(t.undo) - 1; i >= 0; i-- {
+		t.undo[i]()
+	}
+	t.undo, t.done = nil, true
+	return nil
+}
+
+// batch runs item for each of n items, each in a Tx of its own folded into
+// tx, or into a Tx of the batch when tx is nil. A rejected item is undone
+// and reported in its result; any other error undoes the whole batch.
+func (s *Store) batch(tx *Tx, n int, item func(tx *Tx, i int) (string, error)) ([]dto.BatchItemOutputDto, error) {
+	outer := tx
+	if outer == nil {
+		outer = s.Begin()
+		defer outer.Rollback()
+	}
+	results := make([]dto.BatchItemOutputDto, n)
+	for i := range results {
+		itemTx := s.Begin()
+		id, err := item(itemTx, i)
+		if err != nil {
+			itemTx.Rollback()
+			if !dberr.Rejected(err) {
+				return nil, err
+			}
+		}
+		outer.adopt(itemTx)
+		results[i] = dto.BatchItemOutputDto{Index: i, ID: id, Err: err}
+	}
+	if tx == nil {
+		return results, outer.Commit()
+	}
+	return results, nil
+}
+
+// adopt takes over the undo log of a finished inner Tx.
+func (t *Tx) adopt(inner *Tx) {
+	inner.mu.Lock()
+	undo := inner.undo
+	inner.undo, inner.done = nil, true
+	inner.mu.Unlock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.done {
+		t.undo = append(t.undo, undo...)
+	}
+}
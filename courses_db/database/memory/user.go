@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+type UserRepository struct {
+	store *Store
+	tx    *Tx
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{
+		store: store,
+	}
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*dto.GetJWTInput, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, u := range r.store.users {
+		if u.Email == email && u.DeletedAt == nil {
+			return &dto.GetJWTInput{Email: email, Password: u.password}, nil
+		}
+	}
+	return nil, dberr.NotFound("user")
+}
+
+func (r *UserRepository) FindIDByEmail(ctx context.Context, email string) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	for _, u := range r.store.users {
+		if u.Email == email && u.DeletedAt == nil {
+			return u.ID, nil
+		}
+	}
+	return "", dberr.NotFound("user")
+}
+
+func (r *UserRepository) Create(ctx context.Context, userDto dto.UserInputDto) (dto.UserOutputDto, error) {
+	if err := dberr.ValidateUser(userDto); err != nil {
+		return dto.UserOutputDto{}, err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	u := user{
+		UserOutputDto: dto.UserOutputDto{
+			ID:        uuid.New().String(),
+			Name:      userDto.Name,
+			Email:     userDto.Email,
+			CreatedAt: now,
+			UpdatedAt: now,
+			CreatedBy: actor,
+			UpdatedBy: actor,
+			Version:   1,
+		},
+		password: userDto.Password,
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if r.emailTaken(u.Email, u.ID) {
+		return dto.UserOutputDto{}, dberr.New(dberr.ErrConflict, "email already in use")
+	}
+	r.store.users[u.ID] = u
+	r.tx.record(func() { delete(r.store.users, u.ID) })
+	return u.UserOutputDto, nil
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, page query.Page) (dto.UserListOutputDto, error) {
+	return r.List(ctx, query.UserSpec{Page: page})
+}
+
+func (r *UserRepository) List(ctx context.Context, spec query.UserSpec) (dto.UserListOutputDto, error) {
+	r.store.mu.RLock()
+	var users []dto.UserOutputDto
+	for _, u := range r.store.users {
+		if u.DeletedAt == nil || spec.IncludeDeleted {
+			users = append(users, u.UserOutputDto)
+		}
+	}
+	r.store.mu.RUnlock()
+	items, next, err := list(users, func(u dto.UserOutputDto) row { return row{id: u.ID} }, query.Sort{}, spec.Page)
+	if err != nil {
+		return dto.UserListOutputDto{}, err
+	}
+	return dto.UserListOutputDto{Users: items, NextCursor: next}, nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	u, ok := r.store.users[id]
+	if !ok || u.DeletedAt != nil {
+		return dberr.NotFound("user")
+	}
+	previous := u
+	deletedAt := time.Now().UTC()
+	u.DeletedAt, u.UpdatedAt, u.UpdatedBy = &deletedAt, deletedAt, audit.Actor(ctx)
+	u.Version++
+	r.store.users[id] = u
+	r.tx.record(func() { r.store.users[id] = previous })
+	return nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, userDto dto.UserInputDto) error {
+	if err := dberr.ValidateUser(userDto); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	u, ok := r.store.users[userDto.ID]
+	if !ok || u.DeletedAt != nil {
+		return dberr.NotFound("user")
+	}
+	if err := dberr.CheckVersion("user", userDto.Version, u.Version); err != nil {
+		return err
+	}
+	if r.emailTaken(userDto.Email, u.ID) {
+		return dberr.New(dberr.ErrConflict, "email already in use")
+	}
+	previous := u
+	u.Name = userDto.Name
+	u.Email = userDto.Email
+	u.password = userDto.Password
+	u.UpdatedAt, u.UpdatedBy = time.Now().UTC(), audit.Actor(ctx)
+	u.Version++
+	r.store.users[u.ID] = u
+	r.tx.record(func() { r.store.users[previous.ID] = previous })
+	return nil
+}
+
+func (r *UserRepository) Find(ctx context.Context, id string) (dto.UserOutputDto, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	u, ok := r.store.users[id]
+	if !ok || u.DeletedAt != nil {
+		return dto.UserOutputDto{}, dberr.NotFound("user")
+	}
+	return u.UserOutputDto, nil
+}
+
+// emailTaken reports whether a user other than id has email, like the
+// unique index of the SQL backends, which also covers deleted users. Callers
+// hold the store lock.
+func (r *UserRepository) emailTaken(email, id string) bool {
+	for _, u := range r.store.users {
+		if u.Email == email && u.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// Restore brings back a deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	u, ok := r.store.users[id]
+	if !ok || u.DeletedAt == nil {
+		return dberr.NotFound("user")
+	}
+	previous := u
+	u.DeletedAt, u.UpdatedAt, u.UpdatedBy = nil, time.Now().UTC(), audit.Actor(ctx)
+	u.Version++
+	r.store.users[id] = u
+	r.tx.record(func() { r.store.users[id] = previous })
+	return nil
+}
+
+// Purge removes users deleted before the given time, with their
+// enrollments and their places among the instructors of courses.
+func (r *UserRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var purged int64
+	for id, u := range r.store.users {
+		if u.DeletedAt == nil || !u.DeletedAt.Before(before) {
+			continue
+		}
+		delete(r.store.users, id)
+		r.tx.record(func() { r.store.users[id] = u })
+		r.store.deleteEnrollments(r.tx, func(key enrollmentKey) bool { return key.user == id })
+		r.store.deleteInstructors(r.tx, func(key instructorKey) bool { return key.user == id })
+		purged++
+	}
+	return purged, nil
+}
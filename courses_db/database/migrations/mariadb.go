@@ -0,0 +1,210 @@
+package migrations
+
+var mariadbMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create tables",
+		Up: []string{
+			"CREATE TABLE IF NOT EXISTS categories (id CHAR(36) PRIMARY KEY, name TEXT, description TEXT)",
+			"CREATE TABLE IF NOT EXISTS courses (id CHAR(36) PRIMARY KEY, name TEXT, description TEXT, category_id TEXT)",
+			"CREATE TABLE IF NOT EXISTS users (id CHAR(36) PRIMARY KEY, name TEXT, email TEXT, password TEXT)",
+		},
+		Down: []string{
+			"DROP TABLE users",
+			"DROP TABLE courses",
+			"DROP TABLE categories",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add created_at",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+			"ALTER TABLE courses ADD COLUMN created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+		},
+		Down: []string{
+			"ALTER TABLE courses DROP COLUMN created_at",
+			"ALTER TABLE categories DROP COLUMN created_at",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add lookup indexes",
+		Up: []string{
+			"CREATE INDEX idx_courses_category_id ON courses (category_id(36))",
+			"CREATE INDEX idx_courses_name ON courses (name(191), id)",
+			"CREATE INDEX idx_categories_name ON categories (name(191), id)",
+			"CREATE INDEX idx_users_email ON users (email(191))",
+		},
+		Down: []string{
+			"DROP INDEX idx_users_email ON users",
+			"DROP INDEX idx_categories_name ON categories",
+			"DROP INDEX idx_courses_name ON courses",
+			"DROP INDEX idx_courses_category_id ON courses",
+		},
+	},
+	{
+		Version: 4,
+		Name:    "unique user email",
+		Up: []string{
+			"DROP INDEX idx_users_email ON users",
+			"CREATE UNIQUE INDEX idx_users_email ON users (email(191))",
+		},
+		Down: []string{
+			"DROP INDEX idx_users_email ON users",
+			"CREATE INDEX idx_users_email ON users (email(191))",
+		},
+	},
+	{
+		// a foreign key needs category_id to have the type of categories.id
+		Version: 5,
+		Name:    "course category foreign key",
+		Up: []string{
+			"DROP INDEX idx_courses_category_id ON courses",
+			"ALTER TABLE courses MODIFY category_id CHAR(36) NOT NULL",
+			"CREATE INDEX idx_courses_category_id ON courses (category_id)",
+			"ALTER TABLE courses ADD CONSTRAINT fk_courses_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE RESTRICT",
+		},
+		Down: []string{
+			"ALTER TABLE courses DROP FOREIGN KEY fk_courses_category",
+			"DROP INDEX idx_courses_category_id ON courses",
+			"ALTER TABLE courses MODIFY category_id TEXT",
+			"CREATE INDEX idx_courses_category_id ON courses (category_id(36))",
+		},
+	},
+	{
+		Version: 6,
+		Name:    "soft delete",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN deleted_at DATETIME(6) NULL",
+			"ALTER TABLE courses ADD COLUMN deleted_at DATETIME(6) NULL",
+			"ALTER TABLE users ADD COLUMN deleted_at DATETIME(6) NULL",
+		},
+		Down: []string{
+			"ALTER TABLE users DROP COLUMN deleted_at",
+			"ALTER TABLE courses DROP COLUMN deleted_at",
+			"ALTER TABLE categories DROP COLUMN deleted_at",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "audit columns and history",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+			"ALTER TABLE categories ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE categories ADD COLUMN updated_by VARCHAR(255) NOT NULL DEFAULT ''",
+			"UPDATE categories SET updated_at = created_at",
+			"ALTER TABLE courses ADD COLUMN updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+			"ALTER TABLE courses ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE courses ADD COLUMN updated_by VARCHAR(255) NOT NULL DEFAULT ''",
+			"UPDATE courses SET updated_at = created_at",
+			"ALTER TABLE users ADD COLUMN created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+			"ALTER TABLE users ADD COLUMN updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+			"ALTER TABLE users ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT ''",
+			"ALTER TABLE users ADD COLUMN updated_by VARCHAR(255) NOT NULL DEFAULT ''",
+			"CREATE TABLE history (id BIGINT AUTO_INCREMENT PRIMARY KEY, entity_type VARCHAR(16) NOT NULL, entity_id CHAR(36) NOT NULL, action VARCHAR(16) NOT NULL, before_state JSON NULL, after_state JSON NULL, changed_by VARCHAR(255) NOT NULL, changed_at DATETIME(6) NOT NULL)",
+			"CREATE INDEX idx_history_entity ON history (entity_type, entity_id, id)",
+		},
+		Down: []string{
+			"DROP INDEX idx_history_entity ON history",
+			"DROP TABLE history",
+			"ALTER TABLE users DROP COLUMN updated_by",
+			"ALTER TABLE users DROP COLUMN created_by",
+			"ALTER TABLE users DROP COLUMN updated_at",
+			"ALTER TABLE users DROP COLUMN created_at",
+			"ALTER TABLE courses DROP COLUMN updated_by",
+			"ALTER TABLE courses DROP COLUMN created_by",
+			"ALTER TABLE courses DROP COLUMN updated_at",
+			"ALTER TABLE categories DROP COLUMN updated_by",
+			"ALTER TABLE categories DROP COLUMN created_by",
+			"ALTER TABLE categories DROP COLUMN updated_at",
+		},
+	},
+	{
+		Version: 8,
+		Name:    "row versions",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 1",
+			"ALTER TABLE courses ADD COLUMN version BIGINT NOT NULL DEFAULT 1",
+			"ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1",
+		},
+		Down: []string{
+			"ALTER TABLE users DROP COLUMN version",
+			"ALTER TABLE courses DROP COLUMN version",
+			"ALTER TABLE categories DROP COLUMN version",
+		},
+	},
+	{
+		Version: 9,
+		Name:    "full-text search",
+		Up: []string{
+			"ALTER TABLE categories ADD FULLTEXT INDEX ft_categories_search (name, description)",
+			"ALTER TABLE courses ADD FULLTEXT INDEX ft_courses_search (name, description)",
+		},
+		Down: []string{
+			"DROP INDEX ft_courses_search ON courses",
+			"DROP INDEX ft_categories_search ON categories",
+		},
+	},
+	{
+		Version: 10,
+		Name:    "modules and lessons",
+		Up: []string{
+			"CREATE TABLE modules (id CHAR(36) PRIMARY KEY, course_id CHAR(36) NOT NULL, title VARCHAR(255) NOT NULL, description TEXT NOT NULL, position INT NOT NULL, created_at DATETIME(6) NOT NULL, updated_at DATETIME(6) NOT NULL, created_by VARCHAR(255) NOT NULL DEFAULT '', updated_by VARCHAR(255) NOT NULL DEFAULT '', version BIGINT NOT NULL DEFAULT 1, INDEX idx_modules_course (course_id, position), CONSTRAINT fk_modules_course FOREIGN KEY (course_id) REFERENCES courses (id) ON DELETE CASCADE)",
+			"CREATE TABLE lessons (id CHAR(36) PRIMARY KEY, module_id CHAR(36) NOT NULL, title VARCHAR(255) NOT NULL, content_type VARCHAR(16) NOT NULL, duration_seconds BIGINT NOT NULL DEFAULT 0, body MEDIUMTEXT NOT NULL, position INT NOT NULL, created_at DATETIME(6) NOT NULL, updated_at DATETIME(6) NOT NULL, created_by VARCHAR(255) NOT NULL DEFAULT '', updated_by VARCHAR(255) NOT NULL DEFAULT '', version BIGINT NOT NULL DEFAULT 1, INDEX idx_lessons_module (module_id, position), CONSTRAINT fk_lessons_module FOREIGN KEY (module_id) REFERENCES modules (id) ON DELETE CASCADE)",
+		},
+		Down: []string{
+			"DROP TABLE lessons",
+			"DROP TABLE modules",
+		},
+	},
+	{
+		Version: 11,
+		Name:    "enrollments",
+		Up: []string{
+			"CREATE TABLE enrollments (user_id CHAR(36) NOT NULL, course_id CHAR(36) NOT NULL, progress INT NOT NULL DEFAULT 0, enrolled_at DATETIME(6) NOT NULL, last_accessed_at DATETIME(6) NULL, PRIMARY KEY (user_id, course_id), INDEX idx_enrollments_course (course_id, user_id), CONSTRAINT fk_enrollments_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE, CONSTRAINT fk_enrollments_course FOREIGN KEY (course_id) REFERENCES courses (id) ON DELETE CASCADE)",
+		},
+		Down: []string{
+			"DROP TABLE enrollments",
+		},
+	},
+	{
+		Version: 12,
+		Name:    "course instructors",
+		// Courses already there are taught by whoever created them.
+		Up: []string{
+			"CREATE TABLE course_instructors (course_id CHAR(36) NOT NULL, user_id CHAR(36) NOT NULL, added_at DATETIME(6) NOT NULL, PRIMARY KEY (course_id, user_id), INDEX idx_course_instructors_user (user_id, course_id), CONSTRAINT fk_course_instructors_course FOREIGN KEY (course_id) REFERENCES courses (id) ON DELETE CASCADE, CONSTRAINT fk_course_instructors_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE)",
+			"INSERT INTO course_instructors (course_id, user_id, added_at) SELECT c.id, u.id, c.created_at FROM courses c JOIN users u ON u.email = c.created_by",
+		},
+		Down: []string{
+			"DROP TABLE course_instructors",
+		},
+	},
+	{
+		Version: 13,
+		Name:    "category tree",
+		// Purging a category lifts its subcategories to the top level.
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN parent_id CHAR(36) NULL",
+			"CREATE INDEX idx_categories_parent ON categories (parent_id, id)",
+			"ALTER TABLE categories ADD CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories (id) ON DELETE SET NULL",
+		},
+		Down: []string{
+			"ALTER TABLE categories DROP FOREIGN KEY fk_categories_parent",
+			"DROP INDEX idx_categories_parent ON categories",
+			"ALTER TABLE categories DROP COLUMN parent_id",
+		},
+	},
+	{
+		Version: 14,
+		Name:    "course tags",
+		Up: []string{
+			"CREATE TABLE tags (id CHAR(36) PRIMARY KEY, name VARCHAR(100) NOT NULL, created_at DATETIME(6) NOT NULL, created_by VARCHAR(255) NOT NULL DEFAULT '', UNIQUE INDEX idx_tags_name (name))",
+			"CREATE TABLE course_tags (course_id CHAR(36) NOT NULL, tag_id CHAR(36) NOT NULL, added_at DATETIME(6) NOT NULL, PRIMARY KEY (course_id, tag_id), INDEX idx_course_tags_tag (tag_id, course_id), CONSTRAINT fk_course_tags_course FOREIGN KEY (course_id) REFERENCES courses (id) ON DELETE CASCADE, CONSTRAINT fk_course_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE)",
+		},
+		Down: []string{
+			"DROP TABLE course_tags",
+			"DROP TABLE tags",
+		},
+	},
+}
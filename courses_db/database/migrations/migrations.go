@@ -0,0 +1,266 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+)
+
+// Migration is one versioned schema change. Up and Down hold the statements
+// that apply and revert it, run in order. Requires, if set, checks before Up
+// that the database can run the statements at all.
+type Migration struct {
+	Version  int
+	Name     string
+	Up       []string
+	Down     []string
+	Requires func(ctx context.Context, tx *sql.Tx) error
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var (
+	ErrUnknownDialect = errors.New("no migrations for database driver")
+	ErrUnknownVersion = errors.New("unknown schema version")
+	ErrOutOfDate      = errors.New("database schema is out of date, run the migrate command")
+	ErrNoFTS5         = errors.New("sqlite3 driver built without FTS5, build and run with -tags sqlite_fts5")
+)
+
+// Migrator applies the migrations of one dialect to a database and records
+// them in the schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	ph         query.Placeholder
+	timestamp  string
+	migrations []Migration
+}
+
+// New returns a Migrator for db, driver being the DB_DRIVER configuration
+// value ("sqlite3", "mysql" or "postgres").
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	var migrations []Migration
+	var ph query.Placeholder
+	timestamp := "DATETIME"
+	switch driver {
+	case "sqlite3":
+		migrations, ph = sqliteMigrations, query.Dollar
+	case "mysql":
+		migrations, ph = mariadbMigrations, query.Question
+	case "postgres":
+		migrations, ph, timestamp = postgresMigrations, query.Dollar, "TIMESTAMPTZ"
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDialect, driver)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return &Migrator{db: db, ph: ph, timestamp: timestamp, migrations: migrations}, nil
+}
+
+// Latest returns the version the schema reaches once every migration is applied.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at "+m.timestamp+" NOT NULL)")
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// Current returns the highest applied version, 0 for an empty database.
+func (m *Migrator) Current(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(statuses, Status{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// Check fails with ErrOutOfDate unless every migration has been applied,
+// and with the error of Requires when the database can no longer run an
+// applied migration, such as a sqlite3 driver built without FTS5.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	current, pending := 0, 0
+	for _, status := range statuses {
+		if status.Applied {
+			current = status.Version
+		} else {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: at version %d, %d pending", ErrOutOfDate, current, pending)
+	}
+	for _, migration := range m.migrations {
+		if migration.Requires != nil {
+			if err := m.requires(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// requires runs the Requires check of migration in a transaction of its own.
+func (m *Migrator) requires(ctx context.Context, migration Migration) error {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := migration.Requires(ctx, tx); err != nil {
+		return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	current, err := m.Current(ctx)
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		return nil
+	}
+	target := 0
+	for _, migration := range m.migrations {
+		if migration.Version < current {
+			target = migration.Version
+		}
+	}
+	return m.To(ctx, target)
+}
+
+// To migrates up or down until version is the latest applied migration.
+// Version 0 reverts everything.
+func (m *Migrator) To(ctx context.Context, version int) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(ctx, migration, migration.Up, true); err != nil {
+				return err
+			}
+		}
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.apply(ctx, migration, migration.Down, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// apply runs the statements of one migration and records it in a single
+// transaction. MariaDB commits DDL implicitly, so there a failing migration
+// may leave its earlier statements applied.
+func (m *Migrator) apply(ctx context.Context, migration Migration, statements []string, up bool) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if up && migration.Requires != nil {
+		if err := migration.Requires(ctx, tx); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	if up {
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, applied_at) VALUES ("+m.ph(1)+", "+m.ph(2)+", "+m.ph(3)+")",
+			migration.Version, migration.Name, time.Now().UTC())
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = "+m.ph(1), migration.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
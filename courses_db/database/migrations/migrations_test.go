@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestMigratorSqlite(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	m, err := New(db, "sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Check(ctx); !errors.Is(err, ErrOutOfDate) {
+		t.Fatalf("Check() on empty database = %v, want ErrOutOfDate", err)
+	}
+	if err := m.Up(ctx); errors.Is(err, ErrNoFTS5) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check() after Up() = %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO categories (id, name, description) VALUES ('c', 'n', 'd')"); err != nil {
+		t.Fatalf("schema not usable: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO courses (id, name, description, category_id) VALUES ('1', 'n', 'd', 'c')"); err != nil {
+		t.Fatalf("schema not usable: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO courses (id, name, description, category_id) VALUES ('2', 'n', 'd', 'missing')"); err == nil {
+		t.Fatal("course with unknown category accepted")
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		want    int
+		wantErr bool
+	}{
+		{name: "down one", run: func() error { return m.Down(ctx) }, want: m.Latest() - 1},
+		{name: "to 1", run: func() error { return m.To(ctx, 1) }, want: 1},
+		{name: "to latest", run: func() error { return m.To(ctx, m.Latest()) }, want: m.Latest()},
+		{name: "to unknown", run: func() error { return m.To(ctx, 999) }, want: m.Latest(), wantErr: true},
+		{name: "to 0", run: func() error { return m.To(ctx, 0) }, want: 0},
+		{name: "down on empty", run: func() error { return m.Down(ctx) }, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := m.Current(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Current() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUnknownDriver(t *testing.T) {
+	if _, err := New(nil, "oracle"); !errors.Is(err, ErrUnknownDialect) {
+		t.Errorf("New() error = %v, want ErrUnknownDialect", err)
+	}
+}
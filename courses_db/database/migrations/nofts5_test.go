@@ -0,0 +1,35 @@
+//go:build !sqlite_fts5 && !fts5
+
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Without the sqlite_fts5 tag the sqlite migrations stop before full-text
+// search with an error naming the tag.
+func TestMigratorSqliteWithoutFTS5(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	m, err := New(db, "sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Up(ctx); !errors.Is(err, ErrNoFTS5) {
+		t.Fatalf("Up() error = %v, want ErrNoFTS5", err)
+	}
+	if got, err := m.Current(ctx); err != nil || got != 8 {
+		t.Errorf("Current() = %d, %v, want 8", got, err)
+	}
+}
@@ -0,0 +1,208 @@
+package migrations
+
+var postgresMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create tables",
+		Up: []string{
+			"CREATE TABLE IF NOT EXISTS categories (id UUID PRIMARY KEY, name TEXT NOT NULL DEFAULT '', description TEXT NOT NULL DEFAULT '')",
+			"CREATE TABLE IF NOT EXISTS courses (id UUID PRIMARY KEY, name TEXT NOT NULL DEFAULT '', description TEXT NOT NULL DEFAULT '', category_id UUID NOT NULL)",
+			"CREATE TABLE IF NOT EXISTS users (id UUID PRIMARY KEY, name TEXT NOT NULL DEFAULT '', email TEXT NOT NULL DEFAULT '', password TEXT NOT NULL DEFAULT '')",
+		},
+		Down: []string{
+			"DROP TABLE users",
+			"DROP TABLE courses",
+			"DROP TABLE categories",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add created_at",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+			"ALTER TABLE courses ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+		},
+		Down: []string{
+			"ALTER TABLE courses DROP COLUMN created_at",
+			"ALTER TABLE categories DROP COLUMN created_at",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add lookup indexes",
+		Up: []string{
+			"CREATE INDEX idx_courses_category_id ON courses (category_id)",
+			"CREATE INDEX idx_courses_name ON courses (name, id)",
+			"CREATE INDEX idx_categories_name ON categories (name, id)",
+			"CREATE INDEX idx_users_email ON users (email)",
+		},
+		Down: []string{
+			"DROP INDEX idx_users_email",
+			"DROP INDEX idx_categories_name",
+			"DROP INDEX idx_courses_name",
+			"DROP INDEX idx_courses_category_id",
+		},
+	},
+	{
+		Version: 4,
+		Name:    "unique user email",
+		Up: []string{
+			"DROP INDEX idx_users_email",
+			"CREATE UNIQUE INDEX idx_users_email ON users (email)",
+		},
+		Down: []string{
+			"DROP INDEX idx_users_email",
+			"CREATE INDEX idx_users_email ON users (email)",
+		},
+	},
+	{
+		Version: 5,
+		Name:    "course category foreign key",
+		Up: []string{
+			"ALTER TABLE courses ADD CONSTRAINT fk_courses_category FOREIGN KEY (category_id) REFERENCES categories (id) ON DELETE RESTRICT",
+		},
+		Down: []string{
+			"ALTER TABLE courses DROP CONSTRAINT fk_courses_category",
+		},
+	},
+	{
+		Version: 6,
+		Name:    "soft delete",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMPTZ NULL",
+			"ALTER TABLE courses ADD COLUMN deleted_at TIMESTAMPTZ NULL",
+			"ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ NULL",
+		},
+		Down: []string{
+			"ALTER TABLE users DROP COLUMN deleted_at",
+			"ALTER TABLE courses DROP COLUMN deleted_at",
+			"ALTER TABLE categories DROP COLUMN deleted_at",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "audit columns and history",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+			"ALTER TABLE categories ADD COLUMN created_by TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE categories ADD COLUMN updated_by TEXT NOT NULL DEFAULT ''",
+			"UPDATE categories SET updated_at = created_at",
+			"ALTER TABLE courses ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+			"ALTER TABLE courses ADD COLUMN created_by TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE courses ADD COLUMN updated_by TEXT NOT NULL DEFAULT ''",
+			"UPDATE courses SET updated_at = created_at",
+			"ALTER TABLE users ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+			"ALTER TABLE users ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now()",
+			"ALTER TABLE users ADD COLUMN created_by TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE users ADD COLUMN updated_by TEXT NOT NULL DEFAULT ''",
+			"CREATE TABLE history (id BIGSERIAL PRIMARY KEY, entity_type VARCHAR(16) NOT NULL, entity_id UUID NOT NULL, action VARCHAR(16) NOT NULL, before_state JSONB NULL, after_state JSONB NULL, changed_by TEXT NOT NULL, changed_at TIMESTAMPTZ NOT NULL)",
+			"CREATE INDEX idx_history_entity ON history (entity_type, entity_id, id)",
+		},
+		Down: []string{
+			"DROP INDEX idx_history_entity",
+			"DROP TABLE history",
+			"ALTER TABLE users DROP COLUMN updated_by",
+			"ALTER TABLE users DROP COLUMN created_by",
+			"ALTER TABLE users DROP COLUMN updated_at",
+			"ALTER TABLE users DROP COLUMN created_at",
+			"ALTER TABLE courses DROP COLUMN updated_by",
+			"ALTER TABLE courses DROP COLUMN created_by",
+			"ALTER TABLE courses DROP COLUMN updated_at",
+			"ALTER TABLE categories DROP COLUMN updated_by",
+			"ALTER TABLE categories DROP COLUMN created_by",
+			"ALTER TABLE categories DROP COLUMN updated_at",
+		},
+	},
+	{
+		Version: 8,
+		Name:    "row versions",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 1",
+			"ALTER TABLE courses ADD COLUMN version BIGINT NOT NULL DEFAULT 1",
+			"ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1",
+		},
+		Down: []string{
+			"ALTER TABLE users DROP COLUMN version",
+			"ALTER TABLE courses DROP COLUMN version",
+			"ALTER TABLE categories DROP COLUMN version",
+		},
+	},
+	{
+		// Search queries must repeat these expressions for the indexes to be
+		// used.
+		Version: 9,
+		Name:    "full-text search",
+		Up: []string{
+			"CREATE INDEX idx_categories_search ON categories USING GIN (to_tsvector('simple', name || ' ' || description))",
+			"CREATE INDEX idx_courses_search ON courses USING GIN (to_tsvector('simple', name || ' ' || description))",
+		},
+		Down: []string{
+			"DROP INDEX idx_courses_search",
+			"DROP INDEX idx_categories_search",
+		},
+	},
+	{
+		Version: 10,
+		Name:    "modules and lessons",
+		Up: []string{
+			"CREATE TABLE modules (id UUID PRIMARY KEY, course_id UUID NOT NULL REFERENCES courses (id) ON DELETE CASCADE, title TEXT NOT NULL, description TEXT NOT NULL DEFAULT '', position INTEGER NOT NULL, created_at TIMESTAMPTZ NOT NULL, updated_at TIMESTAMPTZ NOT NULL, created_by TEXT NOT NULL DEFAULT '', updated_by TEXT NOT NULL DEFAULT '', version BIGINT NOT NULL DEFAULT 1)",
+			"CREATE INDEX idx_modules_course ON modules (course_id, position)",
+			"CREATE TABLE lessons (id UUID PRIMARY KEY, module_id UUID NOT NULL REFERENCES modules (id) ON DELETE CASCADE, title TEXT NOT NULL, content_type VARCHAR(16) NOT NULL, duration_seconds BIGINT NOT NULL DEFAULT 0, body TEXT NOT NULL DEFAULT '', position INTEGER NOT NULL, created_at TIMESTAMPTZ NOT NULL, updated_at TIMESTAMPTZ NOT NULL, created_by TEXT NOT NULL DEFAULT '', updated_by TEXT NOT NULL DEFAULT '', version BIGINT NOT NULL DEFAULT 1)",
+			"CREATE INDEX idx_lessons_module ON lessons (module_id, position)",
+		},
+		Down: []string{
+			"DROP TABLE lessons",
+			"DROP TABLE modules",
+		},
+	},
+	{
+		Version: 11,
+		Name:    "enrollments",
+		Up: []string{
+			"CREATE TABLE enrollments (user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE, course_id UUID NOT NULL REFERENCES courses (id) ON DELETE CASCADE, progress INTEGER NOT NULL DEFAULT 0, enrolled_at TIMESTAMPTZ NOT NULL, last_accessed_at TIMESTAMPTZ NULL, PRIMARY KEY (user_id, course_id))",
+			"CREATE INDEX idx_enrollments_course ON enrollments (course_id, user_id)",
+		},
+		Down: []string{
+			"DROP TABLE enrollments",
+		},
+	},
+	{
+		Version: 12,
+		Name:    "course instructors",
+		// Courses already there are taught by whoever created them.
+		Up: []string{
+			"CREATE TABLE course_instructors (course_id UUID NOT NULL REFERENCES courses (id) ON DELETE CASCADE, user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE, added_at TIMESTAMPTZ NOT NULL, PRIMARY KEY (course_id, user_id))",
+			"CREATE INDEX idx_course_instructors_user ON course_instructors (user_id, course_id)",
+			"INSERT INTO course_instructors (course_id, user_id, added_at) SELECT c.id, u.id, c.created_at FROM courses c JOIN users u ON u.email = c.created_by",
+		},
+		Down: []string{
+			"DROP TABLE course_instructors",
+		},
+	},
+	{
+		Version: 13,
+		Name:    "category tree",
+		// Purging a category lifts its subcategories to the top level.
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN parent_id UUID NULL REFERENCES categories (id) ON DELETE SET NULL",
+			"CREATE INDEX idx_categories_parent ON categories (parent_id, id)",
+		},
+		Down: []string{
+			"ALTER TABLE categories DROP COLUMN parent_id",
+		},
+	},
+	{
+		Version: 14,
+		Name:    "course tags",
+		Up: []string{
+			"CREATE TABLE tags (id UUID PRIMARY KEY, name VARCHAR(100) NOT NULL, created_at TIMESTAMPTZ NOT NULL, created_by TEXT NOT NULL DEFAULT '')",
+			"CREATE UNIQUE INDEX idx_tags_name ON tags (name)",
+			"CREATE TABLE course_tags (course_id UUID NOT NULL REFERENCES courses (id) ON DELETE CASCADE, tag_id UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE, added_at TIMESTAMPTZ NOT NULL, PRIMARY KEY (course_id, tag_id))",
+			"CREATE INDEX idx_course_tags_tag ON course_tags (tag_id, course_id)",
+		},
+		Down: []string{
+			"DROP TABLE course_tags",
+			"DROP TABLE tags",
+		},
+	},
+}
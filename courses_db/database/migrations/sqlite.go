@@ -0,0 +1,272 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+)
+
+var sqliteMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create tables",
+		Up: []string{
+			"CREATE TABLE IF NOT EXISTS categories (id CHAR(36) PRIMARY KEY, name TEXT, description TEXT)",
+			"CREATE TABLE IF NOT EXISTS courses (id CHAR(36) PRIMARY KEY, name TEXT, description TEXT, category_id TEXT)",
+			"CREATE TABLE IF NOT EXISTS users (id CHAR(36) PRIMARY KEY, name TEXT, email TEXT, password TEXT)",
+		},
+		Down: []string{
+			"DROP TABLE users",
+			"DROP TABLE courses",
+			"DROP TABLE categories",
+		},
+	},
+	{
+		Version: 2,
+		Name:    "add created_at",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'",
+			"ALTER TABLE courses ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'",
+		},
+		Down: []string{
+			"ALTER TABLE courses DROP COLUMN created_at",
+			"ALTER TABLE categories DROP COLUMN created_at",
+		},
+	},
+	{
+		Version: 3,
+		Name:    "add lookup indexes",
+		Up: []string{
+			"CREATE INDEX idx_courses_category_id ON courses (category_id)",
+			"CREATE INDEX idx_courses_name ON courses (name, id)",
+			"CREATE INDEX idx_categories_name ON categories (name, id)",
+			"CREATE INDEX idx_users_email ON users (email)",
+		},
+		Down: []string{
+			"DROP INDEX idx_users_email",
+			"DROP INDEX idx_categories_name",
+			"DROP INDEX idx_courses_name",
+			"DROP INDEX idx_courses_category_id",
+		},
+	},
+	{
+		Version: 4,
+		Name:    "unique user email",
+		Up: []string{
+			"DROP INDEX idx_users_email",
+			"CREATE UNIQUE INDEX idx_users_email ON users (email)",
+		},
+		Down: []string{
+			"DROP INDEX idx_users_email",
+			"CREATE INDEX idx_users_email ON users (email)",
+		},
+	},
+	{
+		// SQLite cannot add a constraint to an existing table, so courses is
+		// rebuilt. Courses whose category is gone make this step fail.
+		Version: 5,
+		Name:    "course category foreign key",
+		Up: []string{
+			"CREATE TABLE courses_new (id CHAR(36) PRIMARY KEY, name TEXT, description TEXT, category_id TEXT NOT NULL REFERENCES categories (id) ON DELETE RESTRICT, created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00')",
+			"INSERT INTO courses_new (id, name, description, category_id, created_at) SELECT id, name, description, category_id, created_at FROM courses",
+			"DROP TABLE courses",
+			"ALTER TABLE courses_new RENAME TO courses",
+			"CREATE INDEX idx_courses_category_id ON courses (category_id)",
+			"CREATE INDEX idx_courses_name ON courses (name, id)",
+		},
+		Down: []string{
+			"CREATE TABLE courses_old (id CHAR(36) PRIMARY KEY, name TEXT, description TEXT, category_id TEXT, created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00')",
+			"INSERT INTO courses_old (id, name, description, category_id, created_at) SELECT id, name, description, category_id, created_at FROM courses",
+			"DROP TABLE courses",
+			"ALTER TABLE courses_old RENAME TO courses",
+			"CREATE INDEX idx_courses_category_id ON courses (category_id)",
+			"CREATE INDEX idx_courses_name ON courses (name, id)",
+		},
+	},
+	{
+		Version: 6,
+		Name:    "soft delete",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN deleted_at DATETIME NULL",
+			"ALTER TABLE courses ADD COLUMN deleted_at DATETIME NULL",
+			"ALTER TABLE users ADD COLUMN deleted_at DATETIME NULL",
+		},
+		Down: []string{
+			"ALTER TABLE users DROP COLUMN deleted_at",
+			"ALTER TABLE courses DROP COLUMN deleted_at",
+			"ALTER TABLE categories DROP COLUMN deleted_at",
+		},
+	},
+	{
+		Version: 7,
+		Name:    "audit columns and history",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'",
+			"ALTER TABLE categories ADD COLUMN created_by TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE categories ADD COLUMN updated_by TEXT NOT NULL DEFAULT ''",
+			"UPDATE categories SET updated_at = created_at",
+			"ALTER TABLE courses ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'",
+			"ALTER TABLE courses ADD COLUMN created_by TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE courses ADD COLUMN updated_by TEXT NOT NULL DEFAULT ''",
+			"UPDATE courses SET updated_at = created_at",
+			"ALTER TABLE users ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'",
+			"ALTER TABLE users ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00+00:00'",
+			"ALTER TABLE users ADD COLUMN created_by TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE users ADD COLUMN updated_by TEXT NOT NULL DEFAULT ''",
+			"CREATE TABLE history (id INTEGER PRIMARY KEY AUTOINCREMENT, entity_type VARCHAR(16) NOT NULL, entity_id CHAR(36) NOT NULL, action VARCHAR(16) NOT NULL, before_state TEXT NULL, after_state TEXT NULL, changed_by TEXT NOT NULL, changed_at DATETIME NOT NULL)",
+			"CREATE INDEX idx_history_entity ON history (entity_type, entity_id, id)",
+		},
+		Down: []string{
+			"DROP INDEX idx_history_entity",
+			"DROP TABLE history",
+			"ALTER TABLE users DROP COLUMN updated_by",
+			"ALTER TABLE users DROP COLUMN created_by",
+			"ALTER TABLE users DROP COLUMN updated_at",
+			"ALTER TABLE users DROP COLUMN created_at",
+			"ALTER TABLE courses DROP COLUMN updated_by",
+			"ALTER TABLE courses DROP COLUMN created_by",
+			"ALTER TABLE courses DROP COLUMN updated_at",
+			"ALTER TABLE categories DROP COLUMN updated_by",
+			"ALTER TABLE categories DROP COLUMN created_by",
+			"ALTER TABLE categories DROP COLUMN updated_at",
+		},
+	},
+	{
+		Version: 8,
+		Name:    "row versions",
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN version INTEGER NOT NULL DEFAULT 1",
+			"ALTER TABLE courses ADD COLUMN version INTEGER NOT NULL DEFAULT 1",
+			"ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1",
+		},
+		Down: []string{
+			"ALTER TABLE users DROP COLUMN version",
+			"ALTER TABLE courses DROP COLUMN version",
+			"ALTER TABLE categories DROP COLUMN version",
+		},
+	},
+	{
+		// FTS5 needs go-sqlite3 built with the sqlite_fts5 tag. search_docs
+		// gives every course and category an integer rowid in the index.
+		Version:  9,
+		Name:     "full-text search",
+		Requires: fts5,
+		Up: []string{
+			"CREATE TABLE search_docs (docid INTEGER PRIMARY KEY AUTOINCREMENT, entity_type VARCHAR(16) NOT NULL, entity_id CHAR(36) NOT NULL, UNIQUE (entity_type, entity_id))",
+			"CREATE VIRTUAL TABLE search_index USING fts5(name, description, tokenize='unicode61')",
+			"INSERT INTO search_docs (entity_type, entity_id) SELECT 'category', id FROM categories",
+			"INSERT INTO search_docs (entity_type, entity_id) SELECT 'course', id FROM courses",
+			"INSERT INTO search_index (rowid, name, description) SELECT d.docid, c.name, c.description FROM search_docs d JOIN categories c ON d.entity_type = 'category' AND c.id = d.entity_id",
+			"INSERT INTO search_index (rowid, name, description) SELECT d.docid, c.name, c.description FROM search_docs d JOIN courses c ON d.entity_type = 'course' AND c.id = d.entity_id",
+			`CREATE TRIGGER categories_search_insert AFTER INSERT ON categories BEGIN
+				INSERT INTO search_docs (entity_type, entity_id) VALUES ('category', new.id);
+				INSERT INTO search_index (rowid, name, description) SELECT docid, new.name, new.description FROM search_docs WHERE entity_type = 'category' AND entity_id = new.id;
+			END`,
+			`CREATE TRIGGER categories_search_update AFTER UPDATE OF name, description ON categories BEGIN
+				UPDATE search_index SET name = new.name, description = new.description WHERE rowid = (SELECT docid FROM search_docs WHERE entity_type = 'category' AND entity_id = new.id);
+			END`,
+			`CREATE TRIGGER categories_search_delete AFTER DELETE ON categories BEGIN
+				DELETE FROM search_index WHERE rowid = (SELECT docid FROM search_docs WHERE entity_type = 'category' AND entity_id = old.id);
+				DELETE FROM search_docs WHERE entity_type = 'category' AND entity_id = old.id;
+			END`,
+			`CREATE TRIGGER courses_search_insert AFTER INSERT ON courses BEGIN
+				INSERT INTO search_docs (entity_type, entity_id) VALUES ('course', new.id);
+				INSERT INTO search_index (rowid, name, description) SELECT docid, new.name, new.description FROM search_docs WHERE entity_type = 'course' AND entity_id = new.id;
+			END`,
+			`CREATE TRIGGER courses_search_update AFTER UPDATE OF name, description ON courses BEGIN
+				UPDATE search_index SET name = new.name, description = new.description WHERE rowid = (SELECT docid FROM search_docs WHERE entity_type = 'course' AND entity_id = new.id);
+			END`,
+			`CREATE TRIGGER courses_search_delete AFTER DELETE ON courses BEGIN
+				DELETE FROM search_index WHERE rowid = (SELECT docid FROM search_docs WHERE entity_type = 'course' AND entity_id = old.id);
+				DELETE FROM search_docs WHERE entity_type = 'course' AND entity_id = old.id;
+			END`,
+		},
+		Down: []string{
+			"DROP TRIGGER courses_search_delete",
+			"DROP TRIGGER courses_search_update",
+			"DROP TRIGGER courses_search_insert",
+			"DROP TRIGGER categories_search_delete",
+			"DROP TRIGGER categories_search_update",
+			"DROP TRIGGER categories_search_insert",
+			"DROP TABLE search_index",
+			"DROP TABLE search_docs",
+		},
+	},
+	{
+		Version: 10,
+		Name:    "modules and lessons",
+		Up: []string{
+			"CREATE TABLE modules (id CHAR(36) PRIMARY KEY, course_id CHAR(36) NOT NULL REFERENCES courses (id) ON DELETE CASCADE, title TEXT NOT NULL, description TEXT NOT NULL DEFAULT '', position INTEGER NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL, created_by TEXT NOT NULL DEFAULT '', updated_by TEXT NOT NULL DEFAULT '', version INTEGER NOT NULL DEFAULT 1)",
+			"CREATE INDEX idx_modules_course ON modules (course_id, position)",
+			"CREATE TABLE lessons (id CHAR(36) PRIMARY KEY, module_id CHAR(36) NOT NULL REFERENCES modules (id) ON DELETE CASCADE, title TEXT NOT NULL, content_type VARCHAR(16) NOT NULL, duration_seconds INTEGER NOT NULL DEFAULT 0, body TEXT NOT NULL DEFAULT '', position INTEGER NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL, created_by TEXT NOT NULL DEFAULT '', updated_by TEXT NOT NULL DEFAULT '', version INTEGER NOT NULL DEFAULT 1)",
+			"CREATE INDEX idx_lessons_module ON lessons (module_id, position)",
+		},
+		Down: []string{
+			"DROP TABLE lessons",
+			"DROP TABLE modules",
+		},
+	},
+	{
+		Version: 11,
+		Name:    "enrollments",
+		Up: []string{
+			"CREATE TABLE enrollments (user_id CHAR(36) NOT NULL REFERENCES users (id) ON DELETE CASCADE, course_id CHAR(36) NOT NULL REFERENCES courses (id) ON DELETE CASCADE, progress INTEGER NOT NULL DEFAULT 0, enrolled_at DATETIME NOT NULL, last_accessed_at DATETIME NULL, PRIMARY KEY (user_id, course_id))",
+			"CREATE INDEX idx_enrollments_course ON enrollments (course_id, user_id)",
+		},
+		Down: []string{
+			"DROP TABLE enrollments",
+		},
+	},
+	{
+		Version: 12,
+		Name:    "course instructors",
+		// Courses already there are taught by whoever created them.
+		Up: []string{
+			"CREATE TABLE course_instructors (course_id CHAR(36) NOT NULL REFERENCES courses (id) ON DELETE CASCADE, user_id CHAR(36) NOT NULL REFERENCES users (id) ON DELETE CASCADE, added_at DATETIME NOT NULL, PRIMARY KEY (course_id, user_id))",
+			"CREATE INDEX idx_course_instructors_user ON course_instructors (user_id, course_id)",
+			"INSERT INTO course_instructors (course_id, user_id, added_at) SELECT c.id, u.id, c.created_at FROM courses c JOIN users u ON u.email = c.created_by",
+		},
+		Down: []string{
+			"DROP TABLE course_instructors",
+		},
+	},
+	{
+		Version: 13,
+		Name:    "category tree",
+		// Purging a category lifts its subcategories to the top level.
+		Up: []string{
+			"ALTER TABLE categories ADD COLUMN parent_id CHAR(36) NULL REFERENCES categories (id) ON DELETE SET NULL",
+			"CREATE INDEX idx_categories_parent ON categories (parent_id, id)",
+		},
+		Down: []string{
+			"DROP INDEX idx_categories_parent",
+			"ALTER TABLE categories DROP COLUMN parent_id",
+		},
+	},
+	{
+		Version: 14,
+		Name:    "course tags",
+		Up: []string{
+			"CREATE TABLE tags (id CHAR(36) PRIMARY KEY, name VARCHAR(100) NOT NULL, created_at DATETIME NOT NULL, created_by TEXT NOT NULL DEFAULT '')",
+			"CREATE UNIQUE INDEX idx_tags_name ON tags (name)",
+			"CREATE TABLE course_tags (course_id CHAR(36) NOT NULL REFERENCES courses (id) ON DELETE CASCADE, tag_id CHAR(36) NOT NULL REFERENCES tags (id) ON DELETE CASCADE, added_at DATETIME NOT NULL, PRIMARY KEY (course_id, tag_id))",
+			"CREATE INDEX idx_course_tags_tag ON course_tags (tag_id, course_id)",
+		},
+		Down: []string{
+			"DROP TABLE course_tags",
+			"DROP TABLE tags",
+		},
+	},
+}
+
+// fts5 fails with ErrNoFTS5 unless the sqlite3 driver was compiled with the
+// FTS5 module, instead of letting migration 9 die on "no such module".
+func fts5(ctx context.Context, tx *sql.Tx) error {
+	var enabled bool
+	if err := tx.QueryRowContext(ctx, "SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrNoFTS5
+	}
+	return nil
+}
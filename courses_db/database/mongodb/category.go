@@ -0,0 +1,433 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type category struct {
+	ID          string     `bson:"_id"`
+	Name        string     `bson:"name"`
+	Description string     `bson:"description"`
+	ParentID    string     `bson:"parent_id,omitempty"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at"`
+	CreatedBy   string     `bson:"created_by"`
+	UpdatedBy   string     `bson:"updated_by"`
+	Version     int64      `bson:"version"`
+	DeletedAt   *time.Time `bson:"deleted_at,omitempty"`
+}
+
+func (c category) dto() dto.CategoryOutputDto {
+	updatedAt := c.UpdatedAt
+	if updatedAt.IsZero() {
+		// written before the audit fields existed
+		updatedAt = c.CreatedAt
+	}
+	return dto.CategoryOutputDto{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		ParentID:    c.ParentID,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   updatedAt,
+		CreatedBy:   c.CreatedBy,
+		UpdatedBy:   c.UpdatedBy,
+		Version:     c.Version,
+		DeletedAt:   c.DeletedAt,
+	}
+}
+
+type CategoryRepository struct {
+	db       *mongo.Database
+	onDelete query.OnDelete
+}
+
+func NewCategoryRepository(db *mongo.Database, onDelete query.OnDelete) *CategoryRepository {
+	return &CategoryRepository{db: db, onDelete: onDelete}
+}
+
+func (c *CategoryRepository) Create(ctx context.Context, categoryDto dto.CategoryInputDto) (dto.CategoryOutputDto, error) {
+	if err := dberr.ValidateCategory(categoryDto); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	if err := c.checkParent(ctx, "", categoryDto.ParentID); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	createdAt, actor := now(), audit.Actor(ctx)
+	doc := category{
+		ID:          uuid.New().String(),
+		Name:        categoryDto.Name,
+		Description: categoryDto.Description,
+		ParentID:    categoryDto.ParentID,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	if _, err := c.db.Collection(categoriesCollection).InsertOne(ctx, doc); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	if err := record(ctx, c.db, audit.Category, doc.ID, audit.Create, nil, doc.dto()); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	return doc.dto(), nil
+}
+
+// CreateBatch creates categories with one insert. Invalid categories are
+// rejected in their result and the others created.
+func (c *CategoryRepository) CreateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error) {
+	createdAt, actor := now(), audit.Actor(ctx)
+	results := make([]dto.BatchItemOutputDto, len(categories))
+	var docs, history []any
+	parents := map[string]error{}
+	for i, categoryDto := range categories {
+		results[i] = dto.BatchItemOutputDto{Index: i}
+		err := dberr.ValidateCategory(categoryDto)
+		if err == nil {
+			checked, ok := parents[categoryDto.ParentID]
+			if !ok {
+				checked = c.checkParent(ctx, "", categoryDto.ParentID)
+				if checked != nil && !dberr.Rejected(checked) {
+					return nil, checked
+				}
+				parents[categoryDto.ParentID] = checked
+			}
+			err = checked
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		doc := category{
+			ID:          uuid.New().String(),
+			Name:        categoryDto.Name,
+			Description: categoryDto.Description,
+			ParentID:    categoryDto.ParentID,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			CreatedBy:   actor,
+			UpdatedBy:   actor,
+			Version:     1,
+		}
+		entry, err := historyDoc(ctx, audit.Category, doc.ID, audit.Create, nil, doc.dto())
+		if err != nil {
+			return nil, err
+		}
+		results[i].ID = doc.ID
+		docs, history = append(docs, doc), append(history, entry)
+	}
+	if err := insertMany(ctx, c.db, categoriesCollection, docs, history); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *CategoryRepository) FindAll(ctx context.Context, page query.Page) (dto.CategoryListOutputDto, error) {
+	return c.List(ctx, query.CategorySpec{Page: page})
+}
+
+func (c *CategoryRepository) List(ctx context.Context, spec query.CategorySpec) (dto.CategoryListOutputDto, error) {
+	filter := listFilter(spec.IncludeDeleted)
+	if spec.ParentID != "" {
+		filter = append(filter, bson.E{Key: "parent_id", Value: spec.ParentID})
+	}
+	filter, opts, err := listFind(filter, spec.NamePrefix, spec.Sort, spec.Page)
+	if err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	cursor, err := c.db.Collection(categoriesCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	var docs []category
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	categories := dto.CategoryListOutputDto{}
+	for _, doc := range docs {
+		categories.Categories = append(categories.Categories, doc.dto())
+	}
+	categories.Categories, categories.NextCursor = query.Trim(categories.Categories, spec.Page,
+		func(c dto.CategoryOutputDto) string { return spec.Sort.Cursor(c.ID, c.Name, c.CreatedAt) })
+	return categories, nil
+}
+
+func (c *CategoryRepository) FindByCourseID(ctx context.Context, courseID string) (dto.CategoryOutputDto, error) {
+	var doc course
+	err := c.db.Collection(coursesCollection).FindOne(ctx, liveID(courseID)).Decode(&doc)
+	if err != nil {
+		return dto.CategoryOutputDto{}, noDocuments("category", err)
+	}
+	return c.Find(ctx, doc.CategoryID)
+}
+
+func (c *CategoryRepository) Find(ctx context.Context, id string) (dto.CategoryOutputDto, error) {
+	var doc category
+	err := c.db.Collection(categoriesCollection).FindOne(ctx, liveID(id)).Decode(&doc)
+	if err != nil {
+		return dto.CategoryOutputDto{}, noDocuments("category", err)
+	}
+	return doc.dto(), nil
+}
+
+func (c *CategoryRepository) Update(ctx context.Context, categoryDto dto.CategoryInputDto) error {
+	if err := dberr.ValidateCategory(categoryDto); err != nil {
+		return err
+	}
+	if err := c.checkParent(ctx, categoryDto.ID, categoryDto.ParentID); err != nil {
+		return err
+	}
+	updatedAt := now()
+	fields := []bson.E{
+		{Key: "name", Value: categoryDto.Name},
+		{Key: "description", Value: categoryDto.Description},
+	}
+	if categoryDto.ParentID != "" {
+		fields = append(fields, bson.E{Key: "parent_id", Value: categoryDto.ParentID})
+	}
+	update := bson.D{stamp(ctx, updatedAt, fields...), bump}
+	if categoryDto.ParentID == "" {
+		// top-level categories have no parent_id at all
+		update = append(update, bson.E{Key: "$unset", Value: bson.D{{Key: "parent_id", Value: ""}}})
+	}
+	var doc category
+	err := c.db.Collection(categoriesCollection).FindOneAndUpdate(ctx, versioned(liveID(categoryDto.ID), categoryDto.Version), update, returnBefore).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notUpdated(ctx, c.db.Collection(categoriesCollection), "category", categoryDto.ID, categoryDto.Version)
+	}
+	if err != nil {
+		return err
+	}
+	before := doc.dto()
+	after := before
+	after.Name, after.Description, after.ParentID = categoryDto.Name, categoryDto.Description, categoryDto.ParentID
+	after.UpdatedAt, after.UpdatedBy = updatedAt, audit.Actor(ctx)
+	after.Version++
+	return record(ctx, c.db, audit.Category, doc.ID, audit.Update, before, after)
+}
+
+func (c *CategoryRepository) Delete(ctx context.Context, id string) error {
+	children, err := c.db.Collection(categoriesCollection).CountDocuments(ctx, bson.D{{Key: "parent_id", Value: id}, notDeleted})
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return dberr.CategoryHasChildren
+	}
+	byCategory := bson.D{{Key: "category_id", Value: id}, notDeleted}
+	deletedAt := now()
+	if c.onDelete == query.Cascade {
+		// without a transaction the courses go first, a failure leaves an
+		// empty category rather than courses in a deleted one
+		ids, err := c.db.Collection(coursesCollection).Distinct(ctx, "_id", byCategory)
+		if err != nil {
+			return err
+		}
+		for _, courseID := range ids {
+			if err := deleteCourse(ctx, c.db, courseID.(string), deletedAt); err != nil && !errors.Is(err, dberr.ErrNotFound) {
+				return err
+			}
+		}
+	} else {
+		count, err := c.db.Collection(coursesCollection).CountDocuments(ctx, byCategory)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return dberr.CategoryHasCourses
+		}
+	}
+	var doc category
+	err = c.db.Collection(categoriesCollection).FindOneAndUpdate(ctx, liveID(id), softDelete(ctx, deletedAt), returnBefore).Decode(&doc)
+	if err != nil {
+		return noDocuments("category", err)
+	}
+	before := doc.dto()
+	after := before
+	after.DeletedAt, after.UpdatedAt, after.UpdatedBy = &deletedAt, deletedAt, audit.Actor(ctx)
+	after.Version++
+	return record(ctx, c.db, audit.Category, id, audit.Delete, before, after)
+}
+
+// UpdateBatch updates categories one by one. A category that cannot be
+// updated is rejected in its result and the others updated.
+func (c *CategoryRepository) UpdateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error) {
+	return batch(len(categories), func(i int) (string, error) {
+		return categories[i].ID, c.Update(ctx, categories[i])
+	})
+}
+
+// DeleteBatch deletes categories one by one, with their courses when
+// deletes cascade. A category that cannot be deleted is rejected in its
+// result and the others deleted.
+func (c *CategoryRepository) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	return batch(len(ids), func(i int) (string, error) {
+		return ids[i], c.Delete(ctx, ids[i])
+	})
+}
+
+// Restore brings back a deleted category, provided its parent is not
+// deleted. Its courses stay deleted.
+func (c *CategoryRepository) Restore(ctx context.Context, id string) error {
+	var doc category
+	err := c.db.Collection(categoriesCollection).FindOne(ctx, deletedID(id)).Decode(&doc)
+	if err != nil {
+		return noDocuments("category", err)
+	}
+	if err := c.checkParent(ctx, "", doc.ParentID); err != nil {
+		return err
+	}
+	restoredAt := now()
+	err = c.db.Collection(categoriesCollection).FindOneAndUpdate(ctx, deletedID(id), restore(ctx, restoredAt), returnBefore).Decode(&doc)
+	if err != nil {
+		return noDocuments("category", err)
+	}
+	before := doc.dto()
+	after := before
+	after.DeletedAt, after.UpdatedAt, after.UpdatedBy = nil, restoredAt, audit.Actor(ctx)
+	after.Version++
+	return record(ctx, c.db, audit.Category, id, audit.Restore, before, after)
+}
+
+// Purge removes categories deleted before the given time. Categories still
+// referenced by a course are kept until the course is purged; the
+// subcategories of a purged category move to the top level.
+func (c *CategoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	referenced, err := c.db.Collection(coursesCollection).Distinct(ctx, "category_id", bson.D{})
+	if err != nil {
+		return 0, err
+	}
+	filter := append(deletedBefore(before), bson.E{Key: "_id", Value: bson.D{{Key: "$nin", Value: referenced}}})
+	purged, err := c.db.Collection(categoriesCollection).Distinct(ctx, "_id", filter)
+	if err != nil || len(purged) == 0 {
+		return 0, err
+	}
+	_, err = c.db.Collection(categoriesCollection).UpdateMany(ctx,
+		bson.D{{Key: "parent_id", Value: bson.D{{Key: "$in", Value: purged}}}},
+		bson.D{{Key: "$unset", Value: bson.D{{Key: "parent_id", Value: ""}}}})
+	if err != nil {
+		return 0, err
+	}
+	result, err := c.db.Collection(categoriesCollection).DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: purged}}}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (c *CategoryRepository) FindChildren(ctx context.Context, id string, page query.Page) (dto.CategoryListOutputDto, error) {
+	return c.List(ctx, query.CategorySpec{ParentID: id, Page: page})
+}
+
+// FindAncestors walks up from the category one parent at a time.
+func (c *CategoryRepository) FindAncestors(ctx context.Context, id string) (dto.CategoryListOutputDto, error) {
+	current, err := c.Find(ctx, id)
+	if err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	ancestors := dto.CategoryListOutputDto{}
+	for current.ParentID != "" {
+		var doc category
+		err := c.db.Collection(categoriesCollection).FindOne(ctx, bson.D{{Key: "_id", Value: current.ParentID}}).Decode(&doc)
+		if err != nil {
+			return dto.CategoryListOutputDto{}, noDocuments("category", err)
+		}
+		current = doc.dto()
+		ancestors.Categories = append(ancestors.Categories, current)
+	}
+	slices.Reverse(ancestors.Categories)
+	return ancestors, nil
+}
+
+// FindSubtree reads the tree a level at a time.
+func (c *CategoryRepository) FindSubtree(ctx context.Context, id string) (dto.CategoryListOutputDto, error) {
+	root, err := c.Find(ctx, id)
+	if err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	tree := dto.CategoryListOutputDto{Categories: []dto.CategoryOutputDto{root}}
+	level := []string{id}
+	for len(level) > 0 {
+		docs, err := c.children(ctx, level)
+		if err != nil {
+			return dto.CategoryListOutputDto{}, err
+		}
+		level = level[:0]
+		for _, doc := range docs {
+			tree.Categories = append(tree.Categories, doc.dto())
+			level = append(level, doc.ID)
+		}
+	}
+	return tree, nil
+}
+
+// children returns the live categories directly under any of parents, by id.
+func (c *CategoryRepository) children(ctx context.Context, parents []string) ([]category, error) {
+	filter := bson.D{{Key: "parent_id", Value: bson.D{{Key: "$in", Value: parents}}}, notDeleted}
+	cursor, err := c.db.Collection(categoriesCollection).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []category
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// checkParent rejects a parent that does not exist or is deleted and, when
+// moving the category id, one that is the category itself or one of its
+// subcategories.
+func (c *CategoryRepository) checkParent(ctx context.Context, id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	count, err := c.db.Collection(categoriesCollection).CountDocuments(ctx, liveID(parentID))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return dberr.UnknownParentCategory
+	}
+	if id == "" {
+		return nil
+	}
+	ids, err := subtreeIDs(ctx, c.db, id)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(ids, parentID) {
+		return dberr.CategoryCycle
+	}
+	return nil
+}
+
+// subtreeIDs returns the ids of a category and of its live descendants.
+func subtreeIDs(ctx context.Context, db *mongo.Database, id string) ([]string, error) {
+	ids, level := []string{id}, []any{id}
+	for len(level) > 0 {
+		next, err := db.Collection(categoriesCollection).Distinct(ctx, "_id",
+			bson.D{{Key: "parent_id", Value: bson.D{{Key: "$in", Value: level}}}, notDeleted})
+		if err != nil {
+			return nil, err
+		}
+		level = next
+		for _, child := range next {
+			ids = append(ids, child.(string))
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,338 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type course struct {
+	ID          string     `bson:"_id"`
+	Name        string     `bson:"name"`
+	Description string     `bson:"description"`
+	CategoryID  string     `bson:"category_id"`
+	CreatedAt   time.Time  `bson:"created_at"`
+	UpdatedAt   time.Time  `bson:"updated_at"`
+	CreatedBy   string     `bson:"created_by"`
+	UpdatedBy   string     `bson:"updated_by"`
+	Version     int64      `bson:"version"`
+	DeletedAt   *time.Time `bson:"deleted_at,omitempty"`
+}
+
+func (c course) dto() dto.CourseOutputDto {
+	updatedAt := c.UpdatedAt
+	if updatedAt.IsZero() {
+		// written before the audit fields existed
+		updatedAt = c.CreatedAt
+	}
+	return dto.CourseOutputDto{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		CategoryID:  c.CategoryID,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   updatedAt,
+		CreatedBy:   c.CreatedBy,
+		UpdatedBy:   c.UpdatedBy,
+		Version:     c.Version,
+		DeletedAt:   c.DeletedAt,
+	}
+}
+
+type Course struct {
+	db *mongo.Database
+}
+
+func NewCourseRepository(db *mongo.Database) *Course {
+	return &Course{db: db}
+}
+
+func (c *Course) Create(ctx context.Context, courseDto dto.CourseInputDto) (*dto.CourseOutputDto, error) {
+	if err := dberr.ValidateCourse(courseDto); err != nil {
+		return nil, err
+	}
+	createdAt, actor := now(), audit.Actor(ctx)
+	doc := course{
+		ID:          uuid.New().String(),
+		Name:        courseDto.Name,
+		Description: courseDto.Description,
+		CategoryID:  courseDto.CategoryID,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	if err := c.checkCategory(ctx, courseDto.CategoryID); err != nil {
+		return nil, err
+	}
+	if _, err := c.db.Collection(coursesCollection).InsertOne(ctx, doc); err != nil {
+		return nil, err
+	}
+	if err := addCreator(ctx, c.db, createdAt, doc.ID); err != nil {
+		return nil, err
+	}
+	output := doc.dto()
+	if err := record(ctx, c.db, audit.Course, doc.ID, audit.Create, nil, output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// CreateBatch creates courses with one insert. Invalid courses are rejected
+// in their result and the others created.
+func (c *Course) CreateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	createdAt, actor := now(), audit.Actor(ctx)
+	results := make([]dto.BatchItemOutputDto, len(courses))
+	categories := map[string]error{}
+	var docs, history []any
+	var ids []string
+	for i, courseDto := range courses {
+		results[i] = dto.BatchItemOutputDto{Index: i}
+		err := dberr.ValidateCourse(courseDto)
+		if err == nil {
+			checked, ok := categories[courseDto.CategoryID]
+			if !ok {
+				checked = c.checkCategory(ctx, courseDto.CategoryID)
+				if checked != nil && !dberr.Rejected(checked) {
+					return nil, checked
+				}
+				categories[courseDto.CategoryID] = checked
+			}
+			err = checked
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		doc := course{
+			ID:          uuid.New().String(),
+			Name:        courseDto.Name,
+			Description: courseDto.Description,
+			CategoryID:  courseDto.CategoryID,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+			CreatedBy:   actor,
+			UpdatedBy:   actor,
+			Version:     1,
+		}
+		entry, err := historyDoc(ctx, audit.Course, doc.ID, audit.Create, nil, doc.dto())
+		if err != nil {
+			return nil, err
+		}
+		results[i].ID = doc.ID
+		docs, history = append(docs, doc), append(history, entry)
+		ids = append(ids, doc.ID)
+	}
+	if err := insertMany(ctx, c.db, coursesCollection, docs, history); err != nil {
+		return nil, err
+	}
+	if err := addCreator(ctx, c.db, createdAt, ids...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *Course) FindAll(ctx context.Context, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{Page: page})
+}
+
+func (c *Course) FindByCategoryID(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{CategoryID: categoryID, Page: page})
+}
+
+func (c *Course) FindByCategoryTree(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{CategoryTree: categoryID, Page: page})
+}
+
+func (c *Course) FindByInstructorID(ctx context.Context, userID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{InstructorID: userID, Page: page})
+}
+
+func (c *Course) List(ctx context.Context, spec query.CourseSpec) (dto.CourseListOutputDto, error) {
+	filter := listFilter(spec.IncludeDeleted)
+	if spec.CategoryID != "" {
+		filter = append(filter, bson.E{Key: "category_id", Value: spec.CategoryID})
+	}
+	if spec.CategoryTree != "" {
+		tree, err := subtreeIDs(ctx, c.db, spec.CategoryTree)
+		if err != nil {
+			return dto.CourseListOutputDto{}, err
+		}
+		filter = append(filter, bson.E{Key: "category_id", Value: bson.D{{Key: "$in", Value: tree}}})
+	}
+	// under $and, as the page cursor may add its own condition on _id
+	var among bson.A
+	if spec.InstructorID != "" {
+		taught, err := c.db.Collection(instructorsCollection).Distinct(ctx, "course_id", bson.D{{Key: "user_id", Value: spec.InstructorID}})
+		if err != nil || len(taught) == 0 {
+			return dto.CourseListOutputDto{}, err
+		}
+		among = append(among, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: taught}}}})
+	}
+	if names := query.TagNames(spec.Tags); len(names) > 0 {
+		tagged, err := taggedCourses(ctx, c.db, names, spec.AllTags)
+		if err != nil || len(tagged) == 0 {
+			return dto.CourseListOutputDto{}, err
+		}
+		among = append(among, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: tagged}}}})
+	}
+	if len(among) > 0 {
+		filter = append(filter, bson.E{Key: "$and", Value: among})
+	}
+	filter, opts, err := listFind(filter, spec.NamePrefix, spec.Sort, spec.Page)
+	if err != nil {
+		return dto.CourseListOutputDto{}, err
+	}
+	cursor, err := c.db.Collection(coursesCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return dto.CourseListOutputDto{}, err
+	}
+	var docs []course
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.CourseListOutputDto{}, err
+	}
+	courses := dto.CourseListOutputDto{}
+	for _, doc := range docs {
+		courses.Courses = append(courses.Courses, doc.dto())
+	}
+	courses.Courses, courses.NextCursor = query.Trim(courses.Courses, spec.Page,
+		func(c dto.CourseOutputDto) string { return spec.Sort.Cursor(c.ID, c.Name, c.CreatedAt) })
+	return courses, nil
+}
+
+func (c *Course) Find(ctx context.Context, id string) (dto.CourseOutputDto, error) {
+	var doc course
+	err := c.db.Collection(coursesCollection).FindOne(ctx, liveID(id)).Decode(&doc)
+	if err != nil {
+		return dto.CourseOutputDto{}, noDocuments("course", err)
+	}
+	return doc.dto(), nil
+}
+
+func (c *Course) Update(ctx context.Context, courseDto dto.CourseInputDto) error {
+	if err := dberr.ValidateCourse(courseDto); err != nil {
+		return err
+	}
+	if err := c.checkCategory(ctx, courseDto.CategoryID); err != nil {
+		return err
+	}
+	updatedAt := now()
+	var doc course
+	err := c.db.Collection(coursesCollection).FindOneAndUpdate(ctx, versioned(liveID(courseDto.ID), courseDto.Version), bson.D{stamp(ctx, updatedAt,
+		bson.E{Key: "name", Value: courseDto.Name},
+		bson.E{Key: "description", Value: courseDto.Description},
+		bson.E{Key: "category_id", Value: courseDto.CategoryID},
+	), bump}, returnBefore).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return notUpdated(ctx, c.db.Collection(coursesCollection), "course", courseDto.ID, courseDto.Version)
+	}
+	if err != nil {
+		return err
+	}
+	before := doc.dto()
+	after := before
+	after.Name, after.Description, after.CategoryID = courseDto.Name, courseDto.Description, courseDto.CategoryID
+	after.UpdatedAt, after.UpdatedBy = updatedAt, audit.Actor(ctx)
+	after.Version++
+	return record(ctx, c.db, audit.Course, doc.ID, audit.Update, before, after)
+}
+
+func (c *Course) Delete(ctx context.Context, id string) error {
+	return deleteCourse(ctx, c.db, id, now())
+}
+
+// UpdateBatch updates courses one by one. A course that cannot be updated
+// is rejected in its result and the others updated.
+func (c *Course) UpdateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	return batch(len(courses), func(i int) (string, error) {
+		return courses[i].ID, c.Update(ctx, courses[i])
+	})
+}
+
+// DeleteBatch deletes courses one by one. A course that cannot be deleted
+// is rejected in its result and the others deleted.
+func (c *Course) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	return batch(len(ids), func(i int) (string, error) {
+		return ids[i], c.Delete(ctx, ids[i])
+	})
+}
+
+// deleteCourse soft deletes a live course and records it.
+func deleteCourse(ctx context.Context, db *mongo.Database, id string, deletedAt time.Time) error {
+	var doc course
+	err := db.Collection(coursesCollection).FindOneAndUpdate(ctx, liveID(id), softDelete(ctx, deletedAt), returnBefore).Decode(&doc)
+	if err != nil {
+		return noDocuments("course", err)
+	}
+	before := doc.dto()
+	after := before
+	after.DeletedAt, after.UpdatedAt, after.UpdatedBy = &deletedAt, deletedAt, audit.Actor(ctx)
+	after.Version++
+	return record(ctx, db, audit.Course, id, audit.Delete, before, after)
+}
+
+// Restore brings back a deleted course, provided its category is not deleted.
+func (c *Course) Restore(ctx context.Context, id string) error {
+	var doc course
+	if err := c.db.Collection(coursesCollection).FindOne(ctx, deletedID(id)).Decode(&doc); err != nil {
+		return noDocuments("course", err)
+	}
+	if err := c.checkCategory(ctx, doc.CategoryID); err != nil {
+		return err
+	}
+	restoredAt := now()
+	err := c.db.Collection(coursesCollection).FindOneAndUpdate(ctx, deletedID(id), restore(ctx, restoredAt), returnBefore).Decode(&doc)
+	if err != nil {
+		return noDocuments("course", err)
+	}
+	before := doc.dto()
+	after := before
+	after.DeletedAt, after.UpdatedAt, after.UpdatedBy = nil, restoredAt, audit.Actor(ctx)
+	after.Version++
+	return record(ctx, c.db, audit.Course, id, audit.Restore, before, after)
+}
+
+// Purge removes courses deleted before the given time, with their modules,
+// enrollments, instructors and tags.
+func (c *Course) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ids, err := findIDs(ctx, c.db.Collection(coursesCollection), deletedBefore(before))
+	if err != nil {
+		return 0, err
+	}
+	result, err := c.db.Collection(coursesCollection).DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		return 0, err
+	}
+	if err := purgeEnrollments(ctx, c.db, "course_id", ids); err != nil {
+		return 0, err
+	}
+	if err := purgeInstructors(ctx, c.db, "course_id", ids); err != nil {
+		return 0, err
+	}
+	if err := purgeCourseTags(ctx, c.db, ids); err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, purgeModules(ctx, c.db, ids)
+}
+
+// checkCategory stands in for the foreign key MongoDB does not have; it also
+// rejects a deleted category.
+func (c *Course) checkCategory(ctx context.Context, categoryID string) error {
+	count, err := c.db.Collection(categoriesCollection).CountDocuments(ctx, liveID(categoryID))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return dberr.UnknownCategory
+	}
+	return nil
+}
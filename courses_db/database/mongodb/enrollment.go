@@ -0,0 +1,172 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type enrollment struct {
+	UserID         string     `bson:"user_id"`
+	CourseID       string     `bson:"course_id"`
+	Progress       int        `bson:"progress"`
+	EnrolledAt     time.Time  `bson:"enrolled_at"`
+	LastAccessedAt *time.Time `bson:"last_accessed_at,omitempty"`
+}
+
+func (e enrollment) dto() dto.EnrollmentOutputDto {
+	return dto.EnrollmentOutputDto{
+		UserID:         e.UserID,
+		CourseID:       e.CourseID,
+		Progress:       e.Progress,
+		EnrolledAt:     e.EnrolledAt,
+		LastAccessedAt: e.LastAccessedAt,
+	}
+}
+
+type Enrollment struct {
+	db *mongo.Database
+}
+
+func NewEnrollmentRepository(db *mongo.Database) *Enrollment {
+	return &Enrollment{db: db}
+}
+
+func enrollmentID(userID, courseID string) bson.D {
+	return bson.D{{Key: "user_id", Value: userID}, {Key: "course_id", Value: courseID}}
+}
+
+func (e *Enrollment) Enroll(ctx context.Context, enrollmentDto dto.EnrollmentInputDto) (dto.EnrollmentOutputDto, error) {
+	count, err := e.db.Collection(usersCollection).CountDocuments(ctx, liveID(enrollmentDto.UserID))
+	if err != nil {
+		return dto.EnrollmentOutputDto{}, err
+	}
+	if count == 0 {
+		return dto.EnrollmentOutputDto{}, dberr.UnknownEnrollmentUser
+	}
+	count, err = e.db.Collection(coursesCollection).CountDocuments(ctx, liveID(enrollmentDto.CourseID))
+	if err != nil {
+		return dto.EnrollmentOutputDto{}, err
+	}
+	if count == 0 {
+		return dto.EnrollmentOutputDto{}, dberr.UnknownEnrollmentCourse
+	}
+	doc := enrollment{
+		UserID:     enrollmentDto.UserID,
+		CourseID:   enrollmentDto.CourseID,
+		EnrolledAt: now(),
+	}
+	if _, err := e.db.Collection(enrollmentsCollection).InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return dto.EnrollmentOutputDto{}, dberr.AlreadyEnrolled
+		}
+		return dto.EnrollmentOutputDto{}, err
+	}
+	return doc.dto(), nil
+}
+
+func (e *Enrollment) Unenroll(ctx context.Context, userID, courseID string) error {
+	result, err := e.db.Collection(enrollmentsCollection).DeleteOne(ctx, enrollmentID(userID, courseID))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return dberr.NotFound("enrollment")
+	}
+	return nil
+}
+
+func (e *Enrollment) Find(ctx context.Context, userID, courseID string) (dto.EnrollmentOutputDto, error) {
+	var doc enrollment
+	if err := e.db.Collection(enrollmentsCollection).FindOne(ctx, enrollmentID(userID, courseID)).Decode(&doc); err != nil {
+		return dto.EnrollmentOutputDto{}, noDocuments("enrollment", err)
+	}
+	return doc.dto(), nil
+}
+
+func (e *Enrollment) FindByUserID(ctx context.Context, userID string, page query.Page) (dto.EnrollmentListOutputDto, error) {
+	return e.list(ctx, page, "user_id", userID, "course_id", coursesCollection)
+}
+
+func (e *Enrollment) FindByCourseID(ctx context.Context, courseID string, page query.Page) (dto.EnrollmentListOutputDto, error) {
+	count, err := e.db.Collection(coursesCollection).CountDocuments(ctx, liveID(courseID))
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	if count == 0 {
+		return dto.EnrollmentListOutputDto{}, dberr.NotFound("course")
+	}
+	return e.list(ctx, page, "course_id", courseID, "user_id", usersCollection)
+}
+
+func (e *Enrollment) UpdateProgress(ctx context.Context, enrollmentDto dto.EnrollmentInputDto) error {
+	if err := dberr.ValidateProgress(enrollmentDto); err != nil {
+		return err
+	}
+	result, err := e.db.Collection(enrollmentsCollection).UpdateOne(ctx, enrollmentID(enrollmentDto.UserID, enrollmentDto.CourseID),
+		bson.D{{Key: "$set", Value: bson.D{{Key: "progress", Value: enrollmentDto.Progress}, {Key: "last_accessed_at", Value: now()}}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return dberr.NotFound("enrollment")
+	}
+	return nil
+}
+
+// list pages through the enrollments with key set to id, ordered by the
+// other key. Without joins, the enrollments whose other side is deleted in
+// collection are left out by listing the live ones first.
+func (e *Enrollment) list(ctx context.Context, page query.Page, key, id, other, collection string) (dto.EnrollmentListOutputDto, error) {
+	after, err := page.After()
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	enrolled, err := e.db.Collection(enrollmentsCollection).Distinct(ctx, other, bson.D{{Key: key, Value: id}})
+	if err != nil || len(enrolled) == 0 {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	live, err := findIDs(ctx, e.db.Collection(collection), bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: enrolled}}}, notDeleted})
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	filter := bson.D{
+		{Key: key, Value: id},
+		{Key: other, Value: bson.D{{Key: "$in", Value: live}, {Key: "$gt", Value: after}}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: other, Value: 1}}).SetLimit(int64(page.Size() + 1))
+	cursor, err := e.db.Collection(enrollmentsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	var docs []enrollment
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	enrollments := dto.EnrollmentListOutputDto{}
+	for _, doc := range docs {
+		enrollments.Enrollments = append(enrollments.Enrollments, doc.dto())
+	}
+	enrollments.Enrollments, enrollments.NextCursor = query.Trim(enrollments.Enrollments, page, func(e dto.EnrollmentOutputDto) string {
+		if other == "user_id" {
+			return query.EncodeCursor(e.UserID)
+		}
+		return query.EncodeCursor(e.CourseID)
+	})
+	return enrollments, nil
+}
+
+// purgeEnrollments removes the enrollments with key set to one of ids.
+func purgeEnrollments(ctx context.Context, db *mongo.Database, key string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := db.Collection(enrollmentsCollection).DeleteMany(ctx, bson.D{{Key: key, Value: bson.D{{Key: "$in", Value: ids}}}})
+	return err
+}
@@ -0,0 +1,113 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// change is a history entry; before and after hold the JSON snapshots.
+type change struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	EntityType string             `bson:"entity_type"`
+	EntityID   string             `bson:"entity_id"`
+	Action     string             `bson:"action"`
+	Before     *string            `bson:"before,omitempty"`
+	After      *string            `bson:"after,omitempty"`
+	ChangedBy  string             `bson:"changed_by"`
+	ChangedAt  time.Time          `bson:"changed_at"`
+}
+
+func (c change) dto() dto.HistoryOutputDto {
+	entry := dto.HistoryOutputDto{
+		ID:         c.ID.Hex(),
+		EntityType: c.EntityType,
+		EntityID:   c.EntityID,
+		Action:     c.Action,
+		ChangedBy:  c.ChangedBy,
+		ChangedAt:  c.ChangedAt,
+	}
+	if c.Before != nil {
+		entry.Before = []byte(*c.Before)
+	}
+	if c.After != nil {
+		entry.After = []byte(*c.After)
+	}
+	return entry
+}
+
+type HistoryRepository struct {
+	db *mongo.Database
+}
+
+func NewHistoryRepository(db *mongo.Database) *HistoryRepository {
+	return &HistoryRepository{db: db}
+}
+
+func (h *HistoryRepository) List(ctx context.Context, spec query.HistorySpec) (dto.HistoryListOutputDto, error) {
+	filter := bson.D{{Key: "entity_type", Value: spec.EntityType}, {Key: "entity_id", Value: spec.EntityID}}
+	after, err := spec.Page.After()
+	if err != nil {
+		return dto.HistoryListOutputDto{}, err
+	}
+	if after != "" {
+		id, err := primitive.ObjectIDFromHex(after)
+		if err != nil {
+			return dto.HistoryListOutputDto{}, query.ErrInvalidCursor
+		}
+		filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: id}}})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(spec.Page.Size() + 1))
+	cursor, err := h.db.Collection(historyCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return dto.HistoryListOutputDto{}, err
+	}
+	var docs []change
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.HistoryListOutputDto{}, err
+	}
+	history := dto.HistoryListOutputDto{}
+	for _, doc := range docs {
+		history.Entries = append(history.Entries, doc.dto())
+	}
+	history.Entries, history.NextCursor = query.Trim(history.Entries, spec.Page,
+		func(e dto.HistoryOutputDto) string { return query.EncodeCursor(e.ID) })
+	return history, nil
+}
+
+// record appends a change to the history. Without transactions it is
+// written after the change, which stays made if the write fails.
+func record(ctx context.Context, db *mongo.Database, entityType, entityID, action string, before, after any) error {
+	doc, err := historyDoc(ctx, entityType, entityID, action, before, after)
+	if err != nil {
+		return err
+	}
+	_, err = db.Collection(historyCollection).InsertOne(ctx, doc)
+	return err
+}
+
+func historyDoc(ctx context.Context, entityType, entityID, action string, before, after any) (change, error) {
+	doc := change{
+		ID:         primitive.NewObjectID(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ChangedBy:  audit.Actor(ctx),
+		ChangedAt:  now(),
+	}
+	var err error
+	if doc.Before, err = audit.Snapshot(before); err != nil {
+		return change{}, err
+	}
+	if doc.After, err = audit.Snapshot(after); err != nil {
+		return change{}, err
+	}
+	return doc, nil
+}
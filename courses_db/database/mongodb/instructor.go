@@ -0,0 +1,179 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type instructor struct {
+	CourseID string    `bson:"course_id"`
+	UserID   string    `bson:"user_id"`
+	AddedAt  time.Time `bson:"added_at"`
+}
+
+func (i instructor) dto(u user) dto.InstructorOutputDto {
+	return dto.InstructorOutputDto{
+		CourseID: i.CourseID,
+		UserID:   i.UserID,
+		Name:     u.Name,
+		Email:    u.Email,
+		AddedAt:  i.AddedAt,
+	}
+}
+
+type Instructor struct {
+	db *mongo.Database
+}
+
+func NewInstructorRepository(db *mongo.Database) *Instructor {
+	return &Instructor{db: db}
+}
+
+func instructorID(courseID, userID string) bson.D {
+	return bson.D{{Key: "course_id", Value: courseID}, {Key: "user_id", Value: userID}}
+}
+
+func (i *Instructor) Add(ctx context.Context, instructorDto dto.InstructorInputDto) (dto.InstructorOutputDto, error) {
+	var u user
+	err := i.db.Collection(usersCollection).FindOne(ctx, liveID(instructorDto.UserID)).Decode(&u)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dto.InstructorOutputDto{}, dberr.UnknownInstructorUser
+	}
+	if err != nil {
+		return dto.InstructorOutputDto{}, err
+	}
+	count, err := i.db.Collection(coursesCollection).CountDocuments(ctx, liveID(instructorDto.CourseID))
+	if err != nil {
+		return dto.InstructorOutputDto{}, err
+	}
+	if count == 0 {
+		return dto.InstructorOutputDto{}, dberr.UnknownInstructorCourse
+	}
+	doc := instructor{CourseID: instructorDto.CourseID, UserID: instructorDto.UserID, AddedAt: now()}
+	if _, err := i.db.Collection(instructorsCollection).InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return dto.InstructorOutputDto{}, dberr.AlreadyInstructor
+		}
+		return dto.InstructorOutputDto{}, err
+	}
+	return doc.dto(u), nil
+}
+
+// Remove checks that another instructor is left before removing one. Without
+// transactions, two removals at once may still leave none.
+func (i *Instructor) Remove(ctx context.Context, courseID, userID string) error {
+	collection := i.db.Collection(instructorsCollection)
+	count, err := collection.CountDocuments(ctx, instructorID(courseID, userID))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return dberr.NotFound("instructor")
+	}
+	others, err := collection.CountDocuments(ctx, bson.D{{Key: "course_id", Value: courseID}, {Key: "user_id", Value: bson.D{{Key: "$ne", Value: userID}}}})
+	if err != nil {
+		return err
+	}
+	if others == 0 {
+		return dberr.LastInstructor
+	}
+	result, err := collection.DeleteOne(ctx, instructorID(courseID, userID))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return dberr.NotFound("instructor")
+	}
+	return nil
+}
+
+// FindByCourseID reads the instructors, then the live users among them.
+func (i *Instructor) FindByCourseID(ctx context.Context, courseID string) (dto.InstructorListOutputDto, error) {
+	count, err := i.db.Collection(coursesCollection).CountDocuments(ctx, liveID(courseID))
+	if err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	if count == 0 {
+		return dto.InstructorListOutputDto{}, dberr.NotFound("course")
+	}
+	cursor, err := i.db.Collection(instructorsCollection).Find(ctx, bson.D{{Key: "course_id", Value: courseID}},
+		options.Find().SetSort(bson.D{{Key: "user_id", Value: 1}}))
+	if err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	var docs []instructor
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	instructors := dto.InstructorListOutputDto{}
+	if len(docs) == 0 {
+		return instructors, nil
+	}
+	userIDs := make([]string, len(docs))
+	for n, doc := range docs {
+		userIDs[n] = doc.UserID
+	}
+	cursor, err = i.db.Collection(usersCollection).Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: userIDs}}}, notDeleted})
+	if err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	var users []user
+	if err := cursor.All(ctx, &users); err != nil {
+		return dto.InstructorListOutputDto{}, err
+	}
+	live := make(map[string]user, len(users))
+	for _, u := range users {
+		live[u.ID] = u
+	}
+	for _, doc := range docs {
+		if u, ok := live[doc.UserID]; ok {
+			instructors.Instructors = append(instructors.Instructors, doc.dto(u))
+		}
+	}
+	return instructors, nil
+}
+
+func (i *Instructor) Teaches(ctx context.Context, userID, courseID string) (bool, error) {
+	count, err := i.db.Collection(instructorsCollection).CountDocuments(ctx, instructorID(courseID, userID))
+	return count > 0, err
+}
+
+// addCreator makes the live user the changes of ctx are attributed to, if
+// any, the first instructor of new courses.
+func addCreator(ctx context.Context, db *mongo.Database, addedAt time.Time, courseIDs ...string) error {
+	actor := audit.Actor(ctx)
+	if actor == "" || len(courseIDs) == 0 {
+		return nil
+	}
+	var u user
+	err := db.Collection(usersCollection).FindOne(ctx, bson.D{{Key: "email", Value: actor}, notDeleted}).Decode(&u)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	docs := make([]any, len(courseIDs))
+	for n, courseID := range courseIDs {
+		docs[n] = instructor{CourseID: courseID, UserID: u.ID, AddedAt: addedAt}
+	}
+	_, err = db.Collection(instructorsCollection).InsertMany(ctx, docs)
+	return err
+}
+
+// purgeInstructors removes the instructors with key set to one of ids.
+func purgeInstructors(ctx context.Context, db *mongo.Database, key string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := db.Collection(instructorsCollection).DeleteMany(ctx, bson.D{{Key: key, Value: bson.D{{Key: "$in", Value: ids}}}})
+	return err
+}
@@ -0,0 +1,172 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type lesson struct {
+	ID              string    `bson:"_id"`
+	ModuleID        string    `bson:"module_id"`
+	Title           string    `bson:"title"`
+	ContentType     string    `bson:"content_type"`
+	DurationSeconds int64     `bson:"duration_seconds"`
+	Body            string    `bson:"body"`
+	Position        int       `bson:"position"`
+	CreatedAt       time.Time `bson:"created_at"`
+	UpdatedAt       time.Time `bson:"updated_at"`
+	CreatedBy       string    `bson:"created_by"`
+	UpdatedBy       string    `bson:"updated_by"`
+	Version         int64     `bson:"version"`
+}
+
+func (l lesson) dto() dto.LessonOutputDto {
+	return dto.LessonOutputDto{
+		ID:              l.ID,
+		ModuleID:        l.ModuleID,
+		Title:           l.Title,
+		ContentType:     l.ContentType,
+		DurationSeconds: l.DurationSeconds,
+		Body:            l.Body,
+		Position:        l.Position,
+		CreatedAt:       l.CreatedAt,
+		UpdatedAt:       l.UpdatedAt,
+		CreatedBy:       l.CreatedBy,
+		UpdatedBy:       l.UpdatedBy,
+		Version:         l.Version,
+	}
+}
+
+type Lesson struct {
+	db *mongo.Database
+}
+
+func NewLessonRepository(db *mongo.Database) *Lesson {
+	return &Lesson{db: db}
+}
+
+// Create adds a lesson at the end of its module.
+func (l *Lesson) Create(ctx context.Context, lessonDto dto.LessonInputDto) (dto.LessonOutputDto, error) {
+	if err := dberr.ValidateLesson(lessonDto); err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	found, err := l.moduleExists(ctx, lessonDto.ModuleID)
+	if err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	if !found {
+		return dto.LessonOutputDto{}, dberr.UnknownModule
+	}
+	count, err := l.db.Collection(lessonsCollection).CountDocuments(ctx, bson.D{{Key: "module_id", Value: lessonDto.ModuleID}})
+	if err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	createdAt, actor := now(), audit.Actor(ctx)
+	doc := lesson{
+		ID:              uuid.New().String(),
+		ModuleID:        lessonDto.ModuleID,
+		Title:           lessonDto.Title,
+		ContentType:     lessonDto.ContentType,
+		DurationSeconds: lessonDto.DurationSeconds,
+		Body:            lessonDto.Body,
+		Position:        int(count) + 1,
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt,
+		CreatedBy:       actor,
+		UpdatedBy:       actor,
+		Version:         1,
+	}
+	if _, err := l.db.Collection(lessonsCollection).InsertOne(ctx, doc); err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	return doc.dto(), nil
+}
+
+func (l *Lesson) FindByModuleID(ctx context.Context, moduleID string) (dto.LessonListOutputDto, error) {
+	found, err := l.moduleExists(ctx, moduleID)
+	if err != nil {
+		return dto.LessonListOutputDto{}, err
+	}
+	if !found {
+		return dto.LessonListOutputDto{}, dberr.NotFound("module")
+	}
+	cursor, err := l.db.Collection(lessonsCollection).Find(ctx, bson.D{{Key: "module_id", Value: moduleID}}, byPosition)
+	if err != nil {
+		return dto.LessonListOutputDto{}, err
+	}
+	var docs []lesson
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.LessonListOutputDto{}, err
+	}
+	lessons := dto.LessonListOutputDto{}
+	for _, doc := range docs {
+		lessons.Lessons = append(lessons.Lessons, doc.dto())
+	}
+	return lessons, nil
+}
+
+func (l *Lesson) Find(ctx context.Context, id string) (dto.LessonOutputDto, error) {
+	var doc lesson
+	if err := l.db.Collection(lessonsCollection).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc); err != nil {
+		return dto.LessonOutputDto{}, noDocuments("lesson", err)
+	}
+	return doc.dto(), nil
+}
+
+func (l *Lesson) Update(ctx context.Context, lessonDto dto.LessonInputDto) error {
+	if err := dberr.ValidateLesson(lessonDto); err != nil {
+		return err
+	}
+	collection := l.db.Collection(lessonsCollection)
+	result, err := collection.UpdateOne(ctx, versioned(bson.D{{Key: "_id", Value: lessonDto.ID}}, lessonDto.Version), bson.D{stamp(ctx, now(),
+		bson.E{Key: "title", Value: lessonDto.Title},
+		bson.E{Key: "content_type", Value: lessonDto.ContentType},
+		bson.E{Key: "duration_seconds", Value: lessonDto.DurationSeconds},
+		bson.E{Key: "body", Value: lessonDto.Body},
+	), bump})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return notUpdated(ctx, collection, "lesson", lessonDto.ID, lessonDto.Version)
+	}
+	return nil
+}
+
+// Delete removes a lesson; the lessons after it move up.
+func (l *Lesson) Delete(ctx context.Context, id string) error {
+	var doc lesson
+	err := l.db.Collection(lessonsCollection).FindOneAndDelete(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if err != nil {
+		return noDocuments("lesson", err)
+	}
+	return moveUp(ctx, l.db.Collection(lessonsCollection), "module_id", doc.ModuleID, doc.Position)
+}
+
+func (l *Lesson) Reorder(ctx context.Context, moduleID string, ids []string) error {
+	lessons, err := l.FindByModuleID(ctx, moduleID)
+	if err != nil {
+		return err
+	}
+	positions := make(map[string]int, len(lessons.Lessons))
+	current := make([]string, len(lessons.Lessons))
+	for i, lesson := range lessons.Lessons {
+		positions[lesson.ID], current[i] = lesson.Position, lesson.ID
+	}
+	if err := dberr.CheckOrder(current, ids, "lesson", "module"); err != nil {
+		return err
+	}
+	return reorder(ctx, l.db.Collection(lessonsCollection), ids, positions)
+}
+
+func (l *Lesson) moduleExists(ctx context.Context, moduleID string) (bool, error) {
+	count, err := l.db.Collection(modulesCollection).CountDocuments(ctx, bson.D{{Key: "_id", Value: moduleID}})
+	return count > 0, err
+}
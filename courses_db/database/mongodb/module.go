@@ -0,0 +1,232 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type module struct {
+	ID          string    `bson:"_id"`
+	CourseID    string    `bson:"course_id"`
+	Title       string    `bson:"title"`
+	Description string    `bson:"description"`
+	Position    int       `bson:"position"`
+	CreatedAt   time.Time `bson:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at"`
+	CreatedBy   string    `bson:"created_by"`
+	UpdatedBy   string    `bson:"updated_by"`
+	Version     int64     `bson:"version"`
+}
+
+func (m module) dto() dto.ModuleOutputDto {
+	return dto.ModuleOutputDto{
+		ID:          m.ID,
+		CourseID:    m.CourseID,
+		Title:       m.Title,
+		Description: m.Description,
+		Position:    m.Position,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+		CreatedBy:   m.CreatedBy,
+		UpdatedBy:   m.UpdatedBy,
+		Version:     m.Version,
+	}
+}
+
+// byPosition sorts the children of a parent in order.
+var byPosition = options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+
+type Module struct {
+	db *mongo.Database
+}
+
+func NewModuleRepository(db *mongo.Database) *Module {
+	return &Module{db: db}
+}
+
+// Create adds a module at the end of its course.
+func (m *Module) Create(ctx context.Context, moduleDto dto.ModuleInputDto) (dto.ModuleOutputDto, error) {
+	if err := dberr.ValidateModule(moduleDto); err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	live, err := m.liveCourse(ctx, moduleDto.CourseID)
+	if err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	if !live {
+		return dto.ModuleOutputDto{}, dberr.UnknownCourse
+	}
+	count, err := m.db.Collection(modulesCollection).CountDocuments(ctx, bson.D{{Key: "course_id", Value: moduleDto.CourseID}})
+	if err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	createdAt, actor := now(), audit.Actor(ctx)
+	doc := module{
+		ID:          uuid.New().String(),
+		CourseID:    moduleDto.CourseID,
+		Title:       moduleDto.Title,
+		Description: moduleDto.Description,
+		Position:    int(count) + 1,
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	if _, err := m.db.Collection(modulesCollection).InsertOne(ctx, doc); err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	return doc.dto(), nil
+}
+
+func (m *Module) FindByCourseID(ctx context.Context, courseID string) (dto.ModuleListOutputDto, error) {
+	live, err := m.liveCourse(ctx, courseID)
+	if err != nil {
+		return dto.ModuleListOutputDto{}, err
+	}
+	if !live {
+		return dto.ModuleListOutputDto{}, dberr.NotFound("course")
+	}
+	cursor, err := m.db.Collection(modulesCollection).Find(ctx, bson.D{{Key: "course_id", Value: courseID}}, byPosition)
+	if err != nil {
+		return dto.ModuleListOutputDto{}, err
+	}
+	var docs []module
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.ModuleListOutputDto{}, err
+	}
+	modules := dto.ModuleListOutputDto{}
+	for _, doc := range docs {
+		modules.Modules = append(modules.Modules, doc.dto())
+	}
+	return modules, nil
+}
+
+func (m *Module) Find(ctx context.Context, id string) (dto.ModuleOutputDto, error) {
+	var doc module
+	if err := m.db.Collection(modulesCollection).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc); err != nil {
+		return dto.ModuleOutputDto{}, noDocuments("module", err)
+	}
+	return doc.dto(), nil
+}
+
+func (m *Module) Update(ctx context.Context, moduleDto dto.ModuleInputDto) error {
+	if err := dberr.ValidateModule(moduleDto); err != nil {
+		return err
+	}
+	collection := m.db.Collection(modulesCollection)
+	result, err := collection.UpdateOne(ctx, versioned(bson.D{{Key: "_id", Value: moduleDto.ID}}, moduleDto.Version), bson.D{stamp(ctx, now(),
+		bson.E{Key: "title", Value: moduleDto.Title},
+		bson.E{Key: "description", Value: moduleDto.Description},
+	), bump})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return notUpdated(ctx, collection, "module", moduleDto.ID, moduleDto.Version)
+	}
+	return nil
+}
+
+// Delete removes a module and its lessons. The modules after it move up.
+func (m *Module) Delete(ctx context.Context, id string) error {
+	var doc module
+	err := m.db.Collection(modulesCollection).FindOneAndDelete(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc)
+	if err != nil {
+		return noDocuments("module", err)
+	}
+	if _, err := m.db.Collection(lessonsCollection).DeleteMany(ctx, bson.D{{Key: "module_id", Value: id}}); err != nil {
+		return err
+	}
+	return moveUp(ctx, m.db.Collection(modulesCollection), "course_id", doc.CourseID, doc.Position)
+}
+
+func (m *Module) Reorder(ctx context.Context, courseID string, ids []string) error {
+	modules, err := m.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	positions := make(map[string]int, len(modules.Modules))
+	current := make([]string, len(modules.Modules))
+	for i, module := range modules.Modules {
+		positions[module.ID], current[i] = module.Position, module.ID
+	}
+	if err := dberr.CheckOrder(current, ids, "module", "course"); err != nil {
+		return err
+	}
+	return reorder(ctx, m.db.Collection(modulesCollection), ids, positions)
+}
+
+// liveCourse reports whether the course exists and is not deleted.
+func (m *Module) liveCourse(ctx context.Context, courseID string) (bool, error) {
+	count, err := m.db.Collection(coursesCollection).CountDocuments(ctx, liveID(courseID))
+	return count > 0, err
+}
+
+// moveUp closes the gap a deleted child of parentID left at position.
+func moveUp(ctx context.Context, collection *mongo.Collection, parentKey, parentID string, position int) error {
+	_, err := collection.UpdateMany(ctx, bson.D{
+		{Key: parentKey, Value: parentID},
+		{Key: "position", Value: bson.D{{Key: "$gt", Value: position}}},
+	}, bson.D{{Key: "$inc", Value: bson.D{{Key: "position", Value: -1}}}})
+	return err
+}
+
+// reorder gives the children listed in ids their place in the list. Without
+// transactions, the children are moved one by one.
+func reorder(ctx context.Context, collection *mongo.Collection, ids []string, positions map[string]int) error {
+	movedAt := now()
+	for i, id := range ids {
+		if positions[id] == i+1 {
+			continue
+		}
+		_, err := collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, bson.D{stamp(ctx, movedAt, bson.E{Key: "position", Value: i + 1}), bump})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeModules removes the modules of the given courses and their lessons.
+func purgeModules(ctx context.Context, db *mongo.Database, courseIDs []string) error {
+	if len(courseIDs) == 0 {
+		return nil
+	}
+	moduleIDs, err := findIDs(ctx, db.Collection(modulesCollection), bson.D{{Key: "course_id", Value: bson.D{{Key: "$in", Value: courseIDs}}}})
+	if err != nil {
+		return err
+	}
+	if _, err := db.Collection(lessonsCollection).DeleteMany(ctx, bson.D{{Key: "module_id", Value: bson.D{{Key: "$in", Value: moduleIDs}}}}); err != nil {
+		return err
+	}
+	_, err = db.Collection(modulesCollection).DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: moduleIDs}}}})
+	return err
+}
+
+// findIDs returns the ids of the documents matching filter.
+func findIDs(ctx context.Context, collection *mongo.Collection, filter bson.D) ([]string, error) {
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,235 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	categoriesCollection  = "categories"
+	coursesCollection     = "courses"
+	usersCollection       = "users"
+	historyCollection     = "history"
+	modulesCollection     = "modules"
+	lessonsCollection     = "lessons"
+	enrollmentsCollection = "enrollments"
+	instructorsCollection = "course_instructors"
+	tagsCollection        = "tags"
+	courseTagsCollection  = "course_tags"
+)
+
+// EnsureIndexes creates the indexes the repositories rely on and versions
+// older documents. It is idempotent and is run every time the database is
+// opened.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := map[string][]mongo.IndexModel{
+		categoriesCollection: {
+			{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "_id", Value: 1}}},
+			searchIndex,
+		},
+		coursesCollection: {
+			{Keys: bson.D{{Key: "category_id", Value: 1}}},
+			{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+			searchIndex,
+		},
+		usersCollection: {
+			{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		historyCollection: {
+			{Keys: bson.D{{Key: "entity_type", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "_id", Value: 1}}},
+		},
+		modulesCollection: {
+			{Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "position", Value: 1}}},
+		},
+		lessonsCollection: {
+			{Keys: bson.D{{Key: "module_id", Value: 1}, {Key: "position", Value: 1}}},
+		},
+		enrollmentsCollection: {
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "course_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "user_id", Value: 1}}},
+		},
+		instructorsCollection: {
+			{Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "course_id", Value: 1}}},
+		},
+		tagsCollection: {
+			{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		courseTagsCollection: {
+			{Keys: bson.D{{Key: "course_id", Value: 1}, {Key: "tag_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "tag_id", Value: 1}, {Key: "course_id", Value: 1}}},
+		},
+	}
+	for collection, models := range indexes {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+	// documents written before versions existed start at 1
+	unversioned := bson.D{{Key: "version", Value: bson.D{{Key: "$exists", Value: false}}}}
+	for _, collection := range []string{categoriesCollection, coursesCollection, usersCollection} {
+		_, err := db.Collection(collection).UpdateMany(ctx, unversioned, bson.D{{Key: "$set", Value: bson.D{{Key: "version", Value: 1}}}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notDeleted matches documents that are not soft deleted; a missing
+// deleted_at counts as not deleted.
+var notDeleted = bson.E{Key: "deleted_at", Value: nil}
+
+// listFilter is the base filter of a listing.
+func listFilter(includeDeleted bool) bson.D {
+	if includeDeleted {
+		return bson.D{}
+	}
+	return bson.D{notDeleted}
+}
+
+// liveID matches the document with id unless it is soft deleted.
+func liveID(id string) bson.D {
+	return bson.D{{Key: "_id", Value: id}, notDeleted}
+}
+
+// deletedID matches the document with id only if it is soft deleted.
+func deletedID(id string) bson.D {
+	return bson.D{{Key: "_id", Value: id}, {Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}}}
+}
+
+// deletedBefore matches the documents soft deleted before t.
+func deletedBefore(t time.Time) bson.D {
+	return bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$lt", Value: t}}}}
+}
+
+// stamp sets the given fields along with who changed the document and when.
+func stamp(ctx context.Context, t time.Time, fields ...bson.E) bson.E {
+	set := append(fields, bson.E{Key: "updated_at", Value: t}, bson.E{Key: "updated_by", Value: audit.Actor(ctx)})
+	return bson.E{Key: "$set", Value: set}
+}
+
+// bump moves the version of the updated documents on.
+var bump = bson.E{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}}
+
+// versioned narrows the filter of an update to the version it names, if any.
+func versioned(filter bson.D, version int64) bson.D {
+	if version == 0 {
+		return filter
+	}
+	return append(filter, bson.E{Key: "version", Value: version})
+}
+
+// notUpdated explains a versioned update of id that matched nothing: the
+// document is gone, or it has moved past the version the update names.
+func notUpdated(ctx context.Context, collection *mongo.Collection, what, id string, version int64) error {
+	if version != 0 {
+		count, err := collection.CountDocuments(ctx, liveID(id))
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return dberr.Stale(what)
+		}
+	}
+	return dberr.NotFound(what)
+}
+
+// softDelete marks the matched documents as deleted at t.
+func softDelete(ctx context.Context, t time.Time) bson.D {
+	return bson.D{stamp(ctx, t, bson.E{Key: "deleted_at", Value: t}), bump}
+}
+
+// restore clears the deletion of the matched documents.
+func restore(ctx context.Context, t time.Time) bson.D {
+	return bson.D{{Key: "$unset", Value: bson.D{{Key: "deleted_at", Value: ""}}}, stamp(ctx, t), bump}
+}
+
+// now is the time stamped on changes, at the millisecond precision BSON
+// dates keep.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// returnBefore makes FindOneAndUpdate return the document as it was before
+// the update, the "before" of the history entry.
+var returnBefore = options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+// listFind turns the filter, sort and page of a listing into the filter and
+// options of a Find, mirroring query.CourseSpec.Select. Name prefixes match
+// regardless of case, as on the other backends.
+func listFind(filter bson.D, namePrefix string, sort query.Sort, page query.Page) (bson.D, *options.FindOptions, error) {
+	if namePrefix != "" {
+		filter = append(filter, bson.E{Key: "name", Value: bson.D{{Key: "$regex", Value: "^" + regexp.QuoteMeta(namePrefix)}, {Key: "$options", Value: "i"}}})
+	}
+	key, id, ok, err := page.Keyset(sort)
+	if err != nil {
+		return nil, nil, err
+	}
+	op, dir := "$gt", 1
+	if sort.Desc {
+		op, dir = "$lt", -1
+	}
+	column := sort.Column()
+	if ok {
+		if column == "" {
+			filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: op, Value: id}}})
+		} else {
+			filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+				bson.D{{Key: column, Value: bson.D{{Key: op, Value: key}}}},
+				bson.D{{Key: column, Value: key}, {Key: "_id", Value: bson.D{{Key: op, Value: id}}}},
+			}})
+		}
+	}
+	order := bson.D{{Key: "_id", Value: dir}}
+	if column != "" {
+		order = bson.D{{Key: column, Value: dir}, {Key: "_id", Value: dir}}
+	}
+	return filter, options.Find().SetSort(order).SetLimit(int64(page.Size() + 1)), nil
+}
+
+// batch runs item for each of n items. Without transactions the items are
+// applied one by one: a rejected item is reported in its result, and after
+// any other error the items before it stay applied.
+func batch(n int, item func(i int) (string, error)) ([]dto.BatchItemOutputDto, error) {
+	results := make([]dto.BatchItemOutputDto, n)
+	for i := range results {
+		id, err := item(i)
+		if err != nil && !dberr.Rejected(err) {
+			return nil, err
+		}
+		results[i] = dto.BatchItemOutputDto{Index: i, ID: id, Err: err}
+	}
+	return results, nil
+}
+
+// insertMany writes the documents created by a batch and their history.
+func insertMany(ctx context.Context, db *mongo.Database, collection string, docs, history []any) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	if _, err := db.Collection(collection).InsertMany(ctx, docs); err != nil {
+		return err
+	}
+	_, err := db.Collection(historyCollection).InsertMany(ctx, history)
+	return err
+}
+
+// noDocuments turns mongo.ErrNoDocuments into a not found error for what.
+func noDocuments(what string, err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dberr.Wrap(dberr.ErrNotFound, what+" not found", err)
+	}
+	return err
+}
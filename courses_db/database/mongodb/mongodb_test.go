@@ -0,0 +1,176 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// openTestDB connects to the mongod named by MONGODB_TEST_URI, for example
+// "mongodb://localhost:27017", and recreates the courses_test database.
+func openTestDB(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI not set")
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Disconnect(ctx) })
+	db := client.Database("courses_test")
+	if err := db.Drop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureIndexes(ctx, db); err != nil {
+		t.Fatalf("EnsureIndexes() error = %v", err)
+	}
+	return db
+}
+
+func TestRepositories(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	categories := NewCategoryRepository(db, query.Restrict)
+	courses := NewCourseRepository(db)
+	users := NewUserRepository(db)
+
+	category, err := categories.Create(ctx, dto.CategoryInputDto{Name: "Go", Description: "golang"})
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	course, err := courses.Create(ctx, dto.CourseInputDto{Name: "Basics", Description: "intro", CategoryID: category.ID})
+	if err != nil {
+		t.Fatalf("create course: %v", err)
+	}
+	if _, err := courses.Create(ctx, dto.CourseInputDto{Name: "Advanced", CategoryID: category.ID}); err != nil {
+		t.Fatalf("create course: %v", err)
+	}
+	user, err := users.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "hash"})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{name: "find category", run: func() error {
+			got, err := categories.Find(ctx, category.ID)
+			if err == nil && (got.Name != category.Name || !got.CreatedAt.Equal(category.CreatedAt)) {
+				t.Errorf("Find() = %v, want %v", got, category)
+			}
+			return err
+		}},
+		{name: "category of course", run: func() error {
+			got, err := categories.FindByCourseID(ctx, course.ID)
+			if err == nil && got.ID != category.ID {
+				t.Errorf("FindByCourseID() = %v, want %v", got.ID, category.ID)
+			}
+			return err
+		}},
+		{name: "list courses by name", run: func() error {
+			spec := query.CourseSpec{CategoryID: category.ID, Sort: query.Sort{Field: query.SortByName}, Page: query.Page{Limit: 1}}
+			first, err := courses.List(ctx, spec)
+			if err != nil {
+				return err
+			}
+			spec.Page.Cursor = first.NextCursor
+			second, err := courses.List(ctx, spec)
+			if err != nil {
+				return err
+			}
+			if len(first.Courses) != 1 || first.Courses[0].Name != "Advanced" ||
+				len(second.Courses) != 1 || second.Courses[0].Name != "Basics" || second.NextCursor != "" {
+				t.Errorf("List() pages = %v, %v", first, second)
+			}
+			return nil
+		}},
+		{name: "list courses by prefix", run: func() error {
+			got, err := courses.List(ctx, query.CourseSpec{NamePrefix: "Ba"})
+			if err == nil && len(got.Courses) != 1 {
+				t.Errorf("List() = %v, want one course", got.Courses)
+			}
+			return err
+		}},
+		{name: "duplicate email", run: func() error {
+			_, err := users.Create(ctx, dto.UserInputDto{Name: "Other", Email: user.Email})
+			return err
+		}, wantErr: dberr.ErrConflict},
+		{name: "course with unknown category", run: func() error {
+			_, err := courses.Create(ctx, dto.CourseInputDto{Name: "x", CategoryID: uuid.NewString()})
+			return err
+		}, wantErr: dberr.ErrValidation},
+		{name: "find missing", run: func() error {
+			_, err := courses.Find(ctx, "missing")
+			return err
+		}, wantErr: dberr.ErrNotFound},
+		{name: "delete category with courses", run: func() error {
+			return categories.Delete(ctx, category.ID)
+		}, wantErr: dberr.ErrHasDependents},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("error = %v", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	category, err := NewCategoryRepository(db, query.Restrict).Create(ctx, dto.CategoryInputDto{Name: "Golang", Description: "the Go language"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := NewCourseRepository(db).Create(ctx, dto.CourseInputDto{Name: "Go basics", Description: "Go types and syntax", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	search := NewSearchRepository(db)
+
+	tests := []struct {
+		name string
+		spec query.SearchSpec
+		want []string
+	}{
+		{name: "both", spec: query.SearchSpec{Query: "go"}, want: []string{course.ID, category.ID}},
+		{name: "every word", spec: query.SearchSpec{Query: "go syntax"}, want: []string{course.ID}},
+		{name: "categories only", spec: query.SearchSpec{Query: "go", EntityType: "category"}, want: []string{category.ID}},
+		{name: "no match", spec: query.SearchSpec{Query: "pasta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := search.Search(ctx, tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Results) != len(tt.want) {
+				t.Fatalf("Search() = %+v, want ids %v", got.Results, tt.want)
+			}
+			for i, result := range got.Results {
+				if result.ID != tt.want[i] || !strings.Contains(result.Snippet, query.HighlightStart) {
+					t.Errorf("result %d = %+v, want id %s with a highlighted snippet", i, result, tt.want[i])
+				}
+			}
+		})
+	}
+}
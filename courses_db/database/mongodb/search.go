@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// searchIndex is the text index of courses and categories. Language "none"
+// turns stemming off, so words match whole like in the SQL backends.
+var searchIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+	Options: options.Index().SetName("search").SetDefaultLanguage("none"),
+}
+
+type SearchRepository struct {
+	db *mongo.Database
+}
+
+func NewSearchRepository(db *mongo.Database) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// searchHit is a document matched by $text with its relevance.
+type searchHit struct {
+	ID          string  `bson:"_id"`
+	Name        string  `bson:"name"`
+	Description string  `bson:"description"`
+	Score       float64 `bson:"score"`
+}
+
+// Search queries both collections for the page and merges them by score.
+// Every term is quoted, which makes $text require all of them.
+func (s *SearchRepository) Search(ctx context.Context, spec query.SearchSpec) (dto.SearchOutputDto, error) {
+	terms, err := spec.Terms()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	offset, err := spec.Page.Offset()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	phrases := `"` + strings.Join(terms, `" "`) + `"`
+	filter := bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: phrases}}}, notDeleted}
+	score := bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "name", Value: 1}, {Key: "description", Value: 1}, score[0]}).
+		SetSort(bson.D{score[0], {Key: "_id", Value: 1}}).
+		SetLimit(int64(offset + spec.Page.Size() + 1))
+	var results []dto.SearchResultOutputDto
+	for _, c := range []struct{ entityType, collection string }{{"category", categoriesCollection}, {"course", coursesCollection}} {
+		if !spec.Includes(c.entityType) {
+			continue
+		}
+		cursor, err := s.db.Collection(c.collection).Find(ctx, filter, opts)
+		if err != nil {
+			return dto.SearchOutputDto{}, err
+		}
+		var hits []searchHit
+		if err := cursor.All(ctx, &hits); err != nil {
+			return dto.SearchOutputDto{}, err
+		}
+		for _, hit := range hits {
+			results = append(results, dto.SearchResultOutputDto{
+				EntityType: c.entityType,
+				ID:         hit.ID,
+				Name:       hit.Name,
+				Snippet:    query.Snippet(terms, hit.Name, hit.Description),
+				Score:      hit.Score,
+			})
+		}
+	}
+	slices.SortFunc(results, func(a, b dto.SearchResultOutputDto) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.EntityType, b.EntityType), cmp.Compare(a.ID, b.ID))
+	})
+	results = results[min(offset, len(results)):]
+	results, next := query.TrimOffset(results, spec.Page, offset)
+	return dto.SearchOutputDto{Results: results, NextCursor: next}, nil
+}
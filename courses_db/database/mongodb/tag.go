@@ -0,0 +1,241 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/entity"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type tag struct {
+	ID        string    `bson:"_id"`
+	Name      string    `bson:"name"`
+	CreatedAt time.Time `bson:"created_at"`
+	CreatedBy string    `bson:"created_by"`
+}
+
+func (t tag) dto(courses int64) dto.TagOutputDto {
+	return dto.TagOutputDto{ID: t.ID, Name: t.Name, Courses: courses, CreatedAt: t.CreatedAt, CreatedBy: t.CreatedBy}
+}
+
+type courseTag struct {
+	CourseID string    `bson:"course_id"`
+	TagID    string    `bson:"tag_id"`
+	AddedAt  time.Time `bson:"added_at"`
+}
+
+type Tag struct {
+	db *mongo.Database
+}
+
+func NewTagRepository(db *mongo.Database) *Tag {
+	return &Tag{db: db}
+}
+
+func (t *Tag) Create(ctx context.Context, tagDto dto.TagInputDto) (dto.TagOutputDto, error) {
+	name := entity.NormalizeTagName(tagDto.Name)
+	if err := dberr.ValidateTag(name); err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	doc := tag{ID: uuid.New().String(), Name: name, CreatedAt: now(), CreatedBy: audit.Actor(ctx)}
+	if _, err := t.db.Collection(tagsCollection).InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return dto.TagOutputDto{}, dberr.TagExists
+		}
+		return dto.TagOutputDto{}, err
+	}
+	return doc.dto(0), nil
+}
+
+func (t *Tag) Find(ctx context.Context, id string) (dto.TagOutputDto, error) {
+	var doc tag
+	if err := t.db.Collection(tagsCollection).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&doc); err != nil {
+		return dto.TagOutputDto{}, noDocuments("tag", err)
+	}
+	return t.counted(ctx, doc)
+}
+
+func (t *Tag) List(ctx context.Context, spec query.TagSpec) (dto.TagListOutputDto, error) {
+	filter, opts, err := listFind(bson.D{}, entity.NormalizeTagName(spec.NamePrefix), query.Sort{Field: query.SortByName}, spec.Page)
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	cursor, err := t.db.Collection(tagsCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	tags, err := t.all(ctx, cursor)
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	byName := query.Sort{Field: query.SortByName}
+	tags.Tags, tags.NextCursor = query.Trim(tags.Tags, spec.Page,
+		func(t dto.TagOutputDto) string { return byName.Cursor(t.ID, t.Name, t.CreatedAt) })
+	return tags, nil
+}
+
+// Delete removes the tag, then its place on courses.
+func (t *Tag) Delete(ctx context.Context, id string) error {
+	result, err := t.db.Collection(tagsCollection).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return dberr.NotFound("tag")
+	}
+	_, err = t.db.Collection(courseTagsCollection).DeleteMany(ctx, bson.D{{Key: "tag_id", Value: id}})
+	return err
+}
+
+// Tag creates the tag with an upsert on its unique name, so that tagging
+// two courses at once with a new name leaves a single tag.
+func (t *Tag) Tag(ctx context.Context, courseTagDto dto.CourseTagInputDto) (dto.TagOutputDto, error) {
+	name := entity.NormalizeTagName(courseTagDto.Name)
+	if err := dberr.ValidateTag(name); err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	count, err := t.db.Collection(coursesCollection).CountDocuments(ctx, liveID(courseTagDto.CourseID))
+	if err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	if count == 0 {
+		return dto.TagOutputDto{}, dberr.UnknownTagCourse
+	}
+	t0 := now()
+	var doc tag
+	err = t.db.Collection(tagsCollection).FindOneAndUpdate(ctx, bson.D{{Key: "name", Value: name}},
+		bson.D{{Key: "$setOnInsert", Value: bson.D{
+			{Key: "_id", Value: uuid.New().String()},
+			{Key: "created_at", Value: t0},
+			{Key: "created_by", Value: audit.Actor(ctx)},
+		}}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&doc)
+	if err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	_, err = t.db.Collection(courseTagsCollection).InsertOne(ctx, courseTag{CourseID: courseTagDto.CourseID, TagID: doc.ID, AddedAt: t0})
+	if mongo.IsDuplicateKeyError(err) {
+		return dto.TagOutputDto{}, dberr.AlreadyTagged
+	}
+	if err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	return t.counted(ctx, doc)
+}
+
+func (t *Tag) Untag(ctx context.Context, courseID, tagID string) error {
+	result, err := t.db.Collection(courseTagsCollection).DeleteOne(ctx, bson.D{{Key: "course_id", Value: courseID}, {Key: "tag_id", Value: tagID}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return dberr.NotFound("course tag")
+	}
+	return nil
+}
+
+func (t *Tag) FindByCourseID(ctx context.Context, courseID string) (dto.TagListOutputDto, error) {
+	count, err := t.db.Collection(coursesCollection).CountDocuments(ctx, liveID(courseID))
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	if count == 0 {
+		return dto.TagListOutputDto{}, dberr.NotFound("course")
+	}
+	tagIDs, err := t.db.Collection(courseTagsCollection).Distinct(ctx, "tag_id", bson.D{{Key: "course_id", Value: courseID}})
+	if err != nil || len(tagIDs) == 0 {
+		return dto.TagListOutputDto{}, err
+	}
+	cursor, err := t.db.Collection(tagsCollection).Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: tagIDs}}}},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	return t.all(ctx, cursor)
+}
+
+func (t *Tag) all(ctx context.Context, cursor *mongo.Cursor) (dto.TagListOutputDto, error) {
+	var docs []tag
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.TagListOutputDto{}, err
+	}
+	tags := dto.TagListOutputDto{}
+	for _, doc := range docs {
+		tag, err := t.counted(ctx, doc)
+		if err != nil {
+			return dto.TagListOutputDto{}, err
+		}
+		tags.Tags = append(tags.Tags, tag)
+	}
+	return tags, nil
+}
+
+// counted reads the number of live courses carrying the tag.
+func (t *Tag) counted(ctx context.Context, doc tag) (dto.TagOutputDto, error) {
+	courseIDs, err := t.db.Collection(courseTagsCollection).Distinct(ctx, "course_id", bson.D{{Key: "tag_id", Value: doc.ID}})
+	if err != nil || len(courseIDs) == 0 {
+		return doc.dto(0), err
+	}
+	count, err := t.db.Collection(coursesCollection).CountDocuments(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: courseIDs}}}, notDeleted})
+	if err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	return doc.dto(count), nil
+}
+
+// taggedCourses returns the ids of the courses carrying any of the named
+// tags, or all of them when all is set.
+func taggedCourses(ctx context.Context, db *mongo.Database, names []string, all bool) ([]any, error) {
+	tagIDs, err := db.Collection(tagsCollection).Distinct(ctx, "_id", bson.D{{Key: "name", Value: bson.D{{Key: "$in", Value: names}}}})
+	if err != nil || len(tagIDs) == 0 || all && len(tagIDs) < len(names) {
+		return nil, err
+	}
+	if !all {
+		return db.Collection(courseTagsCollection).Distinct(ctx, "course_id", bson.D{{Key: "tag_id", Value: bson.D{{Key: "$in", Value: tagIDs}}}})
+	}
+	var courseIDs []any
+	for i, tagID := range tagIDs {
+		tagged, err := db.Collection(courseTagsCollection).Distinct(ctx, "course_id", bson.D{{Key: "tag_id", Value: tagID}})
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			tagged = intersect(courseIDs, tagged)
+		}
+		if courseIDs = tagged; len(courseIDs) == 0 {
+			return nil, nil
+		}
+	}
+	return courseIDs, nil
+}
+
+func intersect(a, b []any) []any {
+	in := make(map[any]bool, len(a))
+	for _, v := range a {
+		in[v] = true
+	}
+	var both []any
+	for _, v := range b {
+		if in[v] {
+			both = append(both, v)
+		}
+	}
+	return both
+}
+
+// purgeCourseTags removes the tags of the purged courses.
+func purgeCourseTags(ctx context.Context, db *mongo.Database, courseIDs []string) error {
+	if len(courseIDs) == 0 {
+		return nil
+	}
+	_, err := db.Collection(courseTagsCollection).DeleteMany(ctx, bson.D{{Key: "course_id", Value: bson.D{{Key: "$in", Value: courseIDs}}}})
+	return err
+}
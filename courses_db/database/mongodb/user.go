@@ -0,0 +1,197 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type user struct {
+	ID        string     `bson:"_id"`
+	Name      string     `bson:"name"`
+	Email     string     `bson:"email"`
+	Password  string     `bson:"password"`
+	CreatedAt time.Time  `bson:"created_at"`
+	UpdatedAt time.Time  `bson:"updated_at"`
+	CreatedBy string     `bson:"created_by"`
+	UpdatedBy string     `bson:"updated_by"`
+	Version   int64      `bson:"version"`
+	DeletedAt *time.Time `bson:"deleted_at,omitempty"`
+}
+
+func (u user) dto() dto.UserOutputDto {
+	return dto.UserOutputDto{
+		ID:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+		CreatedBy: u.CreatedBy,
+		UpdatedBy: u.UpdatedBy,
+		Version:   u.Version,
+		DeletedAt: u.DeletedAt,
+	}
+}
+
+type UserRepository struct {
+	db *mongo.Database
+}
+
+func NewUserRepository(db *mongo.Database) *UserRepository {
+	return &UserRepository{
+		db: db,
+	}
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*dto.GetJWTInput, error) {
+	var doc user
+	err := r.db.Collection(usersCollection).FindOne(ctx, bson.D{{Key: "email", Value: email}, notDeleted}).Decode(&doc)
+	if err != nil {
+		return nil, noDocuments("user", err)
+	}
+	return &dto.GetJWTInput{Email: email, Password: doc.Password}, nil
+}
+
+func (r *UserRepository) FindIDByEmail(ctx context.Context, email string) (string, error) {
+	var doc user
+	err := r.db.Collection(usersCollection).FindOne(ctx, bson.D{{Key: "email", Value: email}, notDeleted}).Decode(&doc)
+	if err != nil {
+		return "", noDocuments("user", err)
+	}
+	return doc.ID, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, userDto dto.UserInputDto) (dto.UserOutputDto, error) {
+	if err := dberr.ValidateUser(userDto); err != nil {
+		return dto.UserOutputDto{}, err
+	}
+	createdAt, actor := now(), audit.Actor(ctx)
+	doc := user{
+		ID:        uuid.New().String(),
+		Name:      userDto.Name,
+		Email:     userDto.Email,
+		Password:  userDto.Password,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		CreatedBy: actor,
+		UpdatedBy: actor,
+		Version:   1,
+	}
+	if _, err := r.db.Collection(usersCollection).InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return dto.UserOutputDto{}, dberr.Wrap(dberr.ErrConflict, "email already in use", err)
+		}
+		return dto.UserOutputDto{}, err
+	}
+	return doc.dto(), nil
+}
+
+func (r *UserRepository) FindAll(ctx context.Context, page query.Page) (dto.UserListOutputDto, error) {
+	return r.List(ctx, query.UserSpec{Page: page})
+}
+
+func (r *UserRepository) List(ctx context.Context, spec query.UserSpec) (dto.UserListOutputDto, error) {
+	page := spec.Page
+	after, err := page.After()
+	if err != nil {
+		return dto.UserListOutputDto{}, err
+	}
+	filter := listFilter(spec.IncludeDeleted)
+	if after != "" {
+		filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(page.Size() + 1))
+	cursor, err := r.db.Collection(usersCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return dto.UserListOutputDto{}, err
+	}
+	var docs []user
+	if err := cursor.All(ctx, &docs); err != nil {
+		return dto.UserListOutputDto{}, err
+	}
+	var users dto.UserListOutputDto
+	for _, doc := range docs {
+		users.Users = append(users.Users, doc.dto())
+	}
+	users.Users, users.NextCursor = query.Trim(users.Users, page,
+		func(u dto.UserOutputDto) string { return query.EncodeCursor(u.ID) })
+	return users, nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Collection(usersCollection).UpdateOne(ctx, liveID(id), softDelete(ctx, now()))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return dberr.NotFound("user")
+	}
+	return nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, userDto dto.UserInputDto) error {
+	if err := dberr.ValidateUser(userDto); err != nil {
+		return err
+	}
+	result, err := r.db.Collection(usersCollection).UpdateOne(ctx, versioned(liveID(userDto.ID), userDto.Version), bson.D{stamp(ctx, now(),
+		bson.E{Key: "name", Value: userDto.Name},
+		bson.E{Key: "email", Value: userDto.Email},
+		bson.E{Key: "password", Value: userDto.Password},
+	), bump})
+	if mongo.IsDuplicateKeyError(err) {
+		return dberr.Wrap(dberr.ErrConflict, "email already in use", err)
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return notUpdated(ctx, r.db.Collection(usersCollection), "user", userDto.ID, userDto.Version)
+	}
+	return nil
+}
+
+func (r *UserRepository) Find(ctx context.Context, id string) (dto.UserOutputDto, error) {
+	var doc user
+	err := r.db.Collection(usersCollection).FindOne(ctx, liveID(id)).Decode(&doc)
+	if err != nil {
+		return dto.UserOutputDto{}, noDocuments("user", err)
+	}
+	return doc.dto(), nil
+}
+
+// Restore brings back a deleted user.
+func (r *UserRepository) Restore(ctx context.Context, id string) error {
+	result, err := r.db.Collection(usersCollection).UpdateOne(ctx, deletedID(id), restore(ctx, now()))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return dberr.NotFound("user")
+	}
+	return nil
+}
+
+// Purge removes users deleted before the given time, with their
+// enrollments and their places among the instructors of courses.
+func (r *UserRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ids, err := findIDs(ctx, r.db.Collection(usersCollection), deletedBefore(before))
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.db.Collection(usersCollection).DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		return 0, err
+	}
+	if err := purgeEnrollments(ctx, r.db, "user_id", ids); err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, purgeInstructors(ctx, r.db, "user_id", ids)
+}
@@ -0,0 +1,347 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// WithOwnership lets only the instructors of a course, and the admins, change
+// it: update, delete or restore it, edit its modules and lessons, its
+// instructors or its tags. The caller is the actor of the context, see
+// package audit; admins are given by email and need not be users. Anonymous
+// changes are refused with dberr.NotOwner. Creating a course stays open to
+// everyone.
+//
+// Deleting a category, which may delete its courses, and deleting a tag,
+// which untags every course, are left to the admins, see CheckAdmin.
+func (d *DBImplementation) WithOwnership(admins []string) *DBImplementation {
+	isAdmin := make(map[string]bool, len(admins))
+	for _, email := range admins {
+		if email != "" {
+			isAdmin[email] = true
+		}
+	}
+	d.admins = isAdmin
+	o := &owners{admins: isAdmin, users: d.UserRepository, instructors: d.InstructorRepository, modules: d.ModuleRepository, lessons: d.LessonRepository}
+	d.CourseRepository = &ownedCourseRepository{CourseRepositoryInterface: d.CourseRepository, owners: o}
+	d.ModuleRepository = &ownedModuleRepository{ModuleRepositoryInterface: d.ModuleRepository, owners: o}
+	d.LessonRepository = &ownedLessonRepository{LessonRepositoryInterface: d.LessonRepository, owners: o}
+	d.InstructorRepository = &ownedInstructorRepository{InstructorRepositoryInterface: d.InstructorRepository, owners: o}
+	d.TagRepository = &ownedTagRepository{TagRepositoryInterface: d.TagRepository, owners: o}
+	d.CategoryRepository = &ownedCategoryRepository{CategoryRepositoryInterface: d.CategoryRepository, owners: o}
+	if begin := d.begin; begin != nil {
+		d.begin = func(ctx context.Context) (*Tx, error) {
+			tx, err := begin(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// checks made in a unit of work see its changes
+			o := &owners{admins: isAdmin, users: tx.UserRepository, instructors: tx.InstructorRepository, modules: tx.ModuleRepository, lessons: tx.LessonRepository}
+			tx.CourseRepository = &ownedCourseRepository{CourseRepositoryInterface: tx.CourseRepository, owners: o}
+			tx.ModuleRepository = &ownedModuleRepository{ModuleRepositoryInterface: tx.ModuleRepository, owners: o}
+			tx.LessonRepository = &ownedLessonRepository{LessonRepositoryInterface: tx.LessonRepository, owners: o}
+			tx.InstructorRepository = &ownedInstructorRepository{InstructorRepositoryInterface: tx.InstructorRepository, owners: o}
+			tx.TagRepository = &ownedTagRepository{TagRepositoryInterface: tx.TagRepository, owners: o}
+			tx.CategoryRepository = &ownedCategoryRepository{CategoryRepositoryInterface: tx.CategoryRepository, owners: o}
+			return tx, nil
+		}
+	}
+	return d
+}
+
+// AdminChecker refuses callers that are not admins, for the operations only
+// they may do: purging and listing deleted rows. DBImplementation is one.
+type AdminChecker interface {
+	CheckAdmin(ctx context.Context) error
+}
+
+// CheckAdmin returns dberr.Unauthenticated when the context has no actor and
+// dberr.NotAdmin when the actor is not one of the admins given to
+// WithOwnership. Without WithOwnership nobody is an admin.
+func (d *DBImplementation) CheckAdmin(ctx context.Context) error {
+	return checkAdmin(ctx, d.admins)
+}
+
+func checkAdmin(ctx context.Context, admins map[string]bool) error {
+	actor := audit.Actor(ctx)
+	if actor == "" {
+		return dberr.Unauthenticated
+	}
+	if !admins[actor] {
+		return dberr.NotAdmin
+	}
+	return nil
+}
+
+// owners tells whether the caller may change a course, reading through the
+// repositories it was given, not the guarded ones.
+type owners struct {
+	admins      map[string]bool
+	users       UserRepositoryInterface
+	instructors InstructorRepositoryInterface
+	modules     ModuleRepositoryInterface
+	lessons     LessonRepositoryInterface
+}
+
+func (o *owners) check(ctx context.Context, courseID string) error {
+	actor := audit.Actor(ctx)
+	if actor == "" {
+		return dberr.NotOwner
+	}
+	if o.admins[actor] {
+		return nil
+	}
+	userID, err := o.users.FindIDByEmail(ctx, actor)
+	if errors.Is(err, ErrNotFound) {
+		return dberr.NotOwner
+	}
+	if err != nil {
+		return err
+	}
+	teaches, err := o.instructors.Teaches(ctx, userID, courseID)
+	if err != nil {
+		return err
+	}
+	if !teaches {
+		return dberr.NotOwner
+	}
+	return nil
+}
+
+func (o *owners) checkModule(ctx context.Context, moduleID string) error {
+	module, err := o.modules.Find(ctx, moduleID)
+	if err != nil {
+		return err
+	}
+	return o.check(ctx, module.CourseID)
+}
+
+func (o *owners) checkLesson(ctx context.Context, lessonID string) error {
+	lesson, err := o.lessons.Find(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	return o.checkModule(ctx, lesson.ModuleID)
+}
+
+// ownedBatch applies a batch to the courses the caller may change and
+// reports the others as refused, in their place.
+func ownedBatch[T any](ctx context.Context, o *owners, items []T, courseOf func(T) string, apply func([]T) ([]dto.BatchItemOutputDto, error)) ([]dto.BatchItemOutputDto, error) {
+	results := make([]dto.BatchItemOutputDto, len(items))
+	var allowed []T
+	var positions []int
+	for i, item := range items {
+		results[i] = dto.BatchItemOutputDto{Index: i, ID: courseOf(item)}
+		if err := o.check(ctx, courseOf(item)); err != nil {
+			if !dberr.Rejected(err) {
+				return nil, err
+			}
+			results[i].Err = err
+			continue
+		}
+		allowed, positions = append(allowed, item), append(positions, i)
+	}
+	if len(allowed) == 0 {
+		return results, nil
+	}
+	applied, err := apply(allowed)
+	if err != nil {
+		return nil, err
+	}
+	for j, result := range applied {
+		result.Index = positions[j]
+		results[positions[j]] = result
+	}
+	return results, nil
+}
+
+type ownedCourseRepository struct {
+	CourseRepositoryInterface
+	owners *owners
+}
+
+func (c *ownedCourseRepository) UpdateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	return ownedBatch(ctx, c.owners, courses, func(course dto.CourseInputDto) string { return course.ID },
+		func(courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+			return c.CourseRepositoryInterface.UpdateBatch(ctx, courses)
+		})
+}
+
+func (c *ownedCourseRepository) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	return ownedBatch(ctx, c.owners, ids, func(id string) string { return id },
+		func(ids []string) ([]dto.BatchItemOutputDto, error) {
+			return c.CourseRepositoryInterface.DeleteBatch(ctx, ids)
+		})
+}
+
+func (c *ownedCourseRepository) Update(ctx context.Context, course dto.CourseInputDto) error {
+	if err := c.owners.check(ctx, course.ID); err != nil {
+		return err
+	}
+	return c.CourseRepositoryInterface.Update(ctx, course)
+}
+
+func (c *ownedCourseRepository) Delete(ctx context.Context, id string) error {
+	if err := c.owners.check(ctx, id); err != nil {
+		return err
+	}
+	return c.CourseRepositoryInterface.Delete(ctx, id)
+}
+
+func (c *ownedCourseRepository) Restore(ctx context.Context, id string) error {
+	if err := c.owners.check(ctx, id); err != nil {
+		return err
+	}
+	return c.CourseRepositoryInterface.Restore(ctx, id)
+}
+
+type ownedModuleRepository struct {
+	ModuleRepositoryInterface
+	owners *owners
+}
+
+func (m *ownedModuleRepository) Create(ctx context.Context, module dto.ModuleInputDto) (dto.ModuleOutputDto, error) {
+	if err := m.owners.check(ctx, module.CourseID); err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	return m.ModuleRepositoryInterface.Create(ctx, module)
+}
+
+func (m *ownedModuleRepository) Update(ctx context.Context, module dto.ModuleInputDto) error {
+	if err := m.owners.checkModule(ctx, module.ID); err != nil {
+		return err
+	}
+	return m.ModuleRepositoryInterface.Update(ctx, module)
+}
+
+func (m *ownedModuleRepository) Delete(ctx context.Context, id string) error {
+	if err := m.owners.checkModule(ctx, id); err != nil {
+		return err
+	}
+	return m.ModuleRepositoryInterface.Delete(ctx, id)
+}
+
+func (m *ownedModuleRepository) Reorder(ctx context.Context, courseID string, ids []string) error {
+	if err := m.owners.check(ctx, courseID); err != nil {
+		return err
+	}
+	return m.ModuleRepositoryInterface.Reorder(ctx, courseID, ids)
+}
+
+type ownedLessonRepository struct {
+	LessonRepositoryInterface
+	owners *owners
+}
+
+func (l *ownedLessonRepository) Create(ctx context.Context, lesson dto.LessonInputDto) (dto.LessonOutputDto, error) {
+	if err := l.owners.checkModule(ctx, lesson.ModuleID); err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	return l.LessonRepositoryInterface.Create(ctx, lesson)
+}
+
+func (l *ownedLessonRepository) Update(ctx context.Context, lesson dto.LessonInputDto) error {
+	if err := l.owners.checkLesson(ctx, lesson.ID); err != nil {
+		return err
+	}
+	return l.LessonRepositoryInterface.Update(ctx, lesson)
+}
+
+func (l *ownedLessonRepository) Delete(ctx context.Context, id string) error {
+	if err := l.owners.checkLesson(ctx, id); err != nil {
+		return err
+	}
+	return l.LessonRepositoryInterface.Delete(ctx, id)
+}
+
+func (l *ownedLessonRepository) Reorder(ctx context.Context, moduleID string, ids []string) error {
+	if err := l.owners.checkModule(ctx, moduleID); err != nil {
+		return err
+	}
+	return l.LessonRepositoryInterface.Reorder(ctx, moduleID, ids)
+}
+
+type ownedInstructorRepository struct {
+	InstructorRepositoryInterface
+	owners *owners
+}
+
+func (i *ownedInstructorRepository) Add(ctx context.Context, instructor dto.InstructorInputDto) (dto.InstructorOutputDto, error) {
+	if err := i.owners.check(ctx, instructor.CourseID); err != nil {
+		return dto.InstructorOutputDto{}, err
+	}
+	return i.InstructorRepositoryInterface.Add(ctx, instructor)
+}
+
+func (i *ownedInstructorRepository) Remove(ctx context.Context, courseID, userID string) error {
+	if err := i.owners.check(ctx, courseID); err != nil {
+		return err
+	}
+	return i.InstructorRepositoryInterface.Remove(ctx, courseID, userID)
+}
+
+// ownedTagRepository guards the tagging of courses. Tags themselves are
+// shared by all courses: anyone may create one, only admins delete one.
+type ownedTagRepository struct {
+	TagRepositoryInterface
+	owners *owners
+}
+
+func (t *ownedTagRepository) Tag(ctx context.Context, tag dto.CourseTagInputDto) (dto.TagOutputDto, error) {
+	if err := t.owners.check(ctx, tag.CourseID); err != nil {
+		return dto.TagOutputDto{}, err
+	}
+	return t.TagRepositoryInterface.Tag(ctx, tag)
+}
+
+func (t *ownedTagRepository) Untag(ctx context.Context, courseID, tagID string) error {
+	if err := t.owners.check(ctx, courseID); err != nil {
+		return err
+	}
+	return t.TagRepositoryInterface.Untag(ctx, courseID, tagID)
+}
+
+func (t *ownedTagRepository) Delete(ctx context.Context, id string) error {
+	if err := checkAdmin(ctx, t.owners.admins); err != nil {
+		return err
+	}
+	return t.TagRepositoryInterface.Delete(ctx, id)
+}
+
+// ownedCategoryRepository lets only admins delete categories, whose courses
+// go along with them under query.Cascade, and restore them.
+type ownedCategoryRepository struct {
+	CategoryRepositoryInterface
+	owners *owners
+}
+
+func (c *ownedCategoryRepository) Delete(ctx context.Context, id string) error {
+	if err := checkAdmin(ctx, c.owners.admins); err != nil {
+		return err
+	}
+	return c.CategoryRepositoryInterface.Delete(ctx, id)
+}
+
+func (c *ownedCategoryRepository) Restore(ctx context.Context, id string) error {
+	if err := checkAdmin(ctx, c.owners.admins); err != nil {
+		return err
+	}
+	return c.CategoryRepositoryInterface.Restore(ctx, id)
+}
+
+// DeleteBatch reports every category as refused when the caller is not an
+// admin.
+func (c *ownedCategoryRepository) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	if err := checkAdmin(ctx, c.owners.admins); err != nil {
+		results := make([]dto.BatchItemOutputDto, len(ids))
+		for i, id := range ids {
+			results[i] = dto.BatchItemOutputDto{Index: i, ID: id, Err: err}
+		}
+		return results, nil
+	}
+	return c.CategoryRepositoryInterface.DeleteBatch(ctx, ids)
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func TestOwnership(t *testing.T) {
+	ctx := context.Background()
+	dbi := NewMemoryImplementation(query.Restrict).WithOwnership([]string{"admin@example.com"})
+	ann, bob, admin := audit.WithActor(ctx, "ann@example.com"), audit.WithActor(ctx, "bob@example.com"), audit.WithActor(ctx, "admin@example.com")
+	var bobID string
+	for _, email := range []string{"ann@example.com", "bob@example.com"} {
+		user, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "user", Email: email, Password: "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobID = user.ID
+	}
+	category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	basics, err := dbi.CourseRepository.Create(ann, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "other", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rename := func(ctx context.Context, id, name string) error {
+		return dbi.CourseRepository.Update(ctx, dto.CourseInputDto{ID: id, Name: name, CategoryID: category.ID})
+	}
+
+	for _, tt := range []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{"anonymous update", func() error { return rename(ctx, basics.ID, "x") }, ErrForbidden},
+		{"stranger update", func() error { return rename(bob, basics.ID, "x") }, ErrForbidden},
+		{"stranger delete", func() error { return dbi.CourseRepository.Delete(bob, basics.ID) }, ErrForbidden},
+		{"instructor update", func() error { return rename(ann, basics.ID, "basics 2") }, nil},
+		{"admin update", func() error { return rename(admin, other.ID, "other 2") }, nil},
+		{"stranger module", func() error {
+			_, err := dbi.ModuleRepository.Create(bob, dto.ModuleInputDto{CourseID: basics.ID, Title: "setup"})
+			return err
+		}, ErrForbidden},
+		{"stranger instructor", func() error {
+			_, err := dbi.InstructorRepository.Add(bob, dto.InstructorInputDto{CourseID: basics.ID, UserID: bobID})
+			return err
+		}, ErrForbidden},
+		{"stranger tag", func() error {
+			_, err := dbi.TagRepository.Tag(bob, dto.CourseTagInputDto{CourseID: basics.ID, Name: "go"})
+			return err
+		}, ErrForbidden},
+		{"stranger in a unit of work", func() error {
+			return InTx(ctx, dbi, func(tx *Tx) error {
+				return tx.CourseRepository.Update(bob, dto.CourseInputDto{ID: basics.ID, Name: "x", CategoryID: category.ID})
+			})
+		}, ErrForbidden},
+		{"instructor adds an instructor", func() error {
+			_, err := dbi.InstructorRepository.Add(ann, dto.InstructorInputDto{CourseID: basics.ID, UserID: bobID})
+			return err
+		}, nil},
+		{"new instructor update", func() error { return rename(bob, basics.ID, "basics 3") }, nil},
+	} {
+		if err := tt.run(); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	module, err := dbi.ModuleRepository.Create(ann, dto.ModuleInputDto{CourseID: basics.ID, Title: "setup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lesson, err := dbi.LessonRepository.Create(ann, dto.LessonInputDto{ModuleID: module.ID, Title: "install", ContentType: "text"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.LessonRepository.Delete(audit.WithActor(ctx, "carl@example.com"), lesson.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("lesson delete by a stranger error = %v, want forbidden", err)
+	}
+
+	results, err := dbi.CourseRepository.DeleteBatch(bob, []string{other.ID, basics.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Index != 0 || !errors.Is(results[0].Err, ErrForbidden) || results[1].Index != 1 || results[1].Err != nil {
+		t.Errorf("DeleteBatch() = %+v, want the course bob does not teach refused", results)
+	}
+}
+
+func TestCheckAdmin(t *testing.T) {
+	ctx := context.Background()
+	dbi := NewMemoryImplementation(query.Restrict).WithOwnership([]string{"admin@example.com"})
+	for _, tt := range []struct {
+		actor string
+		want  error
+	}{
+		{"", ErrUnauthenticated},
+		{"ann@example.com", ErrForbidden},
+		{"admin@example.com", nil},
+	} {
+		if err := dbi.CheckAdmin(audit.WithActor(ctx, tt.actor)); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("CheckAdmin(%q) error = %v, want %v", tt.actor, err, tt.want)
+		}
+	}
+}
+
+func TestOwnershipAdminDeletes(t *testing.T) {
+	ctx := context.Background()
+	dbi := NewMemoryImplementation(query.Cascade).WithOwnership([]string{"admin@example.com"})
+	ann, admin := audit.WithActor(ctx, "ann@example.com"), audit.WithActor(ctx, "admin@example.com")
+	if _, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "ann", Email: "ann@example.com", Password: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := dbi.CourseRepository.Create(admin, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := dbi.TagRepository.Tag(admin, dto.CourseTagInputDto{CourseID: course.ID, Name: "go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{"anonymous category delete", func() error { return dbi.CategoryRepository.Delete(ctx, category.ID) }, ErrUnauthenticated},
+		{"category delete", func() error { return dbi.CategoryRepository.Delete(ann, category.ID) }, ErrForbidden},
+		{"category batch delete", func() error {
+			results, err := dbi.CategoryRepository.DeleteBatch(ann, []string{category.ID})
+			if err != nil {
+				return err
+			}
+			return results[0].Err
+		}, ErrForbidden},
+		{"tag delete", func() error { return dbi.TagRepository.Delete(ann, tag.ID) }, ErrForbidden},
+	} {
+		if err := tt.run(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	if _, err := dbi.CourseRepository.Find(ctx, course.ID); err != nil {
+		t.Errorf("course after refused deletes: %v", err)
+	}
+
+	if err := dbi.TagRepository.Delete(admin, tag.ID); err != nil {
+		t.Errorf("admin tag delete: %v", err)
+	}
+	if err := dbi.CategoryRepository.Delete(admin, category.ID); err != nil {
+		t.Errorf("admin category delete: %v", err)
+	}
+	if _, err := dbi.CourseRepository.Find(ctx, course.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("course after the category delete error = %v, want not found", err)
+	}
+
+	if err := dbi.CategoryRepository.Restore(ann, category.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("category restore error = %v, want %v", err, ErrForbidden)
+	}
+	if _, err := dbi.CategoryRepository.Find(ctx, category.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("category after the refused restore error = %v, want not found", err)
+	}
+	if err := dbi.CategoryRepository.Restore(admin, category.ID); err != nil {
+		t.Errorf("admin category restore: %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+const courseColumns = "id, name, description, category_id, created_at, updated_at, created_by, updated_by, version, deleted_at"
+
+type Course struct {
+	db query.DBTX
+}
+
+func NewCourseRepository(db query.DBTX) *Course {
+	return &Course{db: db}
+}
+
+func scanCourse(row interface{ Scan(...any) error }) (dto.CourseOutputDto, error) {
+	var course dto.CourseOutputDto
+	err := row.Scan(&course.ID, &course.Name, &course.Description, &course.CategoryID,
+		&course.CreatedAt, &course.UpdatedAt, &course.CreatedBy, &course.UpdatedBy, &course.Version, &course.DeletedAt)
+	course.CreatedAt, course.UpdatedAt, course.DeletedAt = course.CreatedAt.UTC(), course.UpdatedAt.UTC(), utc(course.DeletedAt)
+	return course, err
+}
+
+func (c *Course) Create(ctx context.Context, course dto.CourseInputDto) (*dto.CourseOutputDto, error) {
+	if err := validateCourse(course); err != nil {
+		return nil, err
+	}
+	now, actor := time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx)
+	created := dto.CourseOutputDto{
+		ID:          uuid.New().String(),
+		Name:        course.Name,
+		Description: course.Description,
+		CategoryID:  course.CategoryID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	err := query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		if err := checkCategory(ctx, q, course.CategoryID); err != nil {
+			return err
+		}
+		_, err := q.ExecContext(ctx, "INSERT INTO courses (id, name, description, category_id, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+			created.ID, created.Name, created.Description, created.CategoryID, now, now, actor, actor)
+		if isForeignKey(err) {
+			return dberr.UnknownCategory
+		}
+		if err != nil {
+			return err
+		}
+		if err := addCreator(ctx, q, now, created.ID); err != nil {
+			return err
+		}
+		return record(ctx, q, audit.Course, created.ID, audit.Create, nil, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// CreateBatch creates courses with multi-row inserts, in one transaction.
+// Invalid courses are rejected in their result and the others created.
+func (c *Course) CreateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	now, actor := time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx)
+	results := make([]dto.BatchItemOutputDto, len(courses))
+	err := query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		categories := map[string]error{}
+		var rows, history [][]any
+		var ids []string
+		for i, course := range courses {
+			results[i] = dto.BatchItemOutputDto{Index: i}
+			err := validateCourse(course)
+			if err == nil {
+				checked, ok := categories[course.CategoryID]
+				if !ok {
+					checked = checkCategory(ctx, q, course.CategoryID)
+					if checked != nil && !dberr.Rejected(checked) {
+						return checked
+					}
+					categories[course.CategoryID] = checked
+				}
+				err = checked
+			}
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			created := dto.CourseOutputDto{
+				ID:          uuid.New().String(),
+				Name:        course.Name,
+				Description: course.Description,
+				CategoryID:  course.CategoryID,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+				CreatedBy:   actor,
+				UpdatedBy:   actor,
+				Version:     1,
+			}
+			entry, err := historyRow(ctx, audit.Course, created.ID, audit.Create, nil, created)
+			if err != nil {
+				return err
+			}
+			results[i].ID = created.ID
+			rows = append(rows, []any{created.ID, created.Name, created.Description, created.CategoryID, now, now, actor, actor})
+			history = append(history, entry)
+			ids = append(ids, created.ID)
+		}
+		err := query.InsertRows(ctx, q, query.Dollar, "INSERT INTO courses (id, name, description, category_id, created_at, updated_at, created_by, updated_by)", rows)
+		if isForeignKey(err) {
+			return dberr.UnknownCategory
+		}
+		if err != nil {
+			return err
+		}
+		if err := addCreator(ctx, q, now, ids...); err != nil {
+			return err
+		}
+		return query.InsertRows(ctx, q, query.Dollar, insertHistory, history)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *Course) FindAll(ctx context.Context, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{Page: page})
+}
+
+func (c *Course) FindByCategoryID(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{CategoryID: categoryID, Page: page})
+}
+
+func (c *Course) FindByCategoryTree(ctx context.Context, categoryID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{CategoryTree: categoryID, Page: page})
+}
+
+func (c *Course) FindByInstructorID(ctx context.Context, userID string, page query.Page) (dto.CourseListOutputDto, error) {
+	return c.List(ctx, query.CourseSpec{InstructorID: userID, Page: page})
+}
+
+func (c *Course) List(ctx context.Context, spec query.CourseSpec) (dto.CourseListOutputDto, error) {
+	if after, err := spec.Page.After(); err == nil && after != "" && !validID(after) {
+		return dto.CourseListOutputDto{}, query.ErrInvalidCursor
+	}
+	if spec.CategoryID != "" && !validID(spec.CategoryID) || spec.CategoryTree != "" && !validID(spec.CategoryTree) || spec.InstructorID != "" && !validID(spec.InstructorID) {
+		return dto.CourseListOutputDto{}, nil
+	}
+	stmt, err := spec.Select(query.Postgres, courseColumns, "courses")
+	if err != nil {
+		return dto.CourseListOutputDto{}, err
+	}
+	rows, err := c.db.QueryContext(ctx, stmt.SQL, stmt.Args...)
+	if err != nil {
+		return dto.CourseListOutputDto{}, err
+	}
+	defer rows.Close()
+	courses := dto.CourseListOutputDto{}
+	for rows.Next() {
+		course, err := scanCourse(rows)
+		if err != nil {
+			return dto.CourseListOutputDto{}, err
+		}
+		courses.Courses = append(courses.Courses, course)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.CourseListOutputDto{}, err
+	}
+	courses.Courses, courses.NextCursor = query.Trim(courses.Courses, spec.Page,
+		func(c dto.CourseOutputDto) string { return spec.Sort.Cursor(c.ID, c.Name, c.CreatedAt) })
+	return courses, nil
+}
+
+func (c *Course) Find(ctx context.Context, id string) (dto.CourseOutputDto, error) {
+	course, err := c.get(ctx, id)
+	if err == nil && course.DeletedAt != nil {
+		return dto.CourseOutputDto{}, dberr.NotFound("course")
+	}
+	return course, err
+}
+
+// get reads a course whether it is deleted or not.
+func (c *Course) get(ctx context.Context, id string) (dto.CourseOutputDto, error) {
+	if !validID(id) {
+		return dto.CourseOutputDto{}, dberr.NotFound("course")
+	}
+	course, err := scanCourse(c.db.QueryRowContext(ctx, "SELECT "+courseColumns+" FROM courses WHERE id = $1", id))
+	if err != nil {
+		return dto.CourseOutputDto{}, dberr.NoRows("course", err)
+	}
+	return course, nil
+}
+
+func (c *Course) Update(ctx context.Context, course dto.CourseInputDto) error {
+	if err := validateCourse(course); err != nil {
+		return err
+	}
+	return query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		before, err := (&Course{db: q}).Find(ctx, course.ID)
+		if err != nil {
+			return err
+		}
+		if err := dberr.CheckVersion("course", course.Version, before.Version); err != nil {
+			return err
+		}
+		if err := checkCategory(ctx, q, course.CategoryID); err != nil {
+			return err
+		}
+		after := before
+		after.Name, after.Description, after.CategoryID = course.Name, course.Description, course.CategoryID
+		after.UpdatedAt, after.UpdatedBy = time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx)
+		after.Version++
+		result, err := q.ExecContext(ctx, "UPDATE courses SET version = version + 1, name = $1, description = $2, category_id = $3, updated_at = $4, updated_by = $5 WHERE id = $6 AND deleted_at IS NULL AND version = $7",
+			after.Name, after.Description, after.CategoryID, after.UpdatedAt, after.UpdatedBy, course.ID, before.Version)
+		if isForeignKey(err) {
+			return dberr.UnknownCategory
+		}
+		if err != nil {
+			return err
+		}
+		if err := dberr.Changed(result, "course"); err != nil {
+			return err
+		}
+		return record(ctx, q, audit.Course, course.ID, audit.Update, before, after)
+	})
+}
+
+func (c *Course) Delete(ctx context.Context, id string) error {
+	return query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		before, err := (&Course{db: q}).Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		return deleteCourse(ctx, q, before, time.Now().UTC().Truncate(time.Microsecond))
+	})
+}
+
+// UpdateBatch updates courses in one transaction. A course that cannot be
+// updated is rejected in its result and the others updated.
+func (c *Course) UpdateBatch(ctx context.Context, courses []dto.CourseInputDto) ([]dto.BatchItemOutputDto, error) {
+	return query.Batch(ctx, c.db, len(courses), func(q query.DBTX, i int) (string, error) {
+		return courses[i].ID, (&Course{db: q}).Update(ctx, courses[i])
+	})
+}
+
+// DeleteBatch deletes courses in one transaction. A course that cannot be
+// deleted is rejected in its result and the others deleted.
+func (c *Course) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	return query.Batch(ctx, c.db, len(ids), func(q query.DBTX, i int) (string, error) {
+		return ids[i], (&Course{db: q}).Delete(ctx, ids[i])
+	})
+}
+
+// deleteCourse soft deletes a live course read in the same transaction.
+func deleteCourse(ctx context.Context, q query.DBTX, before dto.CourseOutputDto, deletedAt time.Time) error {
+	after := before
+	after.DeletedAt, after.UpdatedAt, after.UpdatedBy = &deletedAt, deletedAt, audit.Actor(ctx)
+	after.Version++
+	result, err := q.ExecContext(ctx, "UPDATE courses SET version = version + 1, deleted_at = $1, updated_at = $2, updated_by = $3 WHERE id = $4 AND deleted_at IS NULL",
+		deletedAt, deletedAt, after.UpdatedBy, before.ID)
+	if err != nil {
+		return err
+	}
+	if err := dberr.Affected(result, "course"); err != nil {
+		return err
+	}
+	return record(ctx, q, audit.Course, before.ID, audit.Delete, before, after)
+}
+
+// Restore brings back a deleted course, provided its category is not deleted.
+func (c *Course) Restore(ctx context.Context, id string) error {
+	return query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		before, err := (&Course{db: q}).get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.DeletedAt == nil {
+			return dberr.NotFound("course")
+		}
+		if err := checkCategory(ctx, q, before.CategoryID); err != nil {
+			return err
+		}
+		after := before
+		after.DeletedAt, after.UpdatedAt, after.UpdatedBy = nil, time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx)
+		after.Version++
+		result, err := q.ExecContext(ctx, "UPDATE courses SET version = version + 1, deleted_at = NULL, updated_at = $1, updated_by = $2 WHERE id = $3 AND deleted_at IS NOT NULL",
+			after.UpdatedAt, after.UpdatedBy, id)
+		if err != nil {
+			return err
+		}
+		if err := dberr.Affected(result, "course"); err != nil {
+			return err
+		}
+		return record(ctx, q, audit.Course, id, audit.Restore, before, after)
+	})
+}
+
+// Purge removes courses deleted before the given time.
+func (c *Course) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := c.db.ExecContext(ctx, "DELETE FROM courses WHERE deleted_at < $1", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// checkCategory rejects a category that does not exist or is deleted; the
+// foreign key alone accepts a deleted one.
+func checkCategory(ctx context.Context, q query.DBTX, categoryID string) error {
+	var count int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM categories WHERE id = $1 AND deleted_at IS NULL", categoryID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return dberr.UnknownCategory
+	}
+	return nil
+}
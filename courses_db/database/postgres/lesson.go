@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+const lessonColumns = "id, module_id, title, content_type, duration_seconds, body, position, created_at, updated_at, created_by, updated_by, version"
+
+type Lesson struct {
+	db query.DBTX
+}
+
+func NewLessonRepository(db query.DBTX) *Lesson {
+	return &Lesson{db: db}
+}
+
+func scanLesson(row interface{ Scan(...any) error }) (dto.LessonOutputDto, error) {
+	var lesson dto.LessonOutputDto
+	err := row.Scan(&lesson.ID, &lesson.ModuleID, &lesson.Title, &lesson.ContentType, &lesson.DurationSeconds, &lesson.Body, &lesson.Position,
+		&lesson.CreatedAt, &lesson.UpdatedAt, &lesson.CreatedBy, &lesson.UpdatedBy, &lesson.Version)
+	lesson.CreatedAt, lesson.UpdatedAt = lesson.CreatedAt.UTC(), lesson.UpdatedAt.UTC()
+	return lesson, err
+}
+
+// Create adds a lesson at the end of its module.
+func (l *Lesson) Create(ctx context.Context, lesson dto.LessonInputDto) (dto.LessonOutputDto, error) {
+	if err := dberr.ValidateLesson(lesson); err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	now, actor := time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx)
+	created := dto.LessonOutputDto{
+		ID:              uuid.New().String(),
+		ModuleID:        lesson.ModuleID,
+		Title:           lesson.Title,
+		ContentType:     lesson.ContentType,
+		DurationSeconds: lesson.DurationSeconds,
+		Body:            lesson.Body,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		CreatedBy:       actor,
+		UpdatedBy:       actor,
+		Version:         1,
+	}
+	err := query.Atomic(ctx, l.db, func(q query.DBTX) error {
+		found, err := moduleExists(ctx, q, lesson.ModuleID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return dberr.UnknownModule
+		}
+		err = q.QueryRowContext(ctx, "SELECT COALESCE(MAX(position), 0) + 1 FROM lessons WHERE module_id = $1", lesson.ModuleID).
+			Scan(&created.Position)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, "INSERT INTO lessons (id, module_id, title, content_type, duration_seconds, body, position, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)",
+			created.ID, created.ModuleID, created.Title, created.ContentType, created.DurationSeconds, created.Body, created.Position, now, now, actor, actor)
+		if isForeignKey(err) {
+			return dberr.UnknownModule
+		}
+		return err
+	})
+	if err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	return created, nil
+}
+
+func (l *Lesson) FindByModuleID(ctx context.Context, moduleID string) (dto.LessonListOutputDto, error) {
+	found, err := moduleExists(ctx, l.db, moduleID)
+	if err != nil {
+		return dto.LessonListOutputDto{}, err
+	}
+	if !found {
+		return dto.LessonListOutputDto{}, dberr.NotFound("module")
+	}
+	rows, err := l.db.QueryContext(ctx, "SELECT "+lessonColumns+" FROM lessons WHERE module_id = $1 ORDER BY position, id", moduleID)
+	if err != nil {
+		return dto.LessonListOutputDto{}, err
+	}
+	defer rows.Close()
+	lessons := dto.LessonListOutputDto{}
+	for rows.Next() {
+		lesson, err := scanLesson(rows)
+		if err != nil {
+			return dto.LessonListOutputDto{}, err
+		}
+		lessons.Lessons = append(lessons.Lessons, lesson)
+	}
+	return lessons, rows.Err()
+}
+
+func (l *Lesson) Find(ctx context.Context, id string) (dto.LessonOutputDto, error) {
+	if !validID(id) {
+		return dto.LessonOutputDto{}, dberr.NotFound("lesson")
+	}
+	lesson, err := scanLesson(l.db.QueryRowContext(ctx, "SELECT "+lessonColumns+" FROM lessons WHERE id = $1", id))
+	if err != nil {
+		return dto.LessonOutputDto{}, dberr.NoRows("lesson", err)
+	}
+	return lesson, nil
+}
+
+func (l *Lesson) Update(ctx context.Context, lesson dto.LessonInputDto) error {
+	if err := dberr.ValidateLesson(lesson); err != nil {
+		return err
+	}
+	return query.Atomic(ctx, l.db, func(q query.DBTX) error {
+		before, err := (&Lesson{db: q}).Find(ctx, lesson.ID)
+		if err != nil {
+			return err
+		}
+		if err := dberr.CheckVersion("lesson", lesson.Version, before.Version); err != nil {
+			return err
+		}
+		result, err := q.ExecContext(ctx, "UPDATE lessons SET version = version + 1, title = $1, content_type = $2, duration_seconds = $3, body = $4, updated_at = $5, updated_by = $6 WHERE id = $7 AND version = $8",
+			lesson.Title, lesson.ContentType, lesson.DurationSeconds, lesson.Body, time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx), lesson.ID, before.Version)
+		if err != nil {
+			return err
+		}
+		return dberr.Changed(result, "lesson")
+	})
+}
+
+// Delete removes a lesson; the lessons after it move up.
+func (l *Lesson) Delete(ctx context.Context, id string) error {
+	return query.Atomic(ctx, l.db, func(q query.DBTX) error {
+		before, err := (&Lesson{db: q}).Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, "DELETE FROM lessons WHERE id = $1", id); err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, "UPDATE lessons SET position = position - 1 WHERE module_id = $1 AND position > $2",
+			before.ModuleID, before.Position)
+		return err
+	})
+}
+
+func (l *Lesson) Reorder(ctx context.Context, moduleID string, ids []string) error {
+	return query.Atomic(ctx, l.db, func(q query.DBTX) error {
+		lessons, err := (&Lesson{db: q}).FindByModuleID(ctx, moduleID)
+		if err != nil {
+			return err
+		}
+		positions := make(map[string]int, len(lessons.Lessons))
+		current := make([]string, len(lessons.Lessons))
+		for i, lesson := range lessons.Lessons {
+			positions[lesson.ID], current[i] = lesson.Position, lesson.ID
+		}
+		if err := dberr.CheckOrder(current, ids, "lesson", "module"); err != nil {
+			return err
+		}
+		now, actor := time.Now().UTC().Truncate(time.Microsecond), audit.Actor(ctx)
+		for i, id := range ids {
+			if positions[id] == i+1 {
+				continue
+			}
+			_, err := q.ExecContext(ctx, "UPDATE lessons SET version = version + 1, position = $1, updated_at = $2, updated_by = $3 WHERE id = $4",
+				i+1, now, actor, id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func moduleExists(ctx context.Context, q query.DBTX, moduleID string) (bool, error) {
+	if !validID(moduleID) {
+		return false, nil
+	}
+	var count int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM modules WHERE id = $1", moduleID).Scan(&count)
+	return count > 0, err
+}
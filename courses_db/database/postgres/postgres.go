@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// validID reports whether id can be compared with a uuid column. Any other
+// string cannot identify a row, so lookups treat it as not found instead of
+// letting postgres reject the query.
+func validID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+// validateCourse adds the uuid check of category_id to dberr.ValidateCourse.
+func validateCourse(course dto.CourseInputDto) error {
+	if err := dberr.ValidateCourse(course); err != nil {
+		return err
+	}
+	if !validID(course.CategoryID) {
+		return dberr.New(dberr.ErrValidation, "course category_id is not a valid id")
+	}
+	return nil
+}
+
+func isDuplicate(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// isForeignKey reports a foreign key violation, from either side of the
+// relation.
+func isForeignKey(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// utc converts a nullable timestamp read from the database to UTC.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
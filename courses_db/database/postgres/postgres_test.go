@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/migrations"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+// openTestDB connects to the database named by POSTGRES_TEST_DSN, for example
+// "host=localhost user=postgres password=postgres dbname=courses_test sslmode=disable",
+// and resets its schema. Point it at a throwaway database: every table is
+// dropped and migrated again.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := migrations.New(db, "postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := m.To(ctx, 0); err != nil {
+		t.Fatalf("migrate down: %v", err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	return db
+}
+
+func TestRepositories(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	categories := NewCategoryRepository(db, query.Restrict)
+	courses := NewCourseRepository(db)
+	users := NewUserRepository(db)
+
+	category, err := categories.Create(ctx, dto.CategoryInputDto{Name: "Go", Description: "golang"})
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	course, err := courses.Create(ctx, dto.CourseInputDto{Name: "Basics", Description: "intro", CategoryID: category.ID})
+	if err != nil {
+		t.Fatalf("create course: %v", err)
+	}
+	user, err := users.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "hash"})
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{name: "find category", run: func() error {
+			got, err := categories.Find(ctx, category.ID)
+			if err == nil && (got.Name != category.Name || !got.CreatedAt.Equal(category.CreatedAt)) {
+				t.Errorf("Find() = %v, want %v", got, category)
+			}
+			return err
+		}},
+		{name: "find course", run: func() error {
+			got, err := courses.Find(ctx, course.ID)
+			if err == nil && (got.CategoryID != course.CategoryID || !got.CreatedAt.Equal(course.CreatedAt)) {
+				t.Errorf("Find() = %v, want %v", got, *course)
+			}
+			return err
+		}},
+		{name: "category of course", run: func() error {
+			got, err := categories.FindByCourseID(ctx, course.ID)
+			if err == nil && got.ID != category.ID {
+				t.Errorf("FindByCourseID() = %v, want %v", got.ID, category.ID)
+			}
+			return err
+		}},
+		{name: "list courses by category", run: func() error {
+			got, err := courses.List(ctx, query.CourseSpec{CategoryID: category.ID, NamePrefix: "Ba"})
+			if err == nil && len(got.Courses) != 1 {
+				t.Errorf("List() = %v, want one course", got.Courses)
+			}
+			return err
+		}},
+		{name: "find user by email", run: func() error {
+			got, err := users.FindByEmail(ctx, user.Email)
+			if err == nil && got.Password != "hash" {
+				t.Errorf("FindByEmail() password = %q", got.Password)
+			}
+			return err
+		}},
+		{name: "duplicate email", run: func() error {
+			_, err := users.Create(ctx, dto.UserInputDto{Name: "Other", Email: user.Email})
+			return err
+		}, wantErr: dberr.ErrConflict},
+		{name: "course with non uuid category", run: func() error {
+			_, err := courses.Create(ctx, dto.CourseInputDto{Name: "x", CategoryID: "go"})
+			return err
+		}, wantErr: dberr.ErrValidation},
+		{name: "course with unknown category", run: func() error {
+			_, err := courses.Create(ctx, dto.CourseInputDto{Name: "x", CategoryID: uuid.NewString()})
+			return err
+		}, wantErr: dberr.ErrValidation},
+		{name: "find non uuid", run: func() error {
+			_, err := courses.Find(ctx, "not-a-uuid")
+			return err
+		}, wantErr: dberr.ErrNotFound},
+		{name: "delete category with courses", run: func() error {
+			return categories.Delete(ctx, category.ID)
+		}, wantErr: dberr.ErrHasDependents},
+		{name: "delete course then category", run: func() error {
+			if err := courses.Delete(ctx, course.ID); err != nil {
+				return err
+			}
+			if err := categories.Delete(ctx, category.ID); err != nil {
+				return err
+			}
+			_, err := categories.Find(ctx, category.ID)
+			return err
+		}, wantErr: dberr.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("error = %v", err)
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	category, err := NewCategoryRepository(db, query.Restrict).Create(ctx, dto.CategoryInputDto{Name: "Golang", Description: "the Go language"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	course, err := NewCourseRepository(db).Create(ctx, dto.CourseInputDto{Name: "Go basics", Description: "Go types and syntax", CategoryID: category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	search := NewSearchRepository(db)
+
+	tests := []struct {
+		name string
+		spec query.SearchSpec
+		want []string
+	}{
+		{name: "both", spec: query.SearchSpec{Query: "go"}, want: []string{course.ID, category.ID}},
+		{name: "every word", spec: query.SearchSpec{Query: "go syntax"}, want: []string{course.ID}},
+		{name: "categories only", spec: query.SearchSpec{Query: "go", EntityType: "category"}, want: []string{category.ID}},
+		{name: "no match", spec: query.SearchSpec{Query: "pasta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := search.Search(ctx, tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Results) != len(tt.want) {
+				t.Fatalf("Search() = %+v, want ids %v", got.Results, tt.want)
+			}
+			for i, result := range got.Results {
+				if result.ID != tt.want[i] || !strings.Contains(result.Snippet, query.HighlightStart) {
+					t.Errorf("result %d = %+v, want id %s with a highlighted snippet", i, result, tt.want[i])
+				}
+			}
+		})
+	}
+}
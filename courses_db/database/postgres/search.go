@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+type SearchRepository struct {
+	db query.DBTX
+}
+
+func NewSearchRepository(db query.DBTX) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// searchSelect matches one table, repeating the expression of its GIN index
+// from migration 9.
+const searchSelect = "SELECT '%s' AS entity_type, id::text AS id, name, name || ' ' || description AS document, ts_rank(to_tsvector('simple', name || ' ' || description), q) AS score FROM %s, plainto_tsquery('simple', $1) q WHERE deleted_at IS NULL AND to_tsvector('simple', name || ' ' || description) @@ q"
+
+// Search ranks with ts_rank; snippets come from ts_headline, run on the
+// returned page only.
+func (s *SearchRepository) Search(ctx context.Context, spec query.SearchSpec) (dto.SearchOutputDto, error) {
+	terms, err := spec.Terms()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	offset, err := spec.Page.Offset()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	var selects []string
+	for _, table := range []struct{ entityType, name string }{{"category", "categories"}, {"course", "courses"}} {
+		if spec.Includes(table.entityType) {
+			selects = append(selects, fmt.Sprintf(searchSelect, table.entityType, table.name))
+		}
+	}
+	options := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=%d, MinWords=%d", query.SnippetStart, query.SnippetEnd, query.SnippetWords, query.SnippetWords/2)
+	rows, err := s.db.QueryContext(ctx, "SELECT entity_type, id, name, ts_headline('simple', document, plainto_tsquery('simple', $1), $2), score FROM ("+strings.Join(selects, " UNION ALL ")+") AS results ORDER BY score DESC, entity_type, id LIMIT $3 OFFSET $4",
+		strings.Join(terms, " "), options, spec.Page.Size()+1, offset)
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	defer rows.Close()
+	results := dto.SearchOutputDto{}
+	for rows.Next() {
+		var result dto.SearchResultOutputDto
+		if err := rows.Scan(&result.EntityType, &result.ID, &result.Name, &result.Snippet, &result.Score); err != nil {
+			return dto.SearchOutputDto{}, err
+		}
+		result.Snippet = query.EscapeSnippet(result.Snippet)
+		results.Results = append(results.Results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	results.Results, results.NextCursor = query.TrimOffset(results.Results, spec.Page, offset)
+	return results, nil
+}
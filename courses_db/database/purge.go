@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// DefaultRetention is how long soft deleted rows are kept when a purge does
+// not say otherwise.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// Purger hard deletes soft deleted rows. DBImplementation is one.
+type Purger interface {
+	Purge(ctx context.Context, before time.Time) (dto.PurgeOutputDto, error)
+}
+
+// Purge removes everything deleted before the given time. Courses go first so
+// that the categories they referenced can go in the same run.
+func (d *DBImplementation) Purge(ctx context.Context, before time.Time) (dto.PurgeOutputDto, error) {
+	var result dto.PurgeOutputDto
+	var err error
+	if result.Courses, err = d.CourseRepository.Purge(ctx, before); err != nil {
+		return result, err
+	}
+	if result.Categories, err = d.CategoryRepository.Purge(ctx, before); err != nil {
+		return result, err
+	}
+	if result.Users, err = d.UserRepository.Purge(ctx, before); err != nil {
+		return result, err
+	}
+	return result, nil
+}
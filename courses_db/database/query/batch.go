@@ -0,0 +1,178 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// BatchSize is the number of rows a multi-row insert writes at once, well
+// under the bind parameter limits of the databases.
+const BatchSize = 50
+
+// Values renders the VALUES list of a multi-row insert of rows rows of
+// columns columns each.
+func Values(ph Placeholder, rows, columns int) string {
+	var b strings.Builder
+	n := 0
+	for r := 0; r < rows; r++ {
+		if r > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for c := 0; c < columns; c++ {
+			if c > 0 {
+				b.WriteString(", ")
+			}
+			n++
+			b.WriteString(ph(n))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// InsertRows writes rows with multi-row inserts of BatchSize rows. insert is
+// the statement up to VALUES, as in "INSERT INTO t (a, b)"; every row has
+// one value per column.
+func InsertRows(ctx context.Context, q DBTX, ph Placeholder, insert string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	columns := len(rows[0])
+	var full *sql.Stmt
+	defer func() {
+		if full != nil {
+			full.Close()
+		}
+	}()
+	for start := 0; start < len(rows); start += BatchSize {
+		chunk := rows[start:min(start+BatchSize, len(rows))]
+		args := make([]any, 0, len(chunk)*columns)
+		for _, row := range chunk {
+			args = append(args, row...)
+		}
+		if len(chunk) < BatchSize {
+			if _, err := q.ExecContext(ctx, insert+" VALUES "+Values(ph, len(chunk), columns), args...); err != nil {
+				return err
+			}
+			continue
+		}
+		if full == nil {
+			stmt, err := q.PrepareContext(ctx, insert+" VALUES "+Values(ph, BatchSize, columns))
+			if err != nil {
+				return err
+			}
+			full = stmt
+		}
+		if _, err := full.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prepared is a DBTX preparing each statement once and reusing it, for
+// running the same statements over every item of a batch.
+type Prepared struct {
+	q     DBTX
+	stmts map[string]*sql.Stmt
+}
+
+func NewPrepared(q DBTX) *Prepared {
+	return &Prepared{q: q, stmts: map[string]*sql.Stmt{}}
+}
+
+func (p *Prepared) stmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	if stmt, ok := p.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := p.q.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	p.stmts[query] = stmt
+	return stmt, nil
+}
+
+func (p *Prepared) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, err := p.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (p *Prepared) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.q.PrepareContext(ctx, query)
+}
+
+func (p *Prepared) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, err := p.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// QueryRowContext reports a failed prepare through the row, as *sql.DB
+// does; the empty statement fails too.
+func (p *Prepared) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, err := p.stmt(ctx, query)
+	if err != nil {
+		return p.q.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// Close closes the statements prepared so far.
+func (p *Prepared) Close() error {
+	var first error
+	for _, stmt := range p.stmts {
+		if err := stmt.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	p.stmts = map[string]*sql.Stmt{}
+	return first
+}
+
+// Batch runs item for each of n items in one transaction, on statements
+// prepared once for the batch. An item failing with an error dberr.Rejected
+// recognises is rolled back to a savepoint and reported in its result, and
+// the others go on; any other error rolls the whole batch back.
+func Batch(ctx context.Context, db DBTX, n int, item func(q DBTX, i int) (string, error)) ([]dto.BatchItemOutputDto, error) {
+	results := make([]dto.BatchItemOutputDto, n)
+	err := Atomic(ctx, db, func(q DBTX) error {
+		prepared := NewPrepared(q)
+		defer prepared.Close()
+		for i := range results {
+			if _, err := q.ExecContext(ctx, "SAVEPOINT batch_item"); err != nil {
+				return err
+			}
+			id, err := item(prepared, i)
+			switch {
+			case err == nil:
+				_, err = q.ExecContext(ctx, "RELEASE SAVEPOINT batch_item")
+			case dberr.Rejected(err):
+				// PostgreSQL refuses anything else after a failed statement
+				results[i].Err = err
+				if _, err = q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_item"); err == nil {
+					_, err = q.ExecContext(ctx, "RELEASE SAVEPOINT batch_item")
+				}
+			}
+			if err != nil {
+				return err
+			}
+			results[i].Index, results[i].ID = i, id
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,23 @@
+package query
+
+import "testing"
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		ph            Placeholder
+		rows, columns int
+		want          string
+	}{
+		{name: "dollar", ph: Dollar, rows: 2, columns: 3, want: "($1, $2, $3), ($4, $5, $6)"},
+		{name: "question", ph: Question, rows: 2, columns: 2, want: "(?, ?), (?, ?)"},
+		{name: "one row", ph: Dollar, rows: 1, columns: 1, want: "($1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Values(tt.ph, tt.rows, tt.columns); got != tt.want {
+				t.Errorf("Values() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
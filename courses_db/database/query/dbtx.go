@@ -0,0 +1,39 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is the part of *sql.DB and *sql.Tx the SQL repositories use, so the
+// same repository runs either on its own or inside a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// beginner is a DBTX able to start transactions: a *sql.DB or a Router.
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Atomic runs fn in a transaction of its own when db is a *sql.DB or a
+// Router. When db is already a transaction fn runs on it and the caller
+// decides the outcome.
+func Atomic(ctx context.Context, db DBTX, fn func(q DBTX) error) error {
+	sqlDB, ok := db.(beginner)
+	if !ok {
+		return fn(db)
+	}
+	tx, err := sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,24 @@
+package query
+
+import "fmt"
+
+// OnDelete says what deleting a category does to its courses.
+type OnDelete string
+
+const (
+	// Restrict refuses to delete a category that still has courses.
+	Restrict OnDelete = "restrict"
+	// Cascade deletes the courses together with their category.
+	Cascade OnDelete = "cascade"
+)
+
+// ParseOnDelete reads the CATEGORY_ON_DELETE setting; empty means Restrict.
+func ParseOnDelete(s string) (OnDelete, error) {
+	switch OnDelete(s) {
+	case "", Restrict:
+		return Restrict, nil
+	case Cascade:
+		return Cascade, nil
+	}
+	return "", fmt.Errorf("unknown on-delete policy %q, want restrict or cascade", s)
+}
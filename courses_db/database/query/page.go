@@ -0,0 +1,86 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page asks for at most Limit rows following the row identified by Cursor.
+// An empty Cursor starts from the beginning; Limit falls back to DefaultLimit
+// and is capped at MaxLimit.
+type Page struct {
+	Limit  int
+	Cursor string
+}
+
+// cursor is the decoded form of Page.Cursor. Key holds the value of the sort
+// column of the last row, so the next page can resume after it; Sort and Desc
+// record which ordering the cursor was issued for.
+type cursor struct {
+	Sort SortField `json:"s,omitempty"`
+	Desc bool      `json:"d,omitempty"`
+	Key  string    `json:"k,omitempty"`
+	ID   string    `json:"id"`
+}
+
+func (p Page) Size() int {
+	if p.Limit <= 0 {
+		return DefaultLimit
+	}
+	if p.Limit > MaxLimit {
+		return MaxLimit
+	}
+	return p.Limit
+}
+
+// After returns the id the page starts after, or "" for the first page.
+func (p Page) After() (string, error) {
+	c, err := p.decode()
+	if err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
+
+func (p Page) decode() (cursor, error) {
+	var c cursor
+	if p.Cursor == "" {
+		return c, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(p.Cursor)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// EncodeCursor returns the cursor for a listing ordered by id only.
+func EncodeCursor(id string) string {
+	return cursor{ID: id}.encode()
+}
+
+func (c cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Trim cuts a result fetched with Size()+1 rows down to the page size and
+// returns the cursor for the next page, or "" when there is none.
+func Trim[T any](items []T, p Page, cursorOf func(T) string) ([]T, string) {
+	if len(items) <= p.Size() {
+		return items, ""
+	}
+	items = items[:p.Size()]
+	return items, cursorOf(items[len(items)-1])
+}
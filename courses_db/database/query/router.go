@@ -0,0 +1,47 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+// Router is a DBTX sending writes and transactions to the primary and plain
+// reads to the replicas in turn. Replicas may lag behind: whatever must see
+// its own writes runs in a transaction.
+type Router struct {
+	primary  *sql.DB
+	replicas []*sql.DB
+	next     atomic.Uint64
+}
+
+func NewRouter(primary *sql.DB, replicas []*sql.DB) *Router {
+	return &Router{primary: primary, replicas: replicas}
+}
+
+func (r *Router) reader() *sql.DB {
+	if len(r.replicas) == 0 {
+		return r.primary
+	}
+	return r.replicas[(r.next.Add(1)-1)%uint64(len(r.replicas))]
+}
+
+func (r *Router) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+func (r *Router) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.primary.PrepareContext(ctx, query)
+}
+
+func (r *Router) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.reader().QueryContext(ctx, query, args...)
+}
+
+func (r *Router) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return r.reader().QueryRowContext(ctx, query, args...)
+}
+
+func (r *Router) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return r.primary.BeginTx(ctx, opts)
+}
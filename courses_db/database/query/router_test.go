@@ -0,0 +1,62 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+	open := func(name string) *sql.DB {
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), name+".db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		if _, err := db.Exec("CREATE TABLE t (name TEXT)"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("INSERT INTO t (name) VALUES ($1)", name); err != nil {
+			t.Fatal(err)
+		}
+		return db
+	}
+	primary, replica1, replica2 := open("primary"), open("replica1"), open("replica2")
+	router := NewRouter(primary, []*sql.DB{replica1, replica2})
+
+	read := func(q DBTX) string {
+		t.Helper()
+		var name string
+		if err := q.QueryRowContext(ctx, "SELECT name FROM t").Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+	for _, want := range []string{"replica1", "replica2", "replica1"} {
+		if got := read(router); got != want {
+			t.Errorf("read = %s, want %s", got, want)
+		}
+	}
+	if _, err := router.ExecContext(ctx, "UPDATE t SET name = 'written'"); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(primary); got != "written" {
+		t.Errorf("primary = %s, want the write", got)
+	}
+	err := Atomic(ctx, router, func(q DBTX) error {
+		if got := read(q); got != "written" {
+			t.Errorf("read in a transaction = %s, want the primary", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := read(NewRouter(primary, nil)); got != "written" {
+		t.Errorf("read without replicas = %s, want the primary", got)
+	}
+}
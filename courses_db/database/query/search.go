@@ -0,0 +1,187 @@
+package query
+
+import (
+	"errors"
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// HighlightStart and HighlightEnd surround the matched words of a snippet.
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+)
+
+// SnippetStart and SnippetEnd are what backends building snippets themselves
+// surround the matches with, before EscapeSnippet turns them into
+// HighlightStart and HighlightEnd.
+const (
+	SnippetStart = "\x02"
+	SnippetEnd   = "\x03"
+)
+
+// SnippetWords is how many words a snippet keeps around the first match.
+const SnippetWords = 12
+
+var ErrInvalidSearch = errors.New("search needs at least one word")
+
+// SearchSpec asks for the live courses and categories whose name or
+// description hold every word of Query, best match first. EntityType,
+// "course" or "category", narrows the search to one of them.
+type SearchSpec struct {
+	Query      string
+	EntityType string
+	Page       Page
+}
+
+// Terms splits the query into the lower-cased words the backends match on.
+// Anything else is dropped, so no input can reach the full-text syntax of
+// the database as an operator.
+func (s SearchSpec) Terms() ([]string, error) {
+	switch s.EntityType {
+	case "", "course", "category":
+	default:
+		return nil, ErrInvalidSearch
+	}
+	terms := words(s.Query)
+	if len(terms) == 0 {
+		return nil, ErrInvalidSearch
+	}
+	return terms, nil
+}
+
+// Includes reports whether the search covers the given entity type.
+func (s SearchSpec) Includes(entityType string) bool {
+	return s.EntityType == "" || s.EntityType == entityType
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Offset returns how many results a search page skips. Results are ordered
+// by relevance rather than by a key, so their cursors hold an offset.
+func (p Page) Offset() (int, error) {
+	after, err := p.After()
+	if err != nil || after == "" {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(after)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
+
+// TrimOffset is Trim for a result fetched from offset: the next cursor
+// points past the returned items.
+func TrimOffset[T any](items []T, p Page, offset int) ([]T, string) {
+	if len(items) <= p.Size() {
+		return items, ""
+	}
+	return items[:p.Size()], EncodeCursor(strconv.Itoa(offset + p.Size()))
+}
+
+// Matches counts the words of text equal to one of the terms.
+func Matches(text string, terms []string) int {
+	n := 0
+	for _, w := range words(text) {
+		if isTerm(w, terms) {
+			n++
+		}
+	}
+	return n
+}
+
+// Snippet highlights the terms in the text with the most matches, cut down
+// to SnippetWords words starting a little before the first match. It stands
+// in for backends that cannot build snippets themselves.
+func Snippet(terms []string, texts ...string) string {
+	best, most := "", -1
+	for _, text := range texts {
+		if n := Matches(text, terms); n > most {
+			best, most = text, n
+		}
+	}
+	fields := strings.Fields(best)
+	first := 0
+	for i, field := range fields {
+		if containsTerm(field, terms) {
+			first = i
+			break
+		}
+	}
+	start := max(0, first-2)
+	end := min(len(fields), start+SnippetWords)
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i, field := range fields[start:end] {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(highlight(field, terms))
+	}
+	if end < len(fields) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// EscapeSnippet HTML-escapes a snippet built by a backend, so that only the
+// highlights are markup.
+func EscapeSnippet(snippet string) string {
+	return strings.NewReplacer(SnippetStart, HighlightStart, SnippetEnd, HighlightEnd).Replace(html.EscapeString(snippet))
+}
+
+func isTerm(word string, terms []string) bool {
+	for _, term := range terms {
+		if word == term {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTerm(field string, terms []string) bool {
+	for _, w := range words(field) {
+		if isTerm(w, terms) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight marks the matched words of a whitespace separated field,
+// leaving the punctuation around them alone but HTML-escaped.
+func highlight(field string, terms []string) string {
+	var b strings.Builder
+	runes := []rune(field)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			b.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if isTerm(strings.ToLower(word), terms) {
+			word = HighlightStart + word + HighlightEnd
+		}
+		b.WriteString(word)
+		i = j
+	}
+	return b.String()
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
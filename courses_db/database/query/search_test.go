@@ -0,0 +1,94 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchSpecTerms(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    SearchSpec
+		want    []string
+		wantErr bool
+	}{
+		{name: "words", spec: SearchSpec{Query: "Go Basics"}, want: []string{"go", "basics"}},
+		{name: "operators dropped", spec: SearchSpec{Query: `+go -"basics*" (x)`}, want: []string{"go", "basics", "x"}},
+		{name: "accents kept", spec: SearchSpec{Query: "Programação"}, want: []string{"programação"}},
+		{name: "courses only", spec: SearchSpec{Query: "go", EntityType: "course"}, want: []string{"go"}},
+		{name: "empty", spec: SearchSpec{Query: "  "}, wantErr: true},
+		{name: "punctuation only", spec: SearchSpec{Query: "*!?"}, wantErr: true},
+		{name: "unknown type", spec: SearchSpec{Query: "go", EntityType: "user"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Terms()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Terms() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name  string
+		terms []string
+		texts []string
+		want  string
+	}{
+		{name: "name", terms: []string{"go"}, texts: []string{"Go basics", ""}, want: "<mark>Go</mark> basics"},
+		{name: "best text", terms: []string{"go"}, texts: []string{"Basics", "Learn Go."}, want: "Learn <mark>Go</mark>."},
+		{name: "no match", terms: []string{"go"}, texts: []string{"Basics", "Learn"}, want: "Basics"},
+		{name: "whole words", terms: []string{"go"}, texts: []string{"Golang and go"}, want: "Golang and <mark>go</mark>"},
+		{name: "escaped", terms: []string{"go"}, texts: []string{"<b>Go</b> & more"}, want: "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; &amp; more"},
+		{
+			name:  "window",
+			terms: []string{"go"},
+			texts: []string{"one two three four five six seven eight go nine ten eleven twelve thirteen fourteen"},
+			want:  "…seven eight <mark>go</mark> nine ten eleven twelve thirteen fourteen",
+		},
+		{
+			name:  "cut at the end",
+			terms: []string{"go"},
+			texts: []string{"go one two three four five six seven eight nine ten eleven twelve"},
+			want:  "<mark>go</mark> one two three four five six seven eight nine ten eleven…",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Snippet(tt.terms, tt.texts...); got != tt.want {
+				t.Errorf("Snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeSnippet(t *testing.T) {
+	got := EscapeSnippet("<i>" + SnippetStart + "Go" + SnippetEnd + "</i> & more")
+	if want := "&lt;i&gt;<mark>Go</mark>&lt;/i&gt; &amp; more"; got != want {
+		t.Errorf("EscapeSnippet() = %q, want %q", got, want)
+	}
+}
+
+func TestPageOffset(t *testing.T) {
+	items := []int{1, 2, 3}
+	got, next := TrimOffset(items, Page{Limit: 2}, 4)
+	if !reflect.DeepEqual(got, []int{1, 2}) || next == "" {
+		t.Fatalf("TrimOffset() = %v, %q", got, next)
+	}
+	offset, err := Page{Cursor: next}.Offset()
+	if err != nil || offset != 6 {
+		t.Errorf("Offset() = %d, %v, want 6", offset, err)
+	}
+	if _, err := (Page{Cursor: EncodeCursor("x")}).Offset(); err != ErrInvalidCursor {
+		t.Errorf("Offset() of a key cursor error = %v, want %v", err, ErrInvalidCursor)
+	}
+	if _, next := TrimOffset(items, Page{Limit: 3}, 0); next != "" {
+		t.Errorf("TrimOffset() of a last page cursor = %q", next)
+	}
+}
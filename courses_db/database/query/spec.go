@@ -0,0 +1,321 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/entity"
+)
+
+// SortField selects the column a listing is ordered by. Ties, and the
+// default ordering, are broken by id so that pages are stable.
+type SortField int
+
+const (
+	SortByID SortField = iota
+	SortByName
+	SortByCreated
+)
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+type Sort struct {
+	Field SortField
+	Desc  bool
+}
+
+// ParseSort reads the textual sort parameter used by the HTTP APIs: "name"
+// or "created", optionally prefixed with "-" for descending order. An empty
+// string keeps the default ordering.
+func ParseSort(s string) (Sort, error) {
+	var sort Sort
+	if strings.HasPrefix(s, "-") {
+		sort.Desc = true
+		s = s[1:]
+	}
+	switch s {
+	case "":
+		if sort.Desc {
+			return Sort{}, ErrInvalidSort
+		}
+	case "id":
+		sort.Field = SortByID
+	case "name":
+		sort.Field = SortByName
+	case "created":
+		sort.Field = SortByCreated
+	default:
+		return Sort{}, ErrInvalidSort
+	}
+	return sort, nil
+}
+
+// Cursor returns the cursor pointing after a row with the given values.
+func (s Sort) Cursor(id, name string, createdAt time.Time) string {
+	c := cursor{Sort: s.Field, Desc: s.Desc, ID: id}
+	switch s.Field {
+	case SortByName:
+		c.Key = name
+	case SortByCreated:
+		c.Key = createdAt.UTC().Format(time.RFC3339Nano)
+	}
+	return c.encode()
+}
+
+// CourseSpec describes which courses to list and in which order. Zero
+// fields do not filter, except that soft deleted courses are left out unless
+// IncludeDeleted is set. CategoryTree keeps the courses of a category and of
+// its live subcategories, at any depth. Tags keeps the courses carrying any
+// of the named tags, or all of them when AllTags is set.
+type CourseSpec struct {
+	NamePrefix     string
+	CategoryID     string
+	CategoryTree   string
+	InstructorID   string
+	Tags           []string
+	AllTags        bool
+	IncludeDeleted bool
+	Sort           Sort
+	Page           Page
+}
+
+// CategorySpec describes which categories to list and in which order.
+// ParentID keeps the categories right under a category.
+type CategorySpec struct {
+	NamePrefix     string
+	ParentID       string
+	IncludeDeleted bool
+	Sort           Sort
+	Page           Page
+}
+
+// TagSpec describes which tags to list; tags are ordered by name.
+// NamePrefix serves autocompletion.
+type TagSpec struct {
+	NamePrefix string
+	Page       Page
+}
+
+// UserSpec describes which users to list. Users are always ordered by id.
+type UserSpec struct {
+	IncludeDeleted bool
+	Page           Page
+}
+
+// HistorySpec selects the recorded changes of one entity, oldest first.
+type HistorySpec struct {
+	EntityType string
+	EntityID   string
+	Page       Page
+}
+
+// Placeholder renders the n-th (1-based) bind parameter of a dialect.
+type Placeholder func(n int) string
+
+// Dollar is the $1, $2, ... style used by sqlite and postgres.
+func Dollar(n int) string { return fmt.Sprintf("$%d", n) }
+
+// Question is the ? style used by mariadb.
+func Question(int) string { return "?" }
+
+// Dialect is what the listing statements need to know of a database: its
+// placeholders and the operator matching a LIKE pattern regardless of case.
+type Dialect struct {
+	Placeholder Placeholder
+	Like        string
+}
+
+var (
+	SQLite   = Dialect{Placeholder: Dollar, Like: "LIKE"}
+	MariaDB  = Dialect{Placeholder: Question, Like: "LIKE"}
+	Postgres = Dialect{Placeholder: Dollar, Like: "ILIKE"}
+)
+
+// Statement is a parameterized SQL statement built from a spec.
+type Statement struct {
+	SQL  string
+	Args []any
+}
+
+type builder struct {
+	dialect Dialect
+	where   []string
+	args    []any
+}
+
+func (b *builder) arg(v any) string {
+	b.args = append(b.args, v)
+	return b.dialect.Placeholder(len(b.args))
+}
+
+// prefix matches the names starting with prefix, whatever their case.
+func (b *builder) prefix(prefix string) {
+	b.where = append(b.where, "name "+b.dialect.Like+" "+b.arg(likePrefix(prefix))+" ESCAPE '!'")
+}
+
+// Select builds "SELECT <columns> FROM <table> WHERE ... ORDER BY ... LIMIT"
+// for the spec. The id, name and created_at columns of table are used for
+// filtering, ordering and the cursor. Name prefixes match regardless of case.
+func (s CourseSpec) Select(d Dialect, columns, table string) (Statement, error) {
+	b := &builder{dialect: d}
+	b.live(s.IncludeDeleted)
+	if s.NamePrefix != "" {
+		b.prefix(s.NamePrefix)
+	}
+	if s.CategoryID != "" {
+		b.where = append(b.where, "category_id = "+b.arg(s.CategoryID))
+	}
+	if s.CategoryTree != "" {
+		b.where = append(b.where, "category_id IN ("+CategorySubtree(b.arg(s.CategoryTree))+" SELECT id FROM subtree)")
+	}
+	if s.InstructorID != "" {
+		b.where = append(b.where, "id IN (SELECT course_id FROM course_instructors WHERE user_id = "+b.arg(s.InstructorID)+")")
+	}
+	if names := TagNames(s.Tags); len(names) > 0 {
+		in := make([]string, len(names))
+		for i, name := range names {
+			in[i] = b.arg(name)
+		}
+		tagged := "SELECT ct.course_id FROM course_tags ct JOIN tags t ON t.id = ct.tag_id WHERE t.name IN (" + strings.Join(in, ", ") + ")"
+		if s.AllTags {
+			tagged += " GROUP BY ct.course_id HAVING COUNT(*) = " + b.arg(len(names))
+		}
+		b.where = append(b.where, "id IN ("+tagged+")")
+	}
+	return b.finish(columns, table, s.Sort, s.Page)
+}
+
+// Select builds the listing statement for a tag spec, see CourseSpec.Select.
+func (s TagSpec) Select(d Dialect, columns, table string) (Statement, error) {
+	b := &builder{dialect: d}
+	if name := entity.NormalizeTagName(s.NamePrefix); name != "" {
+		b.prefix(name)
+	}
+	return b.finish(columns, table, Sort{Field: SortByName}, s.Page)
+}
+
+// TagNames normalizes tag names, see entity.NormalizeTagName, and drops the
+// blank and repeated ones.
+func TagNames(names []string) []string {
+	var normalized []string
+	for _, name := range names {
+		if name = entity.NormalizeTagName(name); name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
+
+// Select builds the listing statement for a category spec, see
+// CourseSpec.Select.
+func (s CategorySpec) Select(d Dialect, columns, table string) (Statement, error) {
+	b := &builder{dialect: d}
+	b.live(s.IncludeDeleted)
+	if s.NamePrefix != "" {
+		b.prefix(s.NamePrefix)
+	}
+	if s.ParentID != "" {
+		b.where = append(b.where, "parent_id = "+b.arg(s.ParentID))
+	}
+	return b.finish(columns, table, s.Sort, s.Page)
+}
+
+// CategorySubtree returns a recursive "subtree" common table expression
+// holding the ids of a category, given as a placeholder, and of its live
+// descendants.
+func CategorySubtree(id string) string {
+	return "WITH RECURSIVE subtree (id) AS (SELECT id FROM categories WHERE id = " + id +
+		" UNION SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL)"
+}
+
+// Keyset returns the sort key and id of the row a page starts after; ok is
+// false for the first page. The cursor must have been issued for sort, in the
+// same direction.
+// Backends that do not build SQL use it to resume a listing.
+func (p Page) Keyset(sort Sort) (key any, id string, ok bool, err error) {
+	c, err := p.decode()
+	if err != nil || p.Cursor == "" {
+		return nil, "", false, err
+	}
+	if c.Sort != sort.Field || c.Desc != sort.Desc {
+		return nil, "", false, ErrInvalidCursor
+	}
+	key, err = cursorKey(sort.Field, c.Key)
+	if err != nil {
+		return nil, "", false, err
+	}
+	return key, c.ID, true, nil
+}
+
+// Column returns the column a sort orders by, "" when only id is used.
+func (s Sort) Column() string {
+	switch s.Field {
+	case SortByName:
+		return "name"
+	case SortByCreated:
+		return "created_at"
+	}
+	return ""
+}
+
+// live leaves soft deleted rows out unless includeDeleted is set.
+func (b *builder) live(includeDeleted bool) {
+	if !includeDeleted {
+		b.where = append(b.where, "deleted_at IS NULL")
+	}
+}
+
+func (b *builder) finish(columns, table string, sort Sort, page Page) (Statement, error) {
+	key, id, ok, err := page.Keyset(sort)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	op, dir := ">", "ASC"
+	if sort.Desc {
+		op, dir = "<", "DESC"
+	}
+	column := sort.Column()
+
+	if ok {
+		if column == "" {
+			b.where = append(b.where, "id "+op+" "+b.arg(id))
+		} else {
+			b.where = append(b.where, fmt.Sprintf("(%s %s %s OR (%s = %s AND id %s %s))",
+				column, op, b.arg(key), column, b.arg(key), op, b.arg(id)))
+		}
+	}
+
+	var sql strings.Builder
+	sql.WriteString("SELECT " + columns + " FROM " + table)
+	if len(b.where) > 0 {
+		sql.WriteString(" WHERE " + strings.Join(b.where, " AND "))
+	}
+	if column != "" {
+		sql.WriteString(" ORDER BY " + column + " " + dir + ", id " + dir)
+	} else {
+		sql.WriteString(" ORDER BY id " + dir)
+	}
+	sql.WriteString(" LIMIT " + b.arg(page.Size()+1))
+	return Statement{SQL: sql.String(), Args: b.args}, nil
+}
+
+func cursorKey(field SortField, key string) (any, error) {
+	if field != SortByCreated {
+		return key, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, key)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// likePrefix escapes LIKE wildcards in prefix using '!' as escape character.
+func likePrefix(prefix string) string {
+	r := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+	return r.Replace(prefix) + "%"
+}
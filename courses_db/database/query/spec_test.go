@@ -0,0 +1,196 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    Sort
+		wantErr bool
+	}{
+		{name: "default", arg: "", want: Sort{}},
+		{name: "name", arg: "name", want: Sort{Field: SortByName}},
+		{name: "created desc", arg: "-created", want: Sort{Field: SortByCreated, Desc: true}},
+		{name: "unknown", arg: "price", wantErr: true},
+		{name: "bare minus", arg: "-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSort(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSort() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCourseSpecSelect(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	byName := Sort{Field: SortByName}
+	tests := []struct {
+		name    string
+		spec    CourseSpec
+		dialect Dialect
+		want    Statement
+		wantErr bool
+	}{
+		{
+			name:    "first page",
+			spec:    CourseSpec{},
+			dialect: SQLite,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL ORDER BY id ASC LIMIT $1",
+				Args: []any{DefaultLimit + 1},
+			},
+		},
+		{
+			name:    "filters",
+			spec:    CourseSpec{NamePrefix: "50%_", CategoryID: "c1", Page: Page{Limit: 10}},
+			dialect: MariaDB,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND name LIKE ? ESCAPE '!' AND category_id = ? ORDER BY id ASC LIMIT ?",
+				Args: []any{"50!%!_%", "c1", 11},
+			},
+		},
+		{
+			name:    "postgres prefix",
+			spec:    CourseSpec{NamePrefix: "go", Page: Page{Limit: 10}},
+			dialect: Postgres,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND name ILIKE $1 ESCAPE '!' ORDER BY id ASC LIMIT $2",
+				Args: []any{"go%", 11},
+			},
+		},
+		{
+			name:    "taught by",
+			spec:    CourseSpec{InstructorID: "u1", Page: Page{Limit: 5, Cursor: Sort{}.Cursor("id1", "Go", created)}},
+			dialect: SQLite,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND id IN (SELECT course_id FROM course_instructors WHERE user_id = $1) AND id > $2 ORDER BY id ASC LIMIT $3",
+				Args: []any{"u1", "id1", 6},
+			},
+		},
+		{
+			name:    "category tree",
+			spec:    CourseSpec{CategoryTree: "c1"},
+			dialect: MariaDB,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND category_id IN (WITH RECURSIVE subtree (id) AS (SELECT id FROM categories WHERE id = ? UNION SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL) SELECT id FROM subtree) ORDER BY id ASC LIMIT ?",
+				Args: []any{"c1", 51},
+			},
+		},
+		{
+			name:    "any tag",
+			spec:    CourseSpec{Tags: []string{"Cloud", "go", " cloud"}},
+			dialect: SQLite,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND id IN (SELECT ct.course_id FROM course_tags ct JOIN tags t ON t.id = ct.tag_id WHERE t.name IN ($1, $2)) ORDER BY id ASC LIMIT $3",
+				Args: []any{"cloud", "go", 51},
+			},
+		},
+		{
+			name:    "all tags",
+			spec:    CourseSpec{Tags: []string{"cloud", "go"}, AllTags: true},
+			dialect: SQLite,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND id IN (SELECT ct.course_id FROM course_tags ct JOIN tags t ON t.id = ct.tag_id WHERE t.name IN ($1, $2) GROUP BY ct.course_id HAVING COUNT(*) = $3) ORDER BY id ASC LIMIT $4",
+				Args: []any{"cloud", "go", 2, 51},
+			},
+		},
+		{
+			name:    "after name cursor",
+			spec:    CourseSpec{Sort: byName, Page: Page{Limit: 5, Cursor: byName.Cursor("id1", "Go", created)}},
+			dialect: SQLite,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND (name > $1 OR (name = $2 AND id > $3)) ORDER BY name ASC, id ASC LIMIT $4",
+				Args: []any{"Go", "Go", "id1", 6},
+			},
+		},
+		{
+			name: "descending created",
+			spec: CourseSpec{
+				Sort: Sort{Field: SortByCreated, Desc: true},
+				Page: Page{Limit: 5, Cursor: Sort{Field: SortByCreated, Desc: true}.Cursor("id1", "Go", created)},
+			},
+			dialect: SQLite,
+			want: Statement{
+				SQL:  "SELECT id FROM courses WHERE deleted_at IS NULL AND (created_at < $1 OR (created_at = $2 AND id < $3)) ORDER BY created_at DESC, id DESC LIMIT $4",
+				Args: []any{created, created, "id1", 6},
+			},
+		},
+		{
+			name:    "include deleted",
+			spec:    CourseSpec{IncludeDeleted: true},
+			dialect: SQLite,
+			want: Statement{
+				SQL:  "SELECT id FROM courses ORDER BY id ASC LIMIT $1",
+				Args: []any{DefaultLimit + 1},
+			},
+		},
+		{
+			name:    "cursor from another sort",
+			spec:    CourseSpec{Sort: byName, Page: Page{Cursor: EncodeCursor("id1")}},
+			dialect: SQLite,
+			wantErr: true,
+		},
+		{
+			name:    "cursor from the other direction",
+			spec:    CourseSpec{Sort: Sort{Field: SortByName, Desc: true}, Page: Page{Cursor: byName.Cursor("id1", "Go", created)}},
+			dialect: SQLite,
+			wantErr: true,
+		},
+		{
+			name:    "garbage cursor",
+			spec:    CourseSpec{Page: Page{Cursor: "not a cursor"}},
+			dialect: SQLite,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Select(tt.dialect, "id", "courses")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Select() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagSpecSelect(t *testing.T) {
+	got, err := TagSpec{NamePrefix: " Clo", Page: Page{Limit: 10}}.Select(SQLite, "id", "tags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Statement{
+		SQL:  "SELECT id FROM tags WHERE name LIKE $1 ESCAPE '!' ORDER BY name ASC, id ASC LIMIT $2",
+		Args: []any{"clo%", 11},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Select() = %v, want %v", got, want)
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	asc, desc := Sort{Field: SortByName}, Sort{Field: SortByName, Desc: true}
+	key, id, ok, err := Page{Cursor: desc.Cursor("id1", "Go", time.Time{})}.Keyset(desc)
+	if err != nil || !ok || key != "Go" || id != "id1" {
+		t.Errorf("Keyset() = %v, %q, %v, %v, want Go, id1, true, nil", key, id, ok, err)
+	}
+	if _, _, _, err := (Page{Cursor: asc.Cursor("id1", "Go", time.Time{})}).Keyset(desc); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Keyset() of an ascending cursor on a descending sort error = %v, want ErrInvalidCursor", err)
+	}
+}
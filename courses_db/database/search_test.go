@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func TestSearch(t *testing.T) {
+	implementations := map[string]*DBImplementation{
+		"sqlite": newSqliteImplementation(t, query.Restrict),
+		"memory": NewMemoryImplementation(query.Restrict),
+	}
+	for backend, dbi := range implementations {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			golang, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Golang", Description: "Courses about the Go language"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			cooking, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Cooking", Description: "Food"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			basics, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "Go basics", Description: "Syntax, types and go routines", CategoryID: golang.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			pasta, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "Pasta", Description: "Fresh pasta from scratch", CategoryID: cooking.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			web, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "Web servers", Description: "HTTP in Go", CategoryID: golang.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := dbi.CourseRepository.Update(ctx, dto.CourseInputDto{ID: pasta.ID, Name: "Pasta", Description: "Fresh pasta, the Go way", CategoryID: cooking.ID}); err != nil {
+				t.Fatal(err)
+			}
+			if err := dbi.CourseRepository.Delete(ctx, web.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				name    string
+				spec    query.SearchSpec
+				want    []string
+				wantErr error
+			}{
+				{name: "best match first", spec: query.SearchSpec{Query: "go"}, want: []string{basics.ID, golang.ID, pasta.ID}},
+				{name: "every word", spec: query.SearchSpec{Query: "fresh GO"}, want: []string{pasta.ID}},
+				{name: "courses only", spec: query.SearchSpec{Query: "go", EntityType: "course"}, want: []string{basics.ID, pasta.ID}},
+				{name: "categories only", spec: query.SearchSpec{Query: "food", EntityType: "category"}, want: []string{cooking.ID}},
+				{name: "updated text", spec: query.SearchSpec{Query: "scratch"}},
+				{name: "deleted course", spec: query.SearchSpec{Query: "http"}},
+				{name: "whole words", spec: query.SearchSpec{Query: "gol"}},
+				{name: "syntax is not passed through", spec: query.SearchSpec{Query: `go* "pasta`}, want: []string{pasta.ID}},
+				{name: "no words", spec: query.SearchSpec{Query: "?!"}, wantErr: query.ErrInvalidSearch},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					got, err := dbi.SearchRepository.Search(ctx, tt.spec)
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("Search() error = %v, want %v", err, tt.wantErr)
+					}
+					var ids []string
+					for _, result := range got.Results {
+						ids = append(ids, result.ID)
+					}
+					if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+						t.Errorf("Search() = %+v, want ids %v", got.Results, tt.want)
+					}
+				})
+			}
+
+			got, err := dbi.SearchRepository.Search(ctx, query.SearchSpec{Query: "go basics"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Results) != 1 || got.Results[0].EntityType != "course" || got.Results[0].Name != "Go basics" ||
+				!strings.Contains(got.Results[0].Snippet, query.HighlightStart+"Go"+query.HighlightEnd) {
+				t.Errorf("Search() = %+v, want Go basics highlighted", got.Results)
+			}
+
+			first, err := dbi.SearchRepository.Search(ctx, query.SearchSpec{Query: "go", Page: query.Page{Limit: 2}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rest, err := dbi.SearchRepository.Search(ctx, query.SearchSpec{Query: "go", Page: query.Page{Limit: 2, Cursor: first.NextCursor}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(first.Results) != 2 || len(rest.Results) != 1 || rest.Results[0].ID != pasta.ID || rest.NextCursor != "" {
+				t.Errorf("pages = %+v, %+v", first, rest)
+			}
+
+			if _, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "Magic", Description: "<script>alert(1)</script> tricks", CategoryID: cooking.ID}); err != nil {
+				t.Fatal(err)
+			}
+			got, err = dbi.SearchRepository.Search(ctx, query.SearchSpec{Query: "tricks", EntityType: "course"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Results) != 1 || strings.Contains(got.Results[0].Snippet, "<script>") || !strings.Contains(got.Results[0].Snippet, "&lt;script&gt;") {
+				t.Errorf("Search() = %+v, want the markup of the text escaped", got.Results)
+			}
+		})
+	}
+}
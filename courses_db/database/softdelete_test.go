@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func TestSoftDelete(t *testing.T) {
+	implementations := map[string]*DBImplementation{
+		"sqlite": newSqliteImplementation(t, query.Restrict),
+		"memory": NewMemoryImplementation(query.Restrict),
+	}
+	for backend, dbi := range implementations {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			course, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			user, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "hash"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			steps := []struct {
+				name    string
+				run     func() error
+				wantErr error
+			}{
+				{name: "delete course", run: func() error { return dbi.CourseRepository.Delete(ctx, course.ID) }},
+				{name: "deleted course is hidden", run: func() error {
+					_, err := dbi.CourseRepository.Find(ctx, course.ID)
+					return err
+				}, wantErr: ErrNotFound},
+				{name: "delete twice", run: func() error { return dbi.CourseRepository.Delete(ctx, course.ID) }, wantErr: ErrNotFound},
+				{name: "listing includes deleted on request", run: func() error {
+					got, err := dbi.CourseRepository.List(ctx, query.CourseSpec{CategoryID: category.ID, IncludeDeleted: true})
+					if err == nil && (len(got.Courses) != 1 || got.Courses[0].DeletedAt == nil) {
+						t.Errorf("List() = %v, want the deleted course", got.Courses)
+					}
+					return err
+				}},
+				{name: "restore course", run: func() error { return dbi.CourseRepository.Restore(ctx, course.ID) }},
+				{name: "restore live course", run: func() error { return dbi.CourseRepository.Restore(ctx, course.ID) }, wantErr: ErrNotFound},
+				{name: "delete course and category", run: func() error {
+					if err := dbi.CourseRepository.Delete(ctx, course.ID); err != nil {
+						return err
+					}
+					return dbi.CategoryRepository.Delete(ctx, category.ID)
+				}},
+				{name: "restore course of deleted category", run: func() error {
+					return dbi.CourseRepository.Restore(ctx, course.ID)
+				}, wantErr: ErrValidation},
+				{name: "deleted user cannot log in", run: func() error {
+					if err := dbi.UserRepository.Delete(ctx, user.ID); err != nil {
+						return err
+					}
+					_, err := dbi.UserRepository.FindByEmail(ctx, user.Email)
+					return err
+				}, wantErr: ErrNotFound},
+				{name: "purge keeps recent deletions", run: func() error {
+					got, err := dbi.Purge(ctx, time.Now().Add(-time.Hour))
+					if err == nil && got != (dto.PurgeOutputDto{}) {
+						t.Errorf("Purge() = %+v, want nothing purged", got)
+					}
+					return err
+				}},
+				{name: "purge", run: func() error {
+					got, err := dbi.Purge(ctx, time.Now().Add(time.Hour))
+					if want := (dto.PurgeOutputDto{Courses: 1, Categories: 1, Users: 1}); err == nil && got != want {
+						t.Errorf("Purge() = %+v, want %+v", got, want)
+					}
+					return err
+				}},
+				{name: "purged user is gone", run: func() error { return dbi.UserRepository.Restore(ctx, user.ID) }, wantErr: ErrNotFound},
+			}
+			for _, tt := range steps {
+				err := tt.run()
+				if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
@@ -1,99 +1,409 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
-	"errors"
-
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
 	"github.com/antoniofmoliveira/courses/dto"
 	"github.com/google/uuid"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+const categoryColumns = "id, name, description, parent_id, created_at, updated_at, created_by, updated_by, version, deleted_at"
+
 type CategoryRepository struct {
-	db *sql.DB
+	db       query.DBTX
+	onDelete query.OnDelete
+}
+
+func NewCategoryRepository(db query.DBTX, onDelete query.OnDelete) *CategoryRepository {
+	return &CategoryRepository{db: db, onDelete: onDelete}
 }
 
-func NewCategoryRepository(db *sql.DB) *CategoryRepository {
-	c := &CategoryRepository{db: db}
-	c.db.Exec("CREATE TABLE IF NOT EXISTS categories (id CHAR(36) PRIMARY KEY, name TEXT, description TEXT)")
-	return c
+func scanCategory(row interface{ Scan(...any) error }) (dto.CategoryOutputDto, error) {
+	var category dto.CategoryOutputDto
+	var parentID sql.NullString
+	err := row.Scan(&category.ID, &category.Name, &category.Description, &parentID,
+		&category.CreatedAt, &category.UpdatedAt, &category.CreatedBy, &category.UpdatedBy, &category.Version, &category.DeletedAt)
+	category.ParentID = parentID.String
+	return category, err
+}
+
+// nullID stores an empty id as NULL.
+func nullID(id string) any {
+	if id == "" {
+		return nil
+	}
+	return id
 }
 
-func (c *CategoryRepository) Create(categoryDto dto.CategoryInputDto) (dto.CategoryOutputDto, error) {
+func (c *CategoryRepository) Create(ctx context.Context, categoryDto dto.CategoryInputDto) (dto.CategoryOutputDto, error) {
 	// func (c *Category) Create(name string, description string) (dto.CategoryOutputDto, error) {
 
-	id := uuid.New().String()
-	_, err := c.db.Exec("INSERT INTO categories (id, name, description) VALUES ($1, $2, $3)",
-		id, categoryDto.Name, categoryDto.Description)
+	if err := dberr.ValidateCategory(categoryDto); err != nil {
+		return dto.CategoryOutputDto{}, err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	created := dto.CategoryOutputDto{
+		ID:          uuid.New().String(),
+		Name:        categoryDto.Name,
+		Description: categoryDto.Description,
+		ParentID:    categoryDto.ParentID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	err := query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		if err := checkParent(ctx, q, "", created.ParentID); err != nil {
+			return err
+		}
+		_, err := q.ExecContext(ctx, "INSERT INTO categories (id, name, description, parent_id, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+			created.ID, created.Name, created.Description, nullID(created.ParentID), now, now, actor, actor)
+		if err != nil {
+			return err
+		}
+		return record(ctx, q, audit.Category, created.ID, audit.Create, nil, created)
+	})
 	if err != nil {
 		return dto.CategoryOutputDto{}, err
 	}
-	return dto.CategoryOutputDto{ID: id, Name: categoryDto.Name, Description: categoryDto.Description}, nil
+	return created, nil
 }
 
-func (c *CategoryRepository) FindAll() (dto.CategoryListOutputDto, error) {
-	rows, err := c.db.Query("SELECT id, name, description FROM categories")
+// CreateBatch creates categories with multi-row inserts, in one
+// transaction. Invalid categories are rejected in their result and the
+// others created.
+func (c *CategoryRepository) CreateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error) {
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	results := make([]dto.BatchItemOutputDto, len(categories))
+	err := query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		parents := map[string]error{}
+		var rows, history [][]any
+		for i, category := range categories {
+			results[i] = dto.BatchItemOutputDto{Index: i}
+			err := dberr.ValidateCategory(category)
+			if err == nil {
+				checked, ok := parents[category.ParentID]
+				if !ok {
+					checked = checkParent(ctx, q, "", category.ParentID)
+					if checked != nil && !dberr.Rejected(checked) {
+						return checked
+					}
+					parents[category.ParentID] = checked
+				}
+				err = checked
+			}
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			created := dto.CategoryOutputDto{
+				ID:          uuid.New().String(),
+				Name:        category.Name,
+				Description: category.Description,
+				ParentID:    category.ParentID,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+				CreatedBy:   actor,
+				UpdatedBy:   actor,
+				Version:     1,
+			}
+			entry, err := historyRow(ctx, audit.Category, created.ID, audit.Create, nil, created)
+			if err != nil {
+				return err
+			}
+			results[i].ID = created.ID
+			rows = append(rows, []any{created.ID, created.Name, created.Description, nullID(created.ParentID), now, now, actor, actor})
+			history = append(history, entry)
+		}
+		if err := query.InsertRows(ctx, q, query.Dollar, "INSERT INTO categories (id, name, description, parent_id, created_at, updated_at, created_by, updated_by)", rows); err != nil {
+			return err
+		}
+		return query.InsertRows(ctx, q, query.Dollar, insertHistory, history)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *CategoryRepository) FindAll(ctx context.Context, page query.Page) (dto.CategoryListOutputDto, error) {
+	return c.List(ctx, query.CategorySpec{Page: page})
+}
+
+func (c *CategoryRepository) List(ctx context.Context, spec query.CategorySpec) (dto.CategoryListOutputDto, error) {
+	stmt, err := spec.Select(query.SQLite, categoryColumns, "categories")
+	if err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	rows, err := c.db.QueryContext(ctx, stmt.SQL, stmt.Args...)
 	if err != nil {
 		return dto.CategoryListOutputDto{}, err
 	}
 	defer rows.Close()
 	categories := dto.CategoryListOutputDto{}
 	for rows.Next() {
-		var id, name, description string
-		if err := rows.Scan(&id, &name, &description); err != nil {
+		category, err := scanCategory(rows)
+		if err != nil {
 			return dto.CategoryListOutputDto{}, err
 		}
-		categories.Categories = append(categories.Categories, dto.CategoryOutputDto{ID: id, Name: name, Description: description})
+		categories.Categories = append(categories.Categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.CategoryListOutputDto{}, err
 	}
+	categories.Categories, categories.NextCursor = query.Trim(categories.Categories, spec.Page,
+		func(c dto.CategoryOutputDto) string { return spec.Sort.Cursor(c.ID, c.Name, c.CreatedAt) })
 	return categories, nil
 }
 
-func (c *CategoryRepository) FindByCourseID(courseID string) (dto.CategoryOutputDto, error) {
-	var id, name, description string
-	err := c.db.QueryRow("SELECT c.id, c.name, c.description FROM categories c JOIN courses co ON c.id = co.category_id WHERE co.id = $1", courseID).
-		Scan(&id, &name, &description)
+func (c *CategoryRepository) FindByCourseID(ctx context.Context, courseID string) (dto.CategoryOutputDto, error) {
+	category, err := scanCategory(c.db.QueryRowContext(ctx, "SELECT c.id, c.name, c.description, c.parent_id, c.created_at, c.updated_at, c.created_by, c.updated_by, c.version, c.deleted_at FROM categories c JOIN courses co ON c.id = co.category_id WHERE co.id = $1 AND co.deleted_at IS NULL AND c.deleted_at IS NULL", courseID))
 	if err != nil {
-		return dto.CategoryOutputDto{}, err
+		return dto.CategoryOutputDto{}, dberr.NoRows("category", err)
+	}
+	return category, nil
+}
+
+func (c *CategoryRepository) Find(ctx context.Context, id string) (dto.CategoryOutputDto, error) {
+	category, err := c.get(ctx, id)
+	if err == nil && category.DeletedAt != nil {
+		return dto.CategoryOutputDto{}, dberr.NotFound("category")
 	}
-	return dto.CategoryOutputDto{ID: id, Name: name, Description: description}, nil
+	return category, err
 }
 
-func (c *CategoryRepository) Find(id string) (dto.CategoryOutputDto, error) {
-	var name, description string
-	err := c.db.QueryRow("SELECT name, description FROM categories WHERE id = $1", id).
-		Scan(&name, &description)
+// get reads a category whether it is deleted or not.
+func (c *CategoryRepository) get(ctx context.Context, id string) (dto.CategoryOutputDto, error) {
+	category, err := scanCategory(c.db.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories WHERE id = $1", id))
 	if err != nil {
-		return dto.CategoryOutputDto{}, err
+		return dto.CategoryOutputDto{}, dberr.NoRows("category", err)
+	}
+	return category, nil
+}
+
+func (c *CategoryRepository) FindChildren(ctx context.Context, id string, page query.Page) (dto.CategoryListOutputDto, error) {
+	return c.List(ctx, query.CategorySpec{ParentID: id, Page: page})
+}
+
+func (c *CategoryRepository) FindAncestors(ctx context.Context, id string) (dto.CategoryListOutputDto, error) {
+	if _, err := c.Find(ctx, id); err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	return c.tree(ctx, "WITH RECURSIVE ancestors (ancestor_id, depth) AS (SELECT parent_id, 1 FROM categories WHERE id = $1 AND parent_id IS NOT NULL UNION ALL SELECT c.parent_id, a.depth + 1 FROM categories c JOIN ancestors a ON c.id = a.ancestor_id WHERE c.parent_id IS NOT NULL) SELECT "+categoryColumns+" FROM categories JOIN ancestors ON id = ancestor_id ORDER BY depth DESC", id)
+}
+
+func (c *CategoryRepository) FindSubtree(ctx context.Context, id string) (dto.CategoryListOutputDto, error) {
+	if _, err := c.Find(ctx, id); err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	return c.tree(ctx, "WITH RECURSIVE tree (tree_id, depth) AS (SELECT id, 0 FROM categories WHERE id = $1 UNION ALL SELECT c.id, t.depth + 1 FROM categories c JOIN tree t ON c.parent_id = t.tree_id WHERE c.deleted_at IS NULL) SELECT "+categoryColumns+" FROM categories JOIN tree ON id = tree_id ORDER BY depth, id", id)
+}
+
+// tree reads the categories of a tree query, unpaged.
+func (c *CategoryRepository) tree(ctx context.Context, stmt string, args ...any) (dto.CategoryListOutputDto, error) {
+	rows, err := c.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return dto.CategoryListOutputDto{}, err
+	}
+	defer rows.Close()
+	categories := dto.CategoryListOutputDto{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return dto.CategoryListOutputDto{}, err
+		}
+		categories.Categories = append(categories.Categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.CategoryListOutputDto{}, err
 	}
-	return dto.CategoryOutputDto{ID: id, Name: name, Description: description}, nil
+	return categories, nil
 }
 
-func (c *CategoryRepository) Update(category dto.CategoryInputDto) error {
-	_, err := c.db.Exec("UPDATE categories SET name = $1, description = $2 WHERE id = $3",
-		category.Name, category.Description, category.ID)
+func (c *CategoryRepository) Update(ctx context.Context, category dto.CategoryInputDto) error {
+	if err := dberr.ValidateCategory(category); err != nil {
+		return err
+	}
+	return query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		before, err := (&CategoryRepository{db: q}).Find(ctx, category.ID)
+		if err != nil {
+			return err
+		}
+		if err := dberr.CheckVersion("category", category.Version, before.Version); err != nil {
+			return err
+		}
+		if err := checkParent(ctx, q, category.ID, category.ParentID); err != nil {
+			return err
+		}
+		after := before
+		after.Name, after.Description, after.ParentID = category.Name, category.Description, category.ParentID
+		after.UpdatedAt, after.UpdatedBy = time.Now().UTC(), audit.Actor(ctx)
+		after.Version++
+		result, err := q.ExecContext(ctx, "UPDATE categories SET version = version + 1, name = $1, description = $2, parent_id = $3, updated_at = $4, updated_by = $5 WHERE id = $6 AND deleted_at IS NULL AND version = $7",
+			after.Name, after.Description, nullID(after.ParentID), after.UpdatedAt, after.UpdatedBy, category.ID, before.Version)
+		if err != nil {
+			return err
+		}
+		if err := dberr.Changed(result, "category"); err != nil {
+			return err
+		}
+		return record(ctx, q, audit.Category, category.ID, audit.Update, before, after)
+	})
+}
+
+func (c *CategoryRepository) Delete(ctx context.Context, id string) error {
+	return query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		before, err := (&CategoryRepository{db: q}).Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		var children int
+		if err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM categories WHERE parent_id = $1 AND deleted_at IS NULL", id).Scan(&children); err != nil {
+			return err
+		}
+		if children > 0 {
+			return dberr.CategoryHasChildren
+		}
+		deletedAt := time.Now().UTC()
+		if c.onDelete == query.Cascade {
+			if err := deleteCourses(ctx, q, id, deletedAt); err != nil {
+				return err
+			}
+		}
+		after := before
+		after.DeletedAt, after.UpdatedAt, after.UpdatedBy = &deletedAt, deletedAt, audit.Actor(ctx)
+		after.Version++
+		// check and delete in one statement, a course added in between would
+		// otherwise be left in a deleted category
+		result, err := q.ExecContext(ctx, "UPDATE categories SET version = version + 1, deleted_at = $1, updated_at = $2, updated_by = $3 WHERE id = $4 AND deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM courses WHERE category_id = $5 AND deleted_at IS NULL)",
+			deletedAt, deletedAt, after.UpdatedBy, id, id)
+		if err != nil {
+			return err
+		}
+		if deleted, err := result.RowsAffected(); err != nil || deleted == 0 {
+			if err == nil {
+				err = dberr.CategoryHasCourses
+			}
+			return err
+		}
+		return record(ctx, q, audit.Category, id, audit.Delete, before, after)
+	})
+}
+
+// UpdateBatch updates categories in one transaction. A category that
+// cannot be updated is rejected in its result and the others updated.
+func (c *CategoryRepository) UpdateBatch(ctx context.Context, categories []dto.CategoryInputDto) ([]dto.BatchItemOutputDto, error) {
+	return query.Batch(ctx, c.db, len(categories), func(q query.DBTX, i int) (string, error) {
+		return categories[i].ID, (&CategoryRepository{db: q}).Update(ctx, categories[i])
+	})
+}
+
+// DeleteBatch deletes categories in one transaction, with their courses when
+// deletes cascade. A category that cannot be deleted is rejected in its
+// result and the others deleted.
+func (c *CategoryRepository) DeleteBatch(ctx context.Context, ids []string) ([]dto.BatchItemOutputDto, error) {
+	return query.Batch(ctx, c.db, len(ids), func(q query.DBTX, i int) (string, error) {
+		return ids[i], (&CategoryRepository{db: q, onDelete: c.onDelete}).Delete(ctx, ids[i])
+	})
+}
+
+// deleteCourses soft deletes the live courses of a category, for a cascading
+// delete.
+func deleteCourses(ctx context.Context, q query.DBTX, categoryID string, deletedAt time.Time) error {
+	courses, err := q.QueryContext(ctx, "SELECT "+courseColumns+" FROM courses WHERE category_id = $1 AND deleted_at IS NULL", categoryID)
 	if err != nil {
 		return err
 	}
+	var live []dto.CourseOutputDto
+	for courses.Next() {
+		course, err := scanCourse(courses)
+		if err != nil {
+			courses.Close()
+			return err
+		}
+		live = append(live, course)
+	}
+	courses.Close()
+	if err := courses.Err(); err != nil {
+		return err
+	}
+	for _, course := range live {
+		if err := deleteCourse(ctx, q, course, deletedAt); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (c *CategoryRepository) Delete(id string) error {
-	query, err := c.db.Prepare("select count(*) from courses where category_id = $1")
+// Restore brings back a deleted category, provided its parent is not
+// deleted. Its courses stay deleted.
+func (c *CategoryRepository) Restore(ctx context.Context, id string) error {
+	return query.Atomic(ctx, c.db, func(q query.DBTX) error {
+		before, err := (&CategoryRepository{db: q}).get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before.DeletedAt == nil {
+			return dberr.NotFound("category")
+		}
+		if err := checkParent(ctx, q, "", before.ParentID); err != nil {
+			return err
+		}
+		after := before
+		after.DeletedAt, after.UpdatedAt, after.UpdatedBy = nil, time.Now().UTC(), audit.Actor(ctx)
+		after.Version++
+		result, err := q.ExecContext(ctx, "UPDATE categories SET version = version + 1, deleted_at = NULL, updated_at = $1, updated_by = $2 WHERE id = $3 AND deleted_at IS NOT NULL",
+			after.UpdatedAt, after.UpdatedBy, id)
+		if err != nil {
+			return err
+		}
+		if err := dberr.Affected(result, "category"); err != nil {
+			return err
+		}
+		return record(ctx, q, audit.Category, id, audit.Restore, before, after)
+	})
+}
+
+// Purge removes categories deleted before the given time. Categories still
+// referenced by a course are kept until the course is purged; the
+// subcategories of a purged category move to the top level.
+func (c *CategoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	result, err := c.db.ExecContext(ctx, "DELETE FROM categories WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM courses WHERE courses.category_id = categories.id)", before.UTC())
 	if err != nil {
-		return err
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// checkParent rejects a parent that does not exist or is deleted and, when
+// moving the category id, one that is the category itself or one of its
+// subcategories.
+func checkParent(ctx context.Context, q query.DBTX, id, parentID string) error {
+	if parentID == "" {
+		return nil
 	}
 	var count int
-	err = query.QueryRow(id).Scan(&count)
-	if err != nil {
+	if err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM categories WHERE id = $1 AND deleted_at IS NULL", parentID).Scan(&count); err != nil {
 		return err
 	}
-	if count > 0 {
-		return errors.New("category has courses")
+	if count == 0 {
+		return dberr.UnknownParentCategory
 	}
-	_, err = c.db.Exec("DELETE FROM categories WHERE id = $1", id)
-	if err != nil {
+	if id == "" {
+		return nil
+	}
+	if err := q.QueryRowContext(ctx, query.CategorySubtree("$1")+" SELECT COUNT(*) FROM subtree WHERE id = $2", id, parentID).Scan(&count); err != nil {
 		return err
 	}
+	if count > 0 {
+		return dberr.CategoryCycle
+	}
 	return nil
 }
@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+const enrollmentColumns = "e.user_id, e.course_id, e.progress, e.enrolled_at, e.last_accessed_at"
+
+type Enrollment struct {
+	db query.DBTX
+}
+
+func NewEnrollmentRepository(db query.DBTX) *Enrollment {
+	return &Enrollment{db: db}
+}
+
+func scanEnrollment(row interface{ Scan(...any) error }) (dto.EnrollmentOutputDto, error) {
+	var enrollment dto.EnrollmentOutputDto
+	err := row.Scan(&enrollment.UserID, &enrollment.CourseID, &enrollment.Progress, &enrollment.EnrolledAt, &enrollment.LastAccessedAt)
+	return enrollment, err
+}
+
+func (e *Enrollment) Enroll(ctx context.Context, enrollment dto.EnrollmentInputDto) (dto.EnrollmentOutputDto, error) {
+	created := dto.EnrollmentOutputDto{
+		UserID:     enrollment.UserID,
+		CourseID:   enrollment.CourseID,
+		EnrolledAt: time.Now().UTC(),
+	}
+	err := query.Atomic(ctx, e.db, func(q query.DBTX) error {
+		live, err := liveUser(ctx, q, enrollment.UserID)
+		if err != nil {
+			return err
+		}
+		if !live {
+			return dberr.UnknownEnrollmentUser
+		}
+		if live, err = liveCourse(ctx, q, enrollment.CourseID); err != nil {
+			return err
+		}
+		if !live {
+			return dberr.UnknownEnrollmentCourse
+		}
+		_, err = q.ExecContext(ctx, "INSERT INTO enrollments (user_id, course_id, progress, enrolled_at) VALUES ($1, $2, 0, $3)",
+			created.UserID, created.CourseID, created.EnrolledAt)
+		if isDuplicate(err) {
+			return dberr.AlreadyEnrolled
+		}
+		return err
+	})
+	if err != nil {
+		return dto.EnrollmentOutputDto{}, err
+	}
+	return created, nil
+}
+
+func (e *Enrollment) Unenroll(ctx context.Context, userID, courseID string) error {
+	result, err := e.db.ExecContext(ctx, "DELETE FROM enrollments WHERE user_id = $1 AND course_id = $2", userID, courseID)
+	if err != nil {
+		return err
+	}
+	return dberr.Affected(result, "enrollment")
+}
+
+func (e *Enrollment) Find(ctx context.Context, userID, courseID string) (dto.EnrollmentOutputDto, error) {
+	enrollment, err := scanEnrollment(e.db.QueryRowContext(ctx, "SELECT "+enrollmentColumns+" FROM enrollments e WHERE e.user_id = $1 AND e.course_id = $2",
+		userID, courseID))
+	if err != nil {
+		return dto.EnrollmentOutputDto{}, dberr.NoRows("enrollment", err)
+	}
+	return enrollment, nil
+}
+
+func (e *Enrollment) FindByUserID(ctx context.Context, userID string, page query.Page) (dto.EnrollmentListOutputDto, error) {
+	after, err := page.After()
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	return e.list(ctx, page, func(e dto.EnrollmentOutputDto) string { return e.CourseID },
+		"SELECT "+enrollmentColumns+" FROM enrollments e JOIN courses c ON c.id = e.course_id WHERE e.user_id = $1 AND c.deleted_at IS NULL AND e.course_id > $2 ORDER BY e.course_id LIMIT $3",
+		userID, after, page.Size()+1)
+}
+
+func (e *Enrollment) FindByCourseID(ctx context.Context, courseID string, page query.Page) (dto.EnrollmentListOutputDto, error) {
+	after, err := page.After()
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	live, err := liveCourse(ctx, e.db, courseID)
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	if !live {
+		return dto.EnrollmentListOutputDto{}, dberr.NotFound("course")
+	}
+	return e.list(ctx, page, func(e dto.EnrollmentOutputDto) string { return e.UserID },
+		"SELECT "+enrollmentColumns+" FROM enrollments e JOIN users u ON u.id = e.user_id WHERE e.course_id = $1 AND u.deleted_at IS NULL AND e.user_id > $2 ORDER BY e.user_id LIMIT $3",
+		courseID, after, page.Size()+1)
+}
+
+func (e *Enrollment) UpdateProgress(ctx context.Context, enrollment dto.EnrollmentInputDto) error {
+	if err := dberr.ValidateProgress(enrollment); err != nil {
+		return err
+	}
+	result, err := e.db.ExecContext(ctx, "UPDATE enrollments SET progress = $1, last_accessed_at = $2 WHERE user_id = $3 AND course_id = $4",
+		enrollment.Progress, time.Now().UTC(), enrollment.UserID, enrollment.CourseID)
+	if err != nil {
+		return err
+	}
+	return dberr.Affected(result, "enrollment")
+}
+
+// list runs a page query of enrollments; keyOf returns the column the query
+// orders by.
+func (e *Enrollment) list(ctx context.Context, page query.Page, keyOf func(dto.EnrollmentOutputDto) string, stmt string, args ...any) (dto.EnrollmentListOutputDto, error) {
+	rows, err := e.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	defer rows.Close()
+	enrollments := dto.EnrollmentListOutputDto{}
+	for rows.Next() {
+		enrollment, err := scanEnrollment(rows)
+		if err != nil {
+			return dto.EnrollmentListOutputDto{}, err
+		}
+		enrollments.Enrollments = append(enrollments.Enrollments, enrollment)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.EnrollmentListOutputDto{}, err
+	}
+	enrollments.Enrollments, enrollments.NextCursor = query.Trim(enrollments.Enrollments, page,
+		func(e dto.EnrollmentOutputDto) string { return query.EncodeCursor(keyOf(e)) })
+	return enrollments, nil
+}
+
+// liveUser reports whether the user exists and is not deleted.
+func liveUser(ctx context.Context, q query.DBTX, userID string) (bool, error) {
+	var count int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE id = $1 AND deleted_at IS NULL", userID).Scan(&count)
+	return count > 0, err
+}
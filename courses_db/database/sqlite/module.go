@@ -0,0 +1,178 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/google/uuid"
+)
+
+const moduleColumns = "id, course_id, title, description, position, created_at, updated_at, created_by, updated_by, version"
+
+type Module struct {
+	db query.DBTX
+}
+
+func NewModuleRepository(db query.DBTX) *Module {
+	return &Module{db: db}
+}
+
+func scanModule(row interface{ Scan(...any) error }) (dto.ModuleOutputDto, error) {
+	var module dto.ModuleOutputDto
+	err := row.Scan(&module.ID, &module.CourseID, &module.Title, &module.Description, &module.Position,
+		&module.CreatedAt, &module.UpdatedAt, &module.CreatedBy, &module.UpdatedBy, &module.Version)
+	return module, err
+}
+
+// Create adds a module at the end of its course.
+func (m *Module) Create(ctx context.Context, module dto.ModuleInputDto) (dto.ModuleOutputDto, error) {
+	if err := dberr.ValidateModule(module); err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	now, actor := time.Now().UTC(), audit.Actor(ctx)
+	created := dto.ModuleOutputDto{
+		ID:          uuid.New().String(),
+		CourseID:    module.CourseID,
+		Title:       module.Title,
+		Description: module.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+		Version:     1,
+	}
+	err := query.Atomic(ctx, m.db, func(q query.DBTX) error {
+		live, err := liveCourse(ctx, q, module.CourseID)
+		if err != nil {
+			return err
+		}
+		if !live {
+			return dberr.UnknownCourse
+		}
+		err = q.QueryRowContext(ctx, "SELECT COALESCE(MAX(position), 0) + 1 FROM modules WHERE course_id = $1", module.CourseID).
+			Scan(&created.Position)
+		if err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, "INSERT INTO modules (id, course_id, title, description, position, created_at, updated_at, created_by, updated_by) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+			created.ID, created.CourseID, created.Title, created.Description, created.Position, now, now, actor, actor)
+		if isForeignKey(err) {
+			return dberr.UnknownCourse
+		}
+		return err
+	})
+	if err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	return created, nil
+}
+
+func (m *Module) FindByCourseID(ctx context.Context, courseID string) (dto.ModuleListOutputDto, error) {
+	live, err := liveCourse(ctx, m.db, courseID)
+	if err != nil {
+		return dto.ModuleListOutputDto{}, err
+	}
+	if !live {
+		return dto.ModuleListOutputDto{}, dberr.NotFound("course")
+	}
+	rows, err := m.db.QueryContext(ctx, "SELECT "+moduleColumns+" FROM modules WHERE course_id = $1 ORDER BY position, id", courseID)
+	if err != nil {
+		return dto.ModuleListOutputDto{}, err
+	}
+	defer rows.Close()
+	modules := dto.ModuleListOutputDto{}
+	for rows.Next() {
+		module, err := scanModule(rows)
+		if err != nil {
+			return dto.ModuleListOutputDto{}, err
+		}
+		modules.Modules = append(modules.Modules, module)
+	}
+	return modules, rows.Err()
+}
+
+func (m *Module) Find(ctx context.Context, id string) (dto.ModuleOutputDto, error) {
+	module, err := scanModule(m.db.QueryRowContext(ctx, "SELECT "+moduleColumns+" FROM modules WHERE id = $1", id))
+	if err != nil {
+		return dto.ModuleOutputDto{}, dberr.NoRows("module", err)
+	}
+	return module, nil
+}
+
+func (m *Module) Update(ctx context.Context, module dto.ModuleInputDto) error {
+	if err := dberr.ValidateModule(module); err != nil {
+		return err
+	}
+	return query.Atomic(ctx, m.db, func(q query.DBTX) error {
+		before, err := (&Module{db: q}).Find(ctx, module.ID)
+		if err != nil {
+			return err
+		}
+		if err := dberr.CheckVersion("module", module.Version, before.Version); err != nil {
+			return err
+		}
+		result, err := q.ExecContext(ctx, "UPDATE modules SET version = version + 1, title = $1, description = $2, updated_at = $3, updated_by = $4 WHERE id = $5 AND version = $6",
+			module.Title, module.Description, time.Now().UTC(), audit.Actor(ctx), module.ID, before.Version)
+		if err != nil {
+			return err
+		}
+		return dberr.Changed(result, "module")
+	})
+}
+
+// Delete removes a module and, through the foreign key, its lessons. The
+// modules after it move up.
+func (m *Module) Delete(ctx context.Context, id string) error {
+	return query.Atomic(ctx, m.db, func(q query.DBTX) error {
+		before, err := (&Module{db: q}).Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, "DELETE FROM modules WHERE id = $1", id); err != nil {
+			return err
+		}
+		_, err = q.ExecContext(ctx, "UPDATE modules SET position = position - 1 WHERE course_id = $1 AND position > $2",
+			before.CourseID, before.Position)
+		return err
+	})
+}
+
+func (m *Module) Reorder(ctx context.Context, courseID string, ids []string) error {
+	return query.Atomic(ctx, m.db, func(q query.DBTX) error {
+		modules, err := (&Module{db: q}).FindByCourseID(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		positions := make(map[string]int, len(modules.Modules))
+		current := make([]string, len(modules.Modules))
+		for i, module := range modules.Modules {
+			positions[module.ID], current[i] = module.Position, module.ID
+		}
+		if err := dberr.CheckOrder(current, ids, "module", "course"); err != nil {
+			return err
+		}
+		now, actor := time.Now().UTC(), audit.Actor(ctx)
+		for i, id := range ids {
+			if positions[id] == i+1 {
+				continue
+			}
+			_, err := q.ExecContext(ctx, "UPDATE modules SET version = version + 1, position = $1, updated_at = $2, updated_by = $3 WHERE id = $4",
+				i+1, now, actor, id)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// liveCourse reports whether the course exists and is not deleted.
+func liveCourse(ctx context.Context, q query.DBTX, courseID string) (bool, error) {
+	var count int
+	err := q.QueryRowContext(ctx, "SELECT COUNT(*) FROM courses WHERE id = $1 AND deleted_at IS NULL", courseID).Scan(&count)
+	return count > 0, err
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+type SearchRepository struct {
+	db query.DBTX
+}
+
+func NewSearchRepository(db query.DBTX) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Search matches the terms against the FTS5 search_index kept by the
+// triggers of migration 9, ranked by its bm25. FTS5 scores better matches
+// lower, so the scores are negated.
+func (s *SearchRepository) Search(ctx context.Context, spec query.SearchSpec) (dto.SearchOutputDto, error) {
+	terms, err := spec.Terms()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	offset, err := spec.Page.Offset()
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	phrases := make([]string, len(terms))
+	for i, term := range terms {
+		phrases[i] = `"` + term + `"`
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT d.entity_type, d.entity_id, search_index.name, snippet(search_index, -1, $1, $2, '…', $3), -bm25(search_index) FROM search_index JOIN search_docs d ON d.docid = search_index.rowid LEFT JOIN categories ca ON d.entity_type = 'category' AND ca.id = d.entity_id LEFT JOIN courses co ON d.entity_type = 'course' AND co.id = d.entity_id WHERE search_index MATCH $4 AND ca.deleted_at IS NULL AND co.deleted_at IS NULL AND ($5 = '' OR d.entity_type = $5) ORDER BY bm25(search_index), d.entity_type, d.entity_id LIMIT $6 OFFSET $7",
+		query.SnippetStart, query.SnippetEnd, query.SnippetWords, strings.Join(phrases, " "), spec.EntityType, spec.Page.Size()+1, offset)
+	if err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	defer rows.Close()
+	results := dto.SearchOutputDto{}
+	for rows.Next() {
+		var result dto.SearchResultOutputDto
+		if err := rows.Scan(&result.EntityType, &result.ID, &result.Name, &result.Snippet, &result.Score); err != nil {
+			return dto.SearchOutputDto{}, err
+		}
+		result.Snippet = query.EscapeSnippet(result.Snippet)
+		results.Results = append(results.Results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return dto.SearchOutputDto{}, err
+	}
+	results.Results, results.NextCursor = query.TrimOffset(results.Results, spec.Page, offset)
+	return results, nil
+}
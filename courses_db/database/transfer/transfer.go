@@ -0,0 +1,402 @@
+// Package transfer copies categories, courses, users, their history,
+// modules, lessons, enrollments, course instructors and tags from one SQL
+// database to another, whatever their drivers, keeping ids, audit columns,
+// versions and password hashes. Rows the destination already holds are
+// skipped, so an interrupted copy resumes by running it again.
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/migrations"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+)
+
+// PageSize is the number of rows read, and written in one transaction, at
+// a time.
+const PageSize = 500
+
+// Database is one end of a copy.
+type Database struct {
+	DB     *sql.DB
+	Driver string
+}
+
+func (d Database) placeholder() query.Placeholder {
+	if d.Driver == "mysql" {
+		return query.Question
+	}
+	return query.Dollar
+}
+
+type kind int
+
+const (
+	text kind = iota
+	integer
+	timestamp
+	nullTimestamp
+)
+
+type column struct {
+	name string
+	kind kind
+}
+
+// table is copied in the order of its key, the first columns; most tables
+// are keyed by id alone.
+type table struct {
+	name    string
+	key     int
+	columns []column
+}
+
+// keyColumns returns the names of the key columns.
+func (t table) keyColumns() []string {
+	key := max(t.key, 1)
+	names := make([]string, key)
+	for i := range names {
+		names[i] = t.columns[i].name
+	}
+	return names
+}
+
+// keyOf returns the key of a row read from the table.
+func (t table) keyOf(row []any) []any {
+	key := make([]any, len(t.keyColumns()))
+	for i := range key {
+		switch v := row[i].(type) {
+		case *sql.NullString:
+			key[i] = v.String
+		case *int64:
+			key[i] = *v
+		}
+	}
+	return key
+}
+
+func (t table) columnList() string {
+	names := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		names[i] = c.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// row returns a row to scan into, then insert from.
+func (t table) row() []any {
+	row := make([]any, len(t.columns))
+	for i, c := range t.columns {
+		switch c.kind {
+		case text:
+			row[i] = new(sql.NullString)
+		case integer:
+			row[i] = new(int64)
+		case timestamp:
+			row[i] = new(time.Time)
+		case nullTimestamp:
+			row[i] = new(sql.NullTime)
+		}
+	}
+	return row
+}
+
+var stamped = []column{
+	{"created_at", timestamp}, {"updated_at", timestamp}, {"created_by", text}, {"updated_by", text}, {"version", integer},
+}
+
+// audited are the columns of the tables with soft delete.
+var audited = append(stamped[:len(stamped):len(stamped)], column{"deleted_at", nullTimestamp})
+
+// tables are copied in order, a table after those it refers to. The parent
+// of a category may come after it, so parent_id is copied last, by
+// linkParents.
+var tables = []table{
+	{name: "categories", columns: append([]column{{"id", text}, {"name", text}, {"description", text}}, audited...)},
+	{name: "users", columns: append([]column{{"id", text}, {"name", text}, {"email", text}, {"password", text}}, audited...)},
+	{name: "courses", columns: append([]column{{"id", text}, {"name", text}, {"description", text}, {"category_id", text}}, audited...)},
+	{name: "history", columns: []column{
+		{"id", integer}, {"entity_type", text}, {"entity_id", text}, {"action", text}, {"before_state", text}, {"after_state", text}, {"changed_by", text}, {"changed_at", timestamp},
+	}},
+	{name: "modules", columns: append([]column{{"id", text}, {"course_id", text}, {"title", text}, {"description", text}, {"position", integer}}, stamped...)},
+	{name: "lessons", columns: append([]column{
+		{"id", text}, {"module_id", text}, {"title", text}, {"content_type", text}, {"duration_seconds", integer}, {"body", text}, {"position", integer},
+	}, stamped...)},
+	{name: "enrollments", key: 2, columns: []column{
+		{"user_id", text}, {"course_id", text}, {"progress", integer}, {"enrolled_at", timestamp}, {"last_accessed_at", nullTimestamp},
+	}},
+	{name: "course_instructors", key: 2, columns: []column{{"course_id", text}, {"user_id", text}, {"added_at", timestamp}}},
+	{name: "tags", columns: []column{{"id", text}, {"name", text}, {"created_at", timestamp}, {"created_by", text}}},
+	{name: "course_tags", key: 2, columns: []column{{"course_id", text}, {"tag_id", text}, {"added_at", timestamp}}},
+}
+
+// reference is a column holding the id of a row of another table.
+type reference struct {
+	table, column, references string
+}
+
+var references = []reference{
+	{table: "categories", column: "parent_id", references: "categories"},
+	{table: "courses", column: "category_id", references: "categories"},
+	{table: "modules", column: "course_id", references: "courses"},
+	{table: "lessons", column: "module_id", references: "modules"},
+	{table: "enrollments", column: "user_id", references: "users"},
+	{table: "enrollments", column: "course_id", references: "courses"},
+	{table: "course_instructors", column: "course_id", references: "courses"},
+	{table: "course_instructors", column: "user_id", references: "users"},
+	{table: "course_tags", column: "course_id", references: "courses"},
+	{table: "course_tags", column: "tag_id", references: "tags"},
+}
+
+// TableReport counts the rows of a table: read from the source, copied,
+// skipped as the destination had them already, and in the destination at
+// the end.
+type TableReport struct {
+	Table       string
+	Source      int64
+	Copied      int64
+	Skipped     int64
+	Destination int64
+}
+
+// Copy copies every table from src to dst. Both must be migrated to the
+// latest version, and src must not hold rows referring to missing ones.
+func Copy(ctx context.Context, src, dst Database) ([]TableReport, error) {
+	for _, end := range []struct {
+		name string
+		db   Database
+	}{{"source", src}, {"destination", dst}} {
+		migrator, err := migrations.New(end.db.DB, end.db.Driver)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", end.name, err)
+		}
+		if err := migrator.Check(ctx); err != nil {
+			return nil, fmt.Errorf("%s: %w", end.name, err)
+		}
+	}
+	if err := checkReferences(ctx, src); err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	var reports []TableReport
+	for _, t := range tables {
+		report, err := copyTable(ctx, src, dst, t)
+		if err != nil {
+			return reports, fmt.Errorf("%s: %w", t.name, err)
+		}
+		reports = append(reports, report)
+	}
+	if err := linkParents(ctx, src, dst); err != nil {
+		return reports, fmt.Errorf("categories: %w", err)
+	}
+	if err := resetHistorySequence(ctx, dst); err != nil {
+		return reports, fmt.Errorf("history: %w", err)
+	}
+	if err := checkReferences(ctx, dst); err != nil {
+		return reports, fmt.Errorf("destination: %w", err)
+	}
+	return reports, nil
+}
+
+// checkReferences fails when a row refers to a row that does not exist.
+func checkReferences(ctx context.Context, d Database) error {
+	for _, r := range references {
+		var broken int64
+		err := d.DB.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s t WHERE t.%s IS NOT NULL AND NOT EXISTS (SELECT 1 FROM %s r WHERE r.id = t.%s)",
+			r.table, r.column, r.references, r.column)).Scan(&broken)
+		if err != nil {
+			return err
+		}
+		if broken > 0 {
+			return fmt.Errorf("%d %s refer to missing %s", broken, r.table, r.references)
+		}
+	}
+	return nil
+}
+
+// copyTable copies a table page by page in key order, each page in one
+// transaction of the destination.
+func copyTable(ctx context.Context, src, dst Database, t table) (TableReport, error) {
+	report := TableReport{Table: t.name}
+	var after []any
+	for {
+		rows, err := readPage(ctx, src, t, after)
+		if err != nil {
+			return report, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		report.Source += int64(len(rows))
+		after = t.keyOf(rows[len(rows)-1])
+		err = query.Atomic(ctx, dst.DB, func(q query.DBTX) error {
+			missing, err := withoutExisting(ctx, q, dst.placeholder(), t, rows)
+			if err != nil {
+				return err
+			}
+			report.Copied += int64(len(missing))
+			report.Skipped += int64(len(rows) - len(missing))
+			return query.InsertRows(ctx, q, dst.placeholder(), "INSERT INTO "+t.name+" ("+t.columnList()+")", missing)
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+	err := dst.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+t.name).Scan(&report.Destination)
+	return report, err
+}
+
+// linkParents copies the parent of every category, page by page, once all
+// categories are in. A parent the destination already has is kept.
+func linkParents(ctx context.Context, src, dst Database) error {
+	read := fmt.Sprintf("SELECT id, parent_id FROM categories WHERE parent_id IS NOT NULL AND id > %s ORDER BY id LIMIT %d", src.placeholder()(1), PageSize)
+	link := fmt.Sprintf("UPDATE categories SET parent_id = %s WHERE id = %s AND parent_id IS NULL", dst.placeholder()(1), dst.placeholder()(2))
+	after := ""
+	for {
+		rows, err := src.DB.QueryContext(ctx, read, after)
+		if err != nil {
+			return err
+		}
+		var links [][2]string
+		for rows.Next() {
+			var l [2]string
+			if err := rows.Scan(&l[0], &l[1]); err != nil {
+				rows.Close()
+				return err
+			}
+			links = append(links, l)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		after = links[len(links)-1][0]
+		err = query.Atomic(ctx, dst.DB, func(q query.DBTX) error {
+			for _, l := range links {
+				if _, err := q.ExecContext(ctx, link, l[1], l[0]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// resetHistorySequence moves the PostgreSQL sequence of history ids past the
+// copied ones, which it did not hand out. SQLite and MariaDB keep up on their
+// own.
+func resetHistorySequence(ctx context.Context, d Database) error {
+	if d.Driver != "postgres" {
+		return nil
+	}
+	_, err := d.DB.ExecContext(ctx, "SELECT setval(pg_get_serial_sequence('history', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM history")
+	return err
+}
+
+// readPage reads the rows of a table following the key after, nil for the
+// first page.
+func readPage(ctx context.Context, d Database, t table, after []any) ([][]any, error) {
+	key := t.keyColumns()
+	stmt := "SELECT " + t.columnList() + " FROM " + t.name
+	var args []any
+	if after != nil {
+		// k1 > a1 OR (k1 = a1 AND k2 > a2) OR ..., which every driver
+		// understands, unlike row value comparisons
+		ph := d.placeholder()
+		var or []string
+		for i := range key {
+			var and []string
+			for j := 0; j < i; j++ {
+				args = append(args, after[j])
+				and = append(and, key[j]+" = "+ph(len(args)))
+			}
+			args = append(args, after[i])
+			and = append(and, key[i]+" > "+ph(len(args)))
+			or = append(or, "("+strings.Join(and, " AND ")+")")
+		}
+		stmt += " WHERE " + strings.Join(or, " OR ")
+	}
+	stmt += fmt.Sprintf(" ORDER BY %s LIMIT %d", strings.Join(key, ", "), PageSize)
+	rows, err := d.DB.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var page [][]any
+	for rows.Next() {
+		row := t.row()
+		if err := rows.Scan(row...); err != nil {
+			return nil, err
+		}
+		page = append(page, row)
+	}
+	return page, rows.Err()
+}
+
+// withoutExisting drops the rows whose key the destination already holds.
+func withoutExisting(ctx context.Context, q query.DBTX, ph query.Placeholder, t table, rows [][]any) ([][]any, error) {
+	key := t.keyColumns()
+	var args []any
+	for _, row := range rows {
+		args = append(args, t.keyOf(row)...)
+	}
+	var where string
+	if len(key) == 1 {
+		where = key[0] + " IN " + query.Values(ph, 1, len(args))
+	} else {
+		or := make([]string, len(rows))
+		for i := range rows {
+			and := make([]string, len(key))
+			for j, column := range key {
+				and[j] = column + " = " + ph(i*len(key)+j+1)
+			}
+			or[i] = "(" + strings.Join(and, " AND ") + ")"
+		}
+		where = strings.Join(or, " OR ")
+	}
+	existing, err := q.QueryContext(ctx, "SELECT "+strings.Join(key, ", ")+" FROM "+t.name+" WHERE "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer existing.Close()
+	found := map[string]bool{}
+	for existing.Next() {
+		values := make([]string, len(key))
+		dest := make([]any, len(key))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := existing.Scan(dest...); err != nil {
+			return nil, err
+		}
+		found[strings.Join(values, "\x00")] = true
+	}
+	if err := existing.Err(); err != nil {
+		return nil, err
+	}
+	var missing [][]any
+	for _, row := range rows {
+		if !found[joinKey(t.keyOf(row))] {
+			missing = append(missing, row)
+		}
+	}
+	return missing, nil
+}
+
+// joinKey turns a key into a map key.
+func joinKey(key []any) string {
+	values := make([]string, len(key))
+	for i, v := range key {
+		values[i] = fmt.Sprint(v)
+	}
+	return strings.Join(values, "\x00")
+}
@@ -0,0 +1,230 @@
+package transfer_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/migrations"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/db/database/transfer"
+	"github.com/antoniofmoliveira/courses/dto"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newSqlite(t *testing.T, migrate bool) transfer.Database {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if migrate {
+		m, err := migrations.New(db, "sqlite3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Up(context.Background()); errors.Is(err, migrations.ErrNoFTS5) {
+			t.Skip(err)
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return transfer.Database{DB: db, Driver: "sqlite3"}
+}
+
+func repositories(t *testing.T, d transfer.Database) *database.DBImplementation {
+	t.Helper()
+	dbi, err := database.NewSQLImplementation(d.DB, d.Driver, query.Restrict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dbi
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	src, dst := newSqlite(t, true), newSqlite(t, true)
+	source := repositories(t, src)
+	golang, err := source.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	web, err := source.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Web", ParentID: golang.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var courses []string
+	for _, name := range []string{"basics", "web", "deleted"} {
+		course, err := source.CourseRepository.Create(ctx, dto.CourseInputDto{Name: name, CategoryID: golang.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		courses = append(courses, course.ID)
+	}
+	if err := source.CourseRepository.Delete(ctx, courses[2]); err != nil {
+		t.Fatal(err)
+	}
+	ann, err := source.UserRepository.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.InstructorRepository.Add(ctx, dto.InstructorInputDto{CourseID: courses[0], UserID: ann.ID}); err != nil {
+		t.Fatal(err)
+	}
+	for _, course := range courses[:2] {
+		if _, err := source.EnrollmentRepository.Enroll(ctx, dto.EnrollmentInputDto{UserID: ann.ID, CourseID: course}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := source.TagRepository.Tag(ctx, dto.CourseTagInputDto{CourseID: courses[0], Name: "Beginner"}); err != nil {
+		t.Fatal(err)
+	}
+	module, err := source.ModuleRepository.Create(ctx, dto.ModuleInputDto{CourseID: courses[0], Title: "setup"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.LessonRepository.Create(ctx, dto.LessonInputDto{ModuleID: module.ID, Title: "install", ContentType: "video", DurationSeconds: 90}); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := transfer.Copy(ctx, src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []transfer.TableReport{
+		{Table: "categories", Source: 2, Copied: 2, Destination: 2},
+		{Table: "users", Source: 1, Copied: 1, Destination: 1},
+		{Table: "courses", Source: 3, Copied: 3, Destination: 3},
+		{Table: "history", Source: 6, Copied: 6, Destination: 6},
+		{Table: "modules", Source: 1, Copied: 1, Destination: 1},
+		{Table: "lessons", Source: 1, Copied: 1, Destination: 1},
+		{Table: "enrollments", Source: 2, Copied: 2, Destination: 2},
+		{Table: "course_instructors", Source: 1, Copied: 1, Destination: 1},
+		{Table: "tags", Source: 1, Copied: 1, Destination: 1},
+		{Table: "course_tags", Source: 1, Copied: 1, Destination: 1},
+	}
+	checkReports(t, reports, want)
+
+	destination := repositories(t, dst)
+	copied, err := destination.CourseRepository.Find(ctx, courses[0])
+	if err != nil || copied.Name != "basics" || copied.CategoryID != golang.ID || copied.Version != 1 {
+		t.Errorf("copied course = %+v, %v", copied, err)
+	}
+	if copied, err := destination.CategoryRepository.Find(ctx, web.ID); err != nil || copied.ParentID != golang.ID {
+		t.Errorf("copied subcategory = %+v, %v, want it under %s", copied, err, golang.ID)
+	}
+	if _, err := destination.CourseRepository.Find(ctx, courses[2]); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("deleted course error = %v, want it copied as deleted", err)
+	}
+	wantHash, _ := source.UserRepository.FindByEmail(ctx, "ann@example.com")
+	gotHash, err := destination.UserRepository.FindByEmail(ctx, "ann@example.com")
+	if err != nil || gotHash.Password != wantHash.Password {
+		t.Errorf("copied password hash = %+v, %v, want %q", gotHash, err, wantHash.Password)
+	}
+	if history, err := destination.HistoryRepository.List(ctx, query.HistorySpec{EntityType: "course", EntityID: courses[2]}); err != nil || len(history.Entries) != 2 {
+		t.Errorf("copied history of the deleted course = %+v, %v, want create and delete", history, err)
+	}
+	if lessons, err := destination.LessonRepository.FindByModuleID(ctx, module.ID); err != nil || len(lessons.Lessons) != 1 || lessons.Lessons[0].DurationSeconds != 90 {
+		t.Errorf("copied lessons = %+v, %v", lessons, err)
+	}
+	if enrollments, err := destination.EnrollmentRepository.FindByUserID(ctx, ann.ID, query.Page{}); err != nil || len(enrollments.Enrollments) != 2 {
+		t.Errorf("copied enrollments = %+v, %v", enrollments, err)
+	}
+	if found, err := destination.SearchRepository.Search(ctx, query.SearchSpec{Query: "basics"}); err != nil || len(found.Results) != 1 {
+		t.Errorf("search in the destination = %+v, %v", found, err)
+	}
+
+	if taught, err := destination.CourseRepository.FindByInstructorID(ctx, ann.ID, query.Page{}); err != nil || len(taught.Courses) != 1 {
+		t.Errorf("copied instructors = %+v, %v", taught, err)
+	}
+	if tagged, err := destination.CourseRepository.List(ctx, query.CourseSpec{Tags: []string{"beginner"}}); err != nil || len(tagged.Courses) != 1 || tagged.Courses[0].ID != courses[0] {
+		t.Errorf("copied course tags = %+v, %v", tagged, err)
+	}
+
+	// running again picks up where the last run stopped; a course created
+	// as ann is taught by ann
+	course, err := source.CourseRepository.Create(audit.WithActor(ctx, "ann@example.com"), dto.CourseInputDto{Name: "new", CategoryID: golang.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.EnrollmentRepository.Enroll(ctx, dto.EnrollmentInputDto{UserID: ann.ID, CourseID: course.ID}); err != nil {
+		t.Fatal(err)
+	}
+	reports, err = transfer.Copy(ctx, src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []transfer.TableReport{
+		{Table: "categories", Source: 2, Skipped: 2, Destination: 2},
+		{Table: "users", Source: 1, Skipped: 1, Destination: 1},
+		{Table: "courses", Source: 4, Copied: 1, Skipped: 3, Destination: 4},
+		{Table: "history", Source: 7, Copied: 1, Skipped: 6, Destination: 7},
+		{Table: "modules", Source: 1, Skipped: 1, Destination: 1},
+		{Table: "lessons", Source: 1, Skipped: 1, Destination: 1},
+		{Table: "enrollments", Source: 3, Copied: 1, Skipped: 2, Destination: 3},
+		{Table: "course_instructors", Source: 2, Copied: 1, Skipped: 1, Destination: 2},
+		{Table: "tags", Source: 1, Skipped: 1, Destination: 1},
+		{Table: "course_tags", Source: 1, Skipped: 1, Destination: 1},
+	}
+	checkReports(t, reports, want)
+}
+
+func checkReports(t *testing.T, got, want []transfer.TableReport) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Copy() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Copy() %s = %+v, want %+v", want[i].Table, got[i], want[i])
+		}
+	}
+}
+
+func TestCopyRefuses(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T) (src, dst transfer.Database)
+		wantErr string
+	}{
+		{
+			name: "destination not migrated",
+			prepare: func(t *testing.T) (transfer.Database, transfer.Database) {
+				return newSqlite(t, true), newSqlite(t, false)
+			},
+			wantErr: "destination: " + migrations.ErrOutOfDate.Error(),
+		},
+		{
+			name: "course without category",
+			prepare: func(t *testing.T) (transfer.Database, transfer.Database) {
+				src := newSqlite(t, true)
+				// one connection, so the pragma holds for the insert
+				src.DB.SetMaxOpenConns(1)
+				if _, err := src.DB.Exec("PRAGMA foreign_keys = off"); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := src.DB.Exec("INSERT INTO courses (id, name, description, category_id) VALUES ('c1', 'orphan', '', 'gone')"); err != nil {
+					t.Fatal(err)
+				}
+				return src, newSqlite(t, true)
+			},
+			wantErr: "source: 1 courses refer to missing categories",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, dst := tt.prepare(t)
+			_, err := transfer.Copy(ctx, src, dst)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Copy() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+)
+
+var ErrTransactionsUnsupported = errors.New("database driver does not support transactions")
+
+// Tx is a unit of work: repositories bound to one transaction. Nothing done
+// through them is kept unless Commit succeeds.
+type Tx struct {
+	CategoryRepository   CategoryRepositoryInterface
+	CourseRepository     CourseRepositoryInterface
+	UserRepository       UserRepositoryInterface
+	HistoryRepository    HistoryRepositoryInterface
+	SearchRepository     SearchRepositoryInterface
+	ModuleRepository     ModuleRepositoryInterface
+	LessonRepository     LessonRepositoryInterface
+	EnrollmentRepository EnrollmentRepositoryInterface
+	InstructorRepository InstructorRepositoryInterface
+	TagRepository        TagRepositoryInterface
+	commit               func() error
+	rollback             func() error
+}
+
+func (t *Tx) Commit() error {
+	return t.commit()
+}
+
+// Rollback discards the unit of work. It is a no-op after Commit, so it can
+// be deferred.
+func (t *Tx) Rollback() error {
+	err := t.rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
+
+// Transactor starts units of work. DBImplementation is one.
+type Transactor interface {
+	Begin(ctx context.Context) (*Tx, error)
+}
+
+func (d *DBImplementation) Begin(ctx context.Context) (*Tx, error) {
+	if d.begin == nil {
+		return nil, ErrTransactionsUnsupported
+	}
+	return d.begin(ctx)
+}
+
+// InTx runs fn in a unit of work, committing when fn returns nil and rolling
+// back otherwise.
+func InTx(ctx context.Context, t Transactor, fn func(tx *Tx) error) error {
+	tx, err := t.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqlImplementation builds the DBImplementation of a SQL backend from a
+// function creating its repositories on either the database or a transaction.
+// With replicas, reads outside transactions go to them.
+func sqlImplementation(db *sql.DB, replicas []*sql.DB, repositories func(q query.DBTX) *Tx) *DBImplementation {
+	var q query.DBTX = db
+	if len(replicas) > 0 {
+		q = query.NewRouter(db, replicas)
+	}
+	repos := repositories(q)
+	return &DBImplementation{
+		db:                   db,
+		CategoryRepository:   repos.CategoryRepository,
+		CourseRepository:     repos.CourseRepository,
+		UserRepository:       repos.UserRepository,
+		HistoryRepository:    repos.HistoryRepository,
+		SearchRepository:     repos.SearchRepository,
+		ModuleRepository:     repos.ModuleRepository,
+		LessonRepository:     repos.LessonRepository,
+		EnrollmentRepository: repos.EnrollmentRepository,
+		InstructorRepository: repos.InstructorRepository,
+		TagRepository:        repos.TagRepository,
+		begin: func(ctx context.Context) (*Tx, error) {
+			sqlTx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+			tx := repositories(sqlTx)
+			tx.commit, tx.rollback = sqlTx.Commit, sqlTx.Rollback
+			return tx, nil
+		},
+	}
+}
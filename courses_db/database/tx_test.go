@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/migrations"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func newSqliteImplementation(t *testing.T, onDelete query.OnDelete) *DBImplementation {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := migrations.New(db, "sqlite3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Up(context.Background()); errors.Is(err, migrations.ErrNoFTS5) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return sqlImplementation(db, nil, sqliteRepositories(onDelete))
+}
+
+func TestInTx(t *testing.T) {
+	errAbort := errors.New("abort")
+	implementations := map[string]*DBImplementation{
+		"sqlite": newSqliteImplementation(t, query.Restrict),
+		"memory": NewMemoryImplementation(query.Restrict),
+	}
+	tests := []struct {
+		name        string
+		fail        bool
+		wantCourses int
+	}{
+		{name: "commit", wantCourses: 2},
+		{name: "rollback", fail: true, wantCourses: 0},
+	}
+	for backend, dbi := range implementations {
+		for _, tt := range tests {
+			t.Run(backend+" "+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				var categoryID string
+				err := InTx(ctx, dbi, func(tx *Tx) error {
+					category, err := tx.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: tt.name})
+					if err != nil {
+						return err
+					}
+					categoryID = category.ID
+					for _, name := range []string{"a", "b"} {
+						if _, err := tx.CourseRepository.Create(ctx, dto.CourseInputDto{Name: name, CategoryID: category.ID}); err != nil {
+							return err
+						}
+					}
+					if tt.fail {
+						return errAbort
+					}
+					return nil
+				})
+				if tt.fail != errors.Is(err, errAbort) {
+					t.Fatalf("InTx() error = %v", err)
+				}
+				courses, err := dbi.CourseRepository.FindByCategoryID(ctx, categoryID, query.Page{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(courses.Courses) != tt.wantCourses {
+					t.Errorf("got %d courses, want %d", len(courses.Courses), tt.wantCourses)
+				}
+				_, err = dbi.CategoryRepository.Find(ctx, categoryID)
+				if tt.fail != errors.Is(err, ErrNotFound) {
+					t.Errorf("Find() error = %v", err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func TestVersions(t *testing.T) {
+	implementations := map[string]*DBImplementation{
+		"sqlite": newSqliteImplementation(t, query.Restrict),
+		"memory": NewMemoryImplementation(query.Restrict),
+	}
+	for backend, dbi := range implementations {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			category, err := dbi.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: "Go"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			course, err := dbi.CourseRepository.Create(ctx, dto.CourseInputDto{Name: "basics", CategoryID: category.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			user, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "Ann", Email: "ann@example.com", Password: "hash"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if category.Version != 1 || course.Version != 1 || user.Version != 1 {
+				t.Fatalf("created versions = %d, %d, %d, want 1", category.Version, course.Version, user.Version)
+			}
+
+			updateCourse := func(version int64) error {
+				return dbi.CourseRepository.Update(ctx, dto.CourseInputDto{ID: course.ID, Name: "advanced", CategoryID: category.ID, Version: version})
+			}
+			updateCategory := func(version int64) error {
+				return dbi.CategoryRepository.Update(ctx, dto.CategoryInputDto{ID: category.ID, Name: "Golang", Version: version})
+			}
+			updateUser := func(version int64) error {
+				return dbi.UserRepository.Update(ctx, dto.UserInputDto{ID: user.ID, Name: "Ann", Email: "ann@example.com", Password: "hash", Version: version})
+			}
+			steps := []struct {
+				name    string
+				run     func() error
+				wantErr error
+			}{
+				{name: "course at current version", run: func() error { return updateCourse(1) }},
+				{name: "course at stale version", run: func() error { return updateCourse(1) }, wantErr: ErrStaleVersion},
+				{name: "course without version", run: func() error { return updateCourse(0) }},
+				{name: "course at new version", run: func() error { return updateCourse(3) }},
+				{name: "category at current version", run: func() error { return updateCategory(1) }},
+				{name: "category at stale version", run: func() error { return updateCategory(1) }, wantErr: ErrStaleVersion},
+				{name: "user at current version", run: func() error { return updateUser(1) }},
+				{name: "user at stale version", run: func() error { return updateUser(1) }, wantErr: ErrStaleVersion},
+				{name: "unknown course with version", run: func() error {
+					return dbi.CourseRepository.Update(ctx, dto.CourseInputDto{ID: "00000000-0000-0000-0000-000000000000", Name: "x", CategoryID: category.ID, Version: 1})
+				}, wantErr: ErrNotFound},
+				{name: "unknown user with version", run: func() error {
+					return dbi.UserRepository.Update(ctx, dto.UserInputDto{ID: "00000000-0000-0000-0000-000000000000", Name: "x", Email: "x@example.com", Version: 1})
+				}, wantErr: ErrNotFound},
+				{name: "delete moves the version on", run: func() error { return dbi.CourseRepository.Delete(ctx, course.ID) }},
+				{name: "restore moves the version on", run: func() error { return dbi.CourseRepository.Restore(ctx, course.ID) }},
+				{name: "course at version before delete", run: func() error { return updateCourse(4) }, wantErr: ErrStaleVersion},
+				{name: "course after restore", run: func() error { return updateCourse(6) }},
+			}
+			for _, step := range steps {
+				err := step.run()
+				if step.wantErr == nil && err != nil {
+					t.Fatalf("%s: error = %v", step.name, err)
+				}
+				if step.wantErr != nil && !errors.Is(err, step.wantErr) {
+					t.Fatalf("%s: error = %v, want %v", step.name, err, step.wantErr)
+				}
+				if errors.Is(step.wantErr, ErrStaleVersion) && !errors.Is(err, ErrConflict) {
+					t.Fatalf("%s: error = %v, want a conflict", step.name, err)
+				}
+			}
+
+			got, err := dbi.CourseRepository.Find(ctx, course.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != 7 {
+				t.Errorf("course version = %d, want 7", got.Version)
+			}
+		})
+	}
+}
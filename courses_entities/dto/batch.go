@@ -0,0 +1,10 @@
+package dto
+
+// BatchItemOutputDto is the outcome of one item of a batch, by its position
+// in the batch. ID is the id of the item created, updated or deleted; Err is
+// why the item was rejected, nil when it was applied.
+type BatchItemOutputDto struct {
+	Index int    `json:"index"`
+	ID    string `json:"id,omitempty"`
+	Err   error  `json:"-"`
+}
@@ -1,17 +1,29 @@
 package dto
 
+import "time"
+
 type CategoryInputDto struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	ParentID    string `json:"parent_id,omitempty"`
+	Version     int64  `json:"version,omitempty"`
 }
 
 type CategoryOutputDto struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ParentID    string     `json:"parent_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CreatedBy   string     `json:"created_by"`
+	UpdatedBy   string     `json:"updated_by"`
+	Version     int64      `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CategoryListOutputDto struct {
 	Categories []CategoryOutputDto `json:"categories"`
+	NextCursor string              `json:"next_cursor,omitempty"`
 }
@@ -1,19 +1,30 @@
 package dto
 
+import "time"
+
 type CourseInputDto struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	CategoryID  string `json:"category_id"`
+	// Version is the version the update was made against, 0 to skip the check.
+	Version int64 `json:"version,omitempty"`
 }
 
 type CourseOutputDto struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	CategoryID  string `json:"category_id"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	CategoryID  string     `json:"category_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CreatedBy   string     `json:"created_by"`
+	UpdatedBy   string     `json:"updated_by"`
+	Version     int64      `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CourseListOutputDto struct {
-	Courses []CourseOutputDto `json:"courses"`
+	Courses    []CourseOutputDto `json:"courses"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
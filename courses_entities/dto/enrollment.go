@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// EnrollmentInputDto enrolls a user in a course or, for an update, records
+// how far the user has got through it.
+type EnrollmentInputDto struct {
+	UserID   string `json:"user_id"`
+	CourseID string `json:"course_id"`
+	Progress int    `json:"progress"`
+}
+
+type EnrollmentOutputDto struct {
+	UserID         string     `json:"user_id"`
+	CourseID       string     `json:"course_id"`
+	Progress       int        `json:"progress"`
+	EnrolledAt     time.Time  `json:"enrolled_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
+
+type EnrollmentListOutputDto struct {
+	Enrollments []EnrollmentOutputDto `json:"enrollments"`
+	NextCursor  string                `json:"next_cursor,omitempty"`
+}
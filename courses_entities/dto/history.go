@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// HistoryOutputDto is one recorded change of an entity. Before is empty for
+// a create, After holds the state the change left.
+type HistoryOutputDto struct {
+	ID         string          `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	ChangedBy  string          `json:"changed_by"`
+	ChangedAt  time.Time       `json:"changed_at"`
+}
+
+type HistoryListOutputDto struct {
+	Entries    []HistoryOutputDto `json:"entries"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
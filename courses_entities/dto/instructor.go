@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// InstructorInputDto makes a user an instructor of a course.
+type InstructorInputDto struct {
+	CourseID string `json:"course_id"`
+	UserID   string `json:"user_id"`
+}
+
+// InstructorOutputDto is a user teaching a course.
+type InstructorOutputDto struct {
+	CourseID string    `json:"course_id"`
+	UserID   string    `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+type InstructorListOutputDto struct {
+	Instructors []InstructorOutputDto `json:"instructors"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// LessonInputDto describes a lesson. ContentType is one of
+// entity.ContentTypes.
+type LessonInputDto struct {
+	ID              string `json:"id"`
+	ModuleID        string `json:"module_id"`
+	Title           string `json:"title"`
+	ContentType     string `json:"content_type"`
+	DurationSeconds int64  `json:"duration_seconds"`
+	Body            string `json:"body"`
+	Version         int64  `json:"version,omitempty"`
+}
+
+type LessonOutputDto struct {
+	ID              string    `json:"id"`
+	ModuleID        string    `json:"module_id"`
+	Title           string    `json:"title"`
+	ContentType     string    `json:"content_type"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	Body            string    `json:"body"`
+	Position        int       `json:"position"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	CreatedBy       string    `json:"created_by"`
+	UpdatedBy       string    `json:"updated_by"`
+	Version         int64     `json:"version"`
+}
+
+// LessonListOutputDto holds the lessons of a module, in order.
+type LessonListOutputDto struct {
+	Lessons []LessonOutputDto `json:"lessons"`
+}
+
+// ReorderInputDto lists every child of a parent, in the order wanted.
+type ReorderInputDto struct {
+	IDs []string `json:"ids"`
+}
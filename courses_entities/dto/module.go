@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+type ModuleInputDto struct {
+	ID          string `json:"id"`
+	CourseID    string `json:"course_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     int64  `json:"version,omitempty"`
+}
+
+type ModuleOutputDto struct {
+	ID          string    `json:"id"`
+	CourseID    string    `json:"course_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   string    `json:"updated_by"`
+	Version     int64     `json:"version"`
+}
+
+// ModuleListOutputDto holds the modules of a course, in order.
+type ModuleListOutputDto struct {
+	Modules []ModuleOutputDto `json:"modules"`
+}
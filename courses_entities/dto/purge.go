@@ -0,0 +1,8 @@
+package dto
+
+// PurgeOutputDto counts the soft deleted rows a purge removed for good.
+type PurgeOutputDto struct {
+	Courses    int64 `json:"courses"`
+	Categories int64 `json:"categories"`
+	Users      int64 `json:"users"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+// SearchResultOutputDto is a course or category matching a search. Snippet
+// holds part of its name or description with the matched words marked;
+// Score only orders the results of one search.
+type SearchResultOutputDto struct {
+	EntityType string  `json:"entity_type"`
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Snippet    string  `json:"snippet"`
+	Score      float64 `json:"score"`
+}
+
+type SearchOutputDto struct {
+	Results    []SearchResultOutputDto `json:"results"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
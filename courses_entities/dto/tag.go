@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type TagInputDto struct {
+	Name string `json:"name"`
+}
+
+// CourseTagInputDto tags a course by the name of the tag, which is created
+// when new.
+type CourseTagInputDto struct {
+	CourseID string `json:"course_id"`
+	Name     string `json:"name"`
+}
+
+// TagOutputDto is a tag with the number of live courses carrying it.
+type TagOutputDto struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Courses   int64     `json:"courses"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+}
+
+type TagListOutputDto struct {
+	Tags       []TagOutputDto `json:"tags"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
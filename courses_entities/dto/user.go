@@ -1,10 +1,13 @@
 package dto
 
+import "time"
+
 type UserInputDto struct {
 	ID       string `json:"id"`
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	Version  int64  `json:"version,omitempty"`
 }
 
 type GetJWTInput struct {
@@ -17,11 +20,18 @@ type AccessToken struct {
 }
 
 type UserOutputDto struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	CreatedBy string     `json:"created_by"`
+	UpdatedBy string     `json:"updated_by"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type UserListOutputDto struct {
-	Users []UserOutputDto `json:"users"`
+	Users      []UserOutputDto `json:"users"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
@@ -1,9 +1,18 @@
 package entity
 
+import "time"
+
+// Category groups courses. Categories form a tree: ParentID is the category
+// this one sits under, empty for a top-level category.
 type Category struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ParentID    string    `json:"parent_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   string    `json:"updated_by"`
 }
 
 func NewCategory(id string, name, description string) *Category {
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// ContentType tells what the body of a lesson holds.
+type ContentType string
+
+const (
+	ContentText  ContentType = "text"
+	ContentVideo ContentType = "video"
+	ContentAudio ContentType = "audio"
+	ContentQuiz  ContentType = "quiz"
+)
+
+// ContentTypes lists the valid content types.
+var ContentTypes = []ContentType{ContentText, ContentVideo, ContentAudio, ContentQuiz}
+
+func (t ContentType) Valid() bool {
+	for _, valid := range ContentTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Lesson is a part of a module; Position orders the lessons of a module,
+// starting at 1. Body is the text of the lesson, or where its video, audio
+// or quiz is.
+type Lesson struct {
+	ID          string        `json:"id"`
+	ModuleID    string        `json:"module_id"`
+	Title       string        `json:"title"`
+	ContentType ContentType   `json:"content_type"`
+	Duration    time.Duration `json:"duration"`
+	Body        string        `json:"body"`
+	Position    int           `json:"position"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	CreatedBy   string        `json:"created_by"`
+	UpdatedBy   string        `json:"updated_by"`
+}
+
+func NewLesson(id string, moduleID string, title string, contentType ContentType, duration time.Duration, body string) *Lesson {
+	return &Lesson{
+		ID:          id,
+		ModuleID:    moduleID,
+		Title:       title,
+		ContentType: contentType,
+		Duration:    duration,
+		Body:        body,
+	}
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewLesson(t *testing.T) {
+	type args struct {
+		id          string
+		moduleID    string
+		title       string
+		contentType ContentType
+		duration    time.Duration
+		body        string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Lesson
+	}{
+		{
+			name: "test",
+			args: args{
+				id:          "1",
+				moduleID:    "1",
+				title:       "test",
+				contentType: ContentVideo,
+				duration:    5 * time.Minute,
+				body:        "https://example.com/test.mp4",
+			},
+			want: &Lesson{
+				ID:          "1",
+				ModuleID:    "1",
+				Title:       "test",
+				ContentType: ContentVideo,
+				Duration:    5 * time.Minute,
+				Body:        "https://example.com/test.mp4",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewLesson(tt.args.id, tt.args.moduleID, tt.args.title, tt.args.contentType, tt.args.duration, tt.args.body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewLesson() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentTypeValid(t *testing.T) {
+	tests := []struct {
+		contentType ContentType
+		want        bool
+	}{
+		{ContentText, true},
+		{ContentVideo, true},
+		{ContentAudio, true},
+		{ContentQuiz, true},
+		{"", false},
+		{"Video", false},
+		{"slides", false},
+	}
+	for _, tt := range tests {
+		if got := tt.contentType.Valid(); got != tt.want {
+			t.Errorf("ContentType(%q).Valid() = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Module is a part of a course; Position orders the modules of a course,
+// starting at 1.
+type Module struct {
+	ID          string    `json:"id"`
+	CourseID    string    `json:"course_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	CreatedBy   string    `json:"created_by"`
+	UpdatedBy   string    `json:"updated_by"`
+}
+
+func NewModule(id string, courseID string, title string, description string) *Module {
+	return &Module{
+		ID:          id,
+		CourseID:    courseID,
+		Title:       title,
+		Description: description,
+	}
+}
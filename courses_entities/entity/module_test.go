@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewModule(t *testing.T) {
+	type args struct {
+		id          string
+		courseID    string
+		title       string
+		description string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Module
+	}{
+		{
+			name: "test",
+			args: args{
+				id:          "1",
+				courseID:    "1",
+				title:       "test",
+				description: "test",
+			},
+			want: &Module{
+				ID:          "1",
+				CourseID:    "1",
+				Title:       "test",
+				Description: "test",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewModule(tt.args.id, tt.args.courseID, tt.args.title, tt.args.description); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewModule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
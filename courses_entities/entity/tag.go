@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxTagName is the longest tag name, in bytes.
+const MaxTagName = 100
+
+// Tag labels courses across categories, as "beginner" or "cloud". A course
+// carries any number of tags. Names are unique once normalized, see
+// NormalizeTagName.
+type Tag struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+}
+
+func NewTag(id string, name string) *Tag {
+	return &Tag{
+		ID:   id,
+		Name: NormalizeTagName(name),
+	}
+}
+
+// NormalizeTagName lowercases a tag name and reduces its spaces to single
+// ones between words, so that "Cloud " and "cloud" are the same tag.
+func NormalizeTagName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
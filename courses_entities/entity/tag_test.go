@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewTag(t *testing.T) {
+	type args struct {
+		id   string
+		name string
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Tag
+	}{
+		{
+			name: "test",
+			args: args{
+				id:   "1",
+				name: " Machine  Learning ",
+			},
+			want: &Tag{
+				ID:   "1",
+				Name: "machine learning",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTag(tt.args.id, tt.args.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTagName(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{name: "plain", arg: "cloud", want: "cloud"},
+		{name: "upper case", arg: "Cloud", want: "cloud"},
+		{name: "spaces", arg: "  web \t design ", want: "web design"},
+		{name: "blank", arg: "   ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTagName(tt.arg); got != tt.want {
+				t.Errorf("NormalizeTagName(%q) = %q, want %q", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/antoniofmoliveira/courses/db/database"
 	"github.com/antoniofmoliveira/courses/flatbuffersapi/internal/configs"
 	"github.com/antoniofmoliveira/courses/flatbuffersapi/internal/handlers"
+	"github.com/go-chi/jwtauth"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -48,6 +49,10 @@ func main() {
 	categoryHandler := handlers.NewCategoryHandler(categoryRepository)
 	courseHandler := handlers.NewCourseHandler(courseRepository)
 	userHandler := handlers.NewUserHandler(userRepository)
+	moduleHandler := handlers.NewModuleHandler(dbi.ModuleRepository)
+	lessonHandler := handlers.NewLessonHandler(dbi.LessonRepository)
+	instructorHandler := handlers.NewInstructorHandler(courseRepository, dbi.InstructorRepository)
+	tagHandler := handlers.NewTagHandler(dbi.TagRepository)
 
 	r.HandleFunc("GET /categories", categoryHandler.FindAllCategories)
 	r.HandleFunc("GET /categories/{id}", categoryHandler.FindCategory)
@@ -58,23 +63,53 @@ func main() {
 	r.HandleFunc("GET /courses", courseHandler.FindAllCourses)
 	r.HandleFunc("GET /courses/{id}", courseHandler.FindCourse)
 	r.HandleFunc("POST /courses", courseHandler.CreateCourse)
+	r.HandleFunc("POST /courses:batch", courseHandler.BatchCreateCourses)
 	r.HandleFunc("PUT /courses/{id}", courseHandler.UpdateCourse)
 	r.HandleFunc("DELETE /courses/{id}", courseHandler.DeleteCourse)
 
+	r.HandleFunc("GET /courses/{id}/modules", moduleHandler.FindModules)
+	r.HandleFunc("POST /courses/{id}/modules", moduleHandler.CreateModule)
+	r.HandleFunc("PUT /courses/{id}/modules:order", moduleHandler.ReorderModules)
+	r.HandleFunc("GET /modules/{id}", moduleHandler.FindModule)
+	r.HandleFunc("PUT /modules/{id}", moduleHandler.UpdateModule)
+	r.HandleFunc("DELETE /modules/{id}", moduleHandler.DeleteModule)
+
+	r.HandleFunc("GET /courses/{id}/instructors", instructorHandler.FindInstructors)
+	r.HandleFunc("POST /courses/{id}/instructors", instructorHandler.AddInstructor)
+	r.HandleFunc("DELETE /courses/{id}/instructors/{userID}", instructorHandler.RemoveInstructor)
+
+	r.HandleFunc("GET /courses/{id}/tags", tagHandler.FindCourseTags)
+	r.HandleFunc("POST /courses/{id}/tags", tagHandler.TagCourse)
+	r.HandleFunc("DELETE /courses/{id}/tags/{tagID}", tagHandler.UntagCourse)
+	r.HandleFunc("GET /tags", tagHandler.FindTags)
+	r.HandleFunc("POST /tags", tagHandler.CreateTag)
+	r.HandleFunc("GET /tags/{id}", tagHandler.FindTag)
+	r.HandleFunc("DELETE /tags/{id}", tagHandler.DeleteTag)
+
+	r.HandleFunc("GET /modules/{id}/lessons", lessonHandler.FindLessons)
+	r.HandleFunc("POST /modules/{id}/lessons", lessonHandler.CreateLesson)
+	r.HandleFunc("PUT /modules/{id}/lessons:order", lessonHandler.ReorderLessons)
+	r.HandleFunc("GET /lessons/{id}", lessonHandler.FindLesson)
+	r.HandleFunc("PUT /lessons/{id}", lessonHandler.UpdateLesson)
+	r.HandleFunc("DELETE /lessons/{id}", lessonHandler.DeleteLesson)
+
 	r.HandleFunc("GET /users", userHandler.FindAllUsers)
 	r.HandleFunc("GET /users/{id}", userHandler.FindUser)
 	r.HandleFunc("POST /users", userHandler.CreateUser)
 	r.HandleFunc("PUT /users/{id}", userHandler.UpdateUser)
 	r.HandleFunc("DELETE /users/{id}", userHandler.DeleteUser)
+	r.HandleFunc("GET /users/{id}/courses", instructorHandler.FindTaughtCourses)
 
 	r.HandleFunc("GET /jwt", userHandler.GetJWT)
 
 	// TODO! only for test - REMOVE! in production
 	r.HandleFunc("GET /categorieserror", categoryHandler.CategoriesError)
 
+	// a token is optional, but only instructors and admins may change a
+	// course
 	server := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.WebServerPort),
-		Handler: r,
+		Handler: jwtauth.Verifier(cfg.TokenAuth)(handlers.Actor(r)),
 	}
 
 	go func() {
@@ -61,8 +61,16 @@ func (rcv *Categories) ElementsLength() int {
 	return 0
 }
 
+func (rcv *Categories) NextCursor() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
 func CategoriesStart(builder *flatbuffers.Builder) {
-	builder.StartObject(1)
+	builder.StartObject(2)
 }
 func CategoriesAddElements(builder *flatbuffers.Builder, elements flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(elements), 0)
@@ -70,6 +78,9 @@ func CategoriesAddElements(builder *flatbuffers.Builder, elements flatbuffers.UO
 func CategoriesStartElementsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(4, numElems, 4)
 }
+func CategoriesAddNextCursor(builder *flatbuffers.Builder, nextCursor flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(nextCursor), 0)
+}
 func CategoriesEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
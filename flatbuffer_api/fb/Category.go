@@ -65,8 +65,16 @@ func (rcv *Category) Description() []byte {
 	return nil
 }
 
+func (rcv *Category) ParentId() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
 func CategoryStart(builder *flatbuffers.Builder) {
-	builder.StartObject(3)
+	builder.StartObject(4)
 }
 func CategoryAddId(builder *flatbuffers.Builder, id flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(id), 0)
@@ -77,6 +85,9 @@ func CategoryAddName(builder *flatbuffers.Builder, name flatbuffers.UOffsetT) {
 func CategoryAddDescription(builder *flatbuffers.Builder, description flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(description), 0)
 }
+func CategoryAddParentId(builder *flatbuffers.Builder, parentId flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(parentId), 0)
+}
 func CategoryEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -0,0 +1,108 @@
+// Code generated by the FlatBuffers compiler. DO NOT EDIT.
+
+package fb
+
+import (
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type Instructor struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsInstructor(buf []byte, offset flatbuffers.UOffsetT) *Instructor {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &Instructor{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func FinishInstructorBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.Finish(offset)
+}
+
+func GetSizePrefixedRootAsInstructor(buf []byte, offset flatbuffers.UOffsetT) *Instructor {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &Instructor{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func FinishSizePrefixedInstructorBuffer(builder *flatbuffers.Builder, offset flatbuffers.UOffsetT) {
+	builder.FinishSizePrefixed(offset)
+}
+
+func (rcv *Instructor) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *Instructor) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *Instructor) CourseId() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *Instructor) UserId() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *Instructor) Name() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *Instructor) Email() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *Instructor) AddedAt() int64 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(12))
+	if o != 0 {
+		return rcv._tab.GetInt64(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *Instructor) MutateAddedAt(n int64) bool {
+	return rcv._tab.MutateInt64Slot(12, n)
+}
+
+func InstructorStart(builder *flatbuffers.Builder) {
+	builder.StartObject(5)
+}
+func InstructorAddCourseId(builder *flatbuffers.Builder, courseId flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(courseId), 0)
+}
+func InstructorAddUserId(builder *flatbuffers.Builder, userId flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(userId), 0)
+}
+func InstructorAddName(builder *flatbuffers.Builder, name flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(name), 0)
+}
+func InstructorAddEmail(builder *flatbuffers.Builder, email flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(email), 0)
+}
+func InstructorAddAddedAt(builder *flatbuffers.Builder, addedAt int64) {
+	builder.PrependInt64Slot(4, addedAt, 0)
+}
+func InstructorEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
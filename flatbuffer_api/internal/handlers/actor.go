@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/go-chi/jwtauth"
+)
+
+// Actor attributes the changes a request makes to the subject of its JWT,
+// when jwtauth.Verifier found a valid one. Requests without one stay
+// anonymous, and so may not change courses.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _, err := jwtauth.FromContext(r.Context())
+		if err == nil && token != nil {
+			r = r.WithContext(audit.WithActor(r.Context(), token.Subject()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
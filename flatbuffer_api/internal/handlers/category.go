@@ -57,15 +57,17 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 		ID:          string(fbCategory.Id()),
 		Name:        string(fbCategory.Name()),
 		Description: string(fbCategory.Description()),
+		ParentID:    string(fbCategory.ParentId()),
 	}
 
-	categoryOutputDto, err := h.CategoryRepository.Create(categoryInputDto)
+	categoryOutputDto, err := h.CategoryRepository.Create(r.Context(), categoryInputDto)
 	if err != nil {
 		slog.Error("createCategory", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
+	w.Header().Set("ETag", etag(categoryOutputDto.Version))
 	w.WriteHeader(http.StatusCreated)
 	buf := categoryAsBytes(&categoryOutputDto)
 	w.Write(*buf)
@@ -109,12 +111,21 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 		ID:          string(fbCategory.Id()),
 		Name:        string(fbCategory.Name()),
 		Description: string(fbCategory.Description()),
+		ParentID:    string(fbCategory.ParentId()),
 	}
 
-	err = h.CategoryRepository.Update(categoryInputDto)
+	version, err := ifMatch(r)
 	if err != nil {
 		slog.Error("updateCategory", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	categoryInputDto.Version = version
+
+	err = h.CategoryRepository.Update(r.Context(), categoryInputDto)
+	if err != nil {
+		slog.Error("updateCategory", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), updateStatus(err, version != 0))
 		return
 	}
 
@@ -132,10 +143,17 @@ func (h *CategoryHandler) FindAllCategories(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	categories, err := h.CategoryRepository.FindAll()
+	spec, err := categorySpecFromRequest(r)
+	if err != nil {
+		slog.Error("FindAllCategories", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categories, err := h.CategoryRepository.List(r.Context(), spec)
 	if err != nil {
 		slog.Error("FindAllCategories", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -148,9 +166,11 @@ func (h *CategoryHandler) FindAllCategories(w http.ResponseWriter, r *http.Reque
 		fbBuilder.PrependUOffsetT((*elements)[i])
 	}
 	vec := fbBuilder.EndVector(len(*elements))
+	nextCursor := fbBuilder.CreateString(categories.NextCursor)
 
 	fb.CategoriesStart(fbBuilder)
 	fb.CategoriesAddElements(fbBuilder, vec)
+	fb.CategoriesAddNextCursor(fbBuilder, nextCursor)
 	fbCategoriesOutput := fb.CategoriesEnd(fbBuilder)
 	fbBuilder.Finish(fbCategoriesOutput)
 
@@ -166,10 +186,12 @@ func categoriesAsFlatBufferVector(fbBuilder *flatbuffers.Builder, categories *[]
 		id := fbBuilder.CreateString(category.ID)
 		name := fbBuilder.CreateString(category.Name)
 		description := fbBuilder.CreateString(category.Description)
+		parentID := fbBuilder.CreateString(category.ParentID)
 		fb.CategoryStart(fbBuilder)
 		fb.CategoryAddId(fbBuilder, id)
 		fb.CategoryAddName(fbBuilder, name)
 		fb.CategoryAddDescription(fbBuilder, description)
+		fb.CategoryAddParentId(fbBuilder, parentID)
 		fbCategory := fb.CategoryEnd(fbBuilder)
 		elements = append(elements, fbCategory)
 	}
@@ -187,13 +209,14 @@ func (h *CategoryHandler) FindCategory(w http.ResponseWriter, r *http.Request) {
 
 	id := r.PathValue("id")
 
-	category, err := h.CategoryRepository.Find(id)
+	category, err := h.CategoryRepository.Find(r.Context(), id)
 	if err != nil {
 		slog.Error("FindCategory", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
+	w.Header().Set("ETag", etag(category.Version))
 	w.WriteHeader(http.StatusOK)
 	buf := categoryAsBytes(&category)
 	w.Write(*buf)
@@ -206,10 +229,12 @@ func categoryAsBytes(category *dto.CategoryOutputDto) *[]byte {
 	id := bb.CreateString(category.ID)
 	name := bb.CreateString(category.Name)
 	description := bb.CreateString(category.Description)
+	parentID := bb.CreateString(category.ParentID)
 	fb.CategoryStart(bb)
 	fb.CategoryAddId(bb, id)
 	fb.CategoryAddName(bb, name)
 	fb.CategoryAddDescription(bb, description)
+	fb.CategoryAddParentId(bb, parentID)
 	fbCategoryOutput := fb.CategoryEnd(bb)
 	bb.Finish(fbCategoryOutput)
 	buf := bb.FinishedBytes()
@@ -227,10 +252,10 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 
 	id := r.PathValue("id")
 
-	err := h.CategoryRepository.Delete(id)
+	err := h.CategoryRepository.Delete(r.Context(), id)
 	if err != nil {
 		slog.Error("DeleteCategory", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -32,13 +33,14 @@ func (c *CourseHandler) FindCourse(w http.ResponseWriter, r *http.Request) {
 
 	id := r.PathValue("id")
 
-	course, err := c.CourseRepository.Find(id)
+	course, err := c.CourseRepository.Find(r.Context(), id)
 	if err != nil {
 		slog.Error("FindCourse", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
+	w.Header().Set("ETag", etag(course.Version))
 	w.WriteHeader(http.StatusOK)
 	buf := courseAsBytes(&course)
 	w.Write(*buf)
@@ -72,10 +74,17 @@ func (c *CourseHandler) FindAllCourses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	courses, err := c.CourseRepository.FindAll()
+	spec, err := courseSpecFromRequest(r)
 	if err != nil {
 		slog.Error("FindAllCourses", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	courses, err := c.CourseRepository.List(r.Context(), spec)
+	if err != nil {
+		slog.Error("FindAllCourses", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -88,9 +97,11 @@ func (c *CourseHandler) FindAllCourses(w http.ResponseWriter, r *http.Request) {
 		fbuilder.PrependUOffsetT((*elements)[i])
 	}
 	vec := fbuilder.EndVector(len(*elements))
+	nextCursor := fbuilder.CreateString(courses.NextCursor)
 
 	fb.CoursesStart(fbuilder)
 	fb.CoursesAddElements(fbuilder, vec)
+	fb.CoursesAddNextCursor(fbuilder, nextCursor)
 	fbCoursesOutput := fb.CoursesEnd(fbuilder)
 	fbuilder.Finish(fbCoursesOutput)
 
@@ -157,13 +168,14 @@ func (c *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
 		CategoryID:  string(fbCourse.CategoryId()),
 	}
 
-	course, err := c.CourseRepository.Create(courseInputDto)
+	course, err := c.CourseRepository.Create(r.Context(), courseInputDto)
 	if err != nil {
 		slog.Error("createCourse", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
+	w.Header().Set("ETag", etag(course.Version))
 	w.WriteHeader(http.StatusOK)
 	buf := courseAsBytes(course)
 	w.Write(*buf)
@@ -171,6 +183,106 @@ func (c *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
 	slog.Info("createCourse", "msg", "course created", "id", course.ID)
 }
 
+// maxBatchItems bounds the courses of one batch upload, all created in one
+// transaction.
+const maxBatchItems = 1000
+
+// BatchCreateCourses creates the elements of a Courses vector in one
+// transaction and answers with a BatchResults holding, for each course in
+// order, its id or why it was rejected.
+func (c *CourseHandler) BatchCreateCourses(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("batchCreateCourses", "msg", "unexpected payload")
+			slog.Error("batchCreateCourses", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("batchCreateCourses", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("batchCreateCourses", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("batchCreateCourses", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	fbCourses := fb.GetRootAsCourses(body, 0)
+	if fbCourses.ElementsLength() > maxBatchItems {
+		slog.Error("batchCreateCourses", "msg", "too many courses", "count", fbCourses.ElementsLength())
+		sendFlatBufferMessage(w, fmt.Sprintf("a batch holds at most %d courses", maxBatchItems), http.StatusBadRequest)
+		return
+	}
+	courses := make([]dto.CourseInputDto, fbCourses.ElementsLength())
+	var fbCourse fb.Course
+	for i := range courses {
+		fbCourses.Elements(&fbCourse, i)
+		courses[i] = dto.CourseInputDto{
+			Name:        string(fbCourse.Name()),
+			Description: string(fbCourse.Description()),
+			CategoryID:  string(fbCourse.CategoryId()),
+		}
+	}
+
+	results, err := c.CourseRepository.CreateBatch(r.Context(), courses)
+	if err != nil {
+		slog.Error("batchCreateCourses", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(batchResultsAsBytes(results, http.StatusCreated))
+
+	slog.Info("batchCreateCourses", "msg", "courses created", "count", len(results))
+}
+
+// batchResultsAsBytes builds the BatchResults of a batch; okStatus is the
+// status of the items applied.
+func batchResultsAsBytes(results []dto.BatchItemOutputDto, okStatus int) []byte {
+	fbuilder := flatbuffers.NewBuilder(0)
+	elements := make([]flatbuffers.UOffsetT, 0, len(results))
+	for _, result := range results {
+		status, message := okStatus, ""
+		if result.Err != nil {
+			status, message = errorStatus(result.Err), result.Err.Error()
+		}
+		id := fbuilder.CreateString(result.ID)
+		fbError := fbuilder.CreateString(message)
+		fb.BatchResultStart(fbuilder)
+		fb.BatchResultAddIndex(fbuilder, int32(result.Index))
+		fb.BatchResultAddId(fbuilder, id)
+		fb.BatchResultAddStatus(fbuilder, int32(status))
+		fb.BatchResultAddError(fbuilder, fbError)
+		elements = append(elements, fb.BatchResultEnd(fbuilder))
+	}
+
+	fb.BatchResultsStartElementsVector(fbuilder, len(elements))
+	for i := len(elements) - 1; i >= 0; i-- {
+		fbuilder.PrependUOffsetT(elements[i])
+	}
+	vec := fbuilder.EndVector(len(elements))
+
+	fb.BatchResultsStart(fbuilder)
+	fb.BatchResultsAddElements(fbuilder, vec)
+	fbuilder.Finish(fb.BatchResultsEnd(fbuilder))
+	return fbuilder.FinishedBytes()
+}
+
 func (c *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", octetStream)
 
@@ -210,10 +322,18 @@ func (c *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
 		CategoryID:  string(fbCourse.CategoryId()),
 	}
 
-	err = c.CourseRepository.Update(courseInputDto)
+	version, err := ifMatch(r)
+	if err != nil {
+		slog.Error("updateCourse", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	courseInputDto.Version = version
+
+	err = c.CourseRepository.Update(r.Context(), courseInputDto)
 	if err != nil {
 		slog.Error("updateCourse", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), updateStatus(err, version != 0))
 		return
 	}
 
@@ -233,10 +353,10 @@ func (c *CourseHandler) DeleteCourse(w http.ResponseWriter, r *http.Request) {
 
 	id := r.PathValue("id")	
 
-	err := c.CourseRepository.Delete(id)
+	err := c.CourseRepository.Delete(r.Context(), id)
 	if err != nil {
 		slog.Error("DeleteCourse", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 	
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/flatbuffersapi/fb"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type InstructorHandler struct {
+	CourseRepository     database.CourseRepositoryInterface
+	InstructorRepository database.InstructorRepositoryInterface
+}
+
+func NewInstructorHandler(courseRepository database.CourseRepositoryInterface, instructorRepository database.InstructorRepositoryInterface) *InstructorHandler {
+	return &InstructorHandler{
+		CourseRepository:     courseRepository,
+		InstructorRepository: instructorRepository,
+	}
+}
+
+func instructorAsFlatBuffer(fbuilder *flatbuffers.Builder, instructor *dto.InstructorOutputDto) flatbuffers.UOffsetT {
+	courseID := fbuilder.CreateString(instructor.CourseID)
+	userID := fbuilder.CreateString(instructor.UserID)
+	name := fbuilder.CreateString(instructor.Name)
+	email := fbuilder.CreateString(instructor.Email)
+	fb.InstructorStart(fbuilder)
+	fb.InstructorAddCourseId(fbuilder, courseID)
+	fb.InstructorAddUserId(fbuilder, userID)
+	fb.InstructorAddName(fbuilder, name)
+	fb.InstructorAddEmail(fbuilder, email)
+	fb.InstructorAddAddedAt(fbuilder, instructor.AddedAt.Unix())
+	return fb.InstructorEnd(fbuilder)
+}
+
+// FindInstructors lists the instructors of a course, ordered by user id.
+func (i *InstructorHandler) FindInstructors(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindInstructors", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	courseID := r.PathValue("id")
+
+	instructors, err := i.InstructorRepository.FindByCourseID(r.Context(), courseID)
+	if err != nil {
+		slog.Error("FindInstructors", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	elements := make([]flatbuffers.UOffsetT, 0, len(instructors.Instructors))
+	for _, instructor := range instructors.Instructors {
+		elements = append(elements, instructorAsFlatBuffer(fbuilder, &instructor))
+	}
+
+	fb.InstructorsStartElementsVector(fbuilder, len(elements))
+	for i := len(elements) - 1; i >= 0; i-- {
+		fbuilder.PrependUOffsetT(elements[i])
+	}
+	vec := fbuilder.EndVector(len(elements))
+
+	fb.InstructorsStart(fbuilder)
+	fb.InstructorsAddElements(fbuilder, vec)
+	fbuilder.Finish(fb.InstructorsEnd(fbuilder))
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("FindInstructors", "msg", "instructors found", "course", courseID, "count", len(instructors.Instructors))
+}
+
+// AddInstructor makes the user_id of an Instructor an instructor of the
+// course of the path.
+func (i *InstructorHandler) AddInstructor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("addInstructor", "msg", "unexpected payload")
+			slog.Error("addInstructor", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("addInstructor", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("addInstructor", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("addInstructor", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	fbInstructor := fb.GetRootAsInstructor(body, 0)
+	instructorInputDto := dto.InstructorInputDto{
+		CourseID: r.PathValue("id"),
+		UserID:   string(fbInstructor.UserId()),
+	}
+
+	instructor, err := i.InstructorRepository.Add(r.Context(), instructorInputDto)
+	if err != nil {
+		slog.Error("addInstructor", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(instructorAsFlatBuffer(fbuilder, &instructor))
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("addInstructor", "msg", "instructor added", "course", instructor.CourseID, "user", instructor.UserID)
+}
+
+// RemoveInstructor answers 409 Conflict rather than leave a course without
+// instructors.
+func (i *InstructorHandler) RemoveInstructor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("RemoveInstructor", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	courseID, userID := r.PathValue("id"), r.PathValue("userID")
+
+	err := i.InstructorRepository.Remove(r.Context(), courseID, userID)
+	if err != nil {
+		slog.Error("RemoveInstructor", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	sendFlatBufferMessage(w, "instructor removed", http.StatusOK)
+
+	slog.Info("RemoveInstructor", "msg", "instructor removed", "course", courseID, "user", userID)
+}
+
+// FindTaughtCourses lists the courses the user of the path teaches, a page at
+// a time.
+func (i *InstructorHandler) FindTaughtCourses(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindTaughtCourses", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		slog.Error("FindTaughtCourses", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.PathValue("id")
+
+	courses, err := i.CourseRepository.FindByInstructorID(r.Context(), userID, page)
+	if err != nil {
+		slog.Error("FindTaughtCourses", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+
+	elements := coursesAsFlatBufferVector(fbuilder, &courses.Courses)
+
+	fb.CoursesStartElementsVector(fbuilder, len(*elements))
+	for i := len(*elements) - 1; i >= 0; i-- {
+		fbuilder.PrependUOffsetT((*elements)[i])
+	}
+	vec := fbuilder.EndVector(len(*elements))
+	nextCursor := fbuilder.CreateString(courses.NextCursor)
+
+	fb.CoursesStart(fbuilder)
+	fb.CoursesAddElements(fbuilder, vec)
+	fb.CoursesAddNextCursor(fbuilder, nextCursor)
+	fbuilder.Finish(fb.CoursesEnd(fbuilder))
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("FindTaughtCourses", "msg", "courses found", "user", userID, "count", len(courses.Courses))
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/flatbuffersapi/fb"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type LessonHandler struct {
+	LessonRepository database.LessonRepositoryInterface
+}
+
+func NewLessonHandler(lessonRepository database.LessonRepositoryInterface) *LessonHandler {
+	return &LessonHandler{
+		LessonRepository: lessonRepository,
+	}
+}
+
+func lessonAsFlatBuffer(fbuilder *flatbuffers.Builder, lesson *dto.LessonOutputDto) flatbuffers.UOffsetT {
+	id := fbuilder.CreateString(lesson.ID)
+	moduleID := fbuilder.CreateString(lesson.ModuleID)
+	title := fbuilder.CreateString(lesson.Title)
+	contentType := fbuilder.CreateString(lesson.ContentType)
+	body := fbuilder.CreateString(lesson.Body)
+	fb.LessonStart(fbuilder)
+	fb.LessonAddId(fbuilder, id)
+	fb.LessonAddModuleId(fbuilder, moduleID)
+	fb.LessonAddTitle(fbuilder, title)
+	fb.LessonAddContentType(fbuilder, contentType)
+	fb.LessonAddDurationSeconds(fbuilder, lesson.DurationSeconds)
+	fb.LessonAddBody(fbuilder, body)
+	fb.LessonAddPosition(fbuilder, int32(lesson.Position))
+	return fb.LessonEnd(fbuilder)
+}
+
+func lessonAsBytes(lesson *dto.LessonOutputDto) []byte {
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(lessonAsFlatBuffer(fbuilder, lesson))
+	return fbuilder.FinishedBytes()
+}
+
+// FindLessons lists the lessons of a module in order.
+func (l *LessonHandler) FindLessons(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindLessons", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	moduleID := r.PathValue("id")
+
+	lessons, err := l.LessonRepository.FindByModuleID(r.Context(), moduleID)
+	if err != nil {
+		slog.Error("FindLessons", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	elements := make([]flatbuffers.UOffsetT, 0, len(lessons.Lessons))
+	for _, lesson := range lessons.Lessons {
+		elements = append(elements, lessonAsFlatBuffer(fbuilder, &lesson))
+	}
+
+	fb.LessonsStartElementsVector(fbuilder, len(elements))
+	for i := len(elements) - 1; i >= 0; i-- {
+		fbuilder.PrependUOffsetT(elements[i])
+	}
+	vec := fbuilder.EndVector(len(elements))
+
+	fb.LessonsStart(fbuilder)
+	fb.LessonsAddElements(fbuilder, vec)
+	fbuilder.Finish(fb.LessonsEnd(fbuilder))
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("FindLessons", "msg", "lessons found", "module", moduleID, "count", len(lessons.Lessons))
+}
+
+func (l *LessonHandler) FindLesson(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindLesson", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	id := r.PathValue("id")
+
+	lesson, err := l.LessonRepository.Find(r.Context(), id)
+	if err != nil {
+		slog.Error("FindLesson", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("ETag", etag(lesson.Version))
+	w.WriteHeader(http.StatusOK)
+	w.Write(lessonAsBytes(&lesson))
+
+	slog.Info("FindLesson", "msg", "lesson found", "id", id)
+}
+
+// CreateLesson adds a lesson at the end of the module of the path.
+func (l *LessonHandler) CreateLesson(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("createLesson", "msg", "unexpected payload")
+			slog.Error("createLesson", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("createLesson", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("createLesson", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("createLesson", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	fbLesson := fb.GetRootAsLesson(body, 0)
+	lessonInputDto := dto.LessonInputDto{
+		ModuleID:        r.PathValue("id"),
+		Title:           string(fbLesson.Title()),
+		ContentType:     string(fbLesson.ContentType()),
+		DurationSeconds: fbLesson.DurationSeconds(),
+		Body:            string(fbLesson.Body()),
+	}
+
+	lesson, err := l.LessonRepository.Create(r.Context(), lessonInputDto)
+	if err != nil {
+		slog.Error("createLesson", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("ETag", etag(lesson.Version))
+	w.WriteHeader(http.StatusOK)
+	w.Write(lessonAsBytes(&lesson))
+
+	slog.Info("createLesson", "msg", "lesson created", "id", lesson.ID)
+}
+
+func (l *LessonHandler) UpdateLesson(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("updateLesson", "msg", "unexpected payload")
+			slog.Error("updateLesson", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("updateLesson", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("updateLesson", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("updateLesson", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	fbLesson := fb.GetRootAsLesson(body, 0)
+	lessonInputDto := dto.LessonInputDto{
+		ID:              r.PathValue("id"),
+		Title:           string(fbLesson.Title()),
+		ContentType:     string(fbLesson.ContentType()),
+		DurationSeconds: fbLesson.DurationSeconds(),
+		Body:            string(fbLesson.Body()),
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		slog.Error("updateLesson", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lessonInputDto.Version = version
+
+	err = l.LessonRepository.Update(r.Context(), lessonInputDto)
+	if err != nil {
+		slog.Error("updateLesson", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), updateStatus(err, version != 0))
+		return
+	}
+
+	sendFlatBufferMessage(w, "lesson updated", http.StatusOK)
+
+	slog.Info("updateLesson", "msg", "lesson updated", "id", lessonInputDto.ID)
+}
+
+func (l *LessonHandler) DeleteLesson(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("DeleteLesson", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	id := r.PathValue("id")
+
+	err := l.LessonRepository.Delete(r.Context(), id)
+	if err != nil {
+		slog.Error("DeleteLesson", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	sendFlatBufferMessage(w, "lesson deleted", http.StatusOK)
+
+	slog.Info("DeleteLesson", "msg", "lesson deleted", "id", id)
+}
+
+// ReorderLessons puts the lessons of the module of the path in the order of
+// an Order.
+func (l *LessonHandler) ReorderLessons(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("reorderLessons", "msg", "unexpected payload")
+			slog.Error("reorderLessons", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("reorderLessons", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("reorderLessons", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("reorderLessons", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	moduleID := r.PathValue("id")
+
+	err = l.LessonRepository.Reorder(r.Context(), moduleID, readOrder(body))
+	if err != nil {
+		slog.Error("reorderLessons", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	sendFlatBufferMessage(w, "lessons reordered", http.StatusOK)
+
+	slog.Info("reorderLessons", "msg", "lessons reordered", "module", moduleID)
+}
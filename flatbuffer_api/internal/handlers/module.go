@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/flatbuffersapi/fb"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type ModuleHandler struct {
+	ModuleRepository database.ModuleRepositoryInterface
+}
+
+func NewModuleHandler(moduleRepository database.ModuleRepositoryInterface) *ModuleHandler {
+	return &ModuleHandler{
+		ModuleRepository: moduleRepository,
+	}
+}
+
+func moduleAsFlatBuffer(fbuilder *flatbuffers.Builder, module *dto.ModuleOutputDto) flatbuffers.UOffsetT {
+	id := fbuilder.CreateString(module.ID)
+	courseID := fbuilder.CreateString(module.CourseID)
+	title := fbuilder.CreateString(module.Title)
+	description := fbuilder.CreateString(module.Description)
+	fb.ModuleStart(fbuilder)
+	fb.ModuleAddId(fbuilder, id)
+	fb.ModuleAddCourseId(fbuilder, courseID)
+	fb.ModuleAddTitle(fbuilder, title)
+	fb.ModuleAddDescription(fbuilder, description)
+	fb.ModuleAddPosition(fbuilder, int32(module.Position))
+	return fb.ModuleEnd(fbuilder)
+}
+
+func moduleAsBytes(module *dto.ModuleOutputDto) []byte {
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(moduleAsFlatBuffer(fbuilder, module))
+	return fbuilder.FinishedBytes()
+}
+
+// readOrder reads the ids of an Order.
+func readOrder(body []byte) []string {
+	fbOrder := fb.GetRootAsOrder(body, 0)
+	ids := make([]string, fbOrder.IdsLength())
+	for i := range ids {
+		ids[i] = string(fbOrder.Ids(i))
+	}
+	return ids
+}
+
+// FindModules lists the modules of a course in order.
+func (m *ModuleHandler) FindModules(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindModules", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	courseID := r.PathValue("id")
+
+	modules, err := m.ModuleRepository.FindByCourseID(r.Context(), courseID)
+	if err != nil {
+		slog.Error("FindModules", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	elements := make([]flatbuffers.UOffsetT, 0, len(modules.Modules))
+	for _, module := range modules.Modules {
+		elements = append(elements, moduleAsFlatBuffer(fbuilder, &module))
+	}
+
+	fb.ModulesStartElementsVector(fbuilder, len(elements))
+	for i := len(elements) - 1; i >= 0; i-- {
+		fbuilder.PrependUOffsetT(elements[i])
+	}
+	vec := fbuilder.EndVector(len(elements))
+
+	fb.ModulesStart(fbuilder)
+	fb.ModulesAddElements(fbuilder, vec)
+	fbuilder.Finish(fb.ModulesEnd(fbuilder))
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("FindModules", "msg", "modules found", "course", courseID, "count", len(modules.Modules))
+}
+
+func (m *ModuleHandler) FindModule(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindModule", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	id := r.PathValue("id")
+
+	module, err := m.ModuleRepository.Find(r.Context(), id)
+	if err != nil {
+		slog.Error("FindModule", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("ETag", etag(module.Version))
+	w.WriteHeader(http.StatusOK)
+	w.Write(moduleAsBytes(&module))
+
+	slog.Info("FindModule", "msg", "module found", "id", id)
+}
+
+// CreateModule adds a module at the end of the course of the path.
+func (m *ModuleHandler) CreateModule(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("createModule", "msg", "unexpected payload")
+			slog.Error("createModule", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("createModule", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("createModule", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("createModule", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	fbModule := fb.GetRootAsModule(body, 0)
+	moduleInputDto := dto.ModuleInputDto{
+		CourseID:    r.PathValue("id"),
+		Title:       string(fbModule.Title()),
+		Description: string(fbModule.Description()),
+	}
+
+	module, err := m.ModuleRepository.Create(r.Context(), moduleInputDto)
+	if err != nil {
+		slog.Error("createModule", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("ETag", etag(module.Version))
+	w.WriteHeader(http.StatusOK)
+	w.Write(moduleAsBytes(&module))
+
+	slog.Info("createModule", "msg", "module created", "id", module.ID)
+}
+
+func (m *ModuleHandler) UpdateModule(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("updateModule", "msg", "unexpected payload")
+			slog.Error("updateModule", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("updateModule", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("updateModule", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("updateModule", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	fbModule := fb.GetRootAsModule(body, 0)
+	moduleInputDto := dto.ModuleInputDto{
+		ID:          r.PathValue("id"),
+		Title:       string(fbModule.Title()),
+		Description: string(fbModule.Description()),
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		slog.Error("updateModule", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	moduleInputDto.Version = version
+
+	err = m.ModuleRepository.Update(r.Context(), moduleInputDto)
+	if err != nil {
+		slog.Error("updateModule", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), updateStatus(err, version != 0))
+		return
+	}
+
+	sendFlatBufferMessage(w, "module updated", http.StatusOK)
+
+	slog.Info("updateModule", "msg", "module updated", "id", moduleInputDto.ID)
+}
+
+// DeleteModule deletes a module with its lessons.
+func (m *ModuleHandler) DeleteModule(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("DeleteModule", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	id := r.PathValue("id")
+
+	err := m.ModuleRepository.Delete(r.Context(), id)
+	if err != nil {
+		slog.Error("DeleteModule", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	sendFlatBufferMessage(w, "module deleted", http.StatusOK)
+
+	slog.Info("DeleteModule", "msg", "module deleted", "id", id)
+}
+
+// ReorderModules puts the modules of the course of the path in the order of
+// an Order.
+func (m *ModuleHandler) ReorderModules(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("reorderModules", "msg", "unexpected payload")
+			slog.Error("reorderModules", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("reorderModules", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("reorderModules", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error("reorderModules", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	courseID := r.PathValue("id")
+
+	err = m.ModuleRepository.Reorder(r.Context(), courseID, readOrder(body))
+	if err != nil {
+		slog.Error("reorderModules", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	sendFlatBufferMessage(w, "modules reordered", http.StatusOK)
+
+	slog.Info("reorderModules", "msg", "modules reordered", "course", courseID)
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/flatbuffersapi/fb"
+	flatbuffers "github.com/google/flatbuffers/go"
+)
+
+type TagHandler struct {
+	TagRepository database.TagRepositoryInterface
+}
+
+func NewTagHandler(tagRepository database.TagRepositoryInterface) *TagHandler {
+	return &TagHandler{
+		TagRepository: tagRepository,
+	}
+}
+
+func tagAsFlatBuffer(fbuilder *flatbuffers.Builder, tag *dto.TagOutputDto) flatbuffers.UOffsetT {
+	id := fbuilder.CreateString(tag.ID)
+	name := fbuilder.CreateString(tag.Name)
+	fb.TagStart(fbuilder)
+	fb.TagAddId(fbuilder, id)
+	fb.TagAddName(fbuilder, name)
+	fb.TagAddCourses(fbuilder, tag.Courses)
+	fb.TagAddCreatedAt(fbuilder, tag.CreatedAt.Unix())
+	return fb.TagEnd(fbuilder)
+}
+
+func tagsAsFlatBuffer(fbuilder *flatbuffers.Builder, tags *dto.TagListOutputDto) flatbuffers.UOffsetT {
+	elements := make([]flatbuffers.UOffsetT, 0, len(tags.Tags))
+	for _, tag := range tags.Tags {
+		elements = append(elements, tagAsFlatBuffer(fbuilder, &tag))
+	}
+
+	fb.TagsStartElementsVector(fbuilder, len(elements))
+	for i := len(elements) - 1; i >= 0; i-- {
+		fbuilder.PrependUOffsetT(elements[i])
+	}
+	vec := fbuilder.EndVector(len(elements))
+	nextCursor := fbuilder.CreateString(tags.NextCursor)
+
+	fb.TagsStart(fbuilder)
+	fb.TagsAddElements(fbuilder, vec)
+	fb.TagsAddNextCursor(fbuilder, nextCursor)
+	return fb.TagsEnd(fbuilder)
+}
+
+// readTagName reads the name of the Tag in the body of r. It panics on a
+// body that is not a Tag, which the callers recover from.
+func readTagName(r *http.Request) (string, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	defer r.Body.Close()
+	return string(fb.GetRootAsTag(body, 0).Name()), nil
+}
+
+// FindTags lists the tags by name, a page at a time; ?name_prefix= serves
+// autocompletion.
+func (t *TagHandler) FindTags(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindTags", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		slog.Error("FindTags", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tags, err := t.TagRepository.List(r.Context(), query.TagSpec{NamePrefix: r.URL.Query().Get("name_prefix"), Page: page})
+	if err != nil {
+		slog.Error("FindTags", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(tagsAsFlatBuffer(fbuilder, &tags))
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("FindTags", "msg", "tags found", "count", len(tags.Tags))
+}
+
+func (t *TagHandler) FindTag(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindTag", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	tag, err := t.TagRepository.Find(r.Context(), r.PathValue("id"))
+	if err != nil {
+		slog.Error("FindTag", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(tagAsFlatBuffer(fbuilder, &tag))
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("FindTag", "msg", "tag found", "id", tag.ID)
+}
+
+func (t *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("createTag", "msg", "unexpected payload")
+			slog.Error("createTag", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("createTag", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("createTag", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	name, err := readTagName(r)
+	if err != nil {
+		slog.Error("createTag", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag, err := t.TagRepository.Create(r.Context(), dto.TagInputDto{Name: name})
+	if err != nil {
+		slog.Error("createTag", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(tagAsFlatBuffer(fbuilder, &tag))
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("createTag", "msg", "tag created", "id", tag.ID)
+}
+
+// DeleteTag removes the tag from every course carrying it.
+func (t *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("DeleteTag", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	id := r.PathValue("id")
+
+	err := t.TagRepository.Delete(r.Context(), id)
+	if err != nil {
+		slog.Error("DeleteTag", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	sendFlatBufferMessage(w, "tag deleted", http.StatusOK)
+
+	slog.Info("DeleteTag", "msg", "tag deleted", "id", id)
+}
+
+// FindCourseTags lists the tags of a course, ordered by name.
+func (t *TagHandler) FindCourseTags(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("FindCourseTags", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	courseID := r.PathValue("id")
+
+	tags, err := t.TagRepository.FindByCourseID(r.Context(), courseID)
+	if err != nil {
+		slog.Error("FindCourseTags", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(tagsAsFlatBuffer(fbuilder, &tags))
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("FindCourseTags", "msg", "tags found", "course", courseID, "count", len(tags.Tags))
+}
+
+// TagCourse tags the course of the path with the name of a Tag, creating the
+// tag when no tag has that name yet.
+func (t *TagHandler) TagCourse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Info("tagCourse", "msg", "unexpected payload")
+			slog.Error("tagCourse", "msg", err)
+			sendFlatBufferMessage(w, err.(error).Error(), http.StatusInternalServerError)
+		}
+	}()
+
+	if r.Header.Get("Content-Type") != octetStream {
+		slog.Error("tagCourse", "msg", "invalid content type")
+		sendFlatBufferMessage(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("tagCourse", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	name, err := readTagName(r)
+	if err != nil {
+		slog.Error("tagCourse", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	courseID := r.PathValue("id")
+
+	tag, err := t.TagRepository.Tag(r.Context(), dto.CourseTagInputDto{CourseID: courseID, Name: name})
+	if err != nil {
+		slog.Error("tagCourse", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	fbuilder := flatbuffers.NewBuilder(0)
+	fbuilder.Finish(tagAsFlatBuffer(fbuilder, &tag))
+	w.WriteHeader(http.StatusOK)
+	w.Write(fbuilder.FinishedBytes())
+
+	slog.Info("tagCourse", "msg", "course tagged", "course", courseID, "tag", tag.ID)
+}
+
+func (t *TagHandler) UntagCourse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", octetStream)
+
+	if r.Header.Get("Accept") != octetStream {
+		slog.Error("UntagCourse", "msg", "invalid accept header")
+		sendFlatBufferMessage(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	courseID, tagID := r.PathValue("id"), r.PathValue("tagID")
+
+	err := t.TagRepository.Untag(r.Context(), courseID, tagID)
+	if err != nil {
+		slog.Error("UntagCourse", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	sendFlatBufferMessage(w, "course untagged", http.StatusOK)
+
+	slog.Info("UntagCourse", "msg", "course untagged", "course", courseID, "tag", tagID)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
@@ -35,13 +36,14 @@ func (u *UserHandler) FindUser(w http.ResponseWriter, r *http.Request) {
 
 	id := r.PathValue("id")
 
-	user, err := u.UserRepository.Find(id)
+	user, err := u.UserRepository.Find(r.Context(), id)
 	if err != nil {
 		slog.Error("FindUser", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
+	w.Header().Set("ETag", etag(user.Version))
 	w.WriteHeader(http.StatusOK)
 	buf := userAsBytes(user)
 	w.Write(*buf)
@@ -103,7 +105,7 @@ func (u *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		Password: string(fbUserInput.Password()),
 	}
 
-	_, err = u.UserRepository.FindByEmail(userInputDto.Email)
+	_, err = u.UserRepository.FindByEmail(r.Context(), userInputDto.Email)
 	if err == nil {
 		slog.Error("createUser", "msg", "user already exists")
 		sendFlatBufferMessage(w, "user already exists", http.StatusConflict)
@@ -124,14 +126,15 @@ func (u *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		Password: entityUser.Password,
 	}
 
-	user, err := u.UserRepository.Create(userInputDto)
+	user, err := u.UserRepository.Create(r.Context(), userInputDto)
 
 	if err != nil {
 		slog.Error("createUser", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
+	w.Header().Set("ETag", etag(user.Version))
 	w.WriteHeader(http.StatusCreated)
 	buf := userAsBytes(user)
 	w.Write(*buf)
@@ -180,11 +183,18 @@ func (u *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		Password: entityUser.Password,
 	}
 
-	err = u.UserRepository.Update(userInputDto)
+	version, err := ifMatch(r)
+	if err != nil {
+		slog.Error("UpdateUser", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userInputDto.Version = version
 
+	err = u.UserRepository.Update(r.Context(), userInputDto)
 	if err != nil {
 		slog.Error("UpdateUser", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), updateStatus(err, version != 0))
 		return
 	}
 
@@ -204,10 +214,10 @@ func (u *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	id := r.PathValue("id")
 
-	err := u.UserRepository.Delete(id)
+	err := u.UserRepository.Delete(r.Context(), id)
 	if err != nil {
 		slog.Error("DeleteUser", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -225,10 +235,17 @@ func (u *UserHandler) FindAllUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	users, err := u.UserRepository.FindAll()
+	page, err := pageFromRequest(r)
 	if err != nil {
 		slog.Error("FindAllUsers", "msg", err)
-		sendFlatBufferMessage(w, err.Error(), http.StatusInternalServerError)
+		sendFlatBufferMessage(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	users, err := u.UserRepository.FindAll(r.Context(), page)
+	if err != nil {
+		slog.Error("FindAllUsers", "msg", err)
+		sendFlatBufferMessage(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -241,9 +258,11 @@ func (u *UserHandler) FindAllUsers(w http.ResponseWriter, r *http.Request) {
 		fbBuilder.PrependUOffsetT((*elements)[i])
 	}
 	vec := fbBuilder.EndVector(len(*elements))
+	nextCursor := fbBuilder.CreateString(users.NextCursor)
 
 	fb.UserListOutputStart(fbBuilder)
 	fb.UserListOutputAddElements(fbBuilder, vec)
+	fb.UserListOutputAddNextCursor(fbBuilder, nextCursor)
 	fbUsersOutput := fb.UserListOutputEnd(fbBuilder)
 	fbBuilder.Finish(fbUsersOutput)
 
@@ -299,7 +318,12 @@ func (u *UserHandler) GetJWT(w http.ResponseWriter, r *http.Request) {
 		Password: string(fbUserCredentials.Password()),
 	}
 
-	userFromDB, err := u.UserRepository.FindByEmail(userCredentials.Email)
+	userFromDB, err := u.UserRepository.FindByEmail(r.Context(), userCredentials.Email)
+	if errors.Is(err, database.ErrNotFound) {
+		slog.Error("GetJWT", "msg", err)
+		sendFlatBufferMessage(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
 		slog.Error("GetJWT", "msg", err)
 		sendFlatBufferMessage(w, "invalid credentials", http.StatusInternalServerError)
@@ -9,11 +9,17 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/antoniofmoliveira/courses/db/database"
 	"github.com/antoniofmoliveira/courses/graphql/graph"
+	"github.com/antoniofmoliveira/courses/graphql/internal/configs"
+	"github.com/go-chi/jwtauth"
 )
 
 const defaultPort = "8081"
 
 func main() {
+	cfg, err := configs.LoadConfig(".")
+	if err != nil {
+		panic(err)
+	}
 
 	dbi := database.GetDBImplementation()
 	categoryDb := dbi.CategoryRepository
@@ -25,12 +31,23 @@ func main() {
 	}
 
 	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
-		CategoryDB: categoryDb,
-		CourseDB:   courseDb,
+		CategoryDB:   categoryDb,
+		CourseDB:     courseDb,
+		SearchDB:     dbi.SearchRepository,
+		ModuleDB:     dbi.ModuleRepository,
+		LessonDB:     dbi.LessonRepository,
+		UserDB:       dbi.UserRepository,
+		EnrollmentDB: dbi.EnrollmentRepository,
+		InstructorDB: dbi.InstructorRepository,
+		TagDB:        dbi.TagRepository,
+		Purger:       dbi,
+		Admins:       dbi,
 	}}))
+	srv.SetErrorPresenter(graph.ErrorPresenter)
 
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", srv)
+	// a token is optional; the fields acting for a user ask for it
+	http.Handle("/query", jwtauth.Verifier(cfg.TokenAuth)(graph.Actor(srv)))
 
 	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
 	log.Fatal(http.ListenAndServeTLS(":"+port, "./x509/server_cert.pem", "./x509/server_key.pem", nil))
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/go-chi/jwtauth"
+)
+
+var errUnauthenticated = errors.New("a bearer token of a user is required")
+
+// Actor attributes the changes a request makes to the subject of its JWT,
+// when jwtauth.Verifier found a valid one. Requests without one stay
+// anonymous; only the fields acting for a user need it.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _, err := jwtauth.FromContext(r.Context())
+		if err == nil && token != nil {
+			r = r.WithContext(audit.WithActor(r.Context(), token.Subject()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// me returns the id of the user the token of the request was issued to.
+func (r *Resolver) me(ctx context.Context) (string, error) {
+	email := audit.Actor(ctx)
+	if email == "" {
+		return "", errUnauthenticated
+	}
+	id, err := r.UserDB.FindIDByEmail(ctx, email)
+	if errors.Is(err, database.ErrNotFound) {
+		return "", errUnauthenticated
+	}
+	return id, err
+}
@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter adds a "code" extension to repository errors so clients
+// can tell a missing record from a bad request without parsing messages.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if code := errorCode(err); code != "" {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = map[string]interface{}{}
+		}
+		gqlErr.Extensions["code"] = code
+	}
+	return gqlErr
+}
+
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, errUnauthenticated), errors.Is(err, database.ErrUnauthenticated):
+		return "UNAUTHENTICATED"
+	case errors.Is(err, database.ErrNotFound):
+		return "NOT_FOUND"
+	case errors.Is(err, database.ErrForbidden):
+		return "FORBIDDEN"
+	case errors.Is(err, database.ErrConflict):
+		return "CONFLICT"
+	case errors.Is(err, database.ErrHasDependents):
+		return "HAS_DEPENDENTS"
+	case errors.Is(err, database.ErrValidation):
+		return "VALIDATION"
+	case errors.Is(err, query.ErrInvalidCursor), errors.Is(err, query.ErrInvalidSort), errors.Is(err, query.ErrInvalidSearch):
+		return "BAD_REQUEST"
+	}
+	return ""
+}
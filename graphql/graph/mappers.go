@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/graphql/graph/model"
+)
+
+func categoryFromDto(category dto.CategoryOutputDto) *model.Category {
+	return &model.Category{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: &category.Description,
+		Version:     category.Version,
+		DeletedAt:   category.DeletedAt,
+		ParentID:    nilIfEmpty(category.ParentID),
+	}
+}
+
+func categoryPageFromDto(categories dto.CategoryListOutputDto) *model.CategoryPage {
+	page := &model.CategoryPage{
+		Items:      make([]*model.Category, 0, len(categories.Categories)),
+		NextCursor: nilIfEmpty(categories.NextCursor),
+	}
+	for _, category := range categories.Categories {
+		page.Items = append(page.Items, categoryFromDto(category))
+	}
+	return page
+}
+
+func courseFromDto(course dto.CourseOutputDto) *model.Course {
+	return &model.Course{
+		ID:          course.ID,
+		Name:        course.Name,
+		Description: &course.Description,
+		CategoryID:  course.CategoryID,
+		Version:     course.Version,
+		DeletedAt:   course.DeletedAt,
+	}
+}
+
+func coursePageFromDto(courses dto.CourseListOutputDto) *model.CoursePage {
+	page := &model.CoursePage{
+		Items:      make([]*model.Course, 0, len(courses.Courses)),
+		NextCursor: nilIfEmpty(courses.NextCursor),
+	}
+	for _, course := range courses.Courses {
+		page.Items = append(page.Items, courseFromDto(course))
+	}
+	return page
+}
+
+func moduleFromDto(module dto.ModuleOutputDto) *model.Module {
+	return &model.Module{
+		ID:          module.ID,
+		Title:       module.Title,
+		Description: &module.Description,
+		Position:    module.Position,
+		Version:     module.Version,
+	}
+}
+
+func modulesFromDto(modules dto.ModuleListOutputDto) []*model.Module {
+	items := make([]*model.Module, 0, len(modules.Modules))
+	for _, module := range modules.Modules {
+		items = append(items, moduleFromDto(module))
+	}
+	return items
+}
+
+func lessonFromDto(lesson dto.LessonOutputDto) *model.Lesson {
+	return &model.Lesson{
+		ID:              lesson.ID,
+		Title:           lesson.Title,
+		ContentType:     model.LessonContentType(strings.ToUpper(lesson.ContentType)),
+		DurationSeconds: int(lesson.DurationSeconds),
+		Body:            &lesson.Body,
+		Position:        lesson.Position,
+		Version:         int(lesson.Version),
+	}
+}
+
+func lessonsFromDto(lessons dto.LessonListOutputDto) []*model.Lesson {
+	items := make([]*model.Lesson, 0, len(lessons.Lessons))
+	for _, lesson := range lessons.Lessons {
+		items = append(items, lessonFromDto(lesson))
+	}
+	return items
+}
+
+func enrollmentFromDto(enrollment dto.EnrollmentOutputDto) *model.Enrollment {
+	return &model.Enrollment{
+		UserID:         enrollment.UserID,
+		CourseID:       enrollment.CourseID,
+		Progress:       enrollment.Progress,
+		EnrolledAt:     enrollment.EnrolledAt,
+		LastAccessedAt: enrollment.LastAccessedAt,
+	}
+}
+
+func enrollmentPageFromDto(enrollments dto.EnrollmentListOutputDto) *model.EnrollmentPage {
+	page := &model.EnrollmentPage{
+		Items:      make([]*model.Enrollment, 0, len(enrollments.Enrollments)),
+		NextCursor: nilIfEmpty(enrollments.NextCursor),
+	}
+	for _, enrollment := range enrollments.Enrollments {
+		page.Items = append(page.Items, enrollmentFromDto(enrollment))
+	}
+	return page
+}
+
+func instructorFromDto(instructor dto.InstructorOutputDto) *model.Instructor {
+	return &model.Instructor{
+		UserID:  instructor.UserID,
+		Name:    instructor.Name,
+		Email:   instructor.Email,
+		AddedAt: instructor.AddedAt,
+	}
+}
+
+func instructorsFromDto(instructors dto.InstructorListOutputDto) []*model.Instructor {
+	items := make([]*model.Instructor, 0, len(instructors.Instructors))
+	for _, instructor := range instructors.Instructors {
+		items = append(items, instructorFromDto(instructor))
+	}
+	return items
+}
+
+func tagFromDto(tag dto.TagOutputDto) *model.Tag {
+	return &model.Tag{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Courses:   int(tag.Courses),
+		CreatedAt: tag.CreatedAt,
+	}
+}
+
+func tagPageFromDto(tags dto.TagListOutputDto) *model.TagPage {
+	page := &model.TagPage{
+		Items:      make([]*model.Tag, 0, len(tags.Tags)),
+		NextCursor: nilIfEmpty(tags.NextCursor),
+	}
+	for _, tag := range tags.Tags {
+		page.Items = append(page.Items, tagFromDto(tag))
+	}
+	return page
+}
+
+func searchPageFromDto(results dto.SearchOutputDto) *model.SearchPage {
+	page := &model.SearchPage{
+		Items:      make([]*model.SearchResult, 0, len(results.Results)),
+		NextCursor: nilIfEmpty(results.NextCursor),
+	}
+	for _, result := range results.Results {
+		page.Items = append(page.Items, &model.SearchResult{
+			EntityType: model.SearchEntity(strings.ToUpper(result.EntityType)),
+			ID:         result.ID,
+			Name:       result.Name,
+			Snippet:    result.Snippet,
+			Score:      result.Score,
+		})
+	}
+	return page
+}
+
+func pageFromArgs(limit *int, cursor *string) query.Page {
+	page := query.Page{Cursor: valueOrEmpty(cursor)}
+	if limit != nil {
+		page.Limit = *limit
+	}
+	return page
+}
+
+func sortFromArgs(sort *model.SortOrder) query.Sort {
+	if sort == nil {
+		return query.Sort{}
+	}
+	result := query.Sort{Desc: sort.Descending != nil && *sort.Descending}
+	switch sort.Field {
+	case model.SortFieldName:
+		result.Field = query.SortByName
+	case model.SortFieldCreated:
+		result.Field = query.SortByCreated
+	default:
+		result.Field = query.SortByID
+	}
+	return result
+}
+
+// versionFromArgs is the version an update names, 0 when it names none.
+func versionFromArgs(version *int) int64 {
+	if version == nil {
+		return 0
+	}
+	return int64(*version)
+}
+
+func valueOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func valueOrZero(n *int) int {
+	if n == nil {
+		return 0
+	}
+	return *n
+}
+
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -1,8 +1,13 @@
 package model
 
+import "time"
+
 type Category struct {
 	ID          string    `json:"id"`
 	Name        string    `json:"name"`
 	Description *string   `json:"description,omitempty"`
+	Version     int64     `json:"version"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	ParentID    *string   `json:"parentId,omitempty"`
 	// Courses     []*Course `json:"courses"`
 }
@@ -1,8 +1,13 @@
 package model
 
+import "time"
+
 type Course struct {
 	ID          string    `json:"id"`
 	Name        string    `json:"name"`
 	Description *string   `json:"description,omitempty"`
+	Version     int64     `json:"version"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	CategoryID  string    `json:"-"`
 	// Category    *Category `json:"category"`
 }
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type Enrollment struct {
+	UserID         string     `json:"userId"`
+	CourseID       string     `json:"-"`
+	Progress       int        `json:"progress"`
+	EnrolledAt     time.Time  `json:"enrolledAt"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt,omitempty"`
+}
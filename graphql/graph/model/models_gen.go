@@ -2,12 +2,69 @@
 
 package model
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type CategoryFilter struct {
+	NamePrefix     *string `json:"namePrefix,omitempty"`
+	ParentID       *string `json:"parentId,omitempty"`
+	IncludeDeleted *bool   `json:"includeDeleted,omitempty"`
+}
+
+type CategoryPage struct {
+	Items      []*Category `json:"items"`
+	NextCursor *string     `json:"nextCursor,omitempty"`
+}
+
+type CourseFilter struct {
+	NamePrefix *string `json:"namePrefix,omitempty"`
+	CategoryID *string `json:"categoryId,omitempty"`
+	// Courses carrying any of the tags, or all of them when allTags is set.
+	Tags           []string `json:"tags,omitempty"`
+	AllTags        *bool    `json:"allTags,omitempty"`
+	IncludeDeleted *bool    `json:"includeDeleted,omitempty"`
+}
+
+type CoursePage struct {
+	Items      []*Course `json:"items"`
+	NextCursor *string   `json:"nextCursor,omitempty"`
+}
+
+type EnrollmentPage struct {
+	Items      []*Enrollment `json:"items"`
+	NextCursor *string       `json:"nextCursor,omitempty"`
+}
+
+// An instructor may change the course, as may the admins.
+type Instructor struct {
+	UserID  string    `json:"userId"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+type Lesson struct {
+	ID              string            `json:"id"`
+	Title           string            `json:"title"`
+	ContentType     LessonContentType `json:"contentType"`
+	DurationSeconds int               `json:"durationSeconds"`
+	Body            *string           `json:"body,omitempty"`
+	// 1 for the first lesson of the module.
+	Position int `json:"position"`
+	Version  int `json:"version"`
+}
+
 type Mutation struct {
 }
 
 type NewCategory struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
+	ParentID    *string `json:"parentId,omitempty"`
 }
 
 type NewCourse struct {
@@ -16,5 +73,224 @@ type NewCourse struct {
 	CategoryID  string  `json:"categoryId"`
 }
 
+// New lessons go at the end of the module.
+type NewLesson struct {
+	ModuleID        string            `json:"moduleId"`
+	Title           string            `json:"title"`
+	ContentType     LessonContentType `json:"contentType"`
+	DurationSeconds *int              `json:"durationSeconds,omitempty"`
+	Body            *string           `json:"body,omitempty"`
+}
+
+// New modules go at the end of the course.
+type NewModule struct {
+	CourseID    string  `json:"courseId"`
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+}
+
+type PurgeResult struct {
+	Courses    int `json:"courses"`
+	Categories int `json:"categories"`
+	Users      int `json:"users"`
+}
+
 type Query struct {
 }
+
+type SearchPage struct {
+	Items      []*SearchResult `json:"items"`
+	NextCursor *string         `json:"nextCursor,omitempty"`
+}
+
+// snippet holds part of the name or description with the matched words between <mark> and </mark>.
+type SearchResult struct {
+	EntityType SearchEntity `json:"entityType"`
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Snippet    string       `json:"snippet"`
+	Score      float64      `json:"score"`
+}
+
+type SortOrder struct {
+	Field      SortField `json:"field"`
+	Descending *bool     `json:"descending,omitempty"`
+}
+
+// A topic shared by courses; names are lower case with single spaces.
+type Tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// The number of live courses carrying the tag.
+	Courses   int       `json:"courses"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type TagPage struct {
+	Items      []*Tag  `json:"items"`
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// version is the one the update is made against: when it is no longer the
+// current one the update fails with a CONFLICT error. Leave it out to skip the
+// check. Leaving parentId out moves the category to the top level.
+type UpdateCategory struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	ParentID    *string `json:"parentId,omitempty"`
+	Version     *int    `json:"version,omitempty"`
+}
+
+type UpdateCourse struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	CategoryID  string  `json:"categoryId"`
+	Version     *int    `json:"version,omitempty"`
+}
+
+type UpdateLesson struct {
+	ID              string            `json:"id"`
+	Title           string            `json:"title"`
+	ContentType     LessonContentType `json:"contentType"`
+	DurationSeconds *int              `json:"durationSeconds,omitempty"`
+	Body            *string           `json:"body,omitempty"`
+	Version         *int              `json:"version,omitempty"`
+}
+
+type UpdateModule struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+	Version     *int    `json:"version,omitempty"`
+}
+
+type LessonContentType string
+
+const (
+	LessonContentTypeText  LessonContentType = "TEXT"
+	LessonContentTypeVideo LessonContentType = "VIDEO"
+	LessonContentTypeAudio LessonContentType = "AUDIO"
+	LessonContentTypeQuiz  LessonContentType = "QUIZ"
+)
+
+var AllLessonContentType = []LessonContentType{
+	LessonContentTypeText,
+	LessonContentTypeVideo,
+	LessonContentTypeAudio,
+	LessonContentTypeQuiz,
+}
+
+func (e LessonContentType) IsValid() bool {
+	switch e {
+	case LessonContentTypeText, LessonContentTypeVideo, LessonContentTypeAudio, LessonContentTypeQuiz:
+		return true
+	}
+	return false
+}
+
+func (e LessonContentType) String() string {
+	return string(e)
+}
+
+func (e *LessonContentType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LessonContentType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LessonContentType", str)
+	}
+	return nil
+}
+
+func (e LessonContentType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchEntity string
+
+const (
+	SearchEntityCourse   SearchEntity = "COURSE"
+	SearchEntityCategory SearchEntity = "CATEGORY"
+)
+
+var AllSearchEntity = []SearchEntity{
+	SearchEntityCourse,
+	SearchEntityCategory,
+}
+
+func (e SearchEntity) IsValid() bool {
+	switch e {
+	case SearchEntityCourse, SearchEntityCategory:
+		return true
+	}
+	return false
+}
+
+func (e SearchEntity) String() string {
+	return string(e)
+}
+
+func (e *SearchEntity) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchEntity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchEntity", str)
+	}
+	return nil
+}
+
+func (e SearchEntity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortField string
+
+const (
+	SortFieldID      SortField = "ID"
+	SortFieldName    SortField = "NAME"
+	SortFieldCreated SortField = "CREATED"
+)
+
+var AllSortField = []SortField{
+	SortFieldID,
+	SortFieldName,
+	SortFieldCreated,
+}
+
+func (e SortField) IsValid() bool {
+	switch e {
+	case SortFieldID, SortFieldName, SortFieldCreated:
+		return true
+	}
+	return false
+}
+
+func (e SortField) String() string {
+	return string(e)
+}
+
+func (e *SortField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortField", str)
+	}
+	return nil
+}
+
+func (e SortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
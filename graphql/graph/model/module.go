@@ -0,0 +1,9 @@
+package model
+
+type Module struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+	Position    int     `json:"position"`
+	Version     int64   `json:"version"`
+}
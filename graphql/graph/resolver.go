@@ -7,6 +7,15 @@ import "github.com/antoniofmoliveira/courses/db/database"
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	CategoryDB database.CategoryRepositoryInterface
-	CourseDB   database.CourseRepositoryInterface
+	CategoryDB   database.CategoryRepositoryInterface
+	CourseDB     database.CourseRepositoryInterface
+	SearchDB     database.SearchRepositoryInterface
+	ModuleDB     database.ModuleRepositoryInterface
+	LessonDB     database.LessonRepositoryInterface
+	UserDB       database.UserRepositoryInterface
+	EnrollmentDB database.EnrollmentRepositoryInterface
+	InstructorDB database.InstructorRepositoryInterface
+	TagDB        database.TagRepositoryInterface
+	Purger       database.Purger
+	Admins       database.AdminChecker
 }
@@ -6,39 +6,538 @@ package graph
 
 import (
 	"context"
-	"fmt"
+	"strings"
+	"time"
 
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
 	"github.com/antoniofmoliveira/courses/graphql/graph/model"
 )
 
 // Courses is the resolver for the courses field.
-func (r *categoryResolver) Courses(ctx context.Context, obj *model.Category) ([]*model.Course, error) {
-	panic(fmt.Errorf("not implemented: Courses - courses"))
+func (r *categoryResolver) Courses(ctx context.Context, obj *model.Category, limit *int, cursor *string) (*model.CoursePage, error) {
+	courses, err := r.CourseDB.FindByCategoryID(ctx, obj.ID, pageFromArgs(limit, cursor))
+	if err != nil {
+		return nil, err
+	}
+	return coursePageFromDto(courses), nil
+}
+
+// Parent is the resolver for the parent field.
+func (r *categoryResolver) Parent(ctx context.Context, obj *model.Category) (*model.Category, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	category, err := r.CategoryDB.Find(ctx, *obj.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	return categoryFromDto(category), nil
+}
+
+// Children is the resolver for the children field.
+func (r *categoryResolver) Children(ctx context.Context, obj *model.Category, limit *int, cursor *string) (*model.CategoryPage, error) {
+	categories, err := r.CategoryDB.FindChildren(ctx, obj.ID, pageFromArgs(limit, cursor))
+	if err != nil {
+		return nil, err
+	}
+	return categoryPageFromDto(categories), nil
+}
+
+// Ancestors is the resolver for the ancestors field.
+func (r *categoryResolver) Ancestors(ctx context.Context, obj *model.Category) ([]*model.Category, error) {
+	categories, err := r.CategoryDB.FindAncestors(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return categoryPageFromDto(categories).Items, nil
+}
+
+// CoursesInTree is the resolver for the coursesInTree field.
+func (r *categoryResolver) CoursesInTree(ctx context.Context, obj *model.Category, limit *int, cursor *string) (*model.CoursePage, error) {
+	courses, err := r.CourseDB.FindByCategoryTree(ctx, obj.ID, pageFromArgs(limit, cursor))
+	if err != nil {
+		return nil, err
+	}
+	return coursePageFromDto(courses), nil
 }
 
 // Category is the resolver for the category field.
 func (r *courseResolver) Category(ctx context.Context, obj *model.Course) (*model.Category, error) {
-	panic(fmt.Errorf("not implemented: Category - category"))
+	category, err := r.CategoryDB.Find(ctx, obj.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	return categoryFromDto(category), nil
+}
+
+// Modules is the resolver for the modules field.
+func (r *courseResolver) Modules(ctx context.Context, obj *model.Course) ([]*model.Module, error) {
+	modules, err := r.ModuleDB.FindByCourseID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return modulesFromDto(modules), nil
+}
+
+// Students is the resolver for the students field.
+func (r *courseResolver) Students(ctx context.Context, obj *model.Course, limit *int, cursor *string) (*model.EnrollmentPage, error) {
+	enrollments, err := r.EnrollmentDB.FindByCourseID(ctx, obj.ID, pageFromArgs(limit, cursor))
+	if err != nil {
+		return nil, err
+	}
+	return enrollmentPageFromDto(enrollments), nil
+}
+
+// Instructors is the resolver for the instructors field.
+func (r *courseResolver) Instructors(ctx context.Context, obj *model.Course) ([]*model.Instructor, error) {
+	instructors, err := r.InstructorDB.FindByCourseID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return instructorsFromDto(instructors), nil
+}
+
+// Tags is the resolver for the tags field.
+func (r *courseResolver) Tags(ctx context.Context, obj *model.Course) ([]*model.Tag, error) {
+	tags, err := r.TagDB.FindByCourseID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return tagPageFromDto(tags).Items, nil
+}
+
+// Course is the resolver for the course field.
+func (r *enrollmentResolver) Course(ctx context.Context, obj *model.Enrollment) (*model.Course, error) {
+	course, err := r.CourseDB.Find(ctx, obj.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	return courseFromDto(course), nil
+}
+
+// Lessons is the resolver for the lessons field.
+func (r *moduleResolver) Lessons(ctx context.Context, obj *model.Module) ([]*model.Lesson, error) {
+	lessons, err := r.LessonDB.FindByModuleID(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return lessonsFromDto(lessons), nil
 }
 
 // CreateCategory is the resolver for the createCategory field.
 func (r *mutationResolver) CreateCategory(ctx context.Context, input model.NewCategory) (*model.Category, error) {
-	panic(fmt.Errorf("not implemented: CreateCategory - createCategory"))
+	category, err := r.CategoryDB.Create(ctx, dto.CategoryInputDto{
+		Name:        input.Name,
+		Description: valueOrEmpty(input.Description),
+		ParentID:    valueOrEmpty(input.ParentID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return categoryFromDto(category), nil
 }
 
 // CreateCourse is the resolver for the createCourse field.
 func (r *mutationResolver) CreateCourse(ctx context.Context, input model.NewCourse) (*model.Course, error) {
-	panic(fmt.Errorf("not implemented: CreateCourse - createCourse"))
+	course, err := r.CourseDB.Create(ctx, dto.CourseInputDto{
+		Name:        input.Name,
+		Description: valueOrEmpty(input.Description),
+		CategoryID:  input.CategoryID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return courseFromDto(*course), nil
+}
+
+// UpdateCategory is the resolver for the updateCategory field.
+func (r *mutationResolver) UpdateCategory(ctx context.Context, input model.UpdateCategory) (*model.Category, error) {
+	err := r.CategoryDB.Update(ctx, dto.CategoryInputDto{
+		ID:          input.ID,
+		Name:        input.Name,
+		Description: valueOrEmpty(input.Description),
+		ParentID:    valueOrEmpty(input.ParentID),
+		Version:     versionFromArgs(input.Version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	category, err := r.CategoryDB.Find(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return categoryFromDto(category), nil
+}
+
+// UpdateCourse is the resolver for the updateCourse field.
+func (r *mutationResolver) UpdateCourse(ctx context.Context, input model.UpdateCourse) (*model.Course, error) {
+	err := r.CourseDB.Update(ctx, dto.CourseInputDto{
+		ID:          input.ID,
+		Name:        input.Name,
+		Description: valueOrEmpty(input.Description),
+		CategoryID:  input.CategoryID,
+		Version:     versionFromArgs(input.Version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	course, err := r.CourseDB.Find(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return courseFromDto(course), nil
+}
+
+// DeleteCategory is the resolver for the deleteCategory field.
+func (r *mutationResolver) DeleteCategory(ctx context.Context, id string) (bool, error) {
+	if err := r.CategoryDB.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteCourse is the resolver for the deleteCourse field.
+func (r *mutationResolver) DeleteCourse(ctx context.Context, id string) (bool, error) {
+	if err := r.CourseDB.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RestoreCategory is the resolver for the restoreCategory field.
+func (r *mutationResolver) RestoreCategory(ctx context.Context, id string) (*model.Category, error) {
+	if err := r.CategoryDB.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	category, err := r.CategoryDB.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return categoryFromDto(category), nil
+}
+
+// RestoreCourse is the resolver for the restoreCourse field.
+func (r *mutationResolver) RestoreCourse(ctx context.Context, id string) (*model.Course, error) {
+	if err := r.CourseDB.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	course, err := r.CourseDB.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return courseFromDto(course), nil
+}
+
+// CreateModule is the resolver for the createModule field.
+func (r *mutationResolver) CreateModule(ctx context.Context, input model.NewModule) (*model.Module, error) {
+	module, err := r.ModuleDB.Create(ctx, dto.ModuleInputDto{
+		CourseID:    input.CourseID,
+		Title:       input.Title,
+		Description: valueOrEmpty(input.Description),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moduleFromDto(module), nil
+}
+
+// UpdateModule is the resolver for the updateModule field.
+func (r *mutationResolver) UpdateModule(ctx context.Context, input model.UpdateModule) (*model.Module, error) {
+	err := r.ModuleDB.Update(ctx, dto.ModuleInputDto{
+		ID:          input.ID,
+		Title:       input.Title,
+		Description: valueOrEmpty(input.Description),
+		Version:     versionFromArgs(input.Version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	module, err := r.ModuleDB.Find(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return moduleFromDto(module), nil
+}
+
+// DeleteModule is the resolver for the deleteModule field.
+func (r *mutationResolver) DeleteModule(ctx context.Context, id string) (bool, error) {
+	if err := r.ModuleDB.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReorderModules is the resolver for the reorderModules field.
+func (r *mutationResolver) ReorderModules(ctx context.Context, courseID string, ids []string) ([]*model.Module, error) {
+	if err := r.ModuleDB.Reorder(ctx, courseID, ids); err != nil {
+		return nil, err
+	}
+	modules, err := r.ModuleDB.FindByCourseID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return modulesFromDto(modules), nil
+}
+
+// CreateLesson is the resolver for the createLesson field.
+func (r *mutationResolver) CreateLesson(ctx context.Context, input model.NewLesson) (*model.Lesson, error) {
+	lesson, err := r.LessonDB.Create(ctx, dto.LessonInputDto{
+		ModuleID:        input.ModuleID,
+		Title:           input.Title,
+		ContentType:     strings.ToLower(input.ContentType.String()),
+		DurationSeconds: int64(valueOrZero(input.DurationSeconds)),
+		Body:            valueOrEmpty(input.Body),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lessonFromDto(lesson), nil
+}
+
+// UpdateLesson is the resolver for the updateLesson field.
+func (r *mutationResolver) UpdateLesson(ctx context.Context, input model.UpdateLesson) (*model.Lesson, error) {
+	err := r.LessonDB.Update(ctx, dto.LessonInputDto{
+		ID:              input.ID,
+		Title:           input.Title,
+		ContentType:     strings.ToLower(input.ContentType.String()),
+		DurationSeconds: int64(valueOrZero(input.DurationSeconds)),
+		Body:            valueOrEmpty(input.Body),
+		Version:         versionFromArgs(input.Version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	lesson, err := r.LessonDB.Find(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	return lessonFromDto(lesson), nil
+}
+
+// DeleteLesson is the resolver for the deleteLesson field.
+func (r *mutationResolver) DeleteLesson(ctx context.Context, id string) (bool, error) {
+	if err := r.LessonDB.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReorderLessons is the resolver for the reorderLessons field.
+func (r *mutationResolver) ReorderLessons(ctx context.Context, moduleID string, ids []string) ([]*model.Lesson, error) {
+	if err := r.LessonDB.Reorder(ctx, moduleID, ids); err != nil {
+		return nil, err
+	}
+	lessons, err := r.LessonDB.FindByModuleID(ctx, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	return lessonsFromDto(lessons), nil
+}
+
+// Enroll is the resolver for the enroll field.
+func (r *mutationResolver) Enroll(ctx context.Context, courseID string) (*model.Enrollment, error) {
+	userID, err := r.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := r.EnrollmentDB.Enroll(ctx, dto.EnrollmentInputDto{UserID: userID, CourseID: courseID})
+	if err != nil {
+		return nil, err
+	}
+	return enrollmentFromDto(enrollment), nil
+}
+
+// Unenroll is the resolver for the unenroll field.
+func (r *mutationResolver) Unenroll(ctx context.Context, courseID string) (bool, error) {
+	userID, err := r.me(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := r.EnrollmentDB.Unenroll(ctx, userID, courseID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateProgress is the resolver for the updateProgress field.
+func (r *mutationResolver) UpdateProgress(ctx context.Context, courseID string, progress int) (*model.Enrollment, error) {
+	userID, err := r.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = r.EnrollmentDB.UpdateProgress(ctx, dto.EnrollmentInputDto{UserID: userID, CourseID: courseID, Progress: progress})
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := r.EnrollmentDB.Find(ctx, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return enrollmentFromDto(enrollment), nil
+}
+
+// AddInstructor is the resolver for the addInstructor field.
+func (r *mutationResolver) AddInstructor(ctx context.Context, courseID string, userID string) (*model.Instructor, error) {
+	instructor, err := r.InstructorDB.Add(ctx, dto.InstructorInputDto{CourseID: courseID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	return instructorFromDto(instructor), nil
+}
+
+// RemoveInstructor is the resolver for the removeInstructor field.
+func (r *mutationResolver) RemoveInstructor(ctx context.Context, courseID string, userID string) (bool, error) {
+	if err := r.InstructorDB.Remove(ctx, courseID, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateTag is the resolver for the createTag field.
+func (r *mutationResolver) CreateTag(ctx context.Context, name string) (*model.Tag, error) {
+	tag, err := r.TagDB.Create(ctx, dto.TagInputDto{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return tagFromDto(tag), nil
+}
+
+// DeleteTag is the resolver for the deleteTag field.
+func (r *mutationResolver) DeleteTag(ctx context.Context, id string) (bool, error) {
+	if err := r.TagDB.Delete(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TagCourse is the resolver for the tagCourse field.
+func (r *mutationResolver) TagCourse(ctx context.Context, courseID string, name string) (*model.Tag, error) {
+	tag, err := r.TagDB.Tag(ctx, dto.CourseTagInputDto{CourseID: courseID, Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return tagFromDto(tag), nil
+}
+
+// UntagCourse is the resolver for the untagCourse field.
+func (r *mutationResolver) UntagCourse(ctx context.Context, courseID string, tagID string) (bool, error) {
+	if err := r.TagDB.Untag(ctx, courseID, tagID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Purge is the resolver for the purge field.
+func (r *mutationResolver) Purge(ctx context.Context, olderThan *string) (*model.PurgeResult, error) {
+	if err := r.Admins.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	retention := database.DefaultRetention
+	if olderThan != nil {
+		d, err := time.ParseDuration(*olderThan)
+		if err != nil || d < 0 {
+			return nil, dberr.New(dberr.ErrValidation, "invalid olderThan")
+		}
+		retention = d
+	}
+	purged, err := r.Purger.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return nil, err
+	}
+	return &model.PurgeResult{
+		Courses:    int(purged.Courses),
+		Categories: int(purged.Categories),
+		Users:      int(purged.Users),
+	}, nil
 }
 
 // Categories is the resolver for the categories field.
-func (r *queryResolver) Categories(ctx context.Context) ([]*model.Category, error) {
-	panic(fmt.Errorf("not implemented: Categories - categories"))
+func (r *queryResolver) Categories(ctx context.Context, limit *int, cursor *string, filter *model.CategoryFilter, sort *model.SortOrder) (*model.CategoryPage, error) {
+	spec := query.CategorySpec{Sort: sortFromArgs(sort), Page: pageFromArgs(limit, cursor)}
+	if filter != nil {
+		spec.NamePrefix = valueOrEmpty(filter.NamePrefix)
+		spec.ParentID = valueOrEmpty(filter.ParentID)
+		spec.IncludeDeleted = filter.IncludeDeleted != nil && *filter.IncludeDeleted
+	}
+	if spec.IncludeDeleted {
+		if err := r.Admins.CheckAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+	categories, err := r.CategoryDB.List(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return categoryPageFromDto(categories), nil
 }
 
 // Courses is the resolver for the courses field.
-func (r *queryResolver) Courses(ctx context.Context) ([]*model.Course, error) {
-	panic(fmt.Errorf("not implemented: Courses - courses"))
+func (r *queryResolver) Courses(ctx context.Context, limit *int, cursor *string, filter *model.CourseFilter, sort *model.SortOrder) (*model.CoursePage, error) {
+	spec := query.CourseSpec{Sort: sortFromArgs(sort), Page: pageFromArgs(limit, cursor)}
+	if filter != nil {
+		spec.NamePrefix = valueOrEmpty(filter.NamePrefix)
+		spec.CategoryID = valueOrEmpty(filter.CategoryID)
+		spec.Tags = filter.Tags
+		spec.AllTags = filter.AllTags != nil && *filter.AllTags
+		spec.IncludeDeleted = filter.IncludeDeleted != nil && *filter.IncludeDeleted
+	}
+	if spec.IncludeDeleted {
+		if err := r.Admins.CheckAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+	courses, err := r.CourseDB.List(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return coursePageFromDto(courses), nil
+}
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, q string, typeArg *model.SearchEntity, limit *int, cursor *string) (*model.SearchPage, error) {
+	spec := query.SearchSpec{Query: q, Page: pageFromArgs(limit, cursor)}
+	if typeArg != nil {
+		spec.EntityType = strings.ToLower(typeArg.String())
+	}
+	results, err := r.SearchDB.Search(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return searchPageFromDto(results), nil
+}
+
+// MyEnrollments is the resolver for the myEnrollments field.
+func (r *queryResolver) MyEnrollments(ctx context.Context, limit *int, cursor *string) (*model.EnrollmentPage, error) {
+	userID, err := r.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := r.EnrollmentDB.FindByUserID(ctx, userID, pageFromArgs(limit, cursor))
+	if err != nil {
+		return nil, err
+	}
+	return enrollmentPageFromDto(enrollments), nil
+}
+
+// CoursesTaughtBy is the resolver for the coursesTaughtBy field.
+func (r *queryResolver) CoursesTaughtBy(ctx context.Context, userID string, limit *int, cursor *string) (*model.CoursePage, error) {
+	courses, err := r.CourseDB.FindByInstructorID(ctx, userID, pageFromArgs(limit, cursor))
+	if err != nil {
+		return nil, err
+	}
+	return coursePageFromDto(courses), nil
+}
+
+// Tags is the resolver for the tags field.
+func (r *queryResolver) Tags(ctx context.Context, prefix *string, limit *int, cursor *string) (*model.TagPage, error) {
+	tags, err := r.TagDB.List(ctx, query.TagSpec{NamePrefix: valueOrEmpty(prefix), Page: pageFromArgs(limit, cursor)})
+	if err != nil {
+		return nil, err
+	}
+	return tagPageFromDto(tags), nil
 }
 
 // Category returns CategoryResolver implementation.
@@ -47,6 +546,12 @@ func (r *Resolver) Category() CategoryResolver { return &categoryResolver{r} }
 // Course returns CourseResolver implementation.
 func (r *Resolver) Course() CourseResolver { return &courseResolver{r} }
 
+// Enrollment returns EnrollmentResolver implementation.
+func (r *Resolver) Enrollment() EnrollmentResolver { return &enrollmentResolver{r} }
+
+// Module returns ModuleResolver implementation.
+func (r *Resolver) Module() ModuleResolver { return &moduleResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
@@ -55,5 +560,7 @@ func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
 type categoryResolver struct{ *Resolver }
 type courseResolver struct{ *Resolver }
+type enrollmentResolver struct{ *Resolver }
+type moduleResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -135,7 +135,7 @@ func createCategory(c pb.CategoryServiceClient) *pb.Category {
 }
 
 func listCategories(c pb.CategoryServiceClient) *pb.CategoryList {
-	categories, err := c.ListCategories(context.Background(), &pb.Blank{})
+	categories, err := c.ListCategories(context.Background(), &pb.ListCategoriesRequest{})
 	if err != nil {
 		log.Fatalf("could not list categories: %v", err)
 	}
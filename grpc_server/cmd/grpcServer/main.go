@@ -7,6 +7,7 @@ import (
 	"github.com/antoniofmoliveira/courses/db/database"
 	"github.com/antoniofmoliveira/courses/grpcproto/pb"
 	"github.com/antoniofmoliveira/courses/grpcserver/internal/service"
+	"github.com/antoniofmoliveira/courses/grpcserver/internal/service/configs"
 	"google.golang.org/grpc/reflection"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -18,8 +19,19 @@ func main() {
 
 	dbi := database.GetDBImplementation()
 	
-	categoryService := service.NewCategoryService(dbi.CategoryRepository)
-	courseService := service.NewCourseService(dbi.CourseRepository)
+	categoryService := service.NewCategoryService(dbi.CategoryRepository, dbi.CourseRepository, dbi, dbi)
+	courseService := service.NewCourseService(dbi.CourseRepository, dbi.SearchRepository, dbi)
+	adminService := service.NewAdminService(dbi, dbi)
+	historyService := service.NewHistoryService(dbi.HistoryRepository)
+	lessonService := service.NewLessonService(dbi.ModuleRepository, dbi.LessonRepository)
+	enrollmentService := service.NewEnrollmentService(dbi.UserRepository, dbi.EnrollmentRepository)
+	instructorService := service.NewInstructorService(dbi.CourseRepository, dbi.InstructorRepository)
+	tagService := service.NewTagService(dbi.TagRepository)
+
+	cfg, err := configs.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
 
 	// with authentication
 	creds, err := credentials.NewServerTLSFromFile("x509/server_cert.pem", "x509/server_key.pem")
@@ -27,13 +39,21 @@ func main() {
 		log.Fatalf("failed to create credentials: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	grpcServer := grpc.NewServer(grpc.Creds(creds),
+		grpc.UnaryInterceptor(service.ActorUnaryInterceptor(cfg.TokenAuth)),
+		grpc.StreamInterceptor(service.ActorStreamInterceptor(cfg.TokenAuth)))
 
 	// without authentication
 	// grpcServer := grpc.NewServer()
 
 	pb.RegisterCategoryServiceServer(grpcServer, categoryService)
 	pb.RegisterCourseServiceServer(grpcServer, courseService)
+	pb.RegisterAdminServiceServer(grpcServer, adminService)
+	pb.RegisterHistoryServiceServer(grpcServer, historyService)
+	pb.RegisterLessonServiceServer(grpcServer, lessonService)
+	pb.RegisterEnrollmentServiceServer(grpcServer, enrollmentService)
+	pb.RegisterInstructorServiceServer(grpcServer, instructorService)
+	pb.RegisterTagServiceServer(grpcServer, tagService)
 	reflection.Register(grpcServer)
 
 	lis, err := net.Listen("tcp", ":50051")
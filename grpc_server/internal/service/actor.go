@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/go-chi/jwtauth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// withActor records the subject of the bearer token in the authorization
+// metadata as the actor of the call. Calls without a token stay anonymous;
+// an invalid token is rejected.
+func withActor(ctx context.Context, ja *jwtauth.JWTAuth) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		bearer, ok := strings.CutPrefix(value, "Bearer ")
+		if !ok {
+			continue
+		}
+		token, err := jwtauth.VerifyToken(ja, bearer)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return audit.WithActor(ctx, token.Subject()), nil
+	}
+	return ctx, nil
+}
+
+func ActorUnaryInterceptor(ja *jwtauth.JWTAuth) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := withActor(ctx, ja)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func ActorStreamInterceptor(ja *jwtauth.JWTAuth) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := withActor(ss.Context(), ja)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &actorStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type actorStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *actorStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AdminService struct {
+	pb.UnimplementedAdminServiceServer
+	Purger database.Purger
+	Admins database.AdminChecker
+}
+
+func NewAdminService(purger database.Purger, admins database.AdminChecker) *AdminService {
+	return &AdminService{Purger: purger, Admins: admins}
+}
+
+// Purge hard deletes what was soft deleted longer ago than older_than, or
+// database.DefaultRetention when it is not set. Only admins may purge.
+func (a *AdminService) Purge(ctx context.Context, in *pb.PurgeRequest) (*pb.PurgeResponse, error) {
+	if err := a.Admins.CheckAdmin(ctx); err != nil {
+		return nil, statusError(err)
+	}
+	retention := database.DefaultRetention
+	if in.OlderThan != nil {
+		if err := in.OlderThan.CheckValid(); err != nil || in.OlderThan.AsDuration() < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid older_than")
+		}
+		retention = in.OlderThan.AsDuration()
+	}
+	purged, err := a.Purger.Purge(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.PurgeResponse{Courses: purged.Courses, Categories: purged.Categories, Users: purged.Users}, nil
+}
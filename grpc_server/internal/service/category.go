@@ -2,122 +2,187 @@ package service
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
 	"github.com/antoniofmoliveira/courses/dto"
 	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type CategoryService struct {
 	pb.UnimplementedCategoryServiceServer
 	CategoryDB database.CategoryRepositoryInterface
+	CourseDB   database.CourseRepositoryInterface
+	Transactor database.Transactor
+	Admins     database.AdminChecker
 }
 
-func NewCategoryService(categoryDB database.CategoryRepositoryInterface) *CategoryService {
+func NewCategoryService(categoryDB database.CategoryRepositoryInterface, courseDB database.CourseRepositoryInterface, transactor database.Transactor, admins database.AdminChecker) *CategoryService {
 	return &CategoryService{
 		CategoryDB: categoryDB,
+		CourseDB:   courseDB,
+		Transactor: transactor,
+		Admins:     admins,
 	}
 }
 
 func (c *CategoryService) CreateCategory(ctx context.Context, in *pb.CreateCategoryRequest) (*pb.Category, error) {
-	category, err := c.CategoryDB.Create(dto.CategoryInputDto{Name: in.Name, Description: in.Description})
+	category, err := c.CategoryDB.Create(ctx, dto.CategoryInputDto{Name: in.Name, Description: in.Description, ParentID: in.ParentId})
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
-	categoryResponse := &pb.Category{
-		Id:          category.ID,
-		Name:        category.Name,
-		Description: category.Description,
-	}
-
-	return categoryResponse, nil
+	return categoryToPb(category), nil
 }
 
-func (c *CategoryService) ListCategories(ctx context.Context, in *pb.Blank) (*pb.CategoryList, error) {
-	categories, err := c.CategoryDB.FindAll()
+func (c *CategoryService) ListCategories(ctx context.Context, in *pb.ListCategoriesRequest) (*pb.CategoryList, error) {
+	if in.IncludeDeleted {
+		if err := c.Admins.CheckAdmin(ctx); err != nil {
+			return nil, statusError(err)
+		}
+	}
+	sort, err := sortFromProto(in.SortBy, in.Descending)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	categories, err := c.CategoryDB.List(ctx, query.CategorySpec{
+		NamePrefix:     in.NamePrefix,
+		ParentID:       in.ParentId,
+		IncludeDeleted: in.IncludeDeleted,
+		Sort:           sort,
+		Page:           query.Page{Limit: int(in.Limit), Cursor: in.Cursor},
+	})
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
 	var categoriesResponse []*pb.Category
 
 	for _, category := range categories.Categories {
-		categoryResponse := &pb.Category{
-			Id:          category.ID,
-			Name:        category.Name,
-			Description: category.Description,
-		}
-
-		categoriesResponse = append(categoriesResponse, categoryResponse)
+		categoriesResponse = append(categoriesResponse, categoryToPb(category))
 	}
 
-	return &pb.CategoryList{Categories: categoriesResponse}, nil
+	return &pb.CategoryList{Categories: categoriesResponse, NextCursor: categories.NextCursor}, nil
 }
 
 func (c *CategoryService) GetCategory(ctx context.Context, in *pb.CategoryGetRequest) (*pb.Category, error) {
-	category, err := c.CategoryDB.Find(in.Id)
+	category, err := c.CategoryDB.Find(ctx, in.Id)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
-	categoryResponse := &pb.Category{
-		Id:          category.ID,
-		Name:        category.Name,
-		Description: category.Description,
-	}
+	return categoryToPb(category), nil
+}
 
-	return categoryResponse, nil
+func (c *CategoryService) UpdateCategory(ctx context.Context, in *pb.CategoryUpdateRequest) (*pb.Response, error) {
+	category := dto.CategoryInputDto{ID: in.Id, Name: in.Name, Description: in.Description, ParentID: in.ParentId, Version: in.Version}
+	err := c.CategoryDB.Update(ctx, category)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Category updated successfully"}, nil
 }
 
+// CreateCategoryStream creates the whole streamed batch in one transaction:
+// if any category fails, none is kept.
 func (c *CategoryService) CreateCategoryStream(stream pb.CategoryService_CreateCategoryStreamServer) error {
+	ctx := stream.Context()
 	categories := &pb.CategoryList{}
 
-	for {
-		category, err := stream.Recv()
-		if err == io.EOF {
-			return stream.SendAndClose(categories)
-		}
-		if err != nil {
-			return err
+	err := database.InTx(ctx, c.Transactor, func(tx *database.Tx) error {
+		for {
+			category, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return statusError(err)
+			}
+
+			categoryResult, err := tx.CategoryRepository.Create(ctx, dto.CategoryInputDto{Name: category.Name, Description: category.Description, ParentID: category.ParentId})
+			if err != nil {
+				return statusError(err)
+			}
+
+			categories.Categories = append(categories.Categories, categoryToPb(categoryResult))
 		}
-
-		categoryResult, err := c.CategoryDB.Create(dto.CategoryInputDto{Name: category.Name, Description: category.Description})
-		if err != nil {
-			return err
-		}
-
-		categories.Categories = append(categories.Categories, &pb.Category{
-			Id:          categoryResult.ID,
-			Name:        categoryResult.Name,
-			Description: categoryResult.Description,
-		})
+	})
+	if errors.Is(err, database.ErrTransactionsUnsupported) {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		return statusError(err)
 	}
+	return stream.SendAndClose(categories)
 }
 
 func (c *CategoryService) CreateCategoryStreamBidirectional(stream pb.CategoryService_CreateCategoryStreamBidirectionalServer) error {
+	ctx := stream.Context()
 	for {
 		category, err := stream.Recv()
 		if err == io.EOF {
 			return nil
 		}
 		if err != nil {
-			return err
+			return statusError(err)
 		}
 
-		categoryResult, err := c.CategoryDB.Create(dto.CategoryInputDto{Name: category.Name, Description: category.Description})
+		categoryResult, err := c.CategoryDB.Create(ctx, dto.CategoryInputDto{Name: category.Name, Description: category.Description, ParentID: category.ParentId})
 		if err != nil {
-			return err
+			return statusError(err)
 		}
 
-		err = stream.Send(&pb.Category{
-			Id:          categoryResult.ID,
-			Name:        categoryResult.Name,
-			Description: categoryResult.Description,
-		})
+		err = stream.Send(categoryToPb(categoryResult))
 		if err != nil {
-			return err
+			return statusError(err)
 		}
 	}
 }
+
+func (c *CategoryService) RestoreCategory(ctx context.Context, in *pb.CategoryRestoreRequest) (*pb.Response, error) {
+	err := c.CategoryDB.Restore(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Category restored successfully"}, nil
+}
+
+func (c *CategoryService) ListChildCategories(ctx context.Context, in *pb.ListChildCategoriesRequest) (*pb.CategoryList, error) {
+	categories, err := c.CategoryDB.FindChildren(ctx, in.Id, query.Page{Limit: int(in.Limit), Cursor: in.Cursor})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return categoriesToPb(categories), nil
+}
+
+func (c *CategoryService) ListCategoryAncestors(ctx context.Context, in *pb.CategoryGetRequest) (*pb.CategoryList, error) {
+	categories, err := c.CategoryDB.FindAncestors(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return categoriesToPb(categories), nil
+}
+
+func (c *CategoryService) GetCategorySubtree(ctx context.Context, in *pb.CategoryGetRequest) (*pb.CategoryList, error) {
+	categories, err := c.CategoryDB.FindSubtree(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return categoriesToPb(categories), nil
+}
+
+func (c *CategoryService) ListCoursesInCategoryTree(ctx context.Context, in *pb.ListCoursesFromCategoryRequest) (*pb.Courses, error) {
+	courses, err := c.CourseDB.FindByCategoryTree(ctx, in.CategoryId, query.Page{Limit: int(in.Limit), Cursor: in.Cursor})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	pbCourses := []*pb.Course{}
+	for _, course := range courses.Courses {
+		pbCourses = append(pbCourses, courseToPb(course))
+	}
+	return &pb.Courses{Courses: pbCourses, NextCursor: courses.NextCursor}, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"time"
+
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/entity"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func categoryToPb(category dto.CategoryOutputDto) *pb.Category {
+	return &pb.Category{
+		Id:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		DeletedAt:   deletedAt(category.DeletedAt),
+		CreatedAt:   timestamppb.New(category.CreatedAt),
+		UpdatedAt:   timestamppb.New(category.UpdatedAt),
+		CreatedBy:   category.CreatedBy,
+		UpdatedBy:   category.UpdatedBy,
+		Version:     category.Version,
+		ParentId:    category.ParentID,
+	}
+}
+
+func categoriesToPb(categories dto.CategoryListOutputDto) *pb.CategoryList {
+	list := &pb.CategoryList{NextCursor: categories.NextCursor}
+	for _, category := range categories.Categories {
+		list.Categories = append(list.Categories, categoryToPb(category))
+	}
+	return list
+}
+
+func courseToPb(course dto.CourseOutputDto) *pb.Course {
+	return &pb.Course{
+		Id:          course.ID,
+		Name:        course.Name,
+		Description: course.Description,
+		CategoryId:  course.CategoryID,
+		DeletedAt:   deletedAt(course.DeletedAt),
+		CreatedAt:   timestamppb.New(course.CreatedAt),
+		UpdatedAt:   timestamppb.New(course.UpdatedAt),
+		CreatedBy:   course.CreatedBy,
+		UpdatedBy:   course.UpdatedBy,
+		Version:     course.Version,
+	}
+}
+
+func userToPb(user dto.UserOutputDto) *pb.User {
+	return &pb.User{
+		Id:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		DeletedAt: deletedAt(user.DeletedAt),
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+		CreatedBy: user.CreatedBy,
+		UpdatedBy: user.UpdatedBy,
+		Version:   user.Version,
+	}
+}
+
+func moduleToPb(module dto.ModuleOutputDto) *pb.Module {
+	return &pb.Module{
+		Id:          module.ID,
+		CourseId:    module.CourseID,
+		Title:       module.Title,
+		Description: module.Description,
+		Position:    int32(module.Position),
+		CreatedAt:   timestamppb.New(module.CreatedAt),
+		UpdatedAt:   timestamppb.New(module.UpdatedAt),
+		CreatedBy:   module.CreatedBy,
+		UpdatedBy:   module.UpdatedBy,
+		Version:     module.Version,
+	}
+}
+
+func enrollmentToPb(enrollment dto.EnrollmentOutputDto) *pb.Enrollment {
+	pbEnrollment := &pb.Enrollment{
+		UserId:     enrollment.UserID,
+		CourseId:   enrollment.CourseID,
+		Progress:   int32(enrollment.Progress),
+		EnrolledAt: timestamppb.New(enrollment.EnrolledAt),
+	}
+	if enrollment.LastAccessedAt != nil {
+		pbEnrollment.LastAccessedAt = timestamppb.New(*enrollment.LastAccessedAt)
+	}
+	return pbEnrollment
+}
+
+func enrollmentsToPb(enrollments dto.EnrollmentListOutputDto) *pb.Enrollments {
+	pbEnrollments := []*pb.Enrollment{}
+	for _, enrollment := range enrollments.Enrollments {
+		pbEnrollments = append(pbEnrollments, enrollmentToPb(enrollment))
+	}
+	return &pb.Enrollments{Enrollments: pbEnrollments, NextCursor: enrollments.NextCursor}
+}
+
+func instructorToPb(instructor dto.InstructorOutputDto) *pb.Instructor {
+	return &pb.Instructor{
+		CourseId: instructor.CourseID,
+		UserId:   instructor.UserID,
+		Name:     instructor.Name,
+		Email:    instructor.Email,
+		AddedAt:  timestamppb.New(instructor.AddedAt),
+	}
+}
+
+func tagToPb(tag dto.TagOutputDto) *pb.Tag {
+	return &pb.Tag{
+		Id:        tag.ID,
+		Name:      tag.Name,
+		Courses:   tag.Courses,
+		CreatedAt: timestamppb.New(tag.CreatedAt),
+		CreatedBy: tag.CreatedBy,
+	}
+}
+
+func tagsToPb(tags dto.TagListOutputDto) *pb.Tags {
+	pbTags := []*pb.Tag{}
+	for _, tag := range tags.Tags {
+		pbTags = append(pbTags, tagToPb(tag))
+	}
+	return &pb.Tags{Tags: pbTags, NextCursor: tags.NextCursor}
+}
+
+func lessonToPb(lesson dto.LessonOutputDto) *pb.Lesson {
+	return &pb.Lesson{
+		Id:          lesson.ID,
+		ModuleId:    lesson.ModuleID,
+		Title:       lesson.Title,
+		ContentType: contentTypes[entity.ContentType(lesson.ContentType)],
+		Duration:    durationpb.New(time.Duration(lesson.DurationSeconds) * time.Second),
+		Body:        lesson.Body,
+		Position:    int32(lesson.Position),
+		CreatedAt:   timestamppb.New(lesson.CreatedAt),
+		UpdatedAt:   timestamppb.New(lesson.UpdatedAt),
+		CreatedBy:   lesson.CreatedBy,
+		UpdatedBy:   lesson.UpdatedBy,
+		Version:     lesson.Version,
+	}
+}
+
+var contentTypes = map[entity.ContentType]pb.LessonContentType{
+	entity.ContentText:  pb.LessonContentType_LESSON_CONTENT_TYPE_TEXT,
+	entity.ContentVideo: pb.LessonContentType_LESSON_CONTENT_TYPE_VIDEO,
+	entity.ContentAudio: pb.LessonContentType_LESSON_CONTENT_TYPE_AUDIO,
+	entity.ContentQuiz:  pb.LessonContentType_LESSON_CONTENT_TYPE_QUIZ,
+}
+
+// contentTypeFromPb maps a content type onto its name in the database; the
+// unspecified one maps onto "", which the repository rejects.
+func contentTypeFromPb(contentType pb.LessonContentType) string {
+	for name, value := range contentTypes {
+		if value == contentType {
+			return string(name)
+		}
+	}
+	return ""
+}
+
+// batchToPb gives each rejected item of a batch its status code.
+func batchToPb(results []dto.BatchItemOutputDto) []*pb.BatchItemResult {
+	pbResults := make([]*pb.BatchItemResult, len(results))
+	for i, result := range results {
+		pbResults[i] = &pb.BatchItemResult{Index: int32(result.Index), Id: result.ID}
+		if result.Err != nil {
+			pbResults[i].Code, pbResults[i].Error = int32(status.Code(statusError(result.Err))), result.Err.Error()
+		}
+	}
+	return pbResults
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
 	"github.com/antoniofmoliveira/courses/dto"
 
 	// "github.com/antoniofmoliveira/courses/dto"
@@ -13,68 +14,130 @@ import (
 type CourseService struct {
 	pb.UnimplementedCourseServiceServer
 	CourseDB database.CourseRepositoryInterface
+	SearchDB database.SearchRepositoryInterface
+	Admins   database.AdminChecker
 }
 
-func NewCourseService(courseDB database.CourseRepositoryInterface) *CourseService {
+func NewCourseService(courseDB database.CourseRepositoryInterface, searchDB database.SearchRepositoryInterface, admins database.AdminChecker) *CourseService {
 	return &CourseService{
 		CourseDB: courseDB,
+		SearchDB: searchDB,
+		Admins:   admins,
 	}
 }
 
 func (c *CourseService) CreateCourse(ctx context.Context, in *pb.CreateCourseRequest) (*pb.Course, error) {
 	dtoCourseInputDto := dto.CourseInputDto{Name: in.Name, Description: in.Description, CategoryID: in.CategoryId}
-	course, err := c.CourseDB.Create(dtoCourseInputDto)
+	course, err := c.CourseDB.Create(ctx, dtoCourseInputDto)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	return &pb.Course{Id: course.ID, Name: course.Name, Description: course.Description, CategoryId: course.CategoryID}, nil
+	return courseToPb(*course), nil
 }
 
-func (c *CourseService) ListCourses(ctx context.Context, in *pb.Blank) (*pb.Courses, error) {
-	courses, err := c.CourseDB.FindAll()
+// BatchCreateCourses creates courses in one transaction. A rejected course
+// gets the code CreateCourse would have failed with and the others are
+// created all the same.
+func (c *CourseService) BatchCreateCourses(ctx context.Context, in *pb.BatchCreateCoursesRequest) (*pb.BatchCreateCoursesResponse, error) {
+	courses := make([]dto.CourseInputDto, len(in.Courses))
+	for i, course := range in.Courses {
+		courses[i] = dto.CourseInputDto{Name: course.Name, Description: course.Description, CategoryID: course.CategoryId}
+	}
+	results, err := c.CourseDB.CreateBatch(ctx, courses)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
+	}
+	return &pb.BatchCreateCoursesResponse{Results: batchToPb(results)}, nil
+}
+
+func (c *CourseService) ListCourses(ctx context.Context, in *pb.ListCoursesRequest) (*pb.Courses, error) {
+	if in.IncludeDeleted {
+		if err := c.Admins.CheckAdmin(ctx); err != nil {
+			return nil, statusError(err)
+		}
+	}
+	sort, err := sortFromProto(in.SortBy, in.Descending)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	courses, err := c.CourseDB.List(ctx, query.CourseSpec{
+		NamePrefix:     in.NamePrefix,
+		CategoryID:     in.CategoryId,
+		Tags:           in.Tags,
+		AllTags:        in.AllTags,
+		IncludeDeleted: in.IncludeDeleted,
+		Sort:           sort,
+		Page:           query.Page{Limit: int(in.Limit), Cursor: in.Cursor},
+	})
+	if err != nil {
+		return nil, statusError(err)
 	}
 	pbCourses := []*pb.Course{}
 	for _, course := range courses.Courses {
-		pbCourses = append(pbCourses, &pb.Course{Id: course.ID, Name: course.Name, Description: course.Description, CategoryId: course.CategoryID})
+		pbCourses = append(pbCourses, courseToPb(course))
 	}
-	return &pb.Courses{Courses: pbCourses}, nil
+	return &pb.Courses{Courses: pbCourses, NextCursor: courses.NextCursor}, nil
 }
 
 func (c *CourseService) ListCoursesFromCategory(ctx context.Context, in *pb.ListCoursesFromCategoryRequest) (*pb.Courses, error) {
-	courses, err := c.CourseDB.FindByCategoryID(in.CategoryId)
+	courses, err := c.CourseDB.FindByCategoryID(ctx, in.CategoryId, query.Page{Limit: int(in.Limit), Cursor: in.Cursor})
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	pbCourses := []*pb.Course{}
 	for _, course := range courses.Courses {
-		pbCourses = append(pbCourses, &pb.Course{Id: course.ID, Name: course.Name, Description: course.Description, CategoryId: course.CategoryID})
+		pbCourses = append(pbCourses, courseToPb(course))
 	}
-	return &pb.Courses{Courses: pbCourses}, nil
+	return &pb.Courses{Courses: pbCourses, NextCursor: courses.NextCursor}, nil
 }
 
 func (c *CourseService) GetCourse(ctx context.Context, in *pb.CourseGetRequest) (*pb.Course, error) {
-	course, err := c.CourseDB.Find(in.Id)
+	course, err := c.CourseDB.Find(ctx, in.Id)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	return &pb.Course{Id: course.ID, Name: course.Name, Description: course.Description, CategoryId: course.CategoryID}, nil
+	return courseToPb(course), nil
 }
 
 func (c *CourseService) UpdateCourse(ctx context.Context, in *pb.CourseUpdateRequest) (*pb.Response, error) {
-	course := dto.CourseInputDto{ID: in.Id, Name: in.Name, Description: in.Description, CategoryID: in.CategoryId}
-	err := c.CourseDB.Update(course)
+	course := dto.CourseInputDto{ID: in.Id, Name: in.Name, Description: in.Description, CategoryID: in.CategoryId, Version: in.Version}
+	err := c.CourseDB.Update(ctx, course)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	return &pb.Response{IsSuccess: true, Message: "Course updated successfully"}, nil
 }
 
 func (c *CourseService) DeleteCourse(ctx context.Context, in *pb.CourseDeleteRequest) (*pb.Response, error) {
-	err := c.CourseDB.Delete(in.Id)
+	err := c.CourseDB.Delete(ctx, in.Id)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	return &pb.Response{IsSuccess: true, Message: "Course deleted successfully"}, nil
 }
+
+func (c *CourseService) RestoreCourse(ctx context.Context, in *pb.CourseRestoreRequest) (*pb.Response, error) {
+	err := c.CourseDB.Restore(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Course restored successfully"}, nil
+}
+
+// SearchCourses finds courses by the words of their name and description,
+// best match first.
+func (c *CourseService) SearchCourses(ctx context.Context, in *pb.SearchCoursesRequest) (*pb.SearchCoursesResponse, error) {
+	found, err := c.SearchDB.Search(ctx, query.SearchSpec{
+		Query:      in.Query,
+		EntityType: "course",
+		Page:       query.Page{Limit: int(in.Limit), Cursor: in.Cursor},
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	results := []*pb.CourseSearchResult{}
+	for _, result := range found.Results {
+		results = append(results, &pb.CourseSearchResult{Id: result.ID, Name: result.Name, Snippet: result.Snippet, Score: result.Score})
+	}
+	return &pb.SearchCoursesResponse{Results: results, NextCursor: found.NextCursor}, nil
+}
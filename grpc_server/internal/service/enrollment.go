@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EnrollmentService serves the enrollments of the user of the bearer token
+// and the students of courses.
+type EnrollmentService struct {
+	pb.UnimplementedEnrollmentServiceServer
+	UserDB       database.UserRepositoryInterface
+	EnrollmentDB database.EnrollmentRepositoryInterface
+}
+
+func NewEnrollmentService(userDB database.UserRepositoryInterface, enrollmentDB database.EnrollmentRepositoryInterface) *EnrollmentService {
+	return &EnrollmentService{
+		UserDB:       userDB,
+		EnrollmentDB: enrollmentDB,
+	}
+}
+
+// me returns the id of the user the token of the call was issued to.
+func (e *EnrollmentService) me(ctx context.Context) (string, error) {
+	email := audit.Actor(ctx)
+	if email == "" {
+		return "", status.Error(codes.Unauthenticated, "a bearer token is required")
+	}
+	id, err := e.UserDB.FindIDByEmail(ctx, email)
+	if errors.Is(err, database.ErrNotFound) {
+		return "", status.Error(codes.Unauthenticated, "the token does not belong to a user")
+	}
+	if err != nil {
+		return "", statusError(err)
+	}
+	return id, nil
+}
+
+func (e *EnrollmentService) Enroll(ctx context.Context, in *pb.EnrollRequest) (*pb.Enrollment, error) {
+	userID, err := e.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := e.EnrollmentDB.Enroll(ctx, dto.EnrollmentInputDto{UserID: userID, CourseID: in.CourseId})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return enrollmentToPb(enrollment), nil
+}
+
+func (e *EnrollmentService) GetEnrollment(ctx context.Context, in *pb.EnrollmentGetRequest) (*pb.Enrollment, error) {
+	userID, err := e.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enrollment, err := e.EnrollmentDB.Find(ctx, userID, in.CourseId)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return enrollmentToPb(enrollment), nil
+}
+
+func (e *EnrollmentService) ListMyEnrollments(ctx context.Context, in *pb.ListMyEnrollmentsRequest) (*pb.Enrollments, error) {
+	userID, err := e.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := e.EnrollmentDB.FindByUserID(ctx, userID, query.Page{Limit: int(in.Limit), Cursor: in.Cursor})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return enrollmentsToPb(enrollments), nil
+}
+
+func (e *EnrollmentService) UpdateProgress(ctx context.Context, in *pb.UpdateProgressRequest) (*pb.Response, error) {
+	userID, err := e.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = e.EnrollmentDB.UpdateProgress(ctx, dto.EnrollmentInputDto{UserID: userID, CourseID: in.CourseId, Progress: int(in.Progress)})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Progress updated successfully"}, nil
+}
+
+func (e *EnrollmentService) Unenroll(ctx context.Context, in *pb.UnenrollRequest) (*pb.Response, error) {
+	userID, err := e.me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.EnrollmentDB.Unenroll(ctx, userID, in.CourseId); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Unenrolled successfully"}, nil
+}
+
+// ListStudents lists the enrollments in a course, ordered by user id.
+func (e *EnrollmentService) ListStudents(ctx context.Context, in *pb.ListStudentsRequest) (*pb.Enrollments, error) {
+	enrollments, err := e.EnrollmentDB.FindByCourseID(ctx, in.CourseId, query.Page{Limit: int(in.Limit), Cursor: in.Cursor})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return enrollmentsToPb(enrollments), nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusError gives repository errors their gRPC status code. Other errors
+// are returned as they are.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, database.ErrStaleVersion):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, database.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, database.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, database.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, database.ErrConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, database.ErrHasDependents):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, database.ErrValidation), errors.Is(err, query.ErrInvalidCursor), errors.Is(err, query.ErrInvalidSort), errors.Is(err, query.ErrInvalidSearch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type HistoryService struct {
+	pb.UnimplementedHistoryServiceServer
+	HistoryDB database.HistoryRepositoryInterface
+}
+
+func NewHistoryService(historyDB database.HistoryRepositoryInterface) *HistoryService {
+	return &HistoryService{HistoryDB: historyDB}
+}
+
+// ListHistory lists the changes of a course or category, oldest first.
+func (h *HistoryService) ListHistory(ctx context.Context, in *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	if in.EntityType != audit.Course && in.EntityType != audit.Category {
+		return nil, status.Error(codes.InvalidArgument, "entity_type must be course or category")
+	}
+	history, err := h.HistoryDB.List(ctx, query.HistorySpec{
+		EntityType: in.EntityType,
+		EntityID:   in.EntityId,
+		Page:       query.Page{Limit: int(in.Limit), Cursor: in.Cursor},
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	entries := []*pb.HistoryEntry{}
+	for _, entry := range history.Entries {
+		entries = append(entries, &pb.HistoryEntry{
+			Id:         entry.ID,
+			EntityType: entry.EntityType,
+			EntityId:   entry.EntityID,
+			Action:     entry.Action,
+			Before:     string(entry.Before),
+			After:      string(entry.After),
+			ChangedBy:  entry.ChangedBy,
+			ChangedAt:  timestamppb.New(entry.ChangedAt),
+		})
+	}
+	return &pb.HistoryResponse{Entries: entries, NextCursor: history.NextCursor}, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+)
+
+// InstructorService serves the instructors of courses and the courses users
+// teach.
+type InstructorService struct {
+	pb.UnimplementedInstructorServiceServer
+	CourseDB     database.CourseRepositoryInterface
+	InstructorDB database.InstructorRepositoryInterface
+}
+
+func NewInstructorService(courseDB database.CourseRepositoryInterface, instructorDB database.InstructorRepositoryInterface) *InstructorService {
+	return &InstructorService{
+		CourseDB:     courseDB,
+		InstructorDB: instructorDB,
+	}
+}
+
+func (i *InstructorService) AddInstructor(ctx context.Context, in *pb.AddInstructorRequest) (*pb.Instructor, error) {
+	instructor, err := i.InstructorDB.Add(ctx, dto.InstructorInputDto{CourseID: in.CourseId, UserID: in.UserId})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return instructorToPb(instructor), nil
+}
+
+// RemoveInstructor answers ALREADY_EXISTS, as other conflicts, rather than
+// leave a course without instructors.
+func (i *InstructorService) RemoveInstructor(ctx context.Context, in *pb.RemoveInstructorRequest) (*pb.Response, error) {
+	if err := i.InstructorDB.Remove(ctx, in.CourseId, in.UserId); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Instructor removed successfully"}, nil
+}
+
+func (i *InstructorService) ListInstructors(ctx context.Context, in *pb.ListInstructorsRequest) (*pb.Instructors, error) {
+	instructors, err := i.InstructorDB.FindByCourseID(ctx, in.CourseId)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	pbInstructors := []*pb.Instructor{}
+	for _, instructor := range instructors.Instructors {
+		pbInstructors = append(pbInstructors, instructorToPb(instructor))
+	}
+	return &pb.Instructors{Instructors: pbInstructors}, nil
+}
+
+func (i *InstructorService) ListTaughtCourses(ctx context.Context, in *pb.ListTaughtCoursesRequest) (*pb.Courses, error) {
+	courses, err := i.CourseDB.FindByInstructorID(ctx, in.UserId, query.Page{Limit: int(in.Limit), Cursor: in.Cursor})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	pbCourses := []*pb.Course{}
+	for _, course := range courses.Courses {
+		pbCourses = append(pbCourses, courseToPb(course))
+	}
+	return &pb.Courses{Courses: pbCourses, NextCursor: courses.NextCursor}, nil
+}
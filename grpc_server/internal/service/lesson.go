@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+)
+
+// LessonService serves the modules of courses and the lessons of modules.
+type LessonService struct {
+	pb.UnimplementedLessonServiceServer
+	ModuleDB database.ModuleRepositoryInterface
+	LessonDB database.LessonRepositoryInterface
+}
+
+func NewLessonService(moduleDB database.ModuleRepositoryInterface, lessonDB database.LessonRepositoryInterface) *LessonService {
+	return &LessonService{
+		ModuleDB: moduleDB,
+		LessonDB: lessonDB,
+	}
+}
+
+// CreateModule adds a module at the end of its course.
+func (l *LessonService) CreateModule(ctx context.Context, in *pb.CreateModuleRequest) (*pb.Module, error) {
+	module, err := l.ModuleDB.Create(ctx, dto.ModuleInputDto{CourseID: in.CourseId, Title: in.Title, Description: in.Description})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return moduleToPb(module), nil
+}
+
+func (l *LessonService) GetModule(ctx context.Context, in *pb.ModuleGetRequest) (*pb.Module, error) {
+	module, err := l.ModuleDB.Find(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return moduleToPb(module), nil
+}
+
+func (l *LessonService) ListModules(ctx context.Context, in *pb.ListModulesRequest) (*pb.Modules, error) {
+	modules, err := l.ModuleDB.FindByCourseID(ctx, in.CourseId)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	pbModules := []*pb.Module{}
+	for _, module := range modules.Modules {
+		pbModules = append(pbModules, moduleToPb(module))
+	}
+	return &pb.Modules{Modules: pbModules}, nil
+}
+
+func (l *LessonService) UpdateModule(ctx context.Context, in *pb.ModuleUpdateRequest) (*pb.Response, error) {
+	module := dto.ModuleInputDto{ID: in.Id, Title: in.Title, Description: in.Description, Version: in.Version}
+	err := l.ModuleDB.Update(ctx, module)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Module updated successfully"}, nil
+}
+
+// DeleteModule deletes a module with its lessons.
+func (l *LessonService) DeleteModule(ctx context.Context, in *pb.ModuleDeleteRequest) (*pb.Response, error) {
+	err := l.ModuleDB.Delete(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Module deleted successfully"}, nil
+}
+
+func (l *LessonService) ReorderModules(ctx context.Context, in *pb.ReorderModulesRequest) (*pb.Response, error) {
+	err := l.ModuleDB.Reorder(ctx, in.CourseId, in.Ids)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Modules reordered successfully"}, nil
+}
+
+// CreateLesson adds a lesson at the end of its module.
+func (l *LessonService) CreateLesson(ctx context.Context, in *pb.CreateLessonRequest) (*pb.Lesson, error) {
+	lesson, err := l.LessonDB.Create(ctx, dto.LessonInputDto{
+		ModuleID:        in.ModuleId,
+		Title:           in.Title,
+		ContentType:     contentTypeFromPb(in.ContentType),
+		DurationSeconds: int64(in.Duration.AsDuration().Seconds()),
+		Body:            in.Body,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return lessonToPb(lesson), nil
+}
+
+func (l *LessonService) GetLesson(ctx context.Context, in *pb.LessonGetRequest) (*pb.Lesson, error) {
+	lesson, err := l.LessonDB.Find(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return lessonToPb(lesson), nil
+}
+
+func (l *LessonService) ListLessons(ctx context.Context, in *pb.ListLessonsRequest) (*pb.Lessons, error) {
+	lessons, err := l.LessonDB.FindByModuleID(ctx, in.ModuleId)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	pbLessons := []*pb.Lesson{}
+	for _, lesson := range lessons.Lessons {
+		pbLessons = append(pbLessons, lessonToPb(lesson))
+	}
+	return &pb.Lessons{Lessons: pbLessons}, nil
+}
+
+func (l *LessonService) UpdateLesson(ctx context.Context, in *pb.LessonUpdateRequest) (*pb.Response, error) {
+	lesson := dto.LessonInputDto{
+		ID:              in.Id,
+		Title:           in.Title,
+		ContentType:     contentTypeFromPb(in.ContentType),
+		DurationSeconds: int64(in.Duration.AsDuration().Seconds()),
+		Body:            in.Body,
+		Version:         in.Version,
+	}
+	err := l.LessonDB.Update(ctx, lesson)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Lesson updated successfully"}, nil
+}
+
+func (l *LessonService) DeleteLesson(ctx context.Context, in *pb.LessonDeleteRequest) (*pb.Response, error) {
+	err := l.LessonDB.Delete(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Lesson deleted successfully"}, nil
+}
+
+// ReorderLessons puts the lessons of a module in the order of ids, which
+// must list every one of them.
+func (l *LessonService) ReorderLessons(ctx context.Context, in *pb.ReorderLessonsRequest) (*pb.Response, error) {
+	err := l.LessonDB.Reorder(ctx, in.ModuleId, in.Ids)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Lessons reordered successfully"}, nil
+}
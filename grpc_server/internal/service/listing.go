@@ -0,0 +1,30 @@
+package service
+
+import (
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func sortFromProto(field pb.SortField, descending bool) (query.Sort, error) {
+	switch field {
+	case pb.SortField_SORT_FIELD_ID:
+		return query.Sort{Field: query.SortByID, Desc: descending}, nil
+	case pb.SortField_SORT_FIELD_NAME:
+		return query.Sort{Field: query.SortByName, Desc: descending}, nil
+	case pb.SortField_SORT_FIELD_CREATED:
+		return query.Sort{Field: query.SortByCreated, Desc: descending}, nil
+	}
+	return query.Sort{}, query.ErrInvalidSort
+}
+
+// deletedAt converts the deletion time of a listed item, nil when it is not
+// deleted.
+func deletedAt(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
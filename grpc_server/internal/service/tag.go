@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+	"github.com/antoniofmoliveira/courses/grpcproto/pb"
+)
+
+// TagService serves the tags and the tags of courses.
+type TagService struct {
+	pb.UnimplementedTagServiceServer
+	TagDB database.TagRepositoryInterface
+}
+
+func NewTagService(tagDB database.TagRepositoryInterface) *TagService {
+	return &TagService{
+		TagDB: tagDB,
+	}
+}
+
+func (t *TagService) CreateTag(ctx context.Context, in *pb.CreateTagRequest) (*pb.Tag, error) {
+	tag, err := t.TagDB.Create(ctx, dto.TagInputDto{Name: in.Name})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tagToPb(tag), nil
+}
+
+func (t *TagService) GetTag(ctx context.Context, in *pb.TagGetRequest) (*pb.Tag, error) {
+	tag, err := t.TagDB.Find(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tagToPb(tag), nil
+}
+
+func (t *TagService) ListTags(ctx context.Context, in *pb.ListTagsRequest) (*pb.Tags, error) {
+	tags, err := t.TagDB.List(ctx, query.TagSpec{NamePrefix: in.NamePrefix, Page: query.Page{Limit: int(in.Limit), Cursor: in.Cursor}})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tagsToPb(tags), nil
+}
+
+func (t *TagService) DeleteTag(ctx context.Context, in *pb.TagDeleteRequest) (*pb.Response, error) {
+	if err := t.TagDB.Delete(ctx, in.Id); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Tag deleted successfully"}, nil
+}
+
+func (t *TagService) TagCourse(ctx context.Context, in *pb.TagCourseRequest) (*pb.Tag, error) {
+	tag, err := t.TagDB.Tag(ctx, dto.CourseTagInputDto{CourseID: in.CourseId, Name: in.Name})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tagToPb(tag), nil
+}
+
+func (t *TagService) UntagCourse(ctx context.Context, in *pb.UntagCourseRequest) (*pb.Response, error) {
+	if err := t.TagDB.Untag(ctx, in.CourseId, in.TagId); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "Course untagged successfully"}, nil
+}
+
+func (t *TagService) ListCourseTags(ctx context.Context, in *pb.ListCourseTagsRequest) (*pb.Tags, error) {
+	tags, err := t.TagDB.FindByCourseID(ctx, in.CourseId)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return tagsToPb(tags), nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
 	"github.com/antoniofmoliveira/courses/dto"
 	"github.com/antoniofmoliveira/courses/entity"
 	"github.com/antoniofmoliveira/courses/grpcproto/pb"
@@ -14,12 +15,14 @@ import (
 
 type UserService struct {
 	pb.UnimplementedUserServiceServer
-	db database.UserRepositoryInterface
+	db     database.UserRepositoryInterface
+	admins database.AdminChecker
 }
 
-func NewUserService(db database.UserRepositoryInterface) *UserService {
+func NewUserService(db database.UserRepositoryInterface, admins database.AdminChecker) *UserService {
 	return &UserService{
-		db: db,
+		db:     db,
+		admins: admins,
 	}
 }
 
@@ -28,7 +31,7 @@ func (u *UserService) CreateUser(ctx context.Context, in *pb.CreateUserRequest)
 
 	entityUser, err := entity.NewUser(user.Name, user.Email, user.Password)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	user = dto.UserInputDto{
 		ID:       entityUser.ID,
@@ -37,31 +40,39 @@ func (u *UserService) CreateUser(ctx context.Context, in *pb.CreateUserRequest)
 		Password: entityUser.Password,
 	}
 
-	userOutputDto, err := u.db.Create(user)
+	userOutputDto, err := u.db.Create(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	return &pb.User{Id: userOutputDto.ID, Name: userOutputDto.Name, Email: userOutputDto.Email}, nil
+	return userToPb(userOutputDto), nil
 }
 
 func (u *UserService) GetUser(ctx context.Context, in *pb.UserGetRequest) (*pb.User, error) {
-	userOutputDto, err := u.db.Find(in.Id)
+	userOutputDto, err := u.db.Find(ctx, in.Id)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
-	return &pb.User{Id: userOutputDto.ID, Name: userOutputDto.Name, Email: userOutputDto.Email}, nil
+	return userToPb(userOutputDto), nil
 }
 
-func (u *UserService) ListUsers(ctx context.Context, in *pb.Blank) (*pb.Users, error) {
-	usersOutputDto, err := u.db.FindAll()
+func (u *UserService) ListUsers(ctx context.Context, in *pb.ListUsersRequest) (*pb.Users, error) {
+	if in.IncludeDeleted {
+		if err := u.admins.CheckAdmin(ctx); err != nil {
+			return nil, statusError(err)
+		}
+	}
+	usersOutputDto, err := u.db.List(ctx, query.UserSpec{
+		IncludeDeleted: in.IncludeDeleted,
+		Page:           query.Page{Limit: int(in.Limit), Cursor: in.Cursor},
+	})
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	pbUsers := []*pb.User{}
 	for _, user := range usersOutputDto.Users {
-		pbUsers = append(pbUsers, &pb.User{Id: user.ID, Name: user.Name, Email: user.Email})
+		pbUsers = append(pbUsers, userToPb(user))
 	}
-	return &pb.Users{Users: pbUsers}, nil
+	return &pb.Users{Users: pbUsers, NextCursor: usersOutputDto.NextCursor}, nil
 }
 
 func (u *UserService) UpdateUser(ctx context.Context, in *pb.UserUpdateRequest) (*pb.User, error) {
@@ -70,31 +81,43 @@ func (u *UserService) UpdateUser(ctx context.Context, in *pb.UserUpdateRequest)
 		Name:     in.Name,
 		Email:    in.Email,
 		Password: in.Password,
+		Version:  in.Version,
 	}
-	err := u.db.Update(user)
+	err := u.db.Update(ctx, user)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	return &pb.User{Id: user.ID, Name: user.Name, Email: user.Email}, nil
 }
 
 func (u *UserService) DeleteUser(ctx context.Context, in *pb.UserDeleteRequest) (*pb.Response, error) {
-	err := u.db.Delete(in.Id)
+	err := u.db.Delete(ctx, in.Id)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 	return &pb.Response{IsSuccess: true, Message: "User deleted successfully"}, nil
 }
 
+func (u *UserService) RestoreUser(ctx context.Context, in *pb.UserRestoreRequest) (*pb.Response, error) {
+	if err := u.admins.CheckAdmin(ctx); err != nil {
+		return nil, statusError(err)
+	}
+	err := u.db.Restore(ctx, in.Id)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.Response{IsSuccess: true, Message: "User restored successfully"}, nil
+}
+
 func (u *UserService) GetJWTToken(ctx context.Context, in *pb.UserForJWT) (*pb.JWTToken, error) {
 	userCredentials := dto.UserInputDto{
 		Email:    in.Email,
 		Password: in.Password,
 	}
 
-	userFromDB, err := u.db.FindByEmail(userCredentials.Email)
+	userFromDB, err := u.db.FindByEmail(ctx, userCredentials.Email)
 	if err != nil {
-		return nil, err
+		return nil, statusError(err)
 	}
 
 	entityUser := entity.User{
@@ -104,7 +127,7 @@ func (u *UserService) GetJWTToken(ctx context.Context, in *pb.UserForJWT) (*pb.J
 
 	if !entityUser.ValidatePassword(userCredentials.Password) {
 		slog.Error("GetJWT", "msg", err)
-		return nil, err
+		return nil, statusError(err)
 	}
 
 	jwt := ctx.Value("jwt").(*jwtauth.JWTAuth)
@@ -36,32 +36,93 @@ func main() {
 					middleware.WithValue("jwtExpiresIn", cfg.JWTExpiresIn)(
 						next))))
 	}
-	// public middlewares plus verification
+	// public middlewares plus verification, changes are attributed to the
+	// token subject
 	private := func(next http.Handler) http.Handler {
 		return public(
 			jwtauth.Verifier(cfg.TokenAuth)(
 				jwtauth.Authenticator(
-					next)))
+					handlers.Actor(
+						next))))
 	}
 	r := http.NewServeMux()
-	categoryHandler := handlers.NewCategoryHandler(categoryDb)
-	courseHandler := handlers.NewCourseHandler(courseDb)
-	userHandler := handlers.NewUserHandler(userDB)
+	categoryHandler := handlers.NewCategoryHandler(categoryDb, dbi)
+	courseHandler := handlers.NewCourseHandler(courseDb, dbi)
+	userHandler := handlers.NewUserHandler(userDB, dbi)
+	adminHandler := handlers.NewAdminHandler(dbi, dbi, dbi)
+	historyHandler := handlers.NewHistoryHandler(dbi.HistoryRepository)
+	searchHandler := handlers.NewSearchHandler(dbi.SearchRepository)
+	moduleHandler := handlers.NewModuleHandler(dbi.ModuleRepository)
+	lessonHandler := handlers.NewLessonHandler(dbi.ModuleRepository, dbi.LessonRepository)
+	enrollmentHandler := handlers.NewEnrollmentHandler(userDB, dbi.EnrollmentRepository)
+	instructorHandler := handlers.NewInstructorHandler(courseDb, dbi.InstructorRepository)
+	tagHandler := handlers.NewTagHandler(dbi.TagRepository)
 
 	r.Handle("GET /categories", private(http.HandlerFunc(categoryHandler.FindAllCategories)))
 	r.Handle("GET /categories/{id}", private(http.HandlerFunc(categoryHandler.FindCategory)))
 	r.Handle("POST /categories", private(http.HandlerFunc(categoryHandler.CreateCategory)))
 	r.Handle("PUT /categories/{id}", private(http.HandlerFunc(categoryHandler.UpdateCategory)))
 	r.Handle("DELETE /categories/{id}", private(http.HandlerFunc(categoryHandler.DeleteCategory)))
+	r.Handle("POST /categories/{id}/restore", private(http.HandlerFunc(categoryHandler.RestoreCategory)))
+	r.Handle("GET /categories/{id}/history", private(http.HandlerFunc(historyHandler.CategoryHistory)))
+	r.Handle("GET /categories/{id}/children", private(http.HandlerFunc(categoryHandler.FindChildren)))
+	r.Handle("GET /categories/{id}/ancestors", private(http.HandlerFunc(categoryHandler.FindAncestors)))
+	r.Handle("GET /categories/{id}/subtree", private(http.HandlerFunc(categoryHandler.FindSubtree)))
+	r.Handle("GET /categories/{id}/courses", private(http.HandlerFunc(courseHandler.FindCoursesInCategoryTree)))
 
 	r.Handle("GET /courses", private(http.HandlerFunc(courseHandler.FindAllCourses)))
 	r.Handle("GET /courses/{id}", private(http.HandlerFunc(courseHandler.FindCourse)))
 	r.Handle("POST /courses", private(http.HandlerFunc(courseHandler.CreateCourse)))
+	r.Handle("POST /courses:batch", private(http.HandlerFunc(courseHandler.BatchCreateCourses)))
 	r.Handle("PUT /courses/{id}", private(http.HandlerFunc(courseHandler.UpdateCourse)))
 	r.Handle("DELETE /courses/{id}", private(http.HandlerFunc(courseHandler.DeleteCourse)))
+	r.Handle("POST /courses/{id}/restore", private(http.HandlerFunc(courseHandler.RestoreCourse)))
+	r.Handle("GET /courses/{id}/history", private(http.HandlerFunc(historyHandler.CourseHistory)))
+
+	r.Handle("GET /courses/{id}/modules", private(http.HandlerFunc(moduleHandler.FindModules)))
+	r.Handle("POST /courses/{id}/modules", private(http.HandlerFunc(moduleHandler.CreateModule)))
+	r.Handle("PUT /courses/{id}/modules:order", private(http.HandlerFunc(moduleHandler.ReorderModules)))
+	r.Handle("GET /courses/{id}/modules/{moduleID}", private(http.HandlerFunc(moduleHandler.FindModule)))
+	r.Handle("PUT /courses/{id}/modules/{moduleID}", private(http.HandlerFunc(moduleHandler.UpdateModule)))
+	r.Handle("DELETE /courses/{id}/modules/{moduleID}", private(http.HandlerFunc(moduleHandler.DeleteModule)))
+
+	r.Handle("GET /courses/{id}/modules/{moduleID}/lessons", private(http.HandlerFunc(lessonHandler.FindLessons)))
+	r.Handle("POST /courses/{id}/modules/{moduleID}/lessons", private(http.HandlerFunc(lessonHandler.CreateLesson)))
+	r.Handle("PUT /courses/{id}/modules/{moduleID}/lessons:order", private(http.HandlerFunc(lessonHandler.ReorderLessons)))
+	r.Handle("GET /courses/{id}/modules/{moduleID}/lessons/{lessonID}", private(http.HandlerFunc(lessonHandler.FindLesson)))
+	r.Handle("PUT /courses/{id}/modules/{moduleID}/lessons/{lessonID}", private(http.HandlerFunc(lessonHandler.UpdateLesson)))
+	r.Handle("DELETE /courses/{id}/modules/{moduleID}/lessons/{lessonID}", private(http.HandlerFunc(lessonHandler.DeleteLesson)))
+
+	r.Handle("GET /courses/{id}/students", private(http.HandlerFunc(enrollmentHandler.FindStudents)))
+
+	r.Handle("GET /courses/{id}/instructors", private(http.HandlerFunc(instructorHandler.FindInstructors)))
+	r.Handle("POST /courses/{id}/instructors", private(http.HandlerFunc(instructorHandler.AddInstructor)))
+	r.Handle("DELETE /courses/{id}/instructors/{userID}", private(http.HandlerFunc(instructorHandler.RemoveInstructor)))
+
+	r.Handle("GET /courses/{id}/tags", private(http.HandlerFunc(tagHandler.FindCourseTags)))
+	r.Handle("POST /courses/{id}/tags", private(http.HandlerFunc(tagHandler.TagCourse)))
+	r.Handle("DELETE /courses/{id}/tags/{tagID}", private(http.HandlerFunc(tagHandler.UntagCourse)))
+
+	r.Handle("GET /tags", private(http.HandlerFunc(tagHandler.FindTags)))
+	r.Handle("POST /tags", private(http.HandlerFunc(tagHandler.CreateTag)))
+	r.Handle("GET /tags/{id}", private(http.HandlerFunc(tagHandler.FindTag)))
+	r.Handle("DELETE /tags/{id}", private(http.HandlerFunc(tagHandler.DeleteTag)))
+
+	r.Handle("GET /me/enrollments", private(http.HandlerFunc(enrollmentHandler.FindMyEnrollments)))
+	r.Handle("POST /me/enrollments", private(http.HandlerFunc(enrollmentHandler.Enroll)))
+	r.Handle("GET /me/enrollments/{courseID}", private(http.HandlerFunc(enrollmentHandler.FindMyEnrollment)))
+	r.Handle("PUT /me/enrollments/{courseID}", private(http.HandlerFunc(enrollmentHandler.UpdateProgress)))
+	r.Handle("DELETE /me/enrollments/{courseID}", private(http.HandlerFunc(enrollmentHandler.Unenroll)))
+
+	r.Handle("GET /search", private(http.HandlerFunc(searchHandler.Search)))
 
 	r.Handle("POST /users", private(http.HandlerFunc(userHandler.CreateUser)))
 	r.Handle("GET /users", private(http.HandlerFunc(userHandler.FindByEmail)))
+	r.Handle("POST /users/{id}/restore", private(http.HandlerFunc(userHandler.RestoreUser)))
+	r.Handle("GET /users/{id}/courses", private(http.HandlerFunc(instructorHandler.FindTaughtCourses)))
+
+	r.Handle("POST /admin/purge", private(http.HandlerFunc(adminHandler.Purge)))
+	r.Handle("GET /admin/cache", private(http.HandlerFunc(adminHandler.CacheStats)))
 
 	// r.Handle("POST /userss", public(http.HandlerFunc(userHandler.CreateUser)))
 
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+)
+
+type AdminHandler struct {
+	Purger database.Purger
+	Admins database.AdminChecker
+	Cache  database.CacheReporter
+}
+
+func NewAdminHandler(purger database.Purger, admins database.AdminChecker, cache database.CacheReporter) *AdminHandler {
+	return &AdminHandler{Purger: purger, Admins: admins, Cache: cache}
+}
+
+// Purge hard deletes what was soft deleted more than ?older_than= ago, a Go
+// duration such as 720h. It defaults to database.DefaultRetention. Only
+// admins may purge.
+func (h *AdminHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if err := h.Admins.CheckAdmin(r.Context()); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	retention := database.DefaultRetention
+	if v := r.URL.Query().Get("older_than"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			http.Error(w, "invalid older_than", http.StatusBadRequest)
+			return
+		}
+		retention = d
+	}
+
+	purged, err := h.Purger.Purge(r.Context(), time.Now().Add(-retention))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(purged)
+}
+
+// CacheStats reports the hits, misses, evictions and size of each cached
+// lookup, all zero when CACHE_ENABLED is off. Only admins may see them.
+func (h *AdminHandler) CacheStats(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if err := h.Admins.CheckAdmin(r.Context()); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.Cache.CacheStats())
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+const ndjson = "application/x-ndjson"
+
+// maxBatchItems bounds the items of one batch request, all applied in one
+// transaction.
+const maxBatchItems = 1000
+
+var errBatchTooLarge = fmt.Errorf("a batch holds at most %d items", maxBatchItems)
+
+// batchResult is one line of a batch response: the status the item would
+// have had on its own, with the error when it was rejected.
+type batchResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// decodeNDJSON reads one JSON value per line of body.
+func decodeNDJSON[T any](body io.Reader) ([]T, error) {
+	var items []T
+	decoder := json.NewDecoder(body)
+	for {
+		var item T
+		err := decoder.Decode(&item)
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", len(items)+1, err)
+		}
+		if len(items) == maxBatchItems {
+			return nil, errBatchTooLarge
+		}
+		items = append(items, item)
+	}
+}
+
+// writeBatch answers with one line per item; okStatus is the status of the
+// items applied.
+func writeBatch(w http.ResponseWriter, results []dto.BatchItemOutputDto, okStatus int) {
+	w.Header().Set("Content-Type", ndjson)
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	for _, result := range results {
+		line := batchResult{Index: result.Index, ID: result.ID, Status: okStatus}
+		if result.Err != nil {
+			line.Status, line.Error = errorStatus(result.Err), result.Err.Error()
+		}
+		encoder.Encode(line)
+	}
+}
@@ -10,11 +10,13 @@ import (
 
 type CategoryHandler struct {
 	CategoryDB database.CategoryRepositoryInterface
+	Admins     database.AdminChecker
 }
 
-func NewCategoryHandler(categoryDB database.CategoryRepositoryInterface) *CategoryHandler {
+func NewCategoryHandler(categoryDB database.CategoryRepositoryInterface, admins database.AdminChecker) *CategoryHandler {
 	return &CategoryHandler{
 		CategoryDB: categoryDB,
+		Admins:     admins,
 	}
 }
 
@@ -35,13 +37,14 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	categoryOutputDto, err := h.CategoryDB.Create(categoryInputDto)
+	categoryOutputDto, err := h.CategoryDB.Create(r.Context(), categoryInputDto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(categoryOutputDto.Version))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(categoryOutputDto)
 }
@@ -58,13 +61,14 @@ func (h *CategoryHandler) FindCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	category, err := h.CategoryDB.Find(id)
+	category, err := h.CategoryDB.Find(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(category.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(category)
 }
@@ -75,9 +79,22 @@ func (h *CategoryHandler) FindAllCategories(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	categories, err := h.CategoryDB.FindAll()
+	spec, err := categorySpecFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if spec.IncludeDeleted {
+		if err := h.Admins.CheckAdmin(r.Context()); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+	}
+
+	categories, err := h.CategoryDB.List(r.Context(), spec)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -103,9 +120,18 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = h.CategoryDB.Update(categoryInputDto)
+	version, err := ifMatch(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		categoryInputDto.Version = version
+	}
+
+	err = h.CategoryDB.Update(r.Context(), categoryInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), updateStatus(err, version != 0))
 		return
 	}
 
@@ -120,12 +146,92 @@ func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request)
 	}
 
 	id := r.PathValue("id")
-	err := h.CategoryDB.Delete(id)
+	err := h.CategoryDB.Delete(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *CategoryHandler) RestoreCategory(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	id := r.PathValue("id")
+	err := h.CategoryDB.Restore(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// FindChildren lists the categories directly under the category of the
+// path, a page at a time.
+func (h *CategoryHandler) FindChildren(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	categories, err := h.CategoryDB.FindChildren(r.Context(), r.PathValue("id"), page)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(categories)
+}
+
+// FindAncestors lists the categories above the category of the path, top
+// level first, as a breadcrumb.
+func (h *CategoryHandler) FindAncestors(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	categories, err := h.CategoryDB.FindAncestors(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(categories)
+}
+
+// FindSubtree lists the category of the path and every category below it,
+// level by level.
+func (h *CategoryHandler) FindSubtree(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	categories, err := h.CategoryDB.FindSubtree(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(categories)
 }
@@ -1,5 +1,166 @@
 package handlers
 
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+)
+
 type Error struct {
 	Message string `json:"message"`
-}
\ No newline at end of file
+}
+
+var (
+	errInvalidLimit          = errors.New("invalid limit")
+	errInvalidIncludeDeleted = errors.New("invalid include_deleted")
+	errInvalidIfMatch        = errors.New("invalid If-Match header")
+	errInvalidTagMatch       = errors.New("invalid tag_match, want any or all")
+)
+
+// errorStatus maps an error from a repository to the HTTP status to answer
+// with.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, database.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, database.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, database.ErrConflict), errors.Is(err, database.ErrHasDependents):
+		return http.StatusConflict
+	case errors.Is(err, database.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, query.ErrInvalidCursor), errors.Is(err, query.ErrInvalidSort), errors.Is(err, query.ErrInvalidSearch):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// etag is the entity tag of an item at the given version.
+func etag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatch reads the version an update is made against from the If-Match
+// header: 0, no check, when it is missing or "*".
+func ifMatch(r *http.Request) (int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// updateStatus is errorStatus for an update: a stale version named by
+// If-Match is a failed precondition rather than a conflict.
+func updateStatus(err error, conditional bool) int {
+	if conditional && errors.Is(err, database.ErrStaleVersion) {
+		return http.StatusPreconditionFailed
+	}
+	return errorStatus(err)
+}
+
+// pageFromRequest reads the ?limit= and ?cursor= parameters of a list request.
+func pageFromRequest(r *http.Request) (query.Page, error) {
+	page := query.Page{Cursor: r.URL.Query().Get("cursor")}
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return page, errInvalidLimit
+		}
+		page.Limit = n
+	}
+	return page, nil
+}
+
+// includeDeleted reads the ?include_deleted= parameter of the admin listings.
+func includeDeleted(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("include_deleted")
+	if v == "" {
+		return false, nil
+	}
+	include, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errInvalidIncludeDeleted
+	}
+	return include, nil
+}
+
+// courseSpecFromRequest maps the ?name_prefix=, ?category_id=,
+// ?category_tree=, ?tags=, ?tag_match=, ?include_deleted= and ?sort=
+// parameters, plus the page, onto a course listing spec. Tags are comma
+// separated; tag_match is any, the default, or all.
+func courseSpecFromRequest(r *http.Request) (query.CourseSpec, error) {
+	page, err := pageFromRequest(r)
+	if err != nil {
+		return query.CourseSpec{}, err
+	}
+	sort, err := query.ParseSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		return query.CourseSpec{}, err
+	}
+	deleted, err := includeDeleted(r)
+	if err != nil {
+		return query.CourseSpec{}, err
+	}
+	var allTags bool
+	switch r.URL.Query().Get("tag_match") {
+	case "", "any":
+	case "all":
+		allTags = true
+	default:
+		return query.CourseSpec{}, errInvalidTagMatch
+	}
+	var tags []string
+	if v := r.URL.Query().Get("tags"); v != "" {
+		tags = strings.Split(v, ",")
+	}
+	return query.CourseSpec{
+		NamePrefix:     r.URL.Query().Get("name_prefix"),
+		CategoryID:     r.URL.Query().Get("category_id"),
+		CategoryTree:   r.URL.Query().Get("category_tree"),
+		Tags:           tags,
+		AllTags:        allTags,
+		IncludeDeleted: deleted,
+		Sort:           sort,
+		Page:           page,
+	}, nil
+}
+
+// categorySpecFromRequest maps the ?name_prefix=, ?parent_id=,
+// ?include_deleted= and ?sort= parameters, plus the page, onto a category
+// listing spec.
+func categorySpecFromRequest(r *http.Request) (query.CategorySpec, error) {
+	page, err := pageFromRequest(r)
+	if err != nil {
+		return query.CategorySpec{}, err
+	}
+	sort, err := query.ParseSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		return query.CategorySpec{}, err
+	}
+	deleted, err := includeDeleted(r)
+	if err != nil {
+		return query.CategorySpec{}, err
+	}
+	return query.CategorySpec{
+		NamePrefix:     r.URL.Query().Get("name_prefix"),
+		ParentID:       r.URL.Query().Get("parent_id"),
+		IncludeDeleted: deleted,
+		Sort:           sort,
+		Page:           page,
+	}, nil
+}
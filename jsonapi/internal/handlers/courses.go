@@ -10,11 +10,13 @@ import (
 
 type CourseHandler struct {
 	CourseDB database.CourseRepositoryInterface
+	Admins   database.AdminChecker
 }
 
-func NewCourseHandler(courseDB database.CourseRepositoryInterface) *CourseHandler {
+func NewCourseHandler(courseDB database.CourseRepositoryInterface, admins database.AdminChecker) *CourseHandler {
 	return &CourseHandler{
 		CourseDB: courseDB,
+		Admins:   admins,
 	}
 }
 
@@ -24,9 +26,22 @@ func (c *CourseHandler) FindAllCourses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	courses, err := c.CourseDB.FindAll()
+	spec, err := courseSpecFromRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if spec.IncludeDeleted {
+		if err := c.Admins.CheckAdmin(r.Context()); err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+	}
+
+	courses, err := c.CourseDB.List(r.Context(), spec)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -47,13 +62,14 @@ func (c *CourseHandler) FindCourse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	course, err := c.CourseDB.Find(id)
+	course, err := c.CourseDB.Find(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(course.Version))
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(course)
 }
@@ -75,17 +91,47 @@ func (c *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	courseOutputDto, err := c.CourseDB.Create(courseInputDto)
+	courseOutputDto, err := c.CourseDB.Create(r.Context(), courseInputDto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(courseOutputDto.Version))
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(courseOutputDto)
 }
 
+// BatchCreateCourses creates the courses of an NDJSON body, one per line, in
+// one transaction. It answers with one NDJSON line per course, in order,
+// holding its id or why it was rejected; the other courses are created all
+// the same.
+func (c *CourseHandler) BatchCreateCourses(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != ndjson {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != ndjson {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	courses, err := decodeNDJSON[dto.CourseInputDto](r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := c.CourseDB.CreateBatch(r.Context(), courses)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	writeBatch(w, results, http.StatusCreated)
+}
+
 func (c *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Content-Type") != "application/json" {
 		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
@@ -103,9 +149,18 @@ func (c *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = c.CourseDB.Update(courseInputDto)
+	version, err := ifMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		courseInputDto.Version = version
+	}
+
+	err = c.CourseDB.Update(r.Context(), courseInputDto)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), updateStatus(err, version != 0))
 		return
 	}
 
@@ -120,12 +175,54 @@ func (c *CourseHandler) DeleteCourse(w http.ResponseWriter, r *http.Request) {
 	}
 
 	id := r.PathValue("id")
-	err := c.CourseDB.Delete(id)
+	err := c.CourseDB.Delete(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *CourseHandler) RestoreCourse(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	id := r.PathValue("id")
+	err := c.CourseDB.Restore(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// FindCoursesInCategoryTree lists the courses of the category of the path
+// and of every category below it, a page at a time.
+func (c *CourseHandler) FindCoursesInCategoryTree(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	courses, err := c.CourseDB.FindByCategoryTree(r.Context(), r.PathValue("id"), page)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(courses)
 }
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+var errUnknownActor = errors.New("the token does not belong to a user")
+
+// EnrollmentHandler serves the enrollments of the user of the token, under
+// /me/enrollments, and the students of a course.
+type EnrollmentHandler struct {
+	UserDB       database.UserRepositoryInterface
+	EnrollmentDB database.EnrollmentRepositoryInterface
+}
+
+func NewEnrollmentHandler(userDB database.UserRepositoryInterface, enrollmentDB database.EnrollmentRepositoryInterface) *EnrollmentHandler {
+	return &EnrollmentHandler{UserDB: userDB, EnrollmentDB: enrollmentDB}
+}
+
+// me returns the id of the user the token of the request was issued to.
+func (e *EnrollmentHandler) me(r *http.Request) (string, error) {
+	id, err := e.UserDB.FindIDByEmail(r.Context(), audit.Actor(r.Context()))
+	if errors.Is(err, database.ErrNotFound) {
+		return "", errUnknownActor
+	}
+	return id, err
+}
+
+// meStatus is errorStatus for the lookup of the user of the token.
+func meStatus(err error) int {
+	if errors.Is(err, errUnknownActor) {
+		return http.StatusUnauthorized
+	}
+	return errorStatus(err)
+}
+
+func (e *EnrollmentHandler) FindMyEnrollments(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, err := e.me(r)
+	if err != nil {
+		http.Error(w, err.Error(), meStatus(err))
+		return
+	}
+
+	enrollments, err := e.EnrollmentDB.FindByUserID(r.Context(), userID, page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(enrollments)
+}
+
+func (e *EnrollmentHandler) FindMyEnrollment(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	userID, err := e.me(r)
+	if err != nil {
+		http.Error(w, err.Error(), meStatus(err))
+		return
+	}
+
+	enrollment, err := e.EnrollmentDB.Find(r.Context(), userID, r.PathValue("courseID"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+// Enroll enrolls the user of the token in the course_id of the body.
+func (e *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var enrollmentInputDto dto.EnrollmentInputDto
+	err := json.NewDecoder(r.Body).Decode(&enrollmentInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	enrollmentInputDto.UserID, err = e.me(r)
+	if err != nil {
+		http.Error(w, err.Error(), meStatus(err))
+		return
+	}
+
+	enrollment, err := e.EnrollmentDB.Enroll(r.Context(), enrollmentInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(enrollment)
+}
+
+// UpdateProgress records the progress of the body in a course of the user
+// of the token.
+func (e *EnrollmentHandler) UpdateProgress(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var enrollmentInputDto dto.EnrollmentInputDto
+	err := json.NewDecoder(r.Body).Decode(&enrollmentInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	enrollmentInputDto.UserID, err = e.me(r)
+	if err != nil {
+		http.Error(w, err.Error(), meStatus(err))
+		return
+	}
+	enrollmentInputDto.CourseID = r.PathValue("courseID")
+
+	err = e.EnrollmentDB.UpdateProgress(r.Context(), enrollmentInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (e *EnrollmentHandler) Unenroll(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	userID, err := e.me(r)
+	if err != nil {
+		http.Error(w, err.Error(), meStatus(err))
+		return
+	}
+
+	err = e.EnrollmentDB.Unenroll(r.Context(), userID, r.PathValue("courseID"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// FindStudents lists the enrollments in the course of the path.
+func (e *EnrollmentHandler) FindStudents(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	enrollments, err := e.EnrollmentDB.FindByCourseID(r.Context(), r.PathValue("id"), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(enrollments)
+}
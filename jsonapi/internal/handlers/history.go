@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+)
+
+type HistoryHandler struct {
+	HistoryDB database.HistoryRepositoryInterface
+}
+
+func NewHistoryHandler(historyDB database.HistoryRepositoryInterface) *HistoryHandler {
+	return &HistoryHandler{HistoryDB: historyDB}
+}
+
+// CourseHistory lists the recorded changes of a course, oldest first.
+func (h *HistoryHandler) CourseHistory(w http.ResponseWriter, r *http.Request) {
+	h.history(w, r, audit.Course)
+}
+
+// CategoryHistory lists the recorded changes of a category, oldest first.
+func (h *HistoryHandler) CategoryHistory(w http.ResponseWriter, r *http.Request) {
+	h.history(w, r, audit.Category)
+}
+
+func (h *HistoryHandler) history(w http.ResponseWriter, r *http.Request, entityType string) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.HistoryDB.List(r.Context(), query.HistorySpec{
+		EntityType: entityType,
+		EntityID:   r.PathValue("id"),
+		Page:       page,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// InstructorHandler serves the instructors of a course and the courses a
+// user teaches. Only the instructors of a course and the admins may change
+// them.
+type InstructorHandler struct {
+	CourseDB     database.CourseRepositoryInterface
+	InstructorDB database.InstructorRepositoryInterface
+}
+
+func NewInstructorHandler(courseDB database.CourseRepositoryInterface, instructorDB database.InstructorRepositoryInterface) *InstructorHandler {
+	return &InstructorHandler{CourseDB: courseDB, InstructorDB: instructorDB}
+}
+
+func (i *InstructorHandler) FindInstructors(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	instructors, err := i.InstructorDB.FindByCourseID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(instructors)
+}
+
+// AddInstructor makes the user_id of the body an instructor of the course of
+// the path.
+func (i *InstructorHandler) AddInstructor(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var instructorInputDto dto.InstructorInputDto
+	err := json.NewDecoder(r.Body).Decode(&instructorInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	instructorInputDto.CourseID = r.PathValue("id")
+
+	instructor, err := i.InstructorDB.Add(r.Context(), instructorInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(instructor)
+}
+
+// RemoveInstructor answers 409 Conflict rather than leave a course without
+// instructors.
+func (i *InstructorHandler) RemoveInstructor(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	err := i.InstructorDB.Remove(r.Context(), r.PathValue("id"), r.PathValue("userID"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// FindTaughtCourses lists the courses the user of the path teaches, a page at
+// a time.
+func (i *InstructorHandler) FindTaughtCourses(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	courses, err := i.CourseDB.FindByInstructorID(r.Context(), r.PathValue("id"), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(courses)
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// LessonHandler serves the lessons of a module, under
+// /courses/{id}/modules/{moduleID}/lessons.
+type LessonHandler struct {
+	ModuleDB database.ModuleRepositoryInterface
+	LessonDB database.LessonRepositoryInterface
+}
+
+func NewLessonHandler(moduleDB database.ModuleRepositoryInterface, lessonDB database.LessonRepositoryInterface) *LessonHandler {
+	return &LessonHandler{ModuleDB: moduleDB, LessonDB: lessonDB}
+}
+
+// findLesson finds the lesson of the path, which must belong to the module
+// and course of the path.
+func (l *LessonHandler) findLesson(r *http.Request) (dto.LessonOutputDto, error) {
+	module, err := findModule(r, l.ModuleDB)
+	if err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	lesson, err := l.LessonDB.Find(r.Context(), r.PathValue("lessonID"))
+	if err != nil {
+		return dto.LessonOutputDto{}, err
+	}
+	if lesson.ModuleID != module.ID {
+		return dto.LessonOutputDto{}, dberr.NotFound("lesson")
+	}
+	return lesson, nil
+}
+
+func (l *LessonHandler) FindLessons(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	module, err := findModule(r, l.ModuleDB)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	lessons, err := l.LessonDB.FindByModuleID(r.Context(), module.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lessons)
+}
+
+func (l *LessonHandler) FindLesson(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	lesson, err := l.findLesson(r)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(lesson.Version))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+// CreateLesson adds a lesson at the end of the module.
+func (l *LessonHandler) CreateLesson(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var lessonInputDto dto.LessonInputDto
+	err := json.NewDecoder(r.Body).Decode(&lessonInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	module, err := findModule(r, l.ModuleDB)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	lessonInputDto.ModuleID = module.ID
+
+	lessonOutputDto, err := l.LessonDB.Create(r.Context(), lessonInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(lessonOutputDto.Version))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(lessonOutputDto)
+}
+
+func (l *LessonHandler) UpdateLesson(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var lessonInputDto dto.LessonInputDto
+	err := json.NewDecoder(r.Body).Decode(&lessonInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		lessonInputDto.Version = version
+	}
+
+	lesson, err := l.findLesson(r)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	lessonInputDto.ID, lessonInputDto.ModuleID = lesson.ID, lesson.ModuleID
+
+	err = l.LessonDB.Update(r.Context(), lessonInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), updateStatus(err, version != 0))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (l *LessonHandler) DeleteLesson(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	lesson, err := l.findLesson(r)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = l.LessonDB.Delete(r.Context(), lesson.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// ReorderLessons puts the lessons of a module in the order of the ids of the
+// body, which must list every one of them.
+func (l *LessonHandler) ReorderLessons(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var order dto.ReorderInputDto
+	err := json.NewDecoder(r.Body).Decode(&order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	module, err := findModule(r, l.ModuleDB)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = l.LessonDB.Reorder(r.Context(), module.ID, order.IDs)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
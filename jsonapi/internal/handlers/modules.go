@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/dberr"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+// ModuleHandler serves the modules of a course, under
+// /courses/{id}/modules.
+type ModuleHandler struct {
+	ModuleDB database.ModuleRepositoryInterface
+}
+
+func NewModuleHandler(moduleDB database.ModuleRepositoryInterface) *ModuleHandler {
+	return &ModuleHandler{ModuleDB: moduleDB}
+}
+
+// findModule finds the module of the path, which must belong to the course
+// of the path.
+func findModule(r *http.Request, modules database.ModuleRepositoryInterface) (dto.ModuleOutputDto, error) {
+	module, err := modules.Find(r.Context(), r.PathValue("moduleID"))
+	if err != nil {
+		return dto.ModuleOutputDto{}, err
+	}
+	if module.CourseID != r.PathValue("id") {
+		return dto.ModuleOutputDto{}, dberr.NotFound("module")
+	}
+	return module, nil
+}
+
+func (m *ModuleHandler) FindModules(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	modules, err := m.ModuleDB.FindByCourseID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(modules)
+}
+
+func (m *ModuleHandler) FindModule(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	module, err := findModule(r, m.ModuleDB)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(module.Version))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(module)
+}
+
+// CreateModule adds a module at the end of the course.
+func (m *ModuleHandler) CreateModule(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var moduleInputDto dto.ModuleInputDto
+	err := json.NewDecoder(r.Body).Decode(&moduleInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	moduleInputDto.CourseID = r.PathValue("id")
+
+	moduleOutputDto, err := m.ModuleDB.Create(r.Context(), moduleInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(moduleOutputDto.Version))
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(moduleOutputDto)
+}
+
+func (m *ModuleHandler) UpdateModule(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var moduleInputDto dto.ModuleInputDto
+	err := json.NewDecoder(r.Body).Decode(&moduleInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version != 0 {
+		moduleInputDto.Version = version
+	}
+
+	module, err := findModule(r, m.ModuleDB)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	moduleInputDto.ID, moduleInputDto.CourseID = module.ID, module.CourseID
+
+	err = m.ModuleDB.Update(r.Context(), moduleInputDto)
+	if err != nil {
+		http.Error(w, err.Error(), updateStatus(err, version != 0))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// DeleteModule deletes a module with its lessons.
+func (m *ModuleHandler) DeleteModule(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	module, err := findModule(r, m.ModuleDB)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	err = m.ModuleDB.Delete(r.Context(), module.ID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
+
+// ReorderModules puts the modules of a course in the order of the ids of
+// the body, which must list every one of them.
+func (m *ModuleHandler) ReorderModules(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		http.Error(w, "invalid content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var order dto.ReorderInputDto
+	err := json.NewDecoder(r.Body).Decode(&order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = m.ModuleDB.Reorder(r.Context(), r.PathValue("id"), order.IDs)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+}
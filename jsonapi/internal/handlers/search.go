@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+)
+
+type SearchHandler struct {
+	SearchDB database.SearchRepositoryInterface
+}
+
+func NewSearchHandler(searchDB database.SearchRepositoryInterface) *SearchHandler {
+	return &SearchHandler{SearchDB: searchDB}
+}
+
+// Search finds courses and categories by the words of ?q=, best match first.
+// ?type=course or ?type=category narrows it to one of them.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Accept") != "application/json" {
+		http.Error(w, "invalid accept header", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	page, err := pageFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.SearchDB.Search(r.Context(), query.SearchSpec{
+		Query:      r.URL.Query().Get("q"),
+		EntityType: r.URL.Query().Get("type"),
+		Page:       page,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(results)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
@@ -13,10 +14,11 @@ import (
 
 type UserHandler struct {
 	UserDB database.UserRepositoryInterface
+	Admins database.AdminChecker
 }
 
-func NewUserHandler(userDB database.UserRepositoryInterface) *UserHandler {
-	return &UserHandler{UserDB: userDB}
+func NewUserHandler(userDB database.UserRepositoryInterface, admins database.AdminChecker) *UserHandler {
+	return &UserHandler{UserDB: userDB, Admins: admins}
 }
 
 // Get Jwt godoc
@@ -28,6 +30,7 @@ func NewUserHandler(userDB database.UserRepositoryInterface) *UserHandler {
 // @Param        input  body      dto.GetJWTInput  true  "user request"
 // @Success      200     {object}  dto.AccessToken
 // @Failure      400     {object}  Error
+// @Failure      401     {object}  Error
 // @Failure      500     {object}  Error
 // @Router       /users/generate_token [post]
 func (h *UserHandler) GetJwt(w http.ResponseWriter, r *http.Request) {
@@ -41,8 +44,13 @@ func (h *UserHandler) GetJwt(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	userFromDb, err := h.UserDB.FindByEmail(userCredentials.Email)
+	userFromDb, err := h.UserDB.FindByEmail(r.Context(), userCredentials.Email)
 
+	if errors.Is(err, database.ErrNotFound) {
+		json.NewEncoder(w).Encode(Error{Message: "Invalid credentials"})
+		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		return
+	}
 	if err != nil {
 		json.NewEncoder(w).Encode(Error{Message: err.Error()})
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -83,6 +91,8 @@ func (h *UserHandler) GetJwt(w http.ResponseWriter, r *http.Request) {
 // @Param        input  body      dto.CreateUserInput  true  "user request"
 // @Success      201
 // @Failure      400     {object}  Error
+// @Failure      409     {object}  Error
+// @Failure      422     {object}  Error
 // @Failure      500     {object}  Error
 // @Router       /users [post]
 // @Security     ApiKeyAuth
@@ -101,14 +111,14 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	_, err = h.UserDB.Create(dto.UserInputDto{
+	_, err = h.UserDB.Create(r.Context(), dto.UserInputDto{
 		Name:     entityUser.Name,
 		Email:    entityUser.Email,
 		Password: entityUser.Password,
 	})
 	if err != nil {
 		json.NewEncoder(w).Encode(Error{Message: err.Error()})
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -131,18 +141,47 @@ func (h *UserHandler) FindByEmail(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(Error{Message: "Email is required"})
 		http.Error(w, "Email is required", http.StatusBadRequest)
 	}
-	user, err := h.UserDB.FindByEmail(email)
+	user, err := h.UserDB.FindByEmail(r.Context(), email)
 	if err != nil {
-		if err.Error() == "record not found" {
+		if errors.Is(err, database.ErrNotFound) {
 			json.NewEncoder(w).Encode(Error{Message: "User not found"})
 			http.Error(w, "User not found", http.StatusNotFound)
 			return
 		}
 		json.NewEncoder(w).Encode(Error{Message: err.Error()})
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(user)
 }
+
+// Restore User godoc
+// @Summary      Restore a deleted user
+// @Description  Restore a deleted user
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User id"
+// @Success      200
+// @Failure      401  {object}  Error
+// @Failure      403  {object}  Error
+// @Failure      404  {object}  Error
+// @Failure      500  {object}  Error
+// @Router       /users/{id}/restore [post]
+// @Security     ApiKeyAuth
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.Admins.CheckAdmin(r.Context()); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	id := r.PathValue("id")
+	err := h.UserDB.Restore(r.Context(), id)
+	if err != nil {
+		json.NewEncoder(w).Encode(Error{Message: err.Error()})
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
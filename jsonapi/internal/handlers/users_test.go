@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antoniofmoliveira/courses/db/database"
+	"github.com/antoniofmoliveira/courses/db/database/audit"
+	"github.com/antoniofmoliveira/courses/db/database/query"
+	"github.com/antoniofmoliveira/courses/dto"
+)
+
+func TestRestoreUser(t *testing.T) {
+	ctx := context.Background()
+	dbi := database.NewMemoryImplementation(query.Restrict).WithOwnership([]string{"admin@example.com"})
+	user, err := dbi.UserRepository.Create(ctx, dto.UserInputDto{Name: "ann", Email: "ann@example.com", Password: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.UserRepository.Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	h := NewUserHandler(dbi.UserRepository, dbi)
+
+	for _, tt := range []struct {
+		name  string
+		actor string
+		want  int
+	}{
+		{"anonymous", "", http.StatusUnauthorized},
+		{"user", "ann@example.com", http.StatusForbidden},
+		{"admin", "admin@example.com", http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/users/"+user.ID+"/restore", nil)
+			r.SetPathValue("id", user.ID)
+			if tt.actor != "" {
+				r = r.WithContext(audit.WithActor(r.Context(), tt.actor))
+			}
+			w := httptest.NewRecorder()
+			h.RestoreUser(w, r)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			_, err := dbi.UserRepository.Find(ctx, user.ID)
+			if restored := err == nil; restored != (tt.want == http.StatusOK) {
+				t.Errorf("user restored = %v after status %d", restored, w.Code)
+			}
+		})
+	}
+}
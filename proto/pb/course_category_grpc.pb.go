@@ -26,6 +26,11 @@ const (
 	CategoryService_GetCategory_FullMethodName                       = "/pb.CategoryService/GetCategory"
 	CategoryService_DeleteCategory_FullMethodName                    = "/pb.CategoryService/DeleteCategory"
 	CategoryService_UpdateCategory_FullMethodName                    = "/pb.CategoryService/UpdateCategory"
+	CategoryService_RestoreCategory_FullMethodName                   = "/pb.CategoryService/RestoreCategory"
+	CategoryService_ListChildCategories_FullMethodName               = "/pb.CategoryService/ListChildCategories"
+	CategoryService_ListCategoryAncestors_FullMethodName             = "/pb.CategoryService/ListCategoryAncestors"
+	CategoryService_GetCategorySubtree_FullMethodName                = "/pb.CategoryService/GetCategorySubtree"
+	CategoryService_ListCoursesInCategoryTree_FullMethodName         = "/pb.CategoryService/ListCoursesInCategoryTree"
 )
 
 // CategoryServiceClient is the client API for CategoryService service.
@@ -35,10 +40,18 @@ type CategoryServiceClient interface {
 	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error)
 	CreateCategoryStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateCategoryRequest, CategoryList], error)
 	CreateCategoryStreamBidirectional(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CreateCategoryRequest, Category], error)
-	ListCategories(ctx context.Context, in *Blank, opts ...grpc.CallOption) (*CategoryList, error)
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*CategoryList, error)
 	GetCategory(ctx context.Context, in *CategoryGetRequest, opts ...grpc.CallOption) (*Category, error)
 	DeleteCategory(ctx context.Context, in *CategoryDeleteRequest, opts ...grpc.CallOption) (*Response, error)
 	UpdateCategory(ctx context.Context, in *CategoryUpdateRequest, opts ...grpc.CallOption) (*Response, error)
+	RestoreCategory(ctx context.Context, in *CategoryRestoreRequest, opts ...grpc.CallOption) (*Response, error)
+	ListChildCategories(ctx context.Context, in *ListChildCategoriesRequest, opts ...grpc.CallOption) (*CategoryList, error)
+	// top level first, without the category itself
+	ListCategoryAncestors(ctx context.Context, in *CategoryGetRequest, opts ...grpc.CallOption) (*CategoryList, error)
+	// the category and everything below it, level by level
+	GetCategorySubtree(ctx context.Context, in *CategoryGetRequest, opts ...grpc.CallOption) (*CategoryList, error)
+	// courses of the category and of every category below it
+	ListCoursesInCategoryTree(ctx context.Context, in *ListCoursesFromCategoryRequest, opts ...grpc.CallOption) (*Courses, error)
 }
 
 type categoryServiceClient struct {
@@ -85,7 +98,7 @@ func (c *categoryServiceClient) CreateCategoryStreamBidirectional(ctx context.Co
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type CategoryService_CreateCategoryStreamBidirectionalClient = grpc.BidiStreamingClient[CreateCategoryRequest, Category]
 
-func (c *categoryServiceClient) ListCategories(ctx context.Context, in *Blank, opts ...grpc.CallOption) (*CategoryList, error) {
+func (c *categoryServiceClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*CategoryList, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CategoryList)
 	err := c.cc.Invoke(ctx, CategoryService_ListCategories_FullMethodName, in, out, cOpts...)
@@ -125,6 +138,56 @@ func (c *categoryServiceClient) UpdateCategory(ctx context.Context, in *Category
 	return out, nil
 }
 
+func (c *categoryServiceClient) RestoreCategory(ctx context.Context, in *CategoryRestoreRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, CategoryService_RestoreCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) ListChildCategories(ctx context.Context, in *ListChildCategoriesRequest, opts ...grpc.CallOption) (*CategoryList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryList)
+	err := c.cc.Invoke(ctx, CategoryService_ListChildCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) ListCategoryAncestors(ctx context.Context, in *CategoryGetRequest, opts ...grpc.CallOption) (*CategoryList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryList)
+	err := c.cc.Invoke(ctx, CategoryService_ListCategoryAncestors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) GetCategorySubtree(ctx context.Context, in *CategoryGetRequest, opts ...grpc.CallOption) (*CategoryList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryList)
+	err := c.cc.Invoke(ctx, CategoryService_GetCategorySubtree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *categoryServiceClient) ListCoursesInCategoryTree(ctx context.Context, in *ListCoursesFromCategoryRequest, opts ...grpc.CallOption) (*Courses, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Courses)
+	err := c.cc.Invoke(ctx, CategoryService_ListCoursesInCategoryTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CategoryServiceServer is the server API for CategoryService service.
 // All implementations must embed UnimplementedCategoryServiceServer
 // for forward compatibility.
@@ -132,10 +195,18 @@ type CategoryServiceServer interface {
 	CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error)
 	CreateCategoryStream(grpc.ClientStreamingServer[CreateCategoryRequest, CategoryList]) error
 	CreateCategoryStreamBidirectional(grpc.BidiStreamingServer[CreateCategoryRequest, Category]) error
-	ListCategories(context.Context, *Blank) (*CategoryList, error)
+	ListCategories(context.Context, *ListCategoriesRequest) (*CategoryList, error)
 	GetCategory(context.Context, *CategoryGetRequest) (*Category, error)
 	DeleteCategory(context.Context, *CategoryDeleteRequest) (*Response, error)
 	UpdateCategory(context.Context, *CategoryUpdateRequest) (*Response, error)
+	RestoreCategory(context.Context, *CategoryRestoreRequest) (*Response, error)
+	ListChildCategories(context.Context, *ListChildCategoriesRequest) (*CategoryList, error)
+	// top level first, without the category itself
+	ListCategoryAncestors(context.Context, *CategoryGetRequest) (*CategoryList, error)
+	// the category and everything below it, level by level
+	GetCategorySubtree(context.Context, *CategoryGetRequest) (*CategoryList, error)
+	// courses of the category and of every category below it
+	ListCoursesInCategoryTree(context.Context, *ListCoursesFromCategoryRequest) (*Courses, error)
 	mustEmbedUnimplementedCategoryServiceServer()
 }
 
@@ -155,7 +226,7 @@ func (UnimplementedCategoryServiceServer) CreateCategoryStream(grpc.ClientStream
 func (UnimplementedCategoryServiceServer) CreateCategoryStreamBidirectional(grpc.BidiStreamingServer[CreateCategoryRequest, Category]) error {
 	return status.Errorf(codes.Unimplemented, "method CreateCategoryStreamBidirectional not implemented")
 }
-func (UnimplementedCategoryServiceServer) ListCategories(context.Context, *Blank) (*CategoryList, error) {
+func (UnimplementedCategoryServiceServer) ListCategories(context.Context, *ListCategoriesRequest) (*CategoryList, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
 }
 func (UnimplementedCategoryServiceServer) GetCategory(context.Context, *CategoryGetRequest) (*Category, error) {
@@ -167,6 +238,21 @@ func (UnimplementedCategoryServiceServer) DeleteCategory(context.Context, *Categ
 func (UnimplementedCategoryServiceServer) UpdateCategory(context.Context, *CategoryUpdateRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateCategory not implemented")
 }
+func (UnimplementedCategoryServiceServer) RestoreCategory(context.Context, *CategoryRestoreRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCategory not implemented")
+}
+func (UnimplementedCategoryServiceServer) ListChildCategories(context.Context, *ListChildCategoriesRequest) (*CategoryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChildCategories not implemented")
+}
+func (UnimplementedCategoryServiceServer) ListCategoryAncestors(context.Context, *CategoryGetRequest) (*CategoryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategoryAncestors not implemented")
+}
+func (UnimplementedCategoryServiceServer) GetCategorySubtree(context.Context, *CategoryGetRequest) (*CategoryList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategorySubtree not implemented")
+}
+func (UnimplementedCategoryServiceServer) ListCoursesInCategoryTree(context.Context, *ListCoursesFromCategoryRequest) (*Courses, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCoursesInCategoryTree not implemented")
+}
 func (UnimplementedCategoryServiceServer) mustEmbedUnimplementedCategoryServiceServer() {}
 func (UnimplementedCategoryServiceServer) testEmbeddedByValue()                         {}
 
@@ -221,7 +307,7 @@ func _CategoryService_CreateCategoryStreamBidirectional_Handler(srv interface{},
 type CategoryService_CreateCategoryStreamBidirectionalServer = grpc.BidiStreamingServer[CreateCategoryRequest, Category]
 
 func _CategoryService_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Blank)
+	in := new(ListCategoriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -233,7 +319,7 @@ func _CategoryService_ListCategories_Handler(srv interface{}, ctx context.Contex
 		FullMethod: CategoryService_ListCategories_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CategoryServiceServer).ListCategories(ctx, req.(*Blank))
+		return srv.(CategoryServiceServer).ListCategories(ctx, req.(*ListCategoriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -292,6 +378,96 @@ func _CategoryService_UpdateCategory_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CategoryService_RestoreCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryRestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).RestoreCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CategoryService_RestoreCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).RestoreCategory(ctx, req.(*CategoryRestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_ListChildCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChildCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).ListChildCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CategoryService_ListChildCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).ListChildCategories(ctx, req.(*ListChildCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_ListCategoryAncestors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).ListCategoryAncestors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CategoryService_ListCategoryAncestors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).ListCategoryAncestors(ctx, req.(*CategoryGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_GetCategorySubtree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CategoryGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).GetCategorySubtree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CategoryService_GetCategorySubtree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).GetCategorySubtree(ctx, req.(*CategoryGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CategoryService_ListCoursesInCategoryTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCoursesFromCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CategoryServiceServer).ListCoursesInCategoryTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CategoryService_ListCoursesInCategoryTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CategoryServiceServer).ListCoursesInCategoryTree(ctx, req.(*ListCoursesFromCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CategoryService_ServiceDesc is the grpc.ServiceDesc for CategoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -319,6 +495,26 @@ var CategoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateCategory",
 			Handler:    _CategoryService_UpdateCategory_Handler,
 		},
+		{
+			MethodName: "RestoreCategory",
+			Handler:    _CategoryService_RestoreCategory_Handler,
+		},
+		{
+			MethodName: "ListChildCategories",
+			Handler:    _CategoryService_ListChildCategories_Handler,
+		},
+		{
+			MethodName: "ListCategoryAncestors",
+			Handler:    _CategoryService_ListCategoryAncestors_Handler,
+		},
+		{
+			MethodName: "GetCategorySubtree",
+			Handler:    _CategoryService_GetCategorySubtree_Handler,
+		},
+		{
+			MethodName: "ListCoursesInCategoryTree",
+			Handler:    _CategoryService_ListCoursesInCategoryTree_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -338,11 +534,14 @@ var CategoryService_ServiceDesc = grpc.ServiceDesc{
 
 const (
 	CourseService_CreateCourse_FullMethodName            = "/pb.CourseService/CreateCourse"
+	CourseService_BatchCreateCourses_FullMethodName      = "/pb.CourseService/BatchCreateCourses"
 	CourseService_ListCourses_FullMethodName             = "/pb.CourseService/ListCourses"
 	CourseService_GetCourse_FullMethodName               = "/pb.CourseService/GetCourse"
 	CourseService_DeleteCourse_FullMethodName            = "/pb.CourseService/DeleteCourse"
 	CourseService_UpdateCourse_FullMethodName            = "/pb.CourseService/UpdateCourse"
 	CourseService_ListCoursesFromCategory_FullMethodName = "/pb.CourseService/ListCoursesFromCategory"
+	CourseService_RestoreCourse_FullMethodName           = "/pb.CourseService/RestoreCourse"
+	CourseService_SearchCourses_FullMethodName           = "/pb.CourseService/SearchCourses"
 )
 
 // CourseServiceClient is the client API for CourseService service.
@@ -350,11 +549,14 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CourseServiceClient interface {
 	CreateCourse(ctx context.Context, in *CreateCourseRequest, opts ...grpc.CallOption) (*Course, error)
-	ListCourses(ctx context.Context, in *Blank, opts ...grpc.CallOption) (*Courses, error)
+	BatchCreateCourses(ctx context.Context, in *BatchCreateCoursesRequest, opts ...grpc.CallOption) (*BatchCreateCoursesResponse, error)
+	ListCourses(ctx context.Context, in *ListCoursesRequest, opts ...grpc.CallOption) (*Courses, error)
 	GetCourse(ctx context.Context, in *CourseGetRequest, opts ...grpc.CallOption) (*Course, error)
 	DeleteCourse(ctx context.Context, in *CourseDeleteRequest, opts ...grpc.CallOption) (*Response, error)
 	UpdateCourse(ctx context.Context, in *CourseUpdateRequest, opts ...grpc.CallOption) (*Response, error)
 	ListCoursesFromCategory(ctx context.Context, in *ListCoursesFromCategoryRequest, opts ...grpc.CallOption) (*Courses, error)
+	RestoreCourse(ctx context.Context, in *CourseRestoreRequest, opts ...grpc.CallOption) (*Response, error)
+	SearchCourses(ctx context.Context, in *SearchCoursesRequest, opts ...grpc.CallOption) (*SearchCoursesResponse, error)
 }
 
 type courseServiceClient struct {
@@ -375,7 +577,17 @@ func (c *courseServiceClient) CreateCourse(ctx context.Context, in *CreateCourse
 	return out, nil
 }
 
-func (c *courseServiceClient) ListCourses(ctx context.Context, in *Blank, opts ...grpc.CallOption) (*Courses, error) {
+func (c *courseServiceClient) BatchCreateCourses(ctx context.Context, in *BatchCreateCoursesRequest, opts ...grpc.CallOption) (*BatchCreateCoursesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchCreateCoursesResponse)
+	err := c.cc.Invoke(ctx, CourseService_BatchCreateCourses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseServiceClient) ListCourses(ctx context.Context, in *ListCoursesRequest, opts ...grpc.CallOption) (*Courses, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Courses)
 	err := c.cc.Invoke(ctx, CourseService_ListCourses_FullMethodName, in, out, cOpts...)
@@ -425,16 +637,39 @@ func (c *courseServiceClient) ListCoursesFromCategory(ctx context.Context, in *L
 	return out, nil
 }
 
+func (c *courseServiceClient) RestoreCourse(ctx context.Context, in *CourseRestoreRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, CourseService_RestoreCourse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseServiceClient) SearchCourses(ctx context.Context, in *SearchCoursesRequest, opts ...grpc.CallOption) (*SearchCoursesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchCoursesResponse)
+	err := c.cc.Invoke(ctx, CourseService_SearchCourses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CourseServiceServer is the server API for CourseService service.
 // All implementations must embed UnimplementedCourseServiceServer
 // for forward compatibility.
 type CourseServiceServer interface {
 	CreateCourse(context.Context, *CreateCourseRequest) (*Course, error)
-	ListCourses(context.Context, *Blank) (*Courses, error)
+	BatchCreateCourses(context.Context, *BatchCreateCoursesRequest) (*BatchCreateCoursesResponse, error)
+	ListCourses(context.Context, *ListCoursesRequest) (*Courses, error)
 	GetCourse(context.Context, *CourseGetRequest) (*Course, error)
 	DeleteCourse(context.Context, *CourseDeleteRequest) (*Response, error)
 	UpdateCourse(context.Context, *CourseUpdateRequest) (*Response, error)
 	ListCoursesFromCategory(context.Context, *ListCoursesFromCategoryRequest) (*Courses, error)
+	RestoreCourse(context.Context, *CourseRestoreRequest) (*Response, error)
+	SearchCourses(context.Context, *SearchCoursesRequest) (*SearchCoursesResponse, error)
 	mustEmbedUnimplementedCourseServiceServer()
 }
 
@@ -448,7 +683,10 @@ type UnimplementedCourseServiceServer struct{}
 func (UnimplementedCourseServiceServer) CreateCourse(context.Context, *CreateCourseRequest) (*Course, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateCourse not implemented")
 }
-func (UnimplementedCourseServiceServer) ListCourses(context.Context, *Blank) (*Courses, error) {
+func (UnimplementedCourseServiceServer) BatchCreateCourses(context.Context, *BatchCreateCoursesRequest) (*BatchCreateCoursesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchCreateCourses not implemented")
+}
+func (UnimplementedCourseServiceServer) ListCourses(context.Context, *ListCoursesRequest) (*Courses, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCourses not implemented")
 }
 func (UnimplementedCourseServiceServer) GetCourse(context.Context, *CourseGetRequest) (*Course, error) {
@@ -463,6 +701,12 @@ func (UnimplementedCourseServiceServer) UpdateCourse(context.Context, *CourseUpd
 func (UnimplementedCourseServiceServer) ListCoursesFromCategory(context.Context, *ListCoursesFromCategoryRequest) (*Courses, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCoursesFromCategory not implemented")
 }
+func (UnimplementedCourseServiceServer) RestoreCourse(context.Context, *CourseRestoreRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCourse not implemented")
+}
+func (UnimplementedCourseServiceServer) SearchCourses(context.Context, *SearchCoursesRequest) (*SearchCoursesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCourses not implemented")
+}
 func (UnimplementedCourseServiceServer) mustEmbedUnimplementedCourseServiceServer() {}
 func (UnimplementedCourseServiceServer) testEmbeddedByValue()                       {}
 
@@ -502,8 +746,26 @@ func _CourseService_CreateCourse_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CourseService_BatchCreateCourses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateCoursesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseServiceServer).BatchCreateCourses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CourseService_BatchCreateCourses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseServiceServer).BatchCreateCourses(ctx, req.(*BatchCreateCoursesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CourseService_ListCourses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Blank)
+	in := new(ListCoursesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -515,7 +777,7 @@ func _CourseService_ListCourses_Handler(srv interface{}, ctx context.Context, de
 		FullMethod: CourseService_ListCourses_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CourseServiceServer).ListCourses(ctx, req.(*Blank))
+		return srv.(CourseServiceServer).ListCourses(ctx, req.(*ListCoursesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -592,6 +854,42 @@ func _CourseService_ListCoursesFromCategory_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CourseService_RestoreCourse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CourseRestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseServiceServer).RestoreCourse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CourseService_RestoreCourse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseServiceServer).RestoreCourse(ctx, req.(*CourseRestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseService_SearchCourses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCoursesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseServiceServer).SearchCourses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CourseService_SearchCourses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseServiceServer).SearchCourses(ctx, req.(*SearchCoursesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CourseService_ServiceDesc is the grpc.ServiceDesc for CourseService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -603,6 +901,10 @@ var CourseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateCourse",
 			Handler:    _CourseService_CreateCourse_Handler,
 		},
+		{
+			MethodName: "BatchCreateCourses",
+			Handler:    _CourseService_BatchCreateCourses_Handler,
+		},
 		{
 			MethodName: "ListCourses",
 			Handler:    _CourseService_ListCourses_Handler,
@@ -623,6 +925,14 @@ var CourseService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListCoursesFromCategory",
 			Handler:    _CourseService_ListCoursesFromCategory_Handler,
 		},
+		{
+			MethodName: "RestoreCourse",
+			Handler:    _CourseService_RestoreCourse_Handler,
+		},
+		{
+			MethodName: "SearchCourses",
+			Handler:    _CourseService_SearchCourses_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "course_category.proto",
@@ -635,6 +945,7 @@ const (
 	UserService_GetJWTToken_FullMethodName = "/pb.UserService/GetJWTToken"
 	UserService_DeleleUser_FullMethodName  = "/pb.UserService/DeleleUser"
 	UserService_UpdateUser_FullMethodName  = "/pb.UserService/UpdateUser"
+	UserService_RestoreUser_FullMethodName = "/pb.UserService/RestoreUser"
 )
 
 // UserServiceClient is the client API for UserService service.
@@ -643,11 +954,11 @@ const (
 type UserServiceClient interface {
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
 	GetUser(ctx context.Context, in *UserGetRequest, opts ...grpc.CallOption) (*User, error)
-	// rpc GetUserByEmail(UserByEmailGetRequest) returns (UserForJWT) {}
-	ListUsers(ctx context.Context, in *Blank, opts ...grpc.CallOption) (*Users, error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Users, error)
 	GetJWTToken(ctx context.Context, in *UserForJWT, opts ...grpc.CallOption) (*JWTToken, error)
 	DeleleUser(ctx context.Context, in *UserDeleteRequest, opts ...grpc.CallOption) (*Response, error)
 	UpdateUser(ctx context.Context, in *UserUpdateRequest, opts ...grpc.CallOption) (*Response, error)
+	RestoreUser(ctx context.Context, in *UserRestoreRequest, opts ...grpc.CallOption) (*Response, error)
 }
 
 type userServiceClient struct {
@@ -678,7 +989,7 @@ func (c *userServiceClient) GetUser(ctx context.Context, in *UserGetRequest, opt
 	return out, nil
 }
 
-func (c *userServiceClient) ListUsers(ctx context.Context, in *Blank, opts ...grpc.CallOption) (*Users, error) {
+func (c *userServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*Users, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Users)
 	err := c.cc.Invoke(ctx, UserService_ListUsers_FullMethodName, in, out, cOpts...)
@@ -718,17 +1029,27 @@ func (c *userServiceClient) UpdateUser(ctx context.Context, in *UserUpdateReques
 	return out, nil
 }
 
+func (c *userServiceClient) RestoreUser(ctx context.Context, in *UserRestoreRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, UserService_RestoreUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility.
 type UserServiceServer interface {
 	CreateUser(context.Context, *CreateUserRequest) (*User, error)
 	GetUser(context.Context, *UserGetRequest) (*User, error)
-	// rpc GetUserByEmail(UserByEmailGetRequest) returns (UserForJWT) {}
-	ListUsers(context.Context, *Blank) (*Users, error)
+	ListUsers(context.Context, *ListUsersRequest) (*Users, error)
 	GetJWTToken(context.Context, *UserForJWT) (*JWTToken, error)
 	DeleleUser(context.Context, *UserDeleteRequest) (*Response, error)
 	UpdateUser(context.Context, *UserUpdateRequest) (*Response, error)
+	RestoreUser(context.Context, *UserRestoreRequest) (*Response, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -745,7 +1066,7 @@ func (UnimplementedUserServiceServer) CreateUser(context.Context, *CreateUserReq
 func (UnimplementedUserServiceServer) GetUser(context.Context, *UserGetRequest) (*User, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
 }
-func (UnimplementedUserServiceServer) ListUsers(context.Context, *Blank) (*Users, error) {
+func (UnimplementedUserServiceServer) ListUsers(context.Context, *ListUsersRequest) (*Users, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 }
 func (UnimplementedUserServiceServer) GetJWTToken(context.Context, *UserForJWT) (*JWTToken, error) {
@@ -757,6 +1078,9 @@ func (UnimplementedUserServiceServer) DeleleUser(context.Context, *UserDeleteReq
 func (UnimplementedUserServiceServer) UpdateUser(context.Context, *UserUpdateRequest) (*Response, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
 }
+func (UnimplementedUserServiceServer) RestoreUser(context.Context, *UserRestoreRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreUser not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 func (UnimplementedUserServiceServer) testEmbeddedByValue()                     {}
 
@@ -815,7 +1139,7 @@ func _UserService_GetUser_Handler(srv interface{}, ctx context.Context, dec func
 }
 
 func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Blank)
+	in := new(ListUsersRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -827,7 +1151,7 @@ func _UserService_ListUsers_Handler(srv interface{}, ctx context.Context, dec fu
 		FullMethod: UserService_ListUsers_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServiceServer).ListUsers(ctx, req.(*Blank))
+		return srv.(UserServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -886,6 +1210,24 @@ func _UserService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_RestoreUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).RestoreUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserService_RestoreUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).RestoreUser(ctx, req.(*UserRestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // UserService_ServiceDesc is the grpc.ServiceDesc for UserService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -917,6 +1259,1594 @@ var UserService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateUser",
 			Handler:    _UserService_UpdateUser_Handler,
 		},
+		{
+			MethodName: "RestoreUser",
+			Handler:    _UserService_RestoreUser_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "course_category.proto",
+}
+
+const (
+	AdminService_Purge_FullMethodName = "/pb.AdminService/Purge"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminServiceClient interface {
+	Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeResponse)
+	err := c.cc.Invoke(ctx, AdminService_Purge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+type AdminServiceServer interface {
+	Purge(context.Context, *PurgeRequest) (*PurgeResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) Purge(context.Context, *PurgeRequest) (*PurgeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Purge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Purge(ctx, req.(*PurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Purge",
+			Handler:    _AdminService_Purge_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "course_category.proto",
+}
+
+const (
+	HistoryService_ListHistory_FullMethodName = "/pb.HistoryService/ListHistory"
+)
+
+// HistoryServiceClient is the client API for HistoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HistoryServiceClient interface {
+	ListHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+}
+
+type historyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHistoryServiceClient(cc grpc.ClientConnInterface) HistoryServiceClient {
+	return &historyServiceClient{cc}
+}
+
+func (c *historyServiceClient) ListHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, HistoryService_ListHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HistoryServiceServer is the server API for HistoryService service.
+// All implementations must embed UnimplementedHistoryServiceServer
+// for forward compatibility.
+type HistoryServiceServer interface {
+	ListHistory(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	mustEmbedUnimplementedHistoryServiceServer()
+}
+
+// UnimplementedHistoryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHistoryServiceServer struct{}
+
+func (UnimplementedHistoryServiceServer) ListHistory(context.Context, *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHistory not implemented")
+}
+func (UnimplementedHistoryServiceServer) mustEmbedUnimplementedHistoryServiceServer() {}
+func (UnimplementedHistoryServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeHistoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HistoryServiceServer will
+// result in compilation errors.
+type UnsafeHistoryServiceServer interface {
+	mustEmbedUnimplementedHistoryServiceServer()
+}
+
+func RegisterHistoryServiceServer(s grpc.ServiceRegistrar, srv HistoryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedHistoryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HistoryService_ServiceDesc, srv)
+}
+
+func _HistoryService_ListHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HistoryServiceServer).ListHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HistoryService_ListHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HistoryServiceServer).ListHistory(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HistoryService_ServiceDesc is the grpc.ServiceDesc for HistoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HistoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.HistoryService",
+	HandlerType: (*HistoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListHistory",
+			Handler:    _HistoryService_ListHistory_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "course_category.proto",
+}
+
+const (
+	LessonService_CreateModule_FullMethodName   = "/pb.LessonService/CreateModule"
+	LessonService_GetModule_FullMethodName      = "/pb.LessonService/GetModule"
+	LessonService_ListModules_FullMethodName    = "/pb.LessonService/ListModules"
+	LessonService_UpdateModule_FullMethodName   = "/pb.LessonService/UpdateModule"
+	LessonService_DeleteModule_FullMethodName   = "/pb.LessonService/DeleteModule"
+	LessonService_ReorderModules_FullMethodName = "/pb.LessonService/ReorderModules"
+	LessonService_CreateLesson_FullMethodName   = "/pb.LessonService/CreateLesson"
+	LessonService_GetLesson_FullMethodName      = "/pb.LessonService/GetLesson"
+	LessonService_ListLessons_FullMethodName    = "/pb.LessonService/ListLessons"
+	LessonService_UpdateLesson_FullMethodName   = "/pb.LessonService/UpdateLesson"
+	LessonService_DeleteLesson_FullMethodName   = "/pb.LessonService/DeleteLesson"
+	LessonService_ReorderLessons_FullMethodName = "/pb.LessonService/ReorderLessons"
+)
+
+// LessonServiceClient is the client API for LessonService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LessonServiceClient interface {
+	CreateModule(ctx context.Context, in *CreateModuleRequest, opts ...grpc.CallOption) (*Module, error)
+	GetModule(ctx context.Context, in *ModuleGetRequest, opts ...grpc.CallOption) (*Module, error)
+	ListModules(ctx context.Context, in *ListModulesRequest, opts ...grpc.CallOption) (*Modules, error)
+	UpdateModule(ctx context.Context, in *ModuleUpdateRequest, opts ...grpc.CallOption) (*Response, error)
+	DeleteModule(ctx context.Context, in *ModuleDeleteRequest, opts ...grpc.CallOption) (*Response, error)
+	ReorderModules(ctx context.Context, in *ReorderModulesRequest, opts ...grpc.CallOption) (*Response, error)
+	CreateLesson(ctx context.Context, in *CreateLessonRequest, opts ...grpc.CallOption) (*Lesson, error)
+	GetLesson(ctx context.Context, in *LessonGetRequest, opts ...grpc.CallOption) (*Lesson, error)
+	ListLessons(ctx context.Context, in *ListLessonsRequest, opts ...grpc.CallOption) (*Lessons, error)
+	UpdateLesson(ctx context.Context, in *LessonUpdateRequest, opts ...grpc.CallOption) (*Response, error)
+	DeleteLesson(ctx context.Context, in *LessonDeleteRequest, opts ...grpc.CallOption) (*Response, error)
+	ReorderLessons(ctx context.Context, in *ReorderLessonsRequest, opts ...grpc.CallOption) (*Response, error)
+}
+
+type lessonServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLessonServiceClient(cc grpc.ClientConnInterface) LessonServiceClient {
+	return &lessonServiceClient{cc}
+}
+
+func (c *lessonServiceClient) CreateModule(ctx context.Context, in *CreateModuleRequest, opts ...grpc.CallOption) (*Module, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Module)
+	err := c.cc.Invoke(ctx, LessonService_CreateModule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) GetModule(ctx context.Context, in *ModuleGetRequest, opts ...grpc.CallOption) (*Module, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Module)
+	err := c.cc.Invoke(ctx, LessonService_GetModule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) ListModules(ctx context.Context, in *ListModulesRequest, opts ...grpc.CallOption) (*Modules, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Modules)
+	err := c.cc.Invoke(ctx, LessonService_ListModules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) UpdateModule(ctx context.Context, in *ModuleUpdateRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LessonService_UpdateModule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) DeleteModule(ctx context.Context, in *ModuleDeleteRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LessonService_DeleteModule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) ReorderModules(ctx context.Context, in *ReorderModulesRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LessonService_ReorderModules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) CreateLesson(ctx context.Context, in *CreateLessonRequest, opts ...grpc.CallOption) (*Lesson, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Lesson)
+	err := c.cc.Invoke(ctx, LessonService_CreateLesson_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) GetLesson(ctx context.Context, in *LessonGetRequest, opts ...grpc.CallOption) (*Lesson, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Lesson)
+	err := c.cc.Invoke(ctx, LessonService_GetLesson_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) ListLessons(ctx context.Context, in *ListLessonsRequest, opts ...grpc.CallOption) (*Lessons, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Lessons)
+	err := c.cc.Invoke(ctx, LessonService_ListLessons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) UpdateLesson(ctx context.Context, in *LessonUpdateRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LessonService_UpdateLesson_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) DeleteLesson(ctx context.Context, in *LessonDeleteRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LessonService_DeleteLesson_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lessonServiceClient) ReorderLessons(ctx context.Context, in *ReorderLessonsRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, LessonService_ReorderLessons_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LessonServiceServer is the server API for LessonService service.
+// All implementations must embed UnimplementedLessonServiceServer
+// for forward compatibility.
+type LessonServiceServer interface {
+	CreateModule(context.Context, *CreateModuleRequest) (*Module, error)
+	GetModule(context.Context, *ModuleGetRequest) (*Module, error)
+	ListModules(context.Context, *ListModulesRequest) (*Modules, error)
+	UpdateModule(context.Context, *ModuleUpdateRequest) (*Response, error)
+	DeleteModule(context.Context, *ModuleDeleteRequest) (*Response, error)
+	ReorderModules(context.Context, *ReorderModulesRequest) (*Response, error)
+	CreateLesson(context.Context, *CreateLessonRequest) (*Lesson, error)
+	GetLesson(context.Context, *LessonGetRequest) (*Lesson, error)
+	ListLessons(context.Context, *ListLessonsRequest) (*Lessons, error)
+	UpdateLesson(context.Context, *LessonUpdateRequest) (*Response, error)
+	DeleteLesson(context.Context, *LessonDeleteRequest) (*Response, error)
+	ReorderLessons(context.Context, *ReorderLessonsRequest) (*Response, error)
+	mustEmbedUnimplementedLessonServiceServer()
+}
+
+// UnimplementedLessonServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLessonServiceServer struct{}
+
+func (UnimplementedLessonServiceServer) CreateModule(context.Context, *CreateModuleRequest) (*Module, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateModule not implemented")
+}
+func (UnimplementedLessonServiceServer) GetModule(context.Context, *ModuleGetRequest) (*Module, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModule not implemented")
+}
+func (UnimplementedLessonServiceServer) ListModules(context.Context, *ListModulesRequest) (*Modules, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListModules not implemented")
+}
+func (UnimplementedLessonServiceServer) UpdateModule(context.Context, *ModuleUpdateRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateModule not implemented")
+}
+func (UnimplementedLessonServiceServer) DeleteModule(context.Context, *ModuleDeleteRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteModule not implemented")
+}
+func (UnimplementedLessonServiceServer) ReorderModules(context.Context, *ReorderModulesRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReorderModules not implemented")
+}
+func (UnimplementedLessonServiceServer) CreateLesson(context.Context, *CreateLessonRequest) (*Lesson, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLesson not implemented")
+}
+func (UnimplementedLessonServiceServer) GetLesson(context.Context, *LessonGetRequest) (*Lesson, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLesson not implemented")
+}
+func (UnimplementedLessonServiceServer) ListLessons(context.Context, *ListLessonsRequest) (*Lessons, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLessons not implemented")
+}
+func (UnimplementedLessonServiceServer) UpdateLesson(context.Context, *LessonUpdateRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLesson not implemented")
+}
+func (UnimplementedLessonServiceServer) DeleteLesson(context.Context, *LessonDeleteRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLesson not implemented")
+}
+func (UnimplementedLessonServiceServer) ReorderLessons(context.Context, *ReorderLessonsRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReorderLessons not implemented")
+}
+func (UnimplementedLessonServiceServer) mustEmbedUnimplementedLessonServiceServer() {}
+func (UnimplementedLessonServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeLessonServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LessonServiceServer will
+// result in compilation errors.
+type UnsafeLessonServiceServer interface {
+	mustEmbedUnimplementedLessonServiceServer()
+}
+
+func RegisterLessonServiceServer(s grpc.ServiceRegistrar, srv LessonServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLessonServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LessonService_ServiceDesc, srv)
+}
+
+func _LessonService_CreateModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateModuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).CreateModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_CreateModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).CreateModule(ctx, req.(*CreateModuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_GetModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModuleGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).GetModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_GetModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).GetModule(ctx, req.(*ModuleGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_ListModules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).ListModules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_ListModules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).ListModules(ctx, req.(*ListModulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_UpdateModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModuleUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).UpdateModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_UpdateModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).UpdateModule(ctx, req.(*ModuleUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_DeleteModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModuleDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).DeleteModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_DeleteModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).DeleteModule(ctx, req.(*ModuleDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_ReorderModules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderModulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).ReorderModules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_ReorderModules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).ReorderModules(ctx, req.(*ReorderModulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_CreateLesson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLessonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).CreateLesson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_CreateLesson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).CreateLesson(ctx, req.(*CreateLessonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_GetLesson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LessonGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).GetLesson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_GetLesson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).GetLesson(ctx, req.(*LessonGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_ListLessons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLessonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).ListLessons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_ListLessons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).ListLessons(ctx, req.(*ListLessonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_UpdateLesson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LessonUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).UpdateLesson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_UpdateLesson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).UpdateLesson(ctx, req.(*LessonUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_DeleteLesson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LessonDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).DeleteLesson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_DeleteLesson_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).DeleteLesson(ctx, req.(*LessonDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LessonService_ReorderLessons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderLessonsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LessonServiceServer).ReorderLessons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LessonService_ReorderLessons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LessonServiceServer).ReorderLessons(ctx, req.(*ReorderLessonsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LessonService_ServiceDesc is the grpc.ServiceDesc for LessonService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LessonService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.LessonService",
+	HandlerType: (*LessonServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateModule",
+			Handler:    _LessonService_CreateModule_Handler,
+		},
+		{
+			MethodName: "GetModule",
+			Handler:    _LessonService_GetModule_Handler,
+		},
+		{
+			MethodName: "ListModules",
+			Handler:    _LessonService_ListModules_Handler,
+		},
+		{
+			MethodName: "UpdateModule",
+			Handler:    _LessonService_UpdateModule_Handler,
+		},
+		{
+			MethodName: "DeleteModule",
+			Handler:    _LessonService_DeleteModule_Handler,
+		},
+		{
+			MethodName: "ReorderModules",
+			Handler:    _LessonService_ReorderModules_Handler,
+		},
+		{
+			MethodName: "CreateLesson",
+			Handler:    _LessonService_CreateLesson_Handler,
+		},
+		{
+			MethodName: "GetLesson",
+			Handler:    _LessonService_GetLesson_Handler,
+		},
+		{
+			MethodName: "ListLessons",
+			Handler:    _LessonService_ListLessons_Handler,
+		},
+		{
+			MethodName: "UpdateLesson",
+			Handler:    _LessonService_UpdateLesson_Handler,
+		},
+		{
+			MethodName: "DeleteLesson",
+			Handler:    _LessonService_DeleteLesson_Handler,
+		},
+		{
+			MethodName: "ReorderLessons",
+			Handler:    _LessonService_ReorderLessons_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "course_category.proto",
+}
+
+const (
+	EnrollmentService_Enroll_FullMethodName            = "/pb.EnrollmentService/Enroll"
+	EnrollmentService_GetEnrollment_FullMethodName     = "/pb.EnrollmentService/GetEnrollment"
+	EnrollmentService_ListMyEnrollments_FullMethodName = "/pb.EnrollmentService/ListMyEnrollments"
+	EnrollmentService_UpdateProgress_FullMethodName    = "/pb.EnrollmentService/UpdateProgress"
+	EnrollmentService_Unenroll_FullMethodName          = "/pb.EnrollmentService/Unenroll"
+	EnrollmentService_ListStudents_FullMethodName      = "/pb.EnrollmentService/ListStudents"
+)
+
+// EnrollmentServiceClient is the client API for EnrollmentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EnrollmentService acts for the user of the bearer token, except for
+// ListStudents.
+type EnrollmentServiceClient interface {
+	Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*Enrollment, error)
+	GetEnrollment(ctx context.Context, in *EnrollmentGetRequest, opts ...grpc.CallOption) (*Enrollment, error)
+	ListMyEnrollments(ctx context.Context, in *ListMyEnrollmentsRequest, opts ...grpc.CallOption) (*Enrollments, error)
+	UpdateProgress(ctx context.Context, in *UpdateProgressRequest, opts ...grpc.CallOption) (*Response, error)
+	Unenroll(ctx context.Context, in *UnenrollRequest, opts ...grpc.CallOption) (*Response, error)
+	ListStudents(ctx context.Context, in *ListStudentsRequest, opts ...grpc.CallOption) (*Enrollments, error)
+}
+
+type enrollmentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEnrollmentServiceClient(cc grpc.ClientConnInterface) EnrollmentServiceClient {
+	return &enrollmentServiceClient{cc}
+}
+
+func (c *enrollmentServiceClient) Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*Enrollment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Enrollment)
+	err := c.cc.Invoke(ctx, EnrollmentService_Enroll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enrollmentServiceClient) GetEnrollment(ctx context.Context, in *EnrollmentGetRequest, opts ...grpc.CallOption) (*Enrollment, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Enrollment)
+	err := c.cc.Invoke(ctx, EnrollmentService_GetEnrollment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enrollmentServiceClient) ListMyEnrollments(ctx context.Context, in *ListMyEnrollmentsRequest, opts ...grpc.CallOption) (*Enrollments, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Enrollments)
+	err := c.cc.Invoke(ctx, EnrollmentService_ListMyEnrollments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enrollmentServiceClient) UpdateProgress(ctx context.Context, in *UpdateProgressRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, EnrollmentService_UpdateProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enrollmentServiceClient) Unenroll(ctx context.Context, in *UnenrollRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, EnrollmentService_Unenroll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *enrollmentServiceClient) ListStudents(ctx context.Context, in *ListStudentsRequest, opts ...grpc.CallOption) (*Enrollments, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Enrollments)
+	err := c.cc.Invoke(ctx, EnrollmentService_ListStudents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EnrollmentServiceServer is the server API for EnrollmentService service.
+// All implementations must embed UnimplementedEnrollmentServiceServer
+// for forward compatibility.
+//
+// EnrollmentService acts for the user of the bearer token, except for
+// ListStudents.
+type EnrollmentServiceServer interface {
+	Enroll(context.Context, *EnrollRequest) (*Enrollment, error)
+	GetEnrollment(context.Context, *EnrollmentGetRequest) (*Enrollment, error)
+	ListMyEnrollments(context.Context, *ListMyEnrollmentsRequest) (*Enrollments, error)
+	UpdateProgress(context.Context, *UpdateProgressRequest) (*Response, error)
+	Unenroll(context.Context, *UnenrollRequest) (*Response, error)
+	ListStudents(context.Context, *ListStudentsRequest) (*Enrollments, error)
+	mustEmbedUnimplementedEnrollmentServiceServer()
+}
+
+// UnimplementedEnrollmentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEnrollmentServiceServer struct{}
+
+func (UnimplementedEnrollmentServiceServer) Enroll(context.Context, *EnrollRequest) (*Enrollment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Enroll not implemented")
+}
+func (UnimplementedEnrollmentServiceServer) GetEnrollment(context.Context, *EnrollmentGetRequest) (*Enrollment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEnrollment not implemented")
+}
+func (UnimplementedEnrollmentServiceServer) ListMyEnrollments(context.Context, *ListMyEnrollmentsRequest) (*Enrollments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyEnrollments not implemented")
+}
+func (UnimplementedEnrollmentServiceServer) UpdateProgress(context.Context, *UpdateProgressRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProgress not implemented")
+}
+func (UnimplementedEnrollmentServiceServer) Unenroll(context.Context, *UnenrollRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unenroll not implemented")
+}
+func (UnimplementedEnrollmentServiceServer) ListStudents(context.Context, *ListStudentsRequest) (*Enrollments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStudents not implemented")
+}
+func (UnimplementedEnrollmentServiceServer) mustEmbedUnimplementedEnrollmentServiceServer() {}
+func (UnimplementedEnrollmentServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeEnrollmentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EnrollmentServiceServer will
+// result in compilation errors.
+type UnsafeEnrollmentServiceServer interface {
+	mustEmbedUnimplementedEnrollmentServiceServer()
+}
+
+func RegisterEnrollmentServiceServer(s grpc.ServiceRegistrar, srv EnrollmentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedEnrollmentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EnrollmentService_ServiceDesc, srv)
+}
+
+func _EnrollmentService_Enroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnrollmentServiceServer).Enroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EnrollmentService_Enroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnrollmentServiceServer).Enroll(ctx, req.(*EnrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnrollmentService_GetEnrollment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollmentGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnrollmentServiceServer).GetEnrollment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EnrollmentService_GetEnrollment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnrollmentServiceServer).GetEnrollment(ctx, req.(*EnrollmentGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnrollmentService_ListMyEnrollments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyEnrollmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnrollmentServiceServer).ListMyEnrollments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EnrollmentService_ListMyEnrollments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnrollmentServiceServer).ListMyEnrollments(ctx, req.(*ListMyEnrollmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnrollmentService_UpdateProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnrollmentServiceServer).UpdateProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EnrollmentService_UpdateProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnrollmentServiceServer).UpdateProgress(ctx, req.(*UpdateProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnrollmentService_Unenroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnenrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnrollmentServiceServer).Unenroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EnrollmentService_Unenroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnrollmentServiceServer).Unenroll(ctx, req.(*UnenrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EnrollmentService_ListStudents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStudentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EnrollmentServiceServer).ListStudents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EnrollmentService_ListStudents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EnrollmentServiceServer).ListStudents(ctx, req.(*ListStudentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EnrollmentService_ServiceDesc is the grpc.ServiceDesc for EnrollmentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EnrollmentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.EnrollmentService",
+	HandlerType: (*EnrollmentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Enroll",
+			Handler:    _EnrollmentService_Enroll_Handler,
+		},
+		{
+			MethodName: "GetEnrollment",
+			Handler:    _EnrollmentService_GetEnrollment_Handler,
+		},
+		{
+			MethodName: "ListMyEnrollments",
+			Handler:    _EnrollmentService_ListMyEnrollments_Handler,
+		},
+		{
+			MethodName: "UpdateProgress",
+			Handler:    _EnrollmentService_UpdateProgress_Handler,
+		},
+		{
+			MethodName: "Unenroll",
+			Handler:    _EnrollmentService_Unenroll_Handler,
+		},
+		{
+			MethodName: "ListStudents",
+			Handler:    _EnrollmentService_ListStudents_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "course_category.proto",
+}
+
+const (
+	InstructorService_AddInstructor_FullMethodName     = "/pb.InstructorService/AddInstructor"
+	InstructorService_RemoveInstructor_FullMethodName  = "/pb.InstructorService/RemoveInstructor"
+	InstructorService_ListInstructors_FullMethodName   = "/pb.InstructorService/ListInstructors"
+	InstructorService_ListTaughtCourses_FullMethodName = "/pb.InstructorService/ListTaughtCourses"
+)
+
+// InstructorServiceClient is the client API for InstructorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// InstructorService changes are refused with PERMISSION_DENIED unless the
+// bearer token belongs to an instructor of the course or an admin.
+type InstructorServiceClient interface {
+	AddInstructor(ctx context.Context, in *AddInstructorRequest, opts ...grpc.CallOption) (*Instructor, error)
+	RemoveInstructor(ctx context.Context, in *RemoveInstructorRequest, opts ...grpc.CallOption) (*Response, error)
+	ListInstructors(ctx context.Context, in *ListInstructorsRequest, opts ...grpc.CallOption) (*Instructors, error)
+	ListTaughtCourses(ctx context.Context, in *ListTaughtCoursesRequest, opts ...grpc.CallOption) (*Courses, error)
+}
+
+type instructorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInstructorServiceClient(cc grpc.ClientConnInterface) InstructorServiceClient {
+	return &instructorServiceClient{cc}
+}
+
+func (c *instructorServiceClient) AddInstructor(ctx context.Context, in *AddInstructorRequest, opts ...grpc.CallOption) (*Instructor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Instructor)
+	err := c.cc.Invoke(ctx, InstructorService_AddInstructor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructorServiceClient) RemoveInstructor(ctx context.Context, in *RemoveInstructorRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, InstructorService_RemoveInstructor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructorServiceClient) ListInstructors(ctx context.Context, in *ListInstructorsRequest, opts ...grpc.CallOption) (*Instructors, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Instructors)
+	err := c.cc.Invoke(ctx, InstructorService_ListInstructors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *instructorServiceClient) ListTaughtCourses(ctx context.Context, in *ListTaughtCoursesRequest, opts ...grpc.CallOption) (*Courses, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Courses)
+	err := c.cc.Invoke(ctx, InstructorService_ListTaughtCourses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InstructorServiceServer is the server API for InstructorService service.
+// All implementations must embed UnimplementedInstructorServiceServer
+// for forward compatibility.
+//
+// InstructorService changes are refused with PERMISSION_DENIED unless the
+// bearer token belongs to an instructor of the course or an admin.
+type InstructorServiceServer interface {
+	AddInstructor(context.Context, *AddInstructorRequest) (*Instructor, error)
+	RemoveInstructor(context.Context, *RemoveInstructorRequest) (*Response, error)
+	ListInstructors(context.Context, *ListInstructorsRequest) (*Instructors, error)
+	ListTaughtCourses(context.Context, *ListTaughtCoursesRequest) (*Courses, error)
+	mustEmbedUnimplementedInstructorServiceServer()
+}
+
+// UnimplementedInstructorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInstructorServiceServer struct{}
+
+func (UnimplementedInstructorServiceServer) AddInstructor(context.Context, *AddInstructorRequest) (*Instructor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInstructor not implemented")
+}
+func (UnimplementedInstructorServiceServer) RemoveInstructor(context.Context, *RemoveInstructorRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveInstructor not implemented")
+}
+func (UnimplementedInstructorServiceServer) ListInstructors(context.Context, *ListInstructorsRequest) (*Instructors, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInstructors not implemented")
+}
+func (UnimplementedInstructorServiceServer) ListTaughtCourses(context.Context, *ListTaughtCoursesRequest) (*Courses, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTaughtCourses not implemented")
+}
+func (UnimplementedInstructorServiceServer) mustEmbedUnimplementedInstructorServiceServer() {}
+func (UnimplementedInstructorServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeInstructorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InstructorServiceServer will
+// result in compilation errors.
+type UnsafeInstructorServiceServer interface {
+	mustEmbedUnimplementedInstructorServiceServer()
+}
+
+func RegisterInstructorServiceServer(s grpc.ServiceRegistrar, srv InstructorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInstructorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InstructorService_ServiceDesc, srv)
+}
+
+func _InstructorService_AddInstructor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddInstructorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructorServiceServer).AddInstructor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InstructorService_AddInstructor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructorServiceServer).AddInstructor(ctx, req.(*AddInstructorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstructorService_RemoveInstructor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveInstructorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructorServiceServer).RemoveInstructor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InstructorService_RemoveInstructor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructorServiceServer).RemoveInstructor(ctx, req.(*RemoveInstructorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstructorService_ListInstructors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInstructorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructorServiceServer).ListInstructors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InstructorService_ListInstructors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructorServiceServer).ListInstructors(ctx, req.(*ListInstructorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InstructorService_ListTaughtCourses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTaughtCoursesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InstructorServiceServer).ListTaughtCourses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InstructorService_ListTaughtCourses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InstructorServiceServer).ListTaughtCourses(ctx, req.(*ListTaughtCoursesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InstructorService_ServiceDesc is the grpc.ServiceDesc for InstructorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InstructorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.InstructorService",
+	HandlerType: (*InstructorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddInstructor",
+			Handler:    _InstructorService_AddInstructor_Handler,
+		},
+		{
+			MethodName: "RemoveInstructor",
+			Handler:    _InstructorService_RemoveInstructor_Handler,
+		},
+		{
+			MethodName: "ListInstructors",
+			Handler:    _InstructorService_ListInstructors_Handler,
+		},
+		{
+			MethodName: "ListTaughtCourses",
+			Handler:    _InstructorService_ListTaughtCourses_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "course_category.proto",
+}
+
+const (
+	TagService_CreateTag_FullMethodName      = "/pb.TagService/CreateTag"
+	TagService_GetTag_FullMethodName         = "/pb.TagService/GetTag"
+	TagService_ListTags_FullMethodName       = "/pb.TagService/ListTags"
+	TagService_DeleteTag_FullMethodName      = "/pb.TagService/DeleteTag"
+	TagService_TagCourse_FullMethodName      = "/pb.TagService/TagCourse"
+	TagService_UntagCourse_FullMethodName    = "/pb.TagService/UntagCourse"
+	TagService_ListCourseTags_FullMethodName = "/pb.TagService/ListCourseTags"
+)
+
+// TagServiceClient is the client API for TagService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TagService tags courses by name, creating the tag when no tag has the name
+// yet. Tagging and untagging are refused with PERMISSION_DENIED unless the
+// bearer token belongs to an instructor of the course or an admin.
+type TagServiceClient interface {
+	CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*Tag, error)
+	GetTag(ctx context.Context, in *TagGetRequest, opts ...grpc.CallOption) (*Tag, error)
+	// tags by name; name_prefix serves autocompletion
+	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*Tags, error)
+	DeleteTag(ctx context.Context, in *TagDeleteRequest, opts ...grpc.CallOption) (*Response, error)
+	TagCourse(ctx context.Context, in *TagCourseRequest, opts ...grpc.CallOption) (*Tag, error)
+	UntagCourse(ctx context.Context, in *UntagCourseRequest, opts ...grpc.CallOption) (*Response, error)
+	ListCourseTags(ctx context.Context, in *ListCourseTagsRequest, opts ...grpc.CallOption) (*Tags, error)
+}
+
+type tagServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTagServiceClient(cc grpc.ClientConnInterface) TagServiceClient {
+	return &tagServiceClient{cc}
+}
+
+func (c *tagServiceClient) CreateTag(ctx context.Context, in *CreateTagRequest, opts ...grpc.CallOption) (*Tag, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, TagService_CreateTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) GetTag(ctx context.Context, in *TagGetRequest, opts ...grpc.CallOption) (*Tag, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, TagService_GetTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*Tags, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tags)
+	err := c.cc.Invoke(ctx, TagService_ListTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) DeleteTag(ctx context.Context, in *TagDeleteRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, TagService_DeleteTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) TagCourse(ctx context.Context, in *TagCourseRequest, opts ...grpc.CallOption) (*Tag, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tag)
+	err := c.cc.Invoke(ctx, TagService_TagCourse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) UntagCourse(ctx context.Context, in *UntagCourseRequest, opts ...grpc.CallOption) (*Response, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Response)
+	err := c.cc.Invoke(ctx, TagService_UntagCourse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) ListCourseTags(ctx context.Context, in *ListCourseTagsRequest, opts ...grpc.CallOption) (*Tags, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tags)
+	err := c.cc.Invoke(ctx, TagService_ListCourseTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TagServiceServer is the server API for TagService service.
+// All implementations must embed UnimplementedTagServiceServer
+// for forward compatibility.
+//
+// TagService tags courses by name, creating the tag when no tag has the name
+// yet. Tagging and untagging are refused with PERMISSION_DENIED unless the
+// bearer token belongs to an instructor of the course or an admin.
+type TagServiceServer interface {
+	CreateTag(context.Context, *CreateTagRequest) (*Tag, error)
+	GetTag(context.Context, *TagGetRequest) (*Tag, error)
+	// tags by name; name_prefix serves autocompletion
+	ListTags(context.Context, *ListTagsRequest) (*Tags, error)
+	DeleteTag(context.Context, *TagDeleteRequest) (*Response, error)
+	TagCourse(context.Context, *TagCourseRequest) (*Tag, error)
+	UntagCourse(context.Context, *UntagCourseRequest) (*Response, error)
+	ListCourseTags(context.Context, *ListCourseTagsRequest) (*Tags, error)
+	mustEmbedUnimplementedTagServiceServer()
+}
+
+// UnimplementedTagServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTagServiceServer struct{}
+
+func (UnimplementedTagServiceServer) CreateTag(context.Context, *CreateTagRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTag not implemented")
+}
+func (UnimplementedTagServiceServer) GetTag(context.Context, *TagGetRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTag not implemented")
+}
+func (UnimplementedTagServiceServer) ListTags(context.Context, *ListTagsRequest) (*Tags, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTags not implemented")
+}
+func (UnimplementedTagServiceServer) DeleteTag(context.Context, *TagDeleteRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTag not implemented")
+}
+func (UnimplementedTagServiceServer) TagCourse(context.Context, *TagCourseRequest) (*Tag, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TagCourse not implemented")
+}
+func (UnimplementedTagServiceServer) UntagCourse(context.Context, *UntagCourseRequest) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UntagCourse not implemented")
+}
+func (UnimplementedTagServiceServer) ListCourseTags(context.Context, *ListCourseTagsRequest) (*Tags, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCourseTags not implemented")
+}
+func (UnimplementedTagServiceServer) mustEmbedUnimplementedTagServiceServer() {}
+func (UnimplementedTagServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeTagServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TagServiceServer will
+// result in compilation errors.
+type UnsafeTagServiceServer interface {
+	mustEmbedUnimplementedTagServiceServer()
+}
+
+func RegisterTagServiceServer(s grpc.ServiceRegistrar, srv TagServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTagServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TagService_ServiceDesc, srv)
+}
+
+func _TagService_CreateTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).CreateTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_CreateTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).CreateTag(ctx, req.(*CreateTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_GetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).GetTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_GetTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).GetTag(ctx, req.(*TagGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_ListTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).ListTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_ListTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).ListTags(ctx, req.(*ListTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_DeleteTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).DeleteTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_DeleteTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).DeleteTag(ctx, req.(*TagDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_TagCourse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TagCourseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).TagCourse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_TagCourse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).TagCourse(ctx, req.(*TagCourseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_UntagCourse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UntagCourseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).UntagCourse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_UntagCourse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).UntagCourse(ctx, req.(*UntagCourseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_ListCourseTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCourseTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).ListCourseTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_ListCourseTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).ListCourseTags(ctx, req.(*ListCourseTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TagService_ServiceDesc is the grpc.ServiceDesc for TagService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TagService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.TagService",
+	HandlerType: (*TagServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTag",
+			Handler:    _TagService_CreateTag_Handler,
+		},
+		{
+			MethodName: "GetTag",
+			Handler:    _TagService_GetTag_Handler,
+		},
+		{
+			MethodName: "ListTags",
+			Handler:    _TagService_ListTags_Handler,
+		},
+		{
+			MethodName: "DeleteTag",
+			Handler:    _TagService_DeleteTag_Handler,
+		},
+		{
+			MethodName: "TagCourse",
+			Handler:    _TagService_TagCourse_Handler,
+		},
+		{
+			MethodName: "UntagCourse",
+			Handler:    _TagService_UntagCourse_Handler,
+		},
+		{
+			MethodName: "ListCourseTags",
+			Handler:    _TagService_ListCourseTags_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "course_category.proto",